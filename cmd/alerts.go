@@ -0,0 +1,249 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Higangssh/homebutler/internal/alerts"
+	"github.com/Higangssh/homebutler/internal/config"
+	"github.com/Higangssh/homebutler/internal/docker"
+	"github.com/Higangssh/homebutler/internal/format"
+	"github.com/Higangssh/homebutler/internal/history"
+	"github.com/Higangssh/homebutler/internal/notify"
+)
+
+// notifyTransitions sends the alert changes since the previous
+// `alerts --notify` run to the configured notifiers. Delivery failures are
+// reported on stderr; the state is saved anyway so a dead destination
+// doesn't re-send everything to the working ones next run.
+func notifyTransitions(cfg *config.Config, result *alerts.AlertResult) error {
+	notifiers, err := notify.New(cfg.Alerts.Notify)
+	if err != nil {
+		return fmt.Errorf("config error: %w", err)
+	}
+	if len(notifiers) == 0 {
+		return fmt.Errorf("no notifiers configured (add alerts.notify to your config)")
+	}
+	path := alerts.DefaultStatePath()
+	state, err := alerts.LoadState(path)
+	if err != nil {
+		return err
+	}
+
+	server := cfg.LocalName()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	now := time.Now()
+	for _, t := range state.Update(server, result.Metrics()) {
+		if err := notify.Send(ctx, notifiers, notify.FromTransition(server, t, now)); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: notification failed: %v\n", err)
+		}
+	}
+	return state.Save(path)
+}
+
+// runTestNotify sends a test message to every notifier.
+func runTestNotify(cfg *config.Config, jsonOut bool) error {
+	notifiers, err := notify.New(cfg.Alerts.Notify)
+	if err != nil {
+		return fmt.Errorf("config error: %w", err)
+	}
+	if len(notifiers) == 0 {
+		return fmt.Errorf("no notifiers configured (add alerts.notify to your config)")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	results := notify.SendEach(ctx, notifiers, notify.Test(cfg.LocalName(), time.Now()))
+	if err := output(results, jsonOut); err != nil {
+		return err
+	}
+	failed := 0
+	for _, r := range results {
+		if r.Status != "sent" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d notifiers failed", failed, len(results))
+	}
+	return nil
+}
+
+// runAlertsWatch checks the alerts of every configured server each
+// interval until interrupted, and reports status changes on stdout and to
+// alerts.notify. The state file keeps a restart from re-firing everything.
+// It also records the metrics history; the checks record the alert history.
+func runAlertsWatch(cfg *config.Config, jsonOut bool) error {
+	interval := cfg.Alerts.Watch.Interval
+	if v := getFlag("--interval", ""); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid --interval %q (use e.g. 30s, 1m, 5m)", v)
+		}
+		interval = d
+	}
+	if interval < 5*time.Second {
+		return fmt.Errorf("interval must be at least 5s, got %s", interval)
+	}
+	notifiers, err := notify.New(cfg.Alerts.Notify)
+	if err != nil {
+		return fmt.Errorf("config error: %w", err)
+	}
+	path := alerts.DefaultStatePath()
+	state, err := alerts.LoadState(path)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	servers := cfg.Monitored()
+	fmt.Fprintf(os.Stderr, "Watching alerts on %d server(s) every %s (Ctrl+C to stop)\n", len(servers), interval)
+
+	failing := map[string]string{} // server → last error, reported once
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		results, errs := checkServers(servers, cfg)
+		now := time.Now()
+		for i, srv := range servers {
+			if errs[i] != nil {
+				if failing[srv.Name] != errs[i].Error() {
+					fmt.Fprintf(os.Stderr, "warning: %s: %v\n", srv.Name, errs[i])
+					failing[srv.Name] = errs[i].Error()
+				}
+				continue
+			}
+			if _, ok := failing[srv.Name]; ok {
+				fmt.Fprintf(os.Stderr, "%s: reachable again\n", srv.Name)
+				delete(failing, srv.Name)
+			}
+			for _, t := range state.Observe(srv.Name, results[i].Metrics(), now, &cfg.Alerts.Watch) {
+				msg := notify.FromTransition(srv.Name, t, now)
+				if jsonOut {
+					json.NewEncoder(os.Stdout).Encode(msg)
+				} else {
+					fmt.Print(format.AlertEvent(msg))
+				}
+				if err := notify.Send(ctx, notifiers, msg); err != nil {
+					fmt.Fprintf(os.Stderr, "warning: notification failed: %v\n", err)
+				}
+			}
+		}
+		if err := state.Save(path); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		}
+		// servers that can't be reached were reported above
+		for i, err := range history.Collect(history.DefaultDir(), servers, now) {
+			if err != nil && errs[i] == nil {
+				fmt.Fprintf(os.Stderr, "warning: %s: metrics history: %v\n", servers[i].Name, err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkServers runs the alert checks of all servers in parallel. Remote
+// servers use their own thresholds, except the levels their server entry
+// overrides, like `alerts --server`.
+func checkServers(servers []config.ServerConfig, cfg *config.Config) ([]*alerts.AlertResult, []error) {
+	results := make([]*alerts.AlertResult, len(servers))
+	errs := make([]error, len(servers))
+	var wg sync.WaitGroup
+	for i := range servers {
+		wg.Go(func() { results[i], errs[i] = alerts.Evaluate(cfg, &servers[i]) })
+	}
+	wg.Wait()
+	return results, errs
+}
+
+// runAlertHistory shows the recorded alerts. --server picks a server from
+// the history kept on this machine rather than running remotely.
+func runAlertHistory(jsonOut bool) error {
+	v := getFlag("--since", "24h")
+	d, err := docker.ParseAge(v)
+	if err != nil {
+		return fmt.Errorf("invalid --since %q (use e.g. 24h or 7d)", v)
+	}
+	h, err := alerts.QueryHistory(alerts.DefaultHistoryPath(), alerts.HistoryQuery{
+		Since:  time.Now().Add(-d),
+		Server: getFlag("--server", ""),
+		Metric: getFlag("--metric", ""),
+	})
+	if err != nil {
+		return err
+	}
+	return output(h, jsonOut)
+}
+
+// runSilence creates a silence, or lists (alerts silence list) or ends
+// (alerts silence expire <id>) them. Silences live on the machine whose
+// alerts they mute; --server runs this there.
+func runSilence(cfg *config.Config, jsonOut bool) error {
+	path := alerts.DefaultSilencePath()
+	now := time.Now()
+	sub := ""
+	if len(os.Args) >= 4 && !isFlag(os.Args[3]) {
+		sub = os.Args[3]
+	}
+	switch sub {
+	case "list", "ls":
+		silences, err := alerts.ActiveSilences(path, cfg.Alerts.Maintenance, now)
+		if err != nil {
+			return err
+		}
+		return output(silences, jsonOut)
+	case "expire":
+		if len(os.Args) < 5 {
+			return fmt.Errorf("usage: homebutler alerts silence expire <id>")
+		}
+		silence, err := alerts.ExpireSilence(path, os.Args[4], now)
+		if err != nil {
+			return err
+		}
+		if !jsonOut {
+			fmt.Printf("🔔 Silence %s expired\n", silence.ID)
+			return nil
+		}
+		return output(silence, jsonOut)
+	case "":
+		v := getFlag("--for", "")
+		if v == "" {
+			return fmt.Errorf("usage: homebutler alerts silence --for <duration> [--metric disk,cpu] [--reason <text>]")
+		}
+		d, err := docker.ParseAge(v)
+		if err != nil {
+			return fmt.Errorf("invalid --for %q (use e.g. 30m, 4h or 2d)", v)
+		}
+		silence, err := alerts.AddSilence(path, splitList(getFlag("--metric", "")), getFlag("--reason", ""), d, now)
+		if err != nil {
+			return err
+		}
+		return output(silence, jsonOut)
+	default:
+		return fmt.Errorf("unknown silence command: %s (use list or expire)", sub)
+	}
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Higangssh/homebutler/internal/config"
+	"github.com/Higangssh/homebutler/internal/docker"
+	"github.com/Higangssh/homebutler/internal/history"
+)
+
+// runHistory shows a metric from the history `serve` and `alerts watch`
+// record. The history is kept here, so --server picks from it and --all
+// shows every monitored server.
+func runHistory(cfg *config.Config, jsonOut bool) error {
+	if len(os.Args) < 3 || isFlag(os.Args[2]) {
+		return fmt.Errorf("usage: homebutler history <%s|disk:<mount>> [--server <name>] [--since 24h]", strings.Join(history.Metrics, "|"))
+	}
+	metric := os.Args[2]
+	v := getFlag("--since", "24h")
+	d, err := docker.ParseAge(v)
+	if err != nil {
+		return fmt.Errorf("invalid --since %q (use e.g. 24h or 7d)", v)
+	}
+
+	var servers []string
+	switch name := getFlag("--server", ""); {
+	case hasFlag("--all"):
+		for _, srv := range cfg.Monitored() {
+			servers = append(servers, srv.Name)
+		}
+	case name == "":
+		servers = []string{cfg.LocalName()}
+	case cfg.FindServer(name) == nil && name != cfg.LocalName():
+		return fmt.Errorf("server %q not found in config. Available servers: %s", name, listServerNames(cfg))
+	default:
+		servers = []string{name}
+	}
+
+	now := time.Now()
+	out := []history.Series{}
+	for _, server := range servers {
+		series, err := history.Query(history.DefaultDir(), server, metric, now.Add(-d), now)
+		if err != nil {
+			return err
+		}
+		out = append(out, series...)
+	}
+	return output(out, jsonOut)
+}
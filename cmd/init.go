@@ -30,7 +30,11 @@ func runInit() error {
 	cfgPath := filepath.Join(home, ".config", "homebutler", "config.yaml")
 
 	cfg := &config.Config{
-		Alerts: config.AlertConfig{CPU: 90, Memory: 85, Disk: 90},
+		Alerts: config.AlertConfig{
+			CPU:    config.Threshold{Critical: 90},
+			Memory: config.Threshold{Critical: 85},
+			Disk:   config.Threshold{Critical: 90},
+		},
 	}
 
 	addMode := false // true = keep existing servers, just add new ones
@@ -181,7 +185,7 @@ func runInit() error {
 		}
 	}
 	fmt.Printf("  • Alerts: CPU %g%% / Memory %g%% / Disk %g%%\n",
-		cfg.Alerts.CPU, cfg.Alerts.Memory, cfg.Alerts.Disk)
+		cfg.Alerts.CPU.Critical, cfg.Alerts.Memory.Critical, cfg.Alerts.Disk.Critical)
 	fmt.Println()
 
 	if !promptYN(scanner, "  Save config?", true) {
@@ -237,12 +241,15 @@ func promptRemoteServer(scanner *bufio.Scanner, home string) (*config.ServerConf
 	if keyFile == "" {
 		defaultAuth = "password"
 	}
-	authChoice := promptDefault(scanner, "  Auth method (key/password)", defaultAuth)
+	authChoice := promptDefault(scanner, "  Auth method (key/agent/password)", defaultAuth)
 
-	if strings.ToLower(authChoice) == "password" {
+	switch strings.ToLower(authChoice) {
+	case "password":
 		server.AuthMode = "password"
 		server.Password = promptRequiredInput(scanner, "  Password: ")
-	} else {
+	case "agent":
+		server.AuthMode = "agent"
+	default:
 		defaultKey := shortPath(keyFile, home)
 		if defaultKey == "" {
 			defaultKey = "~/.ssh/id_rsa"
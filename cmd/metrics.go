@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/Higangssh/homebutler/internal/config"
+	"github.com/Higangssh/homebutler/internal/exporter"
+)
+
+// runMetrics handles `metrics push`: it reads every monitored server each
+// interval and sends the metrics to metrics.push.url, for hosts a
+// Prometheus can't scrape. --once pushes a single time, e.g. from cron.
+func runMetrics(cfg *config.Config, jsonOut bool) error {
+	if len(os.Args) < 3 || os.Args[2] != "push" {
+		return fmt.Errorf("usage: homebutler metrics push [--interval 1m] [--once]")
+	}
+	pusher, err := exporter.NewPusher(cfg.Metrics.Push, exporter.DefaultBufferPath())
+	if err != nil {
+		return fmt.Errorf("config error: %w", err)
+	}
+	servers := cfg.Monitored()
+
+	if hasFlag("--once") {
+		result, pushErr := pusher.Push(context.Background(), exporter.LineProtocol(exporter.ScrapeAll(cfg, servers, time.Now())))
+		if result == nil {
+			return pushErr
+		}
+		if err := output(result, jsonOut); err != nil {
+			return err
+		}
+		if pushErr != nil {
+			return fmt.Errorf("metrics push failed")
+		}
+		return nil
+	}
+
+	interval := cfg.Metrics.Push.Interval
+	if v := getFlag("--interval", ""); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid --interval %q (use e.g. 30s, 1m, 5m)", v)
+		}
+		interval = d
+	}
+	if interval < 5*time.Second {
+		return fmt.Errorf("interval must be at least 5s, got %s", interval)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	fmt.Fprintf(os.Stderr, "Pushing metrics of %d server(s) every %s (Ctrl+C to stop)\n", len(servers), interval)
+
+	failing := map[string]string{} // server → last error, reported once
+	pushFailing := ""
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		snaps := exporter.ScrapeAll(cfg, servers, time.Now())
+		for _, s := range snaps {
+			switch {
+			case s.Err != nil && failing[s.Server] != s.Err.Error():
+				fmt.Fprintf(os.Stderr, "warning: %s: %v\n", s.Server, s.Err)
+				failing[s.Server] = s.Err.Error()
+			case s.Err == nil && failing[s.Server] != "":
+				fmt.Fprintf(os.Stderr, "%s: reachable again\n", s.Server)
+				delete(failing, s.Server)
+			}
+		}
+		result, err := pusher.Push(ctx, exporter.LineProtocol(snaps))
+		switch {
+		case err != nil && err.Error() != pushFailing:
+			fmt.Fprintf(os.Stderr, "warning: metrics push: %v\n", err)
+			pushFailing = err.Error()
+		case err == nil && pushFailing != "":
+			fmt.Fprintf(os.Stderr, "metrics push: sent %d buffered lines\n", result.Sent)
+			pushFailing = ""
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 	"sync"
 
 	"github.com/Higangssh/homebutler/internal/alerts"
@@ -38,7 +40,14 @@ func runAllServers(cfg *config.Config, args []string, jsonOut bool) error {
 			result := serverResult{Server: server.Name}
 
 			if server.Local {
-				out, err := runLocalCommand(remoteArgs)
+				out, err := runLocalCommand(cfg, &server, remoteArgs)
+				if err != nil {
+					result.Error = err.Error()
+				} else {
+					result.Data = json.RawMessage(out)
+				}
+			} else if isAlertCheck(remoteArgs) {
+				out, err := checkAlerts(cfg, &server)
 				if err != nil {
 					result.Error = err.Error()
 				} else {
@@ -110,8 +119,28 @@ func getFirstDiskPercent(data map[string]interface{}) float64 {
 	return 0
 }
 
+// isAlertCheck reports whether args are a plain `alerts` check, whose
+// result the levels of a server entry apply to and the alert history here
+// records. --notify is left to the remote, which keeps the state of what it
+// reported.
+func isAlertCheck(args []string) bool {
+	if len(args) == 0 || args[0] != "alerts" || slices.Contains(args, "--notify") {
+		return false
+	}
+	return len(args) == 1 || strings.HasPrefix(args[1], "-")
+}
+
+// checkAlerts checks and records the alerts of a server as JSON.
+func checkAlerts(cfg *config.Config, srv *config.ServerConfig) ([]byte, error) {
+	result, err := alerts.Evaluate(cfg, srv)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(result)
+}
+
 // runLocalCommand runs homebutler locally and captures JSON output.
-func runLocalCommand(args []string) ([]byte, error) {
+func runLocalCommand(cfg *config.Config, srv *config.ServerConfig, args []string) ([]byte, error) {
 	if len(args) == 0 {
 		return nil, fmt.Errorf("no command specified")
 	}
@@ -124,21 +153,52 @@ func runLocalCommand(args []string) ([]byte, error) {
 		}
 		return json.Marshal(info)
 	case "alerts":
-		alertCfg := &config.AlertConfig{CPU: 90, Memory: 85, Disk: 90}
-		result, err := alerts.Check(alertCfg)
-		if err != nil {
-			return nil, err
-		}
-		return json.Marshal(result)
+		return checkAlerts(cfg, srv)
 	case "docker":
+		if len(args) >= 2 && args[1] == "stats" {
+			stats, err := docker.Stats()
+			if err != nil {
+				return nil, err
+			}
+			return json.Marshal(stats)
+		}
+		if len(args) >= 2 && args[1] == "df" {
+			du, err := docker.DiskUsage()
+			if err != nil {
+				return nil, err
+			}
+			return json.Marshal(du)
+		}
+		if len(args) >= 2 && args[1] == "updates" {
+			updates, err := docker.Updates()
+			if err != nil {
+				return nil, err
+			}
+			return json.Marshal(updates)
+		}
 		if len(args) < 2 || (args[1] != "list" && args[1] != "ls") {
-			return nil, fmt.Errorf("only 'docker list' supported with --all")
+			return nil, fmt.Errorf("only 'docker list', 'docker stats', 'docker updates' and 'docker df' supported with --all")
 		}
 		containers, err := docker.List()
 		if err != nil {
 			return nil, err
 		}
 		return json.Marshal(containers)
+	case "compose":
+		if len(args) < 2 || (args[1] != "ls" && args[1] != "list") {
+			return nil, fmt.Errorf("only 'compose ls' supported with --all")
+		}
+		projects, err := docker.Projects()
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(projects)
+	case "sensors":
+		sensors, err := system.Sensors()
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(sensors)
 	case "ports":
 		openPorts, err := ports.List()
 		if err != nil {
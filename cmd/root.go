@@ -2,18 +2,24 @@ package cmd
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/Higangssh/homebutler/internal/alerts"
 	"github.com/Higangssh/homebutler/internal/config"
 	"github.com/Higangssh/homebutler/internal/docker"
+	"github.com/Higangssh/homebutler/internal/exporter"
 	"github.com/Higangssh/homebutler/internal/format"
+	"github.com/Higangssh/homebutler/internal/history"
 	"github.com/Higangssh/homebutler/internal/mcp"
 	"github.com/Higangssh/homebutler/internal/network"
+	"github.com/Higangssh/homebutler/internal/notify"
 	"github.com/Higangssh/homebutler/internal/ports"
 	"github.com/Higangssh/homebutler/internal/remote"
 	"github.com/Higangssh/homebutler/internal/system"
@@ -38,29 +44,53 @@ func Execute(version, buildDate string) error {
 	if err != nil {
 		return fmt.Errorf("config error: %w", err)
 	}
+	defer remote.Close()
+	remote.SetEncryptedKeysOnly(cfg.SSH.EncryptedKeysOnly)
+	system.SetMountFilter(cfg.Disks.Include, cfg.Disks.Exclude)
+	docker.SetComposeDirs(cfg.Compose.Dirs)
 
 	jsonOutput := hasFlag("--json")
 	serverName := getFlag("--server", "")
 	allServers := hasFlag("--all")
 
+	if err := docker.SetRuntime(localRuntime(cfg, serverName)); err != nil {
+		return fmt.Errorf("config error: %w", err)
+	}
+
 	// watch command — always monitors all configured servers
 	if os.Args[1] == "watch" {
+		remote.Connect(cfg.Servers)
+		remote.DisablePrompts()
 		return tui.Run(cfg, nil)
 	}
 
-	// Multi-server: route to remote execution (skip for deploy/upgrade — they handle remoting themselves)
+	// Multi-server: route to remote execution (skip for deploy/upgrade — they handle remoting themselves,
+	// and for alerts history and history, where --server selects from the history kept here)
 	isDeployCmd := len(os.Args) >= 2 && os.Args[1] == "deploy"
 	isUpgradeCmd := len(os.Args) >= 2 && os.Args[1] == "upgrade"
-	if allServers && !isDeployCmd && !isUpgradeCmd {
+	isHistoryCmd := os.Args[1] == "history" || len(os.Args) >= 3 && os.Args[1] == "alerts" && os.Args[2] == "history"
+	if allServers && !isDeployCmd && !isUpgradeCmd && !isHistoryCmd {
 		return runAllServers(cfg, os.Args[1:], jsonOutput)
 	}
-	if serverName != "" && !isDeployCmd && !isUpgradeCmd {
+	if serverName != "" && !isDeployCmd && !isUpgradeCmd && !isHistoryCmd {
 		server := cfg.FindServer(serverName)
 		if server == nil {
 			return fmt.Errorf("server %q not found in config. Available servers: %s", serverName, listServerNames(cfg))
 		}
 		if !server.Local {
 			remoteArgs := filterFlags(os.Args[1:], "--server", "--all")
+			if isFollow() {
+				ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+				defer stop()
+				return remote.Stream(ctx, server, os.Stdout, remoteArgs...)
+			}
+			if isAlertCheck(remoteArgs) {
+				result, err := alerts.Evaluate(cfg, server)
+				if err != nil {
+					return err
+				}
+				return output(result, jsonOutput)
+			}
 			out, err := remote.Run(server, remoteArgs...)
 			if err != nil {
 				return err
@@ -76,16 +106,24 @@ func Execute(version, buildDate string) error {
 		return runStatus(jsonOutput)
 	case "docker":
 		return runDocker(jsonOutput)
+	case "compose":
+		return runCompose(jsonOutput)
 	case "ports":
 		return runPorts(jsonOutput)
 	case "processes":
 		return runProcesses(jsonOutput)
+	case "sensors":
+		return runSensors(jsonOutput)
 	case "network":
 		return runNetwork(jsonOutput)
 	case "wake":
 		return runWake(cfg, jsonOutput)
 	case "alerts":
 		return runAlerts(cfg, jsonOutput)
+	case "history":
+		return runHistory(cfg, jsonOutput)
+	case "metrics":
+		return runMetrics(cfg, jsonOutput)
 	case "trust":
 		return runTrust(cfg)
 	case "deploy":
@@ -120,7 +158,7 @@ func runStatus(jsonOut bool) error {
 
 func runDocker(jsonOutput bool) error {
 	if len(os.Args) < 3 {
-		return fmt.Errorf("usage: homebutler docker <list|restart|stop|logs> [name]")
+		return fmt.Errorf("usage: homebutler docker <list|stats|updates|df|prune|start|stop|restart|pause|unpause|kill|rm|logs> [name]")
 	}
 
 	switch os.Args[2] {
@@ -130,6 +168,42 @@ func runDocker(jsonOutput bool) error {
 			return err
 		}
 		return output(containers, jsonOutput)
+	case "stats":
+		stats, err := docker.Stats()
+		if err != nil {
+			return err
+		}
+		return output(stats, jsonOutput)
+	case "updates":
+		updates, err := docker.Updates()
+		if err != nil {
+			return err
+		}
+		return output(updates, jsonOutput)
+	case "df":
+		du, err := docker.DiskUsage()
+		if err != nil {
+			return err
+		}
+		return output(du, jsonOutput)
+	case "prune":
+		opts := docker.PruneOptions{
+			DryRun:  hasFlag("--dry-run"),
+			All:     hasFlag("--all-images"),
+			Volumes: hasFlag("--volumes"),
+		}
+		if v := getFlag("--older-than", ""); v != "" {
+			age, err := docker.ParseAge(v)
+			if err != nil {
+				return err
+			}
+			opts.OlderThan = age
+		}
+		result, err := docker.Prune(opts)
+		if err != nil {
+			return err
+		}
+		return output(result, jsonOutput)
 	case "restart":
 		if len(os.Args) < 4 {
 			return fmt.Errorf("usage: homebutler docker restart <container>")
@@ -148,15 +222,72 @@ func runDocker(jsonOutput bool) error {
 			return err
 		}
 		return output(result, jsonOutput)
-	case "logs":
+	case "start":
 		if len(os.Args) < 4 {
-			return fmt.Errorf("usage: homebutler docker logs <container> [lines]")
+			return fmt.Errorf("usage: homebutler docker start <container>")
 		}
-		lines := "50"
-		if len(os.Args) >= 5 {
-			lines = os.Args[4]
+		result, err := docker.Start(os.Args[3])
+		if err != nil {
+			return err
 		}
-		result, err := docker.Logs(os.Args[3], lines)
+		return output(result, jsonOutput)
+	case "pause":
+		if len(os.Args) < 4 {
+			return fmt.Errorf("usage: homebutler docker pause <container>")
+		}
+		result, err := docker.Pause(os.Args[3])
+		if err != nil {
+			return err
+		}
+		return output(result, jsonOutput)
+	case "unpause":
+		if len(os.Args) < 4 {
+			return fmt.Errorf("usage: homebutler docker unpause <container>")
+		}
+		result, err := docker.Unpause(os.Args[3])
+		if err != nil {
+			return err
+		}
+		return output(result, jsonOutput)
+	case "kill":
+		if len(os.Args) < 4 || isFlag(os.Args[3]) {
+			return fmt.Errorf("usage: homebutler docker kill <container> [--signal <sig>]")
+		}
+		result, err := docker.Kill(os.Args[3], getFlag("--signal", ""))
+		if err != nil {
+			return err
+		}
+		return output(result, jsonOutput)
+	case "rm":
+		if len(os.Args) < 4 || isFlag(os.Args[3]) {
+			return fmt.Errorf("usage: homebutler docker rm <container> [--force]")
+		}
+		result, err := docker.Remove(os.Args[3], hasFlag("--force"))
+		if err != nil {
+			return err
+		}
+		return output(result, jsonOutput)
+	case "logs":
+		if len(os.Args) < 4 || isFlag(os.Args[3]) {
+			return fmt.Errorf("usage: homebutler docker logs <container> [lines] [--follow] [--since 10m] [--grep pattern]")
+		}
+		opts := docker.LogOptions{
+			Tail:   "50",
+			Since:  getFlag("--since", ""),
+			Grep:   getFlag("--grep", ""),
+			Follow: isFollow(),
+		}
+		if opts.Since != "" {
+			// The time window decides what to show
+			opts.Tail = "all"
+		}
+		if len(os.Args) >= 5 && !isFlag(os.Args[4]) {
+			opts.Tail = os.Args[4]
+		}
+		if opts.Follow {
+			return followLogs(os.Args[3], opts, jsonOutput)
+		}
+		result, err := docker.Logs(os.Args[3], opts)
 		if err != nil {
 			return err
 		}
@@ -166,6 +297,69 @@ func runDocker(jsonOutput bool) error {
 	}
 }
 
+// isFollow reports whether a streaming command (docker logs) should keep
+// running.
+func isFollow() bool {
+	return hasFlag("--follow") || hasFlag("-f")
+}
+
+// followLogs prints log lines as they arrive until Ctrl-C. With --json
+// every line is a JSON object (NDJSON).
+func followLogs(name string, opts docker.LogOptions, jsonOutput bool) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	enc := json.NewEncoder(os.Stdout)
+	return docker.StreamLogs(ctx, name, opts, func(l docker.LogLine) error {
+		if jsonOutput {
+			return enc.Encode(l)
+		}
+		_, err := fmt.Print(format.LogLine(l))
+		return err
+	})
+}
+
+func runCompose(jsonOutput bool) error {
+	if len(os.Args) < 3 {
+		return fmt.Errorf("usage: homebutler compose <ls|ps|restart|up|down|pull> [project]")
+	}
+	if os.Args[2] == "ls" || os.Args[2] == "list" {
+		projects, err := docker.Projects()
+		if err != nil {
+			return err
+		}
+		return output(projects, jsonOutput)
+	}
+	if len(os.Args) < 4 || isFlag(os.Args[3]) {
+		return fmt.Errorf("usage: homebutler compose %s <project>", os.Args[2])
+	}
+	project := os.Args[3]
+
+	var result *docker.ComposeResult
+	var err error
+	switch os.Args[2] {
+	case "ps":
+		containers, err := docker.ProjectContainers(project)
+		if err != nil {
+			return err
+		}
+		return output(containers, jsonOutput)
+	case "restart":
+		result, err = docker.ComposeRestart(project)
+	case "up":
+		result, err = docker.ComposeUp(project)
+	case "down":
+		result, err = docker.ComposeDown(project)
+	case "pull":
+		result, err = docker.ComposePull(project)
+	default:
+		return fmt.Errorf("unknown compose command: %s", os.Args[2])
+	}
+	if err != nil {
+		return err
+	}
+	return output(result, jsonOutput)
+}
+
 func runPorts(jsonOut bool) error {
 	openPorts, err := ports.List()
 	if err != nil {
@@ -182,6 +376,14 @@ func runProcesses(jsonOut bool) error {
 	return output(procs, jsonOut)
 }
 
+func runSensors(jsonOut bool) error {
+	sensors, err := system.Sensors()
+	if err != nil {
+		return err
+	}
+	return output(sensors, jsonOut)
+}
+
 func runNetwork(jsonOut bool) error {
 	if len(os.Args) < 3 || os.Args[2] != "scan" {
 		return fmt.Errorf("usage: homebutler network scan")
@@ -221,10 +423,27 @@ func runWake(cfg *config.Config, jsonOut bool) error {
 }
 
 func runAlerts(cfg *config.Config, jsonOut bool) error {
-	result, err := alerts.Check(&cfg.Alerts)
+	if len(os.Args) >= 3 {
+		switch os.Args[2] {
+		case "test-notify":
+			return runTestNotify(cfg, jsonOut)
+		case "watch":
+			return runAlertsWatch(cfg, jsonOut)
+		case "silence":
+			return runSilence(cfg, jsonOut)
+		case "history":
+			return runAlertHistory(jsonOut)
+		}
+	}
+	result, err := alerts.Evaluate(cfg, nil)
 	if err != nil {
 		return fmt.Errorf("failed to check alerts: %w", err)
 	}
+	if hasFlag("--notify") {
+		if err := notifyTransitions(cfg, result); err != nil {
+			return err
+		}
+	}
 	return output(result, jsonOut)
 }
 
@@ -277,10 +496,40 @@ func output(data any, jsonOut bool) error {
 		fmt.Print(format.Status(v))
 	case []docker.Container:
 		fmt.Print(format.DockerList(v))
+	case []docker.ContainerStats:
+		fmt.Print(format.DockerStats(v))
+	case []docker.ImageUpdate:
+		fmt.Print(format.DockerUpdates(v))
+	case *docker.DiskUsageInfo:
+		fmt.Print(format.DockerDiskUsage(v))
+	case *docker.PruneResult:
+		fmt.Print(format.DockerPrune(v))
+	case []notify.Result:
+		fmt.Print(format.NotifyResults(v))
+	case []alerts.Silence:
+		fmt.Print(format.Silences(v))
+	case *alerts.Silence:
+		fmt.Print(format.Silence(v))
+	case *alerts.History:
+		fmt.Print(format.AlertHistory(v))
+	case []history.Series:
+		fmt.Print(format.MetricsHistory(v))
+	case *exporter.PushResult:
+		fmt.Print(format.PushResult(v))
 	case *docker.ActionResult:
-		fmt.Print(format.DockerAction(v.Action, v.Container))
+		action := v.Action
+		if v.Signal != "" {
+			action += " (" + v.Signal + ")"
+		}
+		fmt.Print(format.DockerAction(action, v.Container))
+	case []docker.Project:
+		fmt.Print(format.ComposeProjects(v))
+	case *docker.ComposeResult:
+		fmt.Print(format.ComposeAction(v))
 	case *docker.LogsResult:
-		fmt.Printf("=== %s (last %s lines) ===\n%s\n", v.Container, v.Lines, v.Logs)
+		fmt.Print(format.DockerLogs(v))
+	case *system.SensorsInfo:
+		fmt.Print(format.Sensors(v))
 	case *alerts.AlertResult:
 		fmt.Print(format.Alerts(v))
 	case []ports.PortInfo:
@@ -323,10 +572,18 @@ func isFlag(s string) bool {
 
 // valueFlags are flags that take a value argument.
 var valueFlags = map[string]bool{
-	"--server": true,
-	"--config": true,
-	"--local":  true,
-	"--port":   true,
+	"--server":     true,
+	"--config":     true,
+	"--local":      true,
+	"--port":       true,
+	"--signal":     true,
+	"--since":      true,
+	"--grep":       true,
+	"--older-than": true,
+	"--interval":   true,
+	"--metric":     true,
+	"--for":        true,
+	"--reason":     true,
 }
 
 func filterFlags(args []string, flags ...string) []string {
@@ -352,6 +609,20 @@ func filterFlags(args []string, flags ...string) []string {
 	return filtered
 }
 
+// localRuntime returns the container runtime configured for this machine:
+// that of the --server entry if it is local, else of the first local server.
+func localRuntime(cfg *config.Config, serverName string) string {
+	if srv := cfg.FindServer(serverName); srv != nil && srv.Local {
+		return srv.Runtime
+	}
+	for _, srv := range cfg.Servers {
+		if srv.Local {
+			return srv.Runtime
+		}
+	}
+	return ""
+}
+
 func listServerNames(cfg *config.Config) string {
 	if len(cfg.Servers) == 0 {
 		return "(none configured)"
@@ -374,13 +645,35 @@ Commands:
   status              System status (CPU, memory, disk, uptime)
   watch               TUI dashboard (monitors all configured servers)
   docker list         List running containers
-  docker restart <n>  Restart a container
+  docker stats        Container CPU, memory, network and block I/O
+  docker updates      Find containers running outdated images
+  docker df           Disk used by images, containers, volumes and build cache
+  docker prune        Remove stopped containers, dangling images, build cache
+  docker start <n>    Start a stopped container
   docker stop <n>     Stop a container
-  docker logs <n>     Show container logs (default: 50 lines)
+  docker restart <n>  Restart a container
+  docker pause <n>    Pause a container (unpause <n> resumes it)
+  docker kill <n>     Send a signal to a container (default SIGKILL)
+  docker rm <n>       Remove a container (--force if running)
+  docker logs <n>     Show container logs (default: 50 lines; -f follows)
+  compose ls          List compose projects
+  compose ps <p>      Containers of a compose project
+  compose restart <p> Restart every container of a project
+  compose up <p>      docker compose up -d (needs the docker CLI)
+  compose down <p>    docker compose down
+  compose pull <p>    Pull newer images for a project
   wake <mac|name>     Send Wake-on-LAN magic packet
   ports               List open ports with process info
+  sensors             Hardware temperatures and fan speeds (Linux)
   network scan        Discover devices on local network
-  alerts              Check resource thresholds (CPU, memory, disk)
+  alerts              Check resource thresholds (CPU, memory, disk, temperature)
+  alerts test-notify  Send a test message to every alerts.notify destination
+  alerts watch        Check all servers every interval, report status changes
+  alerts silence      Silence alerts (--metric disk --for 4h --reason "...")
+  alerts silence list|expire <id>  List active silences / end one early
+  alerts history      Warning and critical alerts of past checks (--since 7d --server nas)
+  history <metric>    Recorded cpu, memory, swap, load, disk, net_rx, net_tx (--since 7d --server nas)
+  metrics push        Push metrics as InfluxDB line protocol to metrics.push.url (--once)
   trust <server>      Trust a remote server's SSH host key
   upgrade             Upgrade local + all remote servers to latest
   deploy              Install homebutler on remote servers
@@ -397,6 +690,22 @@ Flags:
   --local             Upgrade only the local binary (skip remote servers)
   --local <path>      Use local binary for deploy (air-gapped)
   --port <number>     Port for serve command (default: 8080)
+  --signal <sig>      Signal for docker kill (e.g. SIGHUP, TERM, 9)
+  --force             Remove a running container (use with docker rm)
+  -f, --follow        Stream new log lines until Ctrl-C (use with docker logs)
+  --since <when>      Logs since a duration (10m, 2h) or RFC 3339 time; alert and metrics history since (default: 24h)
+  --grep <regexp>     Only log lines matching a regular expression
+  --dry-run           List what docker prune would remove, remove nothing
+  --older-than <age>  Only prune things older than this (7d, 12h)
+  --all-images        Also prune unused tagged images and named volumes
+  --volumes           Also prune unused volumes (anonymous unless --all-images)
+  --notify            Send alert status changes since the last run (use with alerts)
+  --interval <dur>    Interval of alerts watch and metrics push (default: 1m)
+  --once              Push metrics once and exit (use with metrics push)
+  --metric <m,...>    Metrics to silence: cpu, disk, disk:/mnt/data, container:plex... (default: all);
+                      with alerts history, one metric to show
+  --for <dur>         How long to silence (4h, 2d)
+  --reason <text>     Why, shown in silence list
   --demo              Run serve with realistic demo data (no real system calls)
   --config <path>     Config file path (see Configuration below)
 
@@ -116,3 +116,23 @@ func TestIsFlag(t *testing.T) {
 		}
 	}
 }
+
+func TestIsAlertCheck(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected bool
+	}{
+		{[]string{"alerts"}, true},
+		{[]string{"alerts", "--json"}, true},
+		{[]string{"alerts", "--notify"}, false},
+		{[]string{"alerts", "silence", "list"}, false},
+		{[]string{"status"}, false},
+		{nil, false},
+	}
+
+	for _, tc := range tests {
+		if got := isAlertCheck(tc.args); got != tc.expected {
+			t.Errorf("isAlertCheck(%q) = %v, want %v", tc.args, got, tc.expected)
+		}
+	}
+}
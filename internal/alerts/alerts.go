@@ -1,27 +1,82 @@
 package alerts
 
 import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
 	"github.com/Higangssh/homebutler/internal/config"
+	"github.com/Higangssh/homebutler/internal/docker"
 	"github.com/Higangssh/homebutler/internal/system"
 )
 
 type AlertResult struct {
-	CPU    AlertItem   `json:"cpu"`
-	Memory AlertItem   `json:"memory"`
-	Disks  []DiskAlert `json:"disks"`
+	Status         string           `json:"status"` // worst status of the alerts that aren't silenced
+	CPU            AlertItem        `json:"cpu"`
+	Memory         AlertItem        `json:"memory"`
+	Swap           *AlertItem       `json:"swap,omitempty"`
+	MemoryPressure *AlertItem       `json:"memory_pressure,omitempty"`
+	Disks          []DiskAlert      `json:"disks"`
+	Inodes         []DiskAlert      `json:"inodes,omitempty"`
+	Temperatures   []TempAlert      `json:"temperatures,omitempty"`
+	Network        []NetAlert       `json:"network,omitempty"`
+	Containers     []ContainerAlert `json:"containers,omitempty"`
 }
 
 type AlertItem struct {
 	Status    string  `json:"status"`
 	Current   float64 `json:"current"`
-	Threshold float64 `json:"threshold"`
+	Threshold float64 `json:"threshold"` // critical level
+	Warning   float64 `json:"warning,omitempty"`
+	Silenced  bool    `json:"silenced,omitempty"`
 }
 
 type DiskAlert struct {
 	Mount     string  `json:"mount"`
 	Status    string  `json:"status"`
 	Current   float64 `json:"current"`
-	Threshold float64 `json:"threshold"`
+	Threshold float64 `json:"threshold"` // critical level
+	Warning   float64 `json:"warning,omitempty"`
+	Hint      string  `json:"hint,omitempty"`
+	Silenced  bool    `json:"silenced,omitempty"`
+}
+
+// TempAlert is a temperature sensor checked against the configured limit (°C).
+type TempAlert struct {
+	Sensor    string  `json:"sensor"`
+	Status    string  `json:"status"`
+	Current   float64 `json:"current"`
+	Threshold float64 `json:"threshold"` // critical level
+	Warning   float64 `json:"warning,omitempty"`
+	Silenced  bool    `json:"silenced,omitempty"`
+}
+
+// NetAlert is a network interface checked for saturation ("utilization",
+// % of link speed) or error growth ("errors", errors+drops per second).
+type NetAlert struct {
+	Interface string  `json:"interface"`
+	Metric    string  `json:"metric"`
+	Status    string  `json:"status"`
+	Current   float64 `json:"current"`
+	Threshold float64 `json:"threshold"` // critical level
+	Warning   float64 `json:"warning,omitempty"`
+	Silenced  bool    `json:"silenced,omitempty"`
+}
+
+// ContainerAlert is a container rule: "running" (listed in
+// alerts.containers.running, reported even when ok), "health" or
+// "restarts" (reported only when they fire).
+type ContainerAlert struct {
+	Container string  `json:"container"`
+	Rule      string  `json:"rule"`
+	Status    string  `json:"status"`
+	Message   string  `json:"message"`
+	Current   float64 `json:"current,omitempty"`   // exits within the window
+	Threshold float64 `json:"threshold,omitempty"` // exits that trigger the alert
+	Silenced  bool    `json:"silenced,omitempty"`
 }
 
 func Check(cfg *config.AlertConfig) (*AlertResult, error) {
@@ -31,35 +86,290 @@ func Check(cfg *config.AlertConfig) (*AlertResult, error) {
 	}
 
 	result := &AlertResult{
-		CPU: AlertItem{
-			Status:    statusFor(info.CPU.UsagePercent, cfg.CPU),
-			Current:   info.CPU.UsagePercent,
-			Threshold: cfg.CPU,
-		},
-		Memory: AlertItem{
-			Status:    statusFor(info.Memory.Percent, cfg.Memory),
-			Current:   info.Memory.Percent,
-			Threshold: cfg.Memory,
-		},
+		CPU:    item(info.CPU.UsagePercent, cfg.CPU),
+		Memory: item(info.Memory.Percent, cfg.Memory),
 	}
 
 	for _, d := range info.Disks {
+		status, warning, critical := evaluate(d.Percent, cfg.DiskLevel(d.Mount))
 		result.Disks = append(result.Disks, DiskAlert{
 			Mount:     d.Mount,
-			Status:    statusFor(d.Percent, cfg.Disk),
+			Status:    status,
 			Current:   d.Percent,
-			Threshold: cfg.Disk,
+			Threshold: critical,
+			Warning:   warning,
 		})
 	}
+	if slices.ContainsFunc(result.Disks, func(d DiskAlert) bool { return d.Status != "ok" }) {
+		addDockerHint(result.Disks, docker.LocalRootDir())
+	}
+	result.Inodes = checkInodes(info.Disks, cfg.Inodes)
+	result.Swap = checkSwap(info.Memory.Swap, cfg.Swap)
+	result.MemoryPressure = checkMemoryPressure(info.Pressure, cfg.MemoryPressure)
+
+	// Sensors are optional: VMs and non-Linux hosts simply have none.
+	if cfg.Temperature.Critical > 0 {
+		if sensors, err := system.Sensors(); err == nil {
+			result.Temperatures = checkTemperatures(sensors, cfg.Temperature)
+		}
+	}
+
+	result.Network = checkNetwork(info.Network, cfg.Network, cfg.NetErrors)
+	result.Containers = containerAlerts(&cfg.Containers)
+
+	// A broken silences file must not stop alerting; the rest still apply
+	silences, err := ActiveSilences(DefaultSilencePath(), cfg.Maintenance, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: ignoring silences: %v\n", err)
+	}
+	result.applySilences(silences)
 
 	return result, nil
 }
 
-func statusFor(current, threshold float64) string {
-	if current >= threshold {
-		return "critical"
+// containerAlerts applies the container rules to the local runtime. Hosts
+// without one get no container alerts, unless containers must be running.
+func containerAlerts(cfg *config.ContainerAlertConfig) []ContainerAlert {
+	if len(cfg.Running) == 0 && !cfg.Unhealthy && cfg.Restarts <= 0 {
+		return nil
+	}
+	containers, err := docker.List()
+	if err != nil {
+		var alerts []ContainerAlert
+		for _, name := range cfg.Running {
+			alerts = append(alerts, ContainerAlert{
+				Container: name,
+				Rule:      "running",
+				Status:    "critical",
+				Message:   fmt.Sprintf("cannot check %s: %v", name, err),
+			})
+		}
+		return alerts
+	}
+	var exits map[string]int
+	if cfg.Restarts > 0 {
+		// Older runtimes may not keep events; skip the rule rather than fail
+		exits, _ = docker.Exits(time.Now().Add(-cfg.RestartWindow))
+	}
+	return checkContainers(containers, exits, cfg)
+}
+
+func checkContainers(containers []docker.Container, exits map[string]int, cfg *config.ContainerAlertConfig) []ContainerAlert {
+	var alerts []ContainerAlert
+	for _, name := range cfg.Running {
+		alert := ContainerAlert{Container: name, Rule: "running", Status: "ok", Message: name + " is running"}
+		idx := slices.IndexFunc(containers, func(c docker.Container) bool { return c.Name == name })
+		switch {
+		case idx < 0:
+			alert.Status, alert.Message = "critical", name+" not found"
+		case containers[idx].State == "paused":
+			alert.Status, alert.Message = "warning", name+" is paused"
+		case containers[idx].State != "running":
+			alert.Status, alert.Message = "critical", fmt.Sprintf("%s is %s", name, containers[idx].State)
+		}
+		alerts = append(alerts, alert)
+	}
+	if cfg.Unhealthy {
+		for _, c := range containers {
+			if c.Health == "unhealthy" {
+				alerts = append(alerts, ContainerAlert{
+					Container: c.Name,
+					Rule:      "health",
+					Status:    "critical",
+					Message:   c.Name + " is unhealthy",
+				})
+			}
+		}
+	}
+	if cfg.Restarts > 0 {
+		names := slices.Sorted(maps.Keys(exits))
+		for _, name := range names {
+			if n := exits[name]; n >= cfg.Restarts {
+				alerts = append(alerts, ContainerAlert{
+					Container: name,
+					Rule:      "restarts",
+					Status:    "critical",
+					Message:   fmt.Sprintf("%s exited %d times in %s", name, n, formatWindow(cfg.RestartWindow)),
+					Current:   float64(n),
+					Threshold: float64(cfg.Restarts),
+				})
+			}
+		}
+	}
+	return alerts
+}
+
+// formatWindow spells out a window: "10 minutes", "1 hour", "90s".
+func formatWindow(d time.Duration) string {
+	unit, n := "", 0
+	switch {
+	case d >= time.Hour && d%time.Hour == 0:
+		unit, n = "hour", int(d/time.Hour)
+	case d >= time.Minute && d%time.Minute == 0:
+		unit, n = "minute", int(d/time.Minute)
+	default:
+		return d.String()
+	}
+	if n != 1 {
+		unit += "s"
+	}
+	return fmt.Sprintf("%d %s", n, unit)
+}
+
+// addDockerHint points at docker cleanup on a filling disk that holds
+// docker's data directory, the most common culprit.
+func addDockerHint(disks []DiskAlert, rootDir string) {
+	if rootDir == "" {
+		return
 	}
-	if current >= threshold*0.9 {
+	mounts := make([]string, len(disks))
+	for i, d := range disks {
+		mounts[i] = d.Mount
+	}
+	mount := docker.MountFor(rootDir, mounts)
+	for i := range disks {
+		if disks[i].Mount == mount && disks[i].Status != "ok" {
+			disks[i].Hint = fmt.Sprintf("docker data (%s) is on this disk: see `homebutler docker df`, then `homebutler docker prune --dry-run`", rootDir)
+		}
+	}
+}
+
+// checkNetwork skips interfaces that are down, and saturation on links that
+// don't report a speed (wifi, bridges).
+func checkNetwork(nets []system.NetInfo, utilization, errorRate config.Threshold) []NetAlert {
+	var alerts []NetAlert
+	for _, n := range nets {
+		if n.State == "down" {
+			continue
+		}
+		if utilization.Critical > 0 && n.SpeedMbps > 0 {
+			alerts = append(alerts, netAlert(n.Interface, "utilization", n.Utilization, utilization))
+		}
+		if errorRate.Critical > 0 {
+			alerts = append(alerts, netAlert(n.Interface, "errors", n.ErrorsPerSec, errorRate))
+		}
+	}
+	return alerts
+}
+
+func netAlert(iface, metric string, current float64, t config.Threshold) NetAlert {
+	status, warning, critical := evaluate(current, t)
+	return NetAlert{
+		Interface: iface,
+		Metric:    metric,
+		Status:    status,
+		Current:   current,
+		Threshold: critical,
+		Warning:   warning,
+	}
+}
+
+// checkSwap returns nil when swap alerts are disabled or no swap is configured.
+func checkSwap(swap system.SwapInfo, threshold config.Threshold) *AlertItem {
+	if threshold.Critical <= 0 || swap.TotalBytes == 0 {
+		return nil
+	}
+	a := item(swap.Percent, threshold)
+	return &a
+}
+
+// checkMemoryPressure alerts on the share of time tasks stalled waiting for
+// memory (PSI "some" avg60), which catches thrashing that a plain used
+// percentage can't. Returns nil when the kernel has no PSI.
+func checkMemoryPressure(p *system.PressureInfo, threshold config.Threshold) *AlertItem {
+	if threshold.Critical <= 0 || p == nil || p.Memory == nil {
+		return nil
+	}
+	a := item(p.Memory.Some.Avg60, threshold)
+	return &a
+}
+
+// checkInodes skips filesystems without a fixed inode table (btrfs, most
+// network mounts), which report zero inodes.
+func checkInodes(disks []system.DiskInfo, threshold config.Threshold) []DiskAlert {
+	if threshold.Critical <= 0 {
+		return nil
+	}
+	var inodes []DiskAlert
+	for _, d := range disks {
+		if d.InodesTotal == 0 {
+			continue
+		}
+		status, warning, critical := evaluate(d.InodePercent, threshold)
+		inodes = append(inodes, DiskAlert{
+			Mount:     d.Mount,
+			Status:    status,
+			Current:   d.InodePercent,
+			Threshold: critical,
+			Warning:   warning,
+		})
+	}
+	return inodes
+}
+
+func checkTemperatures(sensors *system.SensorsInfo, threshold config.Threshold) []TempAlert {
+	var temps []TempAlert
+	names := sensorNames(sensors.Temperatures)
+	for i, t := range sensors.Temperatures {
+		status, warning, critical := evaluate(t.Celsius, threshold)
+		temps = append(temps, TempAlert{
+			Sensor:    names[i],
+			Status:    status,
+			Current:   t.Celsius,
+			Threshold: critical,
+			Warning:   warning,
+		})
+	}
+	return temps
+}
+
+// sensorNames names each sensor chip/label. Where hwmon repeats a name,
+// like the "Composite" of two NVMe drives, the device is added, or else a
+// number from the second one on.
+func sensorNames(temps []system.TempSensor) []string {
+	names := make([]string, len(temps))
+	count := map[string]int{}
+	for i, t := range temps {
+		names[i] = t.Chip + "/" + t.Label
+		count[names[i]]++
+	}
+	seen := map[string]int{}
+	for i, t := range temps {
+		if count[names[i]] == 1 {
+			continue
+		}
+		if t.Device != "" {
+			names[i] += "@" + t.Device
+		}
+		seen[names[i]]++
+		if n := seen[names[i]]; n > 1 {
+			names[i] += "#" + strconv.Itoa(n)
+		}
+	}
+	return names
+}
+
+func item(current float64, t config.Threshold) AlertItem {
+	status, warning, critical := evaluate(current, t)
+	return AlertItem{Status: status, Current: current, Threshold: critical, Warning: warning}
+}
+
+// evaluate returns the status of current and the levels it was checked
+// against.
+func evaluate(current float64, t config.Threshold) (status string, warning, critical float64) {
+	warning, critical = t.Levels()
+	return statusFor(current, t), warning, critical
+}
+
+// statusFor is "ok" while a level is disabled (critical 0).
+func statusFor(current float64, t config.Threshold) string {
+	warning, critical := t.Levels()
+	switch {
+	case critical <= 0:
+		return "ok"
+	case current >= critical:
+		return "critical"
+	case current >= warning:
 		return "warning"
 	}
 	return "ok"
@@ -1,34 +1,262 @@
 package alerts
 
-import "testing"
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Higangssh/homebutler/internal/config"
+	"github.com/Higangssh/homebutler/internal/docker"
+	"github.com/Higangssh/homebutler/internal/system"
+)
 
 func TestStatusFor(t *testing.T) {
 	tests := []struct {
 		name      string
 		current   float64
-		threshold float64
+		threshold config.Threshold
 		want      string
 	}{
-		{"ok-low", 10, 90, "ok"},
-		{"ok-medium", 50, 90, "ok"},
-		{"ok-below-warning", 80, 90, "ok"},
-		{"warning-at-90pct", 81, 90, "warning"},
-		{"warning-high", 89, 90, "warning"},
-		{"critical-at-threshold", 90, 90, "critical"},
-		{"critical-above", 95, 90, "critical"},
-		{"critical-100", 100, 90, "critical"},
-		{"zero-threshold", 0, 0, "critical"},
-		{"custom-threshold-ok", 40, 50, "ok"},
-		{"custom-threshold-warning", 46, 50, "warning"},
-		{"custom-threshold-critical", 50, 50, "critical"},
+		{"ok-low", 10, config.Threshold{Critical: 90}, "ok"},
+		{"ok-medium", 50, config.Threshold{Critical: 90}, "ok"},
+		{"ok-below-warning", 80, config.Threshold{Critical: 90}, "ok"},
+		{"warning-at-90pct", 81, config.Threshold{Critical: 90}, "warning"},
+		{"warning-high", 89, config.Threshold{Critical: 90}, "warning"},
+		{"critical-at-threshold", 90, config.Threshold{Critical: 90}, "critical"},
+		{"critical-above", 95, config.Threshold{Critical: 90}, "critical"},
+		{"critical-100", 100, config.Threshold{Critical: 90}, "critical"},
+		{"zero-threshold-disabled", 100, config.Threshold{}, "ok"},
+		{"custom-threshold-ok", 40, config.Threshold{Critical: 50}, "ok"},
+		{"custom-threshold-warning", 46, config.Threshold{Critical: 50}, "warning"},
+		{"custom-threshold-critical", 50, config.Threshold{Critical: 50}, "critical"},
+		{"explicit-warning-ok", 79, config.Threshold{Warning: 80, Critical: 97}, "ok"},
+		{"explicit-warning", 90, config.Threshold{Warning: 80, Critical: 97}, "warning"},
+		{"explicit-critical", 97, config.Threshold{Warning: 80, Critical: 97}, "critical"},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got := statusFor(tt.current, tt.threshold)
 			if got != tt.want {
-				t.Errorf("statusFor(%f, %f) = %q, want %q", tt.current, tt.threshold, got, tt.want)
+				t.Errorf("statusFor(%f, %+v) = %q, want %q", tt.current, tt.threshold, got, tt.want)
 			}
 		})
 	}
 }
+
+func TestRelevel(t *testing.T) {
+	r := &AlertResult{
+		CPU:    AlertItem{Status: "ok", Current: 85, Threshold: 95, Warning: 85.5},
+		Memory: AlertItem{Status: "ok", Current: 50, Threshold: 85},
+		Disks: []DiskAlert{
+			{Mount: "/", Status: "warning", Current: 85, Threshold: 90},
+			{Mount: "/mnt/backup", Status: "critical", Current: 96, Threshold: 90},
+		},
+	}
+	r.Relevel(&config.AlertLevels{
+		CPU:    &config.Threshold{Warning: 70, Critical: 90},
+		Disk:   &config.Threshold{Critical: 80},
+		Mounts: map[string]config.Threshold{"/mnt/backup": {Critical: 97}},
+	})
+	if r.CPU.Status != "warning" || r.CPU.Threshold != 90 || r.CPU.Warning != 70 {
+		t.Errorf("unexpected cpu: %+v", r.CPU)
+	}
+	if r.Memory.Threshold != 85 {
+		t.Errorf("memory has no override and should keep its level: %+v", r.Memory)
+	}
+	if r.Disks[0].Status != "critical" || r.Disks[1].Status != "warning" || r.Disks[1].Threshold != 97 {
+		t.Errorf("unexpected disks: %+v", r.Disks)
+	}
+	if r.Status != "critical" {
+		t.Errorf("expected overall critical, got %q", r.Status)
+	}
+}
+
+func TestCheckTemperatures(t *testing.T) {
+	sensors := &system.SensorsInfo{
+		Temperatures: []system.TempSensor{
+			{Chip: "cpu_thermal", Label: "temp1", Celsius: 82.5},
+			{Chip: "nvme", Label: "Composite", Celsius: 41},
+		},
+	}
+	got := checkTemperatures(sensors, config.Threshold{Critical: 80})
+	if len(got) != 2 {
+		t.Fatalf("expected 2 temperature alerts, got %d", len(got))
+	}
+	if got[0].Sensor != "cpu_thermal/temp1" || got[0].Status != "critical" {
+		t.Errorf("unexpected first alert: %+v", got[0])
+	}
+	if got[1].Status != "ok" || got[1].Threshold != 80 {
+		t.Errorf("unexpected second alert: %+v", got[1])
+	}
+
+	// two NVMe drives report the same chip and label
+	sensors.Temperatures = []system.TempSensor{
+		{Chip: "nvme", Label: "Composite", Device: "nvme0", Celsius: 41},
+		{Chip: "nvme", Label: "Composite", Device: "nvme1", Celsius: 45},
+		{Chip: "coretemp", Label: "Core 0", Celsius: 50},
+		{Chip: "coretemp", Label: "Core 0", Celsius: 52},
+	}
+	var names []string
+	for _, a := range checkTemperatures(sensors, config.Threshold{Critical: 80}) {
+		names = append(names, a.Sensor)
+	}
+	want := []string{"nvme/Composite@nvme0", "nvme/Composite@nvme1", "coretemp/Core 0", "coretemp/Core 0#2"}
+	if !slices.Equal(names, want) {
+		t.Errorf("expected %q, got %q", want, names)
+	}
+}
+
+func TestCheckNetwork(t *testing.T) {
+	nets := []system.NetInfo{
+		{Interface: "eth0", State: "up", SpeedMbps: 1000, Utilization: 95, ErrorsPerSec: 0},
+		{Interface: "wlan0", State: "up", ErrorsPerSec: 12},
+		{Interface: "eth1", State: "down", SpeedMbps: 1000},
+	}
+	got := checkNetwork(nets, config.Threshold{Critical: 90}, config.Threshold{Critical: 10})
+	// eth0: utilization + errors, wlan0: errors only (no link speed), eth1 skipped
+	if len(got) != 3 {
+		t.Fatalf("expected 3 network alerts, got %d: %+v", len(got), got)
+	}
+	if got[0].Interface != "eth0" || got[0].Metric != "utilization" || got[0].Status != "critical" {
+		t.Errorf("unexpected eth0 utilization alert: %+v", got[0])
+	}
+	if got[1].Metric != "errors" || got[1].Status != "ok" {
+		t.Errorf("unexpected eth0 errors alert: %+v", got[1])
+	}
+	if got[2].Interface != "wlan0" || got[2].Status != "critical" || got[2].Current != 12 {
+		t.Errorf("unexpected wlan0 alert: %+v", got[2])
+	}
+
+	if got := checkNetwork(nets, config.Threshold{Critical: 0}, config.Threshold{Critical: 0}); len(got) != 0 {
+		t.Errorf("zero thresholds should disable network alerts, got %+v", got)
+	}
+}
+
+func TestCheckInodes(t *testing.T) {
+	disks := []system.DiskInfo{
+		{Mount: "/", InodesTotal: 1000, InodesUsed: 950, InodePercent: 95},
+		{Mount: "/mnt/btrfs", Percent: 40},
+		{Mount: "/var/lib/docker", InodesTotal: 1000, InodesUsed: 100, InodePercent: 10},
+	}
+	got := checkInodes(disks, config.Threshold{Critical: 90})
+	if len(got) != 2 {
+		t.Fatalf("expected 2 inode alerts (btrfs skipped), got %d: %+v", len(got), got)
+	}
+	if got[0].Mount != "/" || got[0].Status != "critical" {
+		t.Errorf("unexpected root alert: %+v", got[0])
+	}
+	if got[1].Status != "ok" || got[1].Threshold != 90 {
+		t.Errorf("unexpected docker alert: %+v", got[1])
+	}
+	if got := checkInodes(disks, config.Threshold{Critical: 0}); got != nil {
+		t.Errorf("zero threshold should disable inode alerts, got %+v", got)
+	}
+}
+
+func TestAddDockerHint(t *testing.T) {
+	disks := []DiskAlert{
+		{Mount: "/", Status: "critical"},
+		{Mount: "/var", Status: "critical"},
+		{Mount: "/home", Status: "warning"},
+	}
+	addDockerHint(disks, "/var/lib/docker")
+	if disks[0].Hint != "" || disks[2].Hint != "" {
+		t.Errorf("only the disk holding docker data should get a hint: %+v", disks)
+	}
+	if !strings.Contains(disks[1].Hint, "/var/lib/docker") || !strings.Contains(disks[1].Hint, "docker prune") {
+		t.Errorf("unexpected hint: %q", disks[1].Hint)
+	}
+
+	ok := []DiskAlert{{Mount: "/", Status: "ok"}}
+	addDockerHint(ok, "/var/lib/docker")
+	if ok[0].Hint != "" {
+		t.Errorf("healthy disks need no hint, got %q", ok[0].Hint)
+	}
+}
+
+func TestCheckSwap(t *testing.T) {
+	if got := checkSwap(system.SwapInfo{}, config.Threshold{Critical: 80}); got != nil {
+		t.Errorf("no swap configured should skip the check, got %+v", got)
+	}
+	swap := system.SwapInfo{TotalBytes: 1 << 30, Percent: 75}
+	if got := checkSwap(swap, config.Threshold{Critical: 80}); got == nil || got.Status != "warning" || got.Current != 75 {
+		t.Errorf("unexpected swap alert: %+v", got)
+	}
+	if got := checkSwap(swap, config.Threshold{Critical: 0}); got != nil {
+		t.Errorf("zero threshold should disable swap alerts, got %+v", got)
+	}
+}
+
+func TestCheckMemoryPressure(t *testing.T) {
+	if got := checkMemoryPressure(nil, config.Threshold{Critical: 20}); got != nil {
+		t.Errorf("kernel without PSI should skip the check, got %+v", got)
+	}
+	p := &system.PressureInfo{Memory: &system.PSI{Some: system.PSIAvg{Avg10: 40, Avg60: 25}}}
+	got := checkMemoryPressure(p, config.Threshold{Critical: 20})
+	if got == nil || got.Status != "critical" || got.Current != 25 {
+		t.Errorf("unexpected pressure alert: %+v", got)
+	}
+	if got := checkMemoryPressure(&system.PressureInfo{}, config.Threshold{Critical: 20}); got != nil {
+		t.Errorf("missing memory PSI should skip the check, got %+v", got)
+	}
+}
+
+func TestCheckContainers(t *testing.T) {
+	containers := []docker.Container{
+		{Name: "jellyfin", State: "running", Health: "healthy"},
+		{Name: "postgres", State: "running", Health: "unhealthy"},
+		{Name: "backup", State: "exited"},
+		{Name: "grafana", State: "paused"},
+	}
+	exits := map[string]int{"jellyfin": 3, "backup": 1}
+	cfg := &config.ContainerAlertConfig{
+		Running:       []string{"jellyfin", "backup", "grafana", "ghost"},
+		Unhealthy:     true,
+		Restarts:      3,
+		RestartWindow: 10 * time.Minute,
+	}
+
+	got := checkContainers(containers, exits, cfg)
+	want := []struct{ container, rule, status, message string }{
+		{"jellyfin", "running", "ok", "jellyfin is running"},
+		{"backup", "running", "critical", "backup is exited"},
+		{"grafana", "running", "warning", "grafana is paused"},
+		{"ghost", "running", "critical", "ghost not found"},
+		{"postgres", "health", "critical", "postgres is unhealthy"},
+		{"jellyfin", "restarts", "critical", "jellyfin exited 3 times in 10 minutes"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d alerts, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.Container != w.container || g.Rule != w.rule || g.Status != w.status || g.Message != w.message {
+			t.Errorf("alert %d = %+v, want %+v", i, g, w)
+		}
+	}
+	if got[5].Current != 3 || got[5].Threshold != 3 {
+		t.Errorf("restart alert should carry the counts: %+v", got[5])
+	}
+
+	// Rules off: nothing to report
+	if got := checkContainers(containers, exits, &config.ContainerAlertConfig{}); len(got) != 0 {
+		t.Errorf("expected no alerts with rules disabled, got %+v", got)
+	}
+}
+
+func TestFormatWindow(t *testing.T) {
+	tests := map[time.Duration]string{
+		10 * time.Minute: "10 minutes",
+		time.Minute:      "1 minute",
+		2 * time.Hour:    "2 hours",
+		90 * time.Minute: "90 minutes",
+		90 * time.Second: "1m30s",
+	}
+	for d, want := range tests {
+		if got := formatWindow(d); got != want {
+			t.Errorf("formatWindow(%s) = %q, want %q", d, got, want)
+		}
+	}
+}
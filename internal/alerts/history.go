@@ -0,0 +1,259 @@
+package alerts
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Higangssh/homebutler/internal/config"
+	"github.com/Higangssh/homebutler/internal/util"
+)
+
+// HistoryEntry is one evaluation of a metric that wasn't ok.
+type HistoryEntry struct {
+	Time      time.Time `json:"time"`
+	Server    string    `json:"server"`
+	Metric    string    `json:"metric"` // Metric key, e.g. "disk:/mnt/data"
+	Label     string    `json:"label"`
+	Status    string    `json:"status"`
+	Value     float64   `json:"value"`
+	Threshold float64   `json:"threshold"`
+	Unit      string    `json:"unit,omitempty"`
+	Message   string    `json:"message,omitempty"`
+	Silenced  bool      `json:"silenced,omitempty"`
+}
+
+// DefaultHistoryPath is history.jsonl next to the alert state.
+func DefaultHistoryPath() string {
+	return filepath.Join(filepath.Dir(DefaultStatePath()), "history.jsonl")
+}
+
+// Evaluate checks the alerts of a server, this machine when srv is nil
+// or local, and records the metrics that aren't ok in the alert history.
+// Every alert check goes through here so the history sees them all; a
+// failure to record is only a warning, the check itself went fine.
+func Evaluate(cfg *config.Config, srv *config.ServerConfig) (*AlertResult, error) {
+	var result *AlertResult
+	var err error
+	name := cfg.LocalName()
+	switch {
+	case srv == nil:
+		result, err = Check(cfg.LocalAlerts())
+	case srv.Local:
+		name = srv.Name
+		result, err = Check(cfg.AlertsFor(srv))
+	default:
+		name = srv.Name
+		result, err = Remote(srv)
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = RecordHistory(DefaultHistoryPath(), name, result.Metrics(), time.Now(), cfg.Alerts.History.Retention)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	}
+	return result, nil
+}
+
+// RecordHistory appends the metrics of one evaluation that aren't ok, one
+// JSON object per line. Entries older than retention are dropped, a day
+// at a time so the file isn't rewritten on every record. A retention of 0
+// records nothing.
+func RecordHistory(path, server string, metrics []Metric, now time.Time, retention time.Duration) error {
+	if retention <= 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, m := range metrics {
+		if m.Status == "ok" {
+			continue
+		}
+		enc.Encode(HistoryEntry{
+			Time:      now,
+			Server:    server,
+			Metric:    m.Key,
+			Label:     m.Label,
+			Status:    m.Status,
+			Value:     m.Current,
+			Threshold: m.Threshold,
+			Unit:      m.Unit,
+			Message:   m.Message,
+			Silenced:  m.Silenced,
+		})
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to record alert history: %w", err)
+	}
+	// several checks may record at once; a prune must not drop
+	// the entries another one appends meanwhile
+	unlock, err := util.LockFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to record alert history: %w", err)
+	}
+	defer unlock()
+	if err := pruneHistory(path, now.Add(-retention)); err != nil {
+		return fmt.Errorf("failed to prune alert history: %w", err)
+	}
+	if buf.Len() == 0 {
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to record alert history: %w", err)
+	}
+	data := buf.Bytes()
+	// start on a new line after one a crash cut off
+	if fi, err := f.Stat(); err == nil && fi.Size() > 0 {
+		last := make([]byte, 1)
+		if _, err := f.ReadAt(last, fi.Size()-1); err == nil && last[0] != '\n' {
+			data = append([]byte{'\n'}, data...)
+		}
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to record alert history: %w", err)
+	}
+	return f.Close()
+}
+
+// pruneHistory rewrites the file without the entries before cutoff, once
+// the oldest one is a day past it. The caller holds the lock.
+func pruneHistory(path string, cutoff time.Time) error {
+	oldest, err := readHistory(path, func(e HistoryEntry) bool { return false })
+	if err != nil || oldest.IsZero() || !oldest.Before(cutoff.Add(-24*time.Hour)) {
+		return err
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	if _, err := readHistory(path, func(e HistoryEntry) bool {
+		if !e.Time.Before(cutoff) {
+			enc.Encode(e)
+		}
+		return true
+	}); err != nil {
+		return err
+	}
+	return util.WriteFileAtomic(path, buf.Bytes(), 0600)
+}
+
+// readHistory calls fn for each entry, oldest first, until it returns
+// false, and returns the time of the first one. A missing file has no
+// entries; lines that don't parse (cut off by a crash) are skipped.
+func readHistory(path string, fn func(HistoryEntry) bool) (time.Time, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read alert history: %w", err)
+	}
+	defer f.Close()
+	var first time.Time
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		var e HistoryEntry
+		if len(line) > 0 && json.Unmarshal(line, &e) == nil {
+			if first.IsZero() {
+				first = e.Time
+			}
+			if !fn(e) {
+				return first, nil
+			}
+		}
+		if err == io.EOF {
+			return first, nil
+		}
+		if err != nil {
+			return first, fmt.Errorf("failed to read alert history: %w", err)
+		}
+	}
+}
+
+// HistoryQuery selects history entries.
+type HistoryQuery struct {
+	Since  time.Time
+	Server string // empty is every server
+	Metric string // kind ("disk") or key ("disk:/mnt/data"); empty is all
+}
+
+// Matches reports whether the query selects an entry.
+func (q HistoryQuery) Matches(e HistoryEntry) bool {
+	return !e.Time.Before(q.Since) && (q.Server == "" || e.Server == q.Server) && (q.Metric == "" || matchesMetric(q.Metric, e.Metric))
+}
+
+// History is the answer to a HistoryQuery: the entries, oldest first, and
+// a summary per server and metric.
+type History struct {
+	Since   time.Time        `json:"since"`
+	Entries []HistoryEntry   `json:"entries"`
+	Summary []HistorySummary `json:"summary"`
+}
+
+// HistorySummary counts the warning and critical evaluations of one metric.
+type HistorySummary struct {
+	Server   string    `json:"server"`
+	Metric   string    `json:"metric"`
+	Label    string    `json:"label"`
+	Warning  int       `json:"warning"`
+	Critical int       `json:"critical"`
+	Peak     float64   `json:"peak"`
+	Unit     string    `json:"unit,omitempty"`
+	First    time.Time `json:"first"`
+	Last     time.Time `json:"last"`
+}
+
+// QueryHistory reads the entries q selects and summarizes them.
+func QueryHistory(path string, q HistoryQuery) (*History, error) {
+	entries := []HistoryEntry{}
+	_, err := readHistory(path, func(e HistoryEntry) bool {
+		if q.Matches(e) {
+			entries = append(entries, e)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &History{Since: q.Since, Entries: entries, Summary: Summarize(entries)}, nil
+}
+
+// Summarize counts entries, oldest first, per server and metric.
+func Summarize(entries []HistoryEntry) []HistorySummary {
+	summary := []HistorySummary{}
+	index := map[[2]string]int{}
+	for _, e := range entries {
+		k := [2]string{e.Server, e.Metric}
+		i, ok := index[k]
+		if !ok {
+			i = len(summary)
+			index[k] = i
+			summary = append(summary, HistorySummary{Server: e.Server, Metric: e.Metric, Label: e.Label, Unit: e.Unit, First: e.Time})
+		}
+		s := &summary[i]
+		if e.Status == "critical" {
+			s.Critical++
+		} else {
+			s.Warning++
+		}
+		s.Peak = max(s.Peak, e.Value)
+		s.Last = e.Time
+	}
+	slices.SortStableFunc(summary, func(a, b HistorySummary) int {
+		if c := strings.Compare(a.Server, b.Server); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Metric, b.Metric)
+	})
+	return summary
+}
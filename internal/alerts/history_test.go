@@ -0,0 +1,149 @@
+package alerts
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Higangssh/homebutler/internal/config"
+)
+
+func TestRecordQueryHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	now := time.Date(2026, 3, 8, 12, 0, 0, 0, time.UTC)
+	month := 30 * 24 * time.Hour
+
+	disk := func(status string, current float64) []Metric {
+		return []Metric{
+			{Key: "cpu", Label: "CPU", Status: "ok", Current: 20, Threshold: 90, Unit: "%"},
+			{Key: "disk:/mnt/data", Label: "Disk /mnt/data", Status: status, Current: current, Threshold: 90, Unit: "%"},
+		}
+	}
+	records := []struct {
+		server  string
+		metrics []Metric
+		at      time.Time
+	}{
+		{"nas", disk("critical", 95), now.Add(-10 * 24 * time.Hour)}, // before --since
+		{"nas", disk("warning", 85), now.Add(-3 * time.Hour)},
+		{"nas", disk("critical", 97), now.Add(-2 * time.Hour)},
+		{"nas", disk("ok", 50), now.Add(-time.Hour)},
+		{"rpi", disk("critical", 92), now},
+	}
+	for _, r := range records {
+		if err := RecordHistory(path, r.server, r.metrics, r.at, month); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	h, err := QueryHistory(path, HistoryQuery{Since: now.Add(-7 * 24 * time.Hour), Server: "nas", Metric: "disk"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(h.Entries) != 2 || h.Entries[0].Status != "warning" || h.Entries[1].Value != 97 {
+		t.Fatalf("unexpected entries: %+v", h.Entries)
+	}
+	want := HistorySummary{Server: "nas", Metric: "disk:/mnt/data", Label: "Disk /mnt/data", Warning: 1, Critical: 1, Peak: 97, Unit: "%", First: now.Add(-3 * time.Hour), Last: now.Add(-2 * time.Hour)}
+	if len(h.Summary) != 1 || h.Summary[0] != want {
+		t.Errorf("unexpected summary: %+v", h.Summary)
+	}
+
+	all, _ := QueryHistory(path, HistoryQuery{})
+	if len(all.Entries) != 4 || len(all.Summary) != 2 || all.Summary[1].Server != "rpi" {
+		t.Errorf("unexpected unfiltered history: %+v", all.Summary)
+	}
+	if none, _ := QueryHistory(path, HistoryQuery{Metric: "cpu"}); len(none.Entries) != 0 || none.Summary == nil {
+		t.Errorf("ok evaluations should not be recorded: %+v", none)
+	}
+}
+
+func TestHistoryRetention(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	now := time.Date(2026, 3, 8, 12, 0, 0, 0, time.UTC)
+	critical := []Metric{{Key: "cpu", Label: "CPU", Status: "critical", Current: 99, Threshold: 90}}
+
+	RecordHistory(path, "nas", critical, now.Add(-72*time.Hour), 24*time.Hour)
+	RecordHistory(path, "nas", critical, now.Add(-12*time.Hour), 24*time.Hour)
+	// a cut-off line from a crash is skipped
+	f, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	f.WriteString(`{"time":"2026-03-08T`)
+	f.Close()
+	if err := RecordHistory(path, "nas", critical, now, 24*time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	h, err := QueryHistory(path, HistoryQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(h.Entries) != 2 || !h.Entries[0].Time.Equal(now.Add(-12*time.Hour)) {
+		t.Errorf("expected the entry past retention pruned, got %+v", h.Entries)
+	}
+	if err := RecordHistory(path, "nas", critical, now, 0); err != nil {
+		t.Fatal(err)
+	}
+	if h, _ := QueryHistory(path, HistoryQuery{}); len(h.Entries) != 2 {
+		t.Errorf("retention 0 should record nothing, got %d entries", len(h.Entries))
+	}
+}
+
+func TestRecordHistoryConcurrently(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	now := time.Date(2026, 3, 8, 12, 0, 0, 0, time.UTC)
+	critical := []Metric{{Key: "cpu", Label: "CPU", Status: "critical", Current: 99, Threshold: 90}}
+
+	// the first record prunes this; the others must not lose theirs to it
+	RecordHistory(path, "old", critical, now.Add(-72*time.Hour), 24*time.Hour)
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Go(func() {
+			if err := RecordHistory(path, "nas", critical, now, 24*time.Hour); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+	wg.Wait()
+	if h, _ := QueryHistory(path, HistoryQuery{}); len(h.Entries) != 20 {
+		t.Errorf("expected 20 entries, got %d", len(h.Entries))
+	}
+}
+
+func TestEvaluateRecordsHistory(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	cfg := &config.Config{
+		Servers: []config.ServerConfig{{Name: "box", Local: true}},
+		Alerts: config.AlertConfig{
+			CPU:     config.Threshold{Critical: 1000},
+			Memory:  config.Threshold{Critical: 1000},
+			Disk:    config.Threshold{Critical: 0.001},
+			History: config.HistoryConfig{Retention: time.Hour},
+		},
+	}
+	result, err := Evaluate(cfg, nil)
+	if err != nil {
+		t.Skipf("no local status here: %v", err)
+	}
+	full := 0
+	for _, d := range result.Disks {
+		if d.Status != "ok" {
+			full++
+		}
+	}
+	if full == 0 {
+		t.Skip("no used disks here")
+	}
+	h, err := QueryHistory(DefaultHistoryPath(), HistoryQuery{Since: time.Now().Add(-time.Minute), Metric: "disk"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(h.Entries) != full {
+		t.Fatalf("expected every disk over its level recorded, got %+v", h.Entries)
+	}
+	for _, e := range h.Entries {
+		if e.Server != "box" || e.Status != "critical" {
+			t.Errorf("unexpected entry: %+v", e)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package alerts
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Metric is one checked value of an AlertResult, keyed so that two
+// evaluations can be compared: "cpu", "disk:/mnt/data",
+// "container:restarts:plex". The part before the first colon names the
+// kind of metric.
+type Metric struct {
+	Key       string  `json:"key"`
+	Label     string  `json:"label"` // "Disk /mnt/data"
+	Status    string  `json:"status"`
+	Current   float64 `json:"current"`
+	Threshold float64 `json:"threshold"`
+	Warning   float64 `json:"warning,omitempty"`
+	Unit      string  `json:"unit,omitempty"`    // "%", "°C", "/s"
+	Message   string  `json:"message,omitempty"` // container alerts describe themselves
+	Silenced  bool    `json:"silenced,omitempty"`
+}
+
+// Metrics flattens the result in a stable order.
+func (r *AlertResult) Metrics() []Metric {
+	metrics := []Metric{
+		{Key: "cpu", Label: "CPU", Status: r.CPU.Status, Current: r.CPU.Current, Threshold: r.CPU.Threshold, Warning: r.CPU.Warning, Unit: "%", Silenced: r.CPU.Silenced},
+		{Key: "memory", Label: "Memory", Status: r.Memory.Status, Current: r.Memory.Current, Threshold: r.Memory.Threshold, Warning: r.Memory.Warning, Unit: "%", Silenced: r.Memory.Silenced},
+	}
+	if s := r.Swap; s != nil {
+		metrics = append(metrics, Metric{Key: "swap", Label: "Swap", Status: s.Status, Current: s.Current, Threshold: s.Threshold, Warning: s.Warning, Unit: "%", Silenced: s.Silenced})
+	}
+	if p := r.MemoryPressure; p != nil {
+		metrics = append(metrics, Metric{Key: "memory_pressure", Label: "Memory pressure", Status: p.Status, Current: p.Current, Threshold: p.Threshold, Warning: p.Warning, Unit: "%", Silenced: p.Silenced})
+	}
+	for _, d := range r.Disks {
+		metrics = append(metrics, Metric{Key: "disk:" + d.Mount, Label: "Disk " + d.Mount, Status: d.Status, Current: d.Current, Threshold: d.Threshold, Warning: d.Warning, Unit: "%", Silenced: d.Silenced})
+	}
+	for _, d := range r.Inodes {
+		metrics = append(metrics, Metric{Key: "inodes:" + d.Mount, Label: "Inodes " + d.Mount, Status: d.Status, Current: d.Current, Threshold: d.Threshold, Warning: d.Warning, Unit: "%", Silenced: d.Silenced})
+	}
+	for _, t := range r.Temperatures {
+		metrics = append(metrics, Metric{Key: "temperature:" + t.Sensor, Label: "Temp " + t.Sensor, Status: t.Status, Current: t.Current, Threshold: t.Threshold, Warning: t.Warning, Unit: "°C", Silenced: t.Silenced})
+	}
+	for _, n := range r.Network {
+		m := Metric{Key: "network:" + n.Interface, Label: "Net " + n.Interface, Status: n.Status, Current: n.Current, Threshold: n.Threshold, Warning: n.Warning, Unit: "%", Silenced: n.Silenced}
+		if n.Metric == "errors" {
+			m.Key, m.Label, m.Unit = "net_errors:"+n.Interface, "Net "+n.Interface+" errors", "/s"
+		}
+		metrics = append(metrics, m)
+	}
+	for _, c := range r.Containers {
+		metrics = append(metrics, Metric{
+			Key:       "container:" + c.Rule + ":" + c.Container,
+			Label:     "Container " + c.Container,
+			Status:    c.Status,
+			Current:   c.Current,
+			Threshold: c.Threshold,
+			Message:   c.Message,
+			Silenced:  c.Silenced,
+		})
+	}
+	return metrics
+}
+
+// Kind is the part of the key before the first colon: "cpu", "disk",
+// "container".
+func (m Metric) Kind() string {
+	kind, _, _ := strings.Cut(m.Key, ":")
+	return kind
+}
+
+// Describe says what the metric is at: "Disk /mnt/data at 97% (threshold
+// 90%)", or the container alert's own message.
+func (m Metric) Describe() string {
+	if m.Message != "" {
+		return m.Message
+	}
+	return fmt.Sprintf("%s at %s%s (threshold %s%s)", m.Label, formatValue(m.Current), m.Unit, formatValue(m.Threshold), m.Unit)
+}
+
+// Transition is a metric whose status changed between two evaluations.
+type Transition struct {
+	Metric
+	From string `json:"from"`
+}
+
+// Transitions compares the current metrics with the previous ones by key.
+// A metric seen for the first time counts as coming from "ok"; one that is
+// gone (a container alert that stopped firing) recovers to "ok", its
+// message prefixed with "resolved: ".
+func Transitions(prev map[string]Metric, cur []Metric) []Transition {
+	var changes []Transition
+	seen := make(map[string]Metric, len(cur))
+	for _, m := range cur {
+		seen[m.Key] = m
+		from := "ok"
+		if p, ok := prev[m.Key]; ok {
+			from = p.Status
+		}
+		if m.Status != from {
+			changes = append(changes, Transition{Metric: m, From: from})
+		}
+	}
+	return append(changes, vanished(prev, seen)...)
+}
+
+// vanished recovers the metrics of prev that are no longer in cur.
+func vanished(prev, cur map[string]Metric) []Transition {
+	var changes []Transition
+	for _, key := range slices.Sorted(maps.Keys(prev)) {
+		if _, ok := cur[key]; ok {
+			continue
+		}
+		if p := prev[key]; p.Status != "ok" {
+			recovered := p
+			recovered.Status, recovered.Current = "ok", 0
+			if p.Message != "" {
+				recovered.Message = "resolved: " + p.Message
+			}
+			changes = append(changes, Transition{Metric: recovered, From: p.Status})
+		}
+	}
+	return changes
+}
+
+// formatValue rounds to one decimal and drops a trailing ".0": 97 → "97",
+// 72.54 → "72.5".
+func formatValue(v float64) string {
+	return strconv.FormatFloat(math.Round(v*10)/10, 'f', -1, 64)
+}
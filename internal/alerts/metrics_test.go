@@ -0,0 +1,68 @@
+package alerts
+
+import (
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	r := &AlertResult{
+		CPU:    AlertItem{Status: "ok", Current: 12, Threshold: 90},
+		Memory: AlertItem{Status: "warning", Current: 78.26, Threshold: 85},
+		Disks:  []DiskAlert{{Mount: "/mnt/data", Status: "critical", Current: 97, Threshold: 90}},
+		Network: []NetAlert{
+			{Interface: "eth0", Metric: "utilization", Status: "ok", Current: 4, Threshold: 90},
+			{Interface: "eth0", Metric: "errors", Status: "ok", Current: 0, Threshold: 10},
+		},
+		Containers: []ContainerAlert{{Container: "plex", Rule: "restarts", Status: "critical", Message: "plex exited 3 times in 10 minutes", Current: 3, Threshold: 3}},
+	}
+	metrics := r.Metrics()
+	keys := []string{"cpu", "memory", "disk:/mnt/data", "network:eth0", "net_errors:eth0", "container:restarts:plex"}
+	if len(metrics) != len(keys) {
+		t.Fatalf("got %d metrics, want %d: %+v", len(metrics), len(keys), metrics)
+	}
+	for i, k := range keys {
+		if metrics[i].Key != k {
+			t.Errorf("metric %d key = %q, want %q", i, metrics[i].Key, k)
+		}
+	}
+	if got := metrics[1].Describe(); got != "Memory at 78.3% (threshold 85%)" {
+		t.Errorf("Describe() = %q", got)
+	}
+	if got := metrics[4].Describe(); got != "Net eth0 errors at 0/s (threshold 10/s)" {
+		t.Errorf("Describe() = %q", got)
+	}
+	if got := metrics[5].Describe(); got != "plex exited 3 times in 10 minutes" {
+		t.Errorf("container Describe() = %q", got)
+	}
+}
+
+func TestTransitions(t *testing.T) {
+	prev := map[string]Metric{
+		"cpu":                     {Key: "cpu", Status: "ok"},
+		"disk:/":                  {Key: "disk:/", Status: "critical"},
+		"container:restarts:plex": {Key: "container:restarts:plex", Label: "Container plex", Status: "critical", Message: "plex exited 3 times"},
+	}
+	cur := []Metric{
+		{Key: "cpu", Status: "warning"},
+		{Key: "disk:/", Status: "critical"},
+		{Key: "memory", Status: "ok"},
+		{Key: "container:health:db", Status: "critical"},
+	}
+	got := Transitions(prev, cur)
+	want := []struct{ key, from, to string }{
+		{"cpu", "ok", "warning"},
+		{"container:health:db", "ok", "critical"},
+		{"container:restarts:plex", "critical", "ok"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d transitions, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		if got[i].Key != w.key || got[i].From != w.from || got[i].Status != w.to {
+			t.Errorf("transition %d = %s %s→%s, want %s %s→%s", i, got[i].Key, got[i].From, got[i].Status, w.key, w.from, w.to)
+		}
+	}
+	if got[2].Label != "Container plex" || got[2].Describe() != "resolved: plex exited 3 times" {
+		t.Errorf("a recovered metric should keep its label, got %+v", got[2])
+	}
+}
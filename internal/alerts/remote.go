@@ -0,0 +1,76 @@
+package alerts
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Higangssh/homebutler/internal/config"
+	"github.com/Higangssh/homebutler/internal/remote"
+)
+
+// Remote checks a remote server's alerts with its own config, then applies
+// the levels its server entry here overrides.
+func Remote(srv *config.ServerConfig) (*AlertResult, error) {
+	out, err := remote.Run(srv, "alerts", "--json")
+	if err != nil {
+		return nil, err
+	}
+	var result AlertResult
+	if err := json.Unmarshal(out, &result); err != nil {
+		return nil, fmt.Errorf("invalid alerts output: %w", err)
+	}
+	if srv.Alerts != nil {
+		result.Relevel(srv.Alerts)
+	}
+	return &result, nil
+}
+
+// Relevel re-evaluates the values of a result against the levels l sets.
+// The others keep the status they were checked with.
+func (r *AlertResult) Relevel(l *config.AlertLevels) {
+	relevelItem(&r.CPU, l.CPU)
+	relevelItem(&r.Memory, l.Memory)
+	if r.Swap != nil {
+		relevelItem(r.Swap, l.Swap)
+	}
+	if r.MemoryPressure != nil {
+		relevelItem(r.MemoryPressure, l.MemoryPressure)
+	}
+	for i := range r.Disks {
+		d := &r.Disks[i]
+		t := l.Disk
+		if m, ok := l.Mounts[d.Mount]; ok {
+			t = &m
+		}
+		if t != nil {
+			d.Status, d.Warning, d.Threshold = evaluate(d.Current, *t)
+		}
+	}
+	for i := range r.Inodes {
+		if d := &r.Inodes[i]; l.Inodes != nil {
+			d.Status, d.Warning, d.Threshold = evaluate(d.Current, *l.Inodes)
+		}
+	}
+	for i := range r.Temperatures {
+		if t := &r.Temperatures[i]; l.Temperature != nil {
+			t.Status, t.Warning, t.Threshold = evaluate(t.Current, *l.Temperature)
+		}
+	}
+	for i := range r.Network {
+		n := &r.Network[i]
+		t := l.Network
+		if n.Metric == "errors" {
+			t = l.NetErrors
+		}
+		if t != nil {
+			n.Status, n.Warning, n.Threshold = evaluate(n.Current, *t)
+		}
+	}
+	r.updateStatus()
+}
+
+func relevelItem(a *AlertItem, t *config.Threshold) {
+	if t != nil {
+		a.Status, a.Warning, a.Threshold = evaluate(a.Current, *t)
+	}
+}
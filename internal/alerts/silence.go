@@ -0,0 +1,215 @@
+package alerts
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Higangssh/homebutler/internal/config"
+)
+
+// Silence mutes matching alerts until End: they still show in the
+// AlertResult, marked silenced, but don't count toward its status or get
+// notified. Silences are kept per machine, like the alerts they mute;
+// open maintenance windows show up as silences too.
+type Silence struct {
+	ID      string    `json:"id"`
+	Metrics []string  `json:"metrics,omitempty"` // kinds ("disk") or keys ("disk:/mnt/data"); empty matches all
+	Reason  string    `json:"reason,omitempty"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Window  string    `json:"window,omitempty"` // maintenance window name
+}
+
+// Matches reports whether the silence covers a metric key. "disk" covers
+// every disk, "container:restarts" every restart loop.
+func (s Silence) Matches(key string) bool {
+	if len(s.Metrics) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(s.Metrics, func(m string) bool { return matchesMetric(m, key) })
+}
+
+// matchesMetric reports whether a kind or key pattern covers a metric key.
+func matchesMetric(pattern, key string) bool {
+	return key == pattern || strings.HasPrefix(key, pattern+":")
+}
+
+// DefaultSilencePath is silences.json next to the alert state.
+func DefaultSilencePath() string {
+	return filepath.Join(filepath.Dir(DefaultStatePath()), "silences.json")
+}
+
+// LoadSilences reads the silences file. A missing file has none.
+func LoadSilences(path string) ([]Silence, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read silences: %w", err)
+	}
+	var silences []Silence
+	if err := json.Unmarshal(data, &silences); err != nil {
+		return nil, fmt.Errorf("failed to parse silences %s: %w", path, err)
+	}
+	return silences, nil
+}
+
+// AddSilence silences metrics from now for d, and drops expired silences.
+func AddSilence(path string, metrics []string, reason string, d time.Duration, now time.Time) (*Silence, error) {
+	if d <= 0 {
+		return nil, fmt.Errorf("silence duration must be positive")
+	}
+	silences, err := LoadSilences(path)
+	if err != nil {
+		return nil, err
+	}
+	id := make([]byte, 4)
+	rand.Read(id)
+	s := Silence{ID: hex.EncodeToString(id), Metrics: metrics, Reason: reason, Start: now, End: now.Add(d)}
+	silences = append(active(silences, now), s)
+	if err := writeJSON(path, silences); err != nil {
+		return nil, fmt.Errorf("failed to save silences: %w", err)
+	}
+	return &s, nil
+}
+
+// ExpireSilence ends a silence now.
+func ExpireSilence(path, id string, now time.Time) (*Silence, error) {
+	if strings.HasPrefix(id, "maintenance:") {
+		return nil, fmt.Errorf("%s is a maintenance window; change alerts.maintenance in the config instead", id)
+	}
+	silences, err := LoadSilences(path)
+	if err != nil {
+		return nil, err
+	}
+	silences = active(silences, now)
+	i := slices.IndexFunc(silences, func(s Silence) bool { return s.ID == id })
+	if i < 0 {
+		return nil, fmt.Errorf("no active silence %q", id)
+	}
+	expired := silences[i]
+	expired.End = now
+	if err := writeJSON(path, slices.Delete(silences, i, i+1)); err != nil {
+		return nil, fmt.Errorf("failed to save silences: %w", err)
+	}
+	return &expired, nil
+}
+
+// ActiveSilences returns the silences in effect at now: the stored ones
+// and the open maintenance windows. When the file can't be read or a
+// window is invalid, the error comes with the silences that could be read.
+func ActiveSilences(path string, windows []config.MaintenanceWindow, now time.Time) ([]Silence, error) {
+	silences, loadErr := LoadSilences(path)
+	open, err := maintenanceSilences(windows, now)
+	all := append(active(silences, now), open...)
+	if all == nil {
+		all = []Silence{}
+	}
+	return all, errors.Join(loadErr, err)
+}
+
+// ValidateSilenceID checks a silence ID before it is put on a remote
+// command line.
+func ValidateSilenceID(id string) error {
+	valid := id != "" && !strings.ContainsFunc(id, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune(":._-", r))
+	})
+	if !valid {
+		return fmt.Errorf("invalid silence id: %s", id)
+	}
+	return nil
+}
+
+func active(silences []Silence, now time.Time) []Silence {
+	return slices.DeleteFunc(silences, func(s Silence) bool { return !now.Before(s.End) })
+}
+
+// maintenanceSilences returns a silence for every window open at now.
+// Windows from a loaded config are parsed already; an invalid one is
+// skipped and reported.
+func maintenanceSilences(windows []config.MaintenanceWindow, now time.Time) ([]Silence, error) {
+	var open []Silence
+	var errs []error
+	for i, w := range windows {
+		if !w.Parsed() {
+			if err := w.Parse(); err != nil {
+				errs = append(errs, fmt.Errorf("alerts.maintenance[%d] (%s): %w", i, w.Name, err))
+				continue
+			}
+		}
+		hour, minute := w.Clock()
+		// a window that started yesterday may still be open
+		for back := range 2 {
+			day := now.AddDate(0, 0, -back)
+			from := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, now.Location())
+			if w.OnDay(from.Weekday()) && !now.Before(from) && now.Before(from.Add(w.Duration)) {
+				open = append(open, Silence{
+					ID:      "maintenance:" + w.Name,
+					Metrics: w.Metrics,
+					Reason:  w.Reason,
+					Start:   from,
+					End:     from.Add(w.Duration),
+					Window:  w.Name,
+				})
+				break
+			}
+		}
+	}
+	return open, errors.Join(errs...)
+}
+
+// applySilences marks the alerts the silences cover and sets the overall
+// status from the rest.
+func (r *AlertResult) applySilences(silences []Silence) {
+	silenced := func(key string) bool {
+		return slices.ContainsFunc(silences, func(s Silence) bool { return s.Matches(key) })
+	}
+	r.CPU.Silenced = silenced("cpu")
+	r.Memory.Silenced = silenced("memory")
+	if r.Swap != nil {
+		r.Swap.Silenced = silenced("swap")
+	}
+	if r.MemoryPressure != nil {
+		r.MemoryPressure.Silenced = silenced("memory_pressure")
+	}
+	for i := range r.Disks {
+		r.Disks[i].Silenced = silenced("disk:" + r.Disks[i].Mount)
+	}
+	for i := range r.Inodes {
+		r.Inodes[i].Silenced = silenced("inodes:" + r.Inodes[i].Mount)
+	}
+	for i := range r.Temperatures {
+		r.Temperatures[i].Silenced = silenced("temperature:" + r.Temperatures[i].Sensor)
+	}
+	for i, n := range r.Network {
+		key := "network:" + n.Interface
+		if n.Metric == "errors" {
+			key = "net_errors:" + n.Interface
+		}
+		r.Network[i].Silenced = silenced(key)
+	}
+	for i, c := range r.Containers {
+		r.Containers[i].Silenced = silenced("container:" + c.Rule + ":" + c.Container)
+	}
+	r.updateStatus()
+}
+
+// updateStatus sets the overall status from the alerts that aren't
+// silenced.
+func (r *AlertResult) updateStatus() {
+	r.Status = "ok"
+	for _, m := range r.Metrics() {
+		if !m.Silenced && severity(m.Status) > severity(r.Status) {
+			r.Status = m.Status
+		}
+	}
+}
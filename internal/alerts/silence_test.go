@@ -0,0 +1,151 @@
+package alerts
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Higangssh/homebutler/internal/config"
+)
+
+func TestSilenceMatches(t *testing.T) {
+	tests := []struct {
+		metrics []string
+		key     string
+		want    bool
+	}{
+		{nil, "cpu", true},
+		{[]string{"disk"}, "disk:/mnt/data", true},
+		{[]string{"disk"}, "inodes:/mnt/data", false},
+		{[]string{"disk:/mnt/data"}, "disk:/mnt/data", true},
+		{[]string{"disk:/mnt"}, "disk:/mnt/data", false},
+		{[]string{"cpu", "container:restarts"}, "container:restarts:plex", true},
+		{[]string{"mem"}, "memory", false},
+	}
+	for _, tt := range tests {
+		if got := (Silence{Metrics: tt.metrics}).Matches(tt.key); got != tt.want {
+			t.Errorf("%v matches %s = %v, want %v", tt.metrics, tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestAddExpireSilence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "silences.json")
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	old, err := AddSilence(path, nil, "reboot", time.Hour, now.Add(-2*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := AddSilence(path, []string{"disk"}, "rebuilding array", 4*time.Hour, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.ID == "" || !s.End.Equal(now.Add(4*time.Hour)) {
+		t.Errorf("unexpected silence: %+v", s)
+	}
+	stored, _ := LoadSilences(path)
+	if len(stored) != 1 || stored[0].ID != s.ID {
+		t.Errorf("expired silence %s should have been dropped, got %+v", old.ID, stored)
+	}
+
+	expired, err := ExpireSilence(path, s.ID, now.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !expired.End.Equal(now.Add(time.Hour)) {
+		t.Errorf("expected end at expiry, got %v", expired.End)
+	}
+	if active, _ := ActiveSilences(path, nil, now.Add(time.Hour)); len(active) != 0 {
+		t.Errorf("expected no active silences, got %+v", active)
+	}
+	if _, err := ExpireSilence(path, s.ID, now); err == nil {
+		t.Error("expiring twice should fail")
+	}
+	if _, err := ExpireSilence(path, "maintenance:backups", now); err == nil || !strings.Contains(err.Error(), "alerts.maintenance") {
+		t.Errorf("maintenance windows can't be expired, got %v", err)
+	}
+}
+
+func TestMaintenanceSilences(t *testing.T) {
+	// Sunday 2026-03-01
+	windows := []config.MaintenanceWindow{
+		{Name: "backups", Days: []string{"sun"}, Start: "03:00", Duration: 2 * time.Hour, Metrics: []string{"disk"}},
+		{Name: "nightly", Start: "23:30", Duration: time.Hour},
+	}
+	at := func(h, m int) time.Time { return time.Date(2026, 3, 1, h, m, 0, 0, time.UTC) }
+
+	open, err := maintenanceSilences(windows, at(4, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(open) != 1 || open[0].ID != "maintenance:backups" || !open[0].End.Equal(at(5, 0)) {
+		t.Errorf("expected backups window, got %+v", open)
+	}
+	if open, _ := maintenanceSilences(windows, at(5, 0)); len(open) != 0 {
+		t.Errorf("window should be closed at its end, got %+v", open)
+	}
+	if open, _ := maintenanceSilences(windows, at(4, 0).AddDate(0, 0, 1)); len(open) != 0 {
+		t.Errorf("backups only runs on Sundays, got %+v", open)
+	}
+	// nightly started yesterday at 23:30 and is still open
+	if open, _ := maintenanceSilences(windows, at(0, 15)); len(open) != 1 || open[0].Window != "nightly" {
+		t.Errorf("expected the window from the day before, got %+v", open)
+	}
+
+	for _, w := range []config.MaintenanceWindow{
+		{Name: "x", Start: "25:00", Duration: time.Hour},
+		{Name: "x", Start: "03:00"},
+		{Name: "x", Start: "03:00", Duration: time.Hour, Days: []string{"someday"}},
+		{Name: "x", Start: "03:00", Duration: time.Hour, Days: []string{"monkey"}},
+		{Name: "x", Start: "03:00", Duration: time.Hour, Days: []string{"su"}},
+	} {
+		if _, err := maintenanceSilences([]config.MaintenanceWindow{w}, at(4, 0)); err == nil {
+			t.Errorf("expected error for %+v", w)
+		}
+	}
+	full := []config.MaintenanceWindow{{Name: "backups", Days: []string{"Sunday"}, Start: "03:00", Duration: time.Hour}}
+	if open, err := maintenanceSilences(full, at(3, 30)); err != nil || len(open) != 1 {
+		t.Errorf("full day names should work, got %+v, %v", open, err)
+	}
+}
+
+func TestActiveSilencesBrokenFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "silences.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	windows := []config.MaintenanceWindow{{Name: "always", Start: "00:00", Duration: 24 * time.Hour}}
+	active, err := ActiveSilences(path, windows, time.Now())
+	if err == nil {
+		t.Error("expected the broken file to be reported")
+	}
+	if len(active) != 1 || active[0].Window != "always" {
+		t.Errorf("maintenance windows should still apply, got %+v", active)
+	}
+}
+
+func TestApplySilences(t *testing.T) {
+	r := &AlertResult{
+		CPU:    AlertItem{Status: "warning", Current: 85, Threshold: 90},
+		Memory: AlertItem{Status: "ok", Current: 40, Threshold: 85},
+		Disks: []DiskAlert{
+			{Mount: "/", Status: "ok", Current: 40, Threshold: 90},
+			{Mount: "/mnt/data", Status: "critical", Current: 97, Threshold: 90},
+		},
+	}
+	r.applySilences(nil)
+	if r.Status != "critical" {
+		t.Errorf("expected critical overall, got %s", r.Status)
+	}
+
+	r.applySilences([]Silence{{Metrics: []string{"disk"}}})
+	if !r.Disks[1].Silenced || r.CPU.Silenced {
+		t.Errorf("only disks should be silenced: %+v", r)
+	}
+	if r.Status != "warning" {
+		t.Errorf("silenced disk should not count, got %s", r.Status)
+	}
+}
@@ -0,0 +1,202 @@
+package alerts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Higangssh/homebutler/internal/config"
+)
+
+// State is the last reported status of every metric, per server. It lets
+// `alerts --notify` and `alerts watch` report only what changed, also
+// across restarts.
+type State struct {
+	Servers map[string]map[string]Metric `json:"servers"`
+	// Pending holds the worse statuses `alerts watch` has seen but not
+	// reported yet, because they haven't held for long enough.
+	Pending map[string]map[string]Pending `json:"pending,omitempty"`
+}
+
+// Pending is a worse status and since when it has held.
+type Pending struct {
+	Status string    `json:"status"`
+	Since  time.Time `json:"since"`
+}
+
+// DefaultStatePath is $XDG_STATE_HOME/homebutler/alerts.json, or
+// ~/.local/state/homebutler/alerts.json.
+func DefaultStatePath() string {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "homebutler-alerts.json"
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "homebutler", "alerts.json")
+}
+
+// LoadState reads the state file. A missing file is an empty state.
+func LoadState(path string) (*State, error) {
+	s := &State{Servers: map[string]map[string]Metric{}, Pending: map[string]map[string]Pending{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read alert state: %w", err)
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("failed to parse alert state %s: %w", path, err)
+	}
+	if s.Servers == nil {
+		s.Servers = map[string]map[string]Metric{}
+	}
+	if s.Pending == nil {
+		s.Pending = map[string]map[string]Pending{}
+	}
+	return s, nil
+}
+
+// Save writes the state through a temporary file, so a crash never leaves
+// a truncated one behind.
+func (s *State) Save(path string) error {
+	if err := writeJSON(path, s); err != nil {
+		return fmt.Errorf("failed to save alert state: %w", err)
+	}
+	return nil
+}
+
+// writeJSON writes v to path through a temporary file.
+func writeJSON(path string, v any) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Update records a server's metrics and returns what changed since the
+// last update.
+func (s *State) Update(server string, metrics []Metric) []Transition {
+	metrics = unsilenced(s.Servers[server], metrics)
+	changes := Transitions(s.Servers[server], metrics)
+	cur := make(map[string]Metric, len(metrics))
+	for _, m := range metrics {
+		cur[m.Key] = m
+	}
+	s.Servers[server] = cur
+	delete(s.Pending, server)
+	return changes
+}
+
+// Observe is Update for `alerts watch`. A worse status only becomes a
+// transition once it has held for cfg.HoldFor(kind), so a short spike
+// doesn't fire; a better one only once the value has dropped cfg.Hysteresis
+// percent below the level it crossed, so a value hovering at the threshold
+// doesn't flap. Until then the metric keeps its reported status.
+func (s *State) Observe(server string, metrics []Metric, now time.Time, cfg *config.WatchConfig) []Transition {
+	prev, pending := s.Servers[server], s.Pending[server]
+	metrics = unsilenced(prev, metrics)
+	cur := make(map[string]Metric, len(metrics))
+	waiting := map[string]Pending{}
+	var changes []Transition
+	for _, m := range metrics {
+		reported := "ok"
+		if p, ok := prev[m.Key]; ok {
+			reported = p.Status
+		}
+		status := settle(m, reported, cfg.Hysteresis)
+		if severity(status) > severity(reported) {
+			// the clock keeps running from warning on to critical
+			since := now
+			if p, ok := pending[m.Key]; ok {
+				since = p.Since
+			}
+			if now.Sub(since) < cfg.HoldFor(m.Kind()) {
+				waiting[m.Key] = Pending{Status: status, Since: since}
+				status = reported
+			}
+		}
+		if status != reported {
+			changes = append(changes, Transition{Metric: withStatus(m, status), From: reported})
+		}
+		cur[m.Key] = withStatus(m, status)
+	}
+	changes = append(changes, vanished(prev, cur)...)
+
+	s.Servers[server] = cur
+	if len(waiting) > 0 {
+		s.Pending[server] = waiting
+	} else {
+		delete(s.Pending, server)
+	}
+	return changes
+}
+
+// unsilenced replaces silenced metrics by their last reported state (ok if
+// there is none), so a silence neither fires nor resolves anything and an
+// alert still firing when the silence ends is reported then.
+func unsilenced(prev map[string]Metric, metrics []Metric) []Metric {
+	out := make([]Metric, len(metrics))
+	for i, m := range metrics {
+		if m.Silenced {
+			if p, ok := prev[m.Key]; ok {
+				m = p
+			} else {
+				m.Status, m.Silenced = "ok", false
+			}
+		}
+		out[i] = m
+	}
+	return out
+}
+
+// settle applies hysteresis to a metric that got better than reported: it
+// is re-evaluated against levels lowered by hysteresis percent, and can't
+// come out worse than reported. Container rules have no levels to lower.
+func settle(m Metric, reported string, hysteresis float64) string {
+	if severity(m.Status) >= severity(reported) || hysteresis <= 0 || m.Kind() == "container" {
+		return m.Status
+	}
+	warning, critical := m.levels()
+	f := 1 - hysteresis/100
+	status := statusFor(m.Current, config.Threshold{Warning: warning * f, Critical: critical * f})
+	if severity(status) > severity(reported) {
+		return reported
+	}
+	return status
+}
+
+// levels are the metric's warning and critical levels. Results from
+// versions without a warning level had it at 90% of critical.
+func (m Metric) levels() (warning, critical float64) {
+	return config.Threshold{Warning: m.Warning, Critical: m.Threshold}.Levels()
+}
+
+func severity(status string) int {
+	switch status {
+	case "critical":
+		return 2
+	case "warning":
+		return 1
+	}
+	return 0
+}
+
+func withStatus(m Metric, status string) Metric {
+	m.Status = status
+	return m
+}
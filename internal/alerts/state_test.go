@@ -0,0 +1,117 @@
+package alerts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Higangssh/homebutler/internal/config"
+)
+
+func TestStateRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "alerts.json")
+	s, err := LoadState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Update("nas", []Metric{{Key: "disk:/", Status: "critical"}}); len(got) != 1 {
+		t.Fatalf("first critical evaluation should be a transition, got %+v", got)
+	}
+	if err := s.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = LoadState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Update("nas", []Metric{{Key: "disk:/", Status: "critical"}}); len(got) != 0 {
+		t.Errorf("unchanged status after reload should not transition, got %+v", got)
+	}
+	if got := s.Update("rpi", []Metric{{Key: "disk:/", Status: "ok"}}); len(got) != 0 {
+		t.Errorf("servers are tracked separately, got %+v", got)
+	}
+}
+
+func TestLoadStateCorrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts.json")
+	os.WriteFile(path, []byte("{not json"), 0600)
+	if _, err := LoadState(path); err == nil {
+		t.Error("expected error for corrupt state file")
+	}
+}
+
+func TestObserveHoldsFor(t *testing.T) {
+	s := &State{Servers: map[string]map[string]Metric{}, Pending: map[string]map[string]Pending{}}
+	cfg := &config.WatchConfig{For: 2 * time.Minute, ForMetrics: map[string]time.Duration{"container": 0}, Hysteresis: 5}
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	cpu := func(status string, current float64) []Metric {
+		return []Metric{{Key: "cpu", Label: "CPU", Status: status, Current: current, Threshold: 90, Unit: "%"}}
+	}
+
+	if got := s.Observe("nas", cpu("warning", 85), start, cfg); len(got) != 0 {
+		t.Fatalf("a fresh spike should not fire, got %+v", got)
+	}
+	if got := s.Observe("nas", cpu("ok", 20), start.Add(time.Minute), cfg); len(got) != 0 || len(s.Pending) != 0 {
+		t.Fatalf("a spike that went away should be forgotten, got %+v / %+v", got, s.Pending)
+	}
+	s.Observe("nas", cpu("warning", 85), start.Add(2*time.Minute), cfg)
+	s.Observe("nas", cpu("critical", 95), start.Add(3*time.Minute), cfg)
+	got := s.Observe("nas", cpu("critical", 96), start.Add(4*time.Minute), cfg)
+	if len(got) != 1 || got[0].Status != "critical" || got[0].From != "ok" || got[0].Current != 96 {
+		t.Fatalf("expected ok → critical after 2m above the levels, got %+v", got)
+	}
+	if got := s.Observe("nas", cpu("critical", 97), start.Add(5*time.Minute), cfg); len(got) != 0 {
+		t.Errorf("unchanged status should not transition, got %+v", got)
+	}
+
+	restart := []Metric{{Key: "container:restarts:plex", Label: "Container plex", Status: "critical", Message: "plex exited 3 times in 10 minutes"}}
+	if got := s.Observe("rpi", restart, start, cfg); len(got) != 1 {
+		t.Errorf("for_metrics container: 0s should fire at once, got %+v", got)
+	}
+	if got := s.Observe("rpi", nil, start.Add(time.Minute), cfg); len(got) != 1 || got[0].Status != "ok" || got[0].Message != "resolved: plex exited 3 times in 10 minutes" {
+		t.Errorf("a vanished alert should recover, got %+v", got)
+	}
+}
+
+func TestObserveHysteresis(t *testing.T) {
+	s := &State{Servers: map[string]map[string]Metric{}, Pending: map[string]map[string]Pending{}}
+	cfg := &config.WatchConfig{Hysteresis: 5}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	disk := func(status string, current float64) []Metric {
+		return []Metric{{Key: "disk:/", Label: "Disk /", Status: status, Current: current, Threshold: 90, Unit: "%"}}
+	}
+
+	s.Observe("nas", disk("critical", 91), now, cfg)
+	// critical again from 90*0.95 = 85.5, warning from 81*0.95 = 76.95
+	if got := s.Observe("nas", disk("warning", 88), now, cfg); len(got) != 0 {
+		t.Errorf("88%% is within the hysteresis band, got %+v", got)
+	}
+	got := s.Observe("nas", disk("warning", 84), now, cfg)
+	if len(got) != 1 || got[0].Status != "warning" || got[0].From != "critical" {
+		t.Errorf("expected critical → warning below 85.5%%, got %+v", got)
+	}
+	if got := s.Observe("nas", disk("ok", 78), now, cfg); len(got) != 0 {
+		t.Errorf("78%% is within the warning band, got %+v", got)
+	}
+	if got := s.Observe("nas", disk("ok", 70), now, cfg); len(got) != 1 || got[0].Status != "ok" {
+		t.Errorf("expected recovery at 70%%, got %+v", got)
+	}
+}
+
+func TestSilencedMetricsKeepTheirState(t *testing.T) {
+	s := &State{Servers: map[string]map[string]Metric{}, Pending: map[string]map[string]Pending{}}
+	disk := func(status string, silenced bool) []Metric {
+		return []Metric{{Key: "disk:/", Label: "Disk /", Status: status, Current: 95, Threshold: 90, Silenced: silenced}}
+	}
+	if got := s.Update("nas", disk("critical", true)); len(got) != 0 {
+		t.Errorf("a silenced alert should not fire, got %+v", got)
+	}
+	if got := s.Update("nas", disk("critical", false)); len(got) != 1 || got[0].From != "ok" {
+		t.Errorf("an alert still firing when the silence ends should fire, got %+v", got)
+	}
+	if got := s.Update("nas", disk("ok", true)); len(got) != 0 {
+		t.Errorf("a silenced alert should not resolve either, got %+v", got)
+	}
+}
@@ -2,8 +2,12 @@ package config
 
 import (
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -12,18 +16,28 @@ type Config struct {
 	Servers []ServerConfig `yaml:"servers"`
 	Wake    []WakeTarget   `yaml:"wake,omitempty"`
 	Alerts  AlertConfig    `yaml:"alerts"`
+	Disks   DiskConfig     `yaml:"disks,omitempty"`
+	Compose ComposeConfig  `yaml:"compose,omitempty"`
+	Metrics MetricsConfig  `yaml:"metrics,omitempty"`
+	SSH     SSHConfig      `yaml:"ssh,omitempty"`
 }
 
 type ServerConfig struct {
-	Name     string `yaml:"name"`
-	Host     string `yaml:"host"`
-	Local    bool   `yaml:"local,omitempty"`
-	User     string `yaml:"user,omitempty"`
-	Port     int    `yaml:"port,omitempty"`
-	KeyFile  string `yaml:"key,omitempty"`
-	Password string `yaml:"password,omitempty"`
-	AuthMode string `yaml:"auth,omitempty"` // "key" (default) or "password"
-	BinPath  string `yaml:"bin,omitempty"`  // remote homebutler path (default: homebutler)
+	Name          string `yaml:"name"`
+	Host          string `yaml:"host"`
+	Local         bool   `yaml:"local,omitempty"`
+	User          string `yaml:"user,omitempty"`
+	Port          int    `yaml:"port,omitempty"`
+	KeyFile       string `yaml:"key,omitempty"`
+	Cert          string `yaml:"cert,omitempty"`           // OpenSSH user certificate (default: <key>-cert.pub if present)
+	PassphraseEnv string `yaml:"passphrase_env,omitempty"` // environment variable holding the key's passphrase
+	PassphraseCmd string `yaml:"passphrase_cmd,omitempty"` // command printing the key's passphrase, e.g. "pass show ssh/nas"
+	Password      string `yaml:"password,omitempty"`
+	AuthMode      string `yaml:"auth,omitempty"`    // "key" (default), "agent", "password", "keyboard-interactive", or several: "key,keyboard-interactive"
+	BinPath       string `yaml:"bin,omitempty"`     // remote homebutler path (default: homebutler)
+	Runtime       string `yaml:"runtime,omitempty"` // container runtime: "docker", "podman" or "" to auto-detect
+
+	Alerts *AlertLevels `yaml:"alerts,omitempty"` // overrides the alert levels for this server
 }
 
 type WakeTarget struct {
@@ -32,10 +46,320 @@ type WakeTarget struct {
 	Broadcast string `yaml:"ip,omitempty"`
 }
 
+// DiskConfig selects which mounts are reported. Each entry matches that
+// mount point and everything below it, except "/" which matches only the
+// root filesystem. Exclude wins over include.
+type DiskConfig struct {
+	Include []string `yaml:"include,omitempty"` // default: /, /home, /mnt, /Volumes
+	Exclude []string `yaml:"exclude,omitempty"`
+}
+
+// ComposeConfig lists directories that hold one compose stack per
+// subdirectory (e.g. /opt/stacks/immich/compose.yaml). Stacks with running
+// or stopped containers are found from their labels without this; it is
+// needed to bring up a stack that is fully down.
+type ComposeConfig struct {
+	Dirs []string `yaml:"dirs,omitempty"`
+}
+
+// SSHConfig applies to every SSH connection.
+type SSHConfig struct {
+	// EncryptedKeysOnly refuses key files without a passphrase; agent
+	// keys are unaffected.
+	EncryptedKeysOnly bool `yaml:"encrypted_keys_only,omitempty"`
+}
+
 type AlertConfig struct {
-	CPU    float64 `yaml:"cpu"`
-	Memory float64 `yaml:"memory"`
-	Disk   float64 `yaml:"disk"`
+	CPU            Threshold            `yaml:"cpu"`
+	Memory         Threshold            `yaml:"memory"`
+	Swap           Threshold            `yaml:"swap"`            // % of swap used, 0 (default) disables
+	MemoryPressure Threshold            `yaml:"memory_pressure"` // PSI memory "some" avg60 %, 0 (default) disables
+	Disk           Threshold            `yaml:"disk"`
+	Mounts         map[string]Threshold `yaml:"mounts,omitempty"` // disk levels per mount point, e.g. {/mnt/backup: 97}
+	Inodes         Threshold            `yaml:"inodes"`           // % of inodes used, 0 (default) disables
+	Temperature    Threshold            `yaml:"temperature"`      // °C, 0 (default) disables temperature alerts
+	Network        Threshold            `yaml:"network"`          // % of link speed, 0 (default) disables saturation alerts
+	NetErrors      Threshold            `yaml:"net_errors"`       // errors+drops per second, 0 (default) disables
+
+	Containers ContainerAlertConfig `yaml:"containers"`
+	Notify     []NotifierConfig     `yaml:"notify,omitempty"`
+	Watch      WatchConfig          `yaml:"watch"`
+
+	Maintenance []MaintenanceWindow `yaml:"maintenance,omitempty"`
+	History     HistoryConfig       `yaml:"history"`
+}
+
+// HistoryConfig is the log of alert evaluations that weren't ok, which
+// every alert check keeps.
+type HistoryConfig struct {
+	Retention time.Duration `yaml:"retention"` // default 720h (30 days), 0 disables the history
+}
+
+// MetricsConfig is where `metrics push` sends the metrics.
+type MetricsConfig struct {
+	Push PushConfig `yaml:"push"`
+}
+
+// PushConfig is an HTTP write endpoint taking InfluxDB line protocol:
+// InfluxDB 1.x /write, InfluxDB 2.x /api/v2/write or VictoriaMetrics /write.
+type PushConfig struct {
+	URL      string        `yaml:"url"`
+	Token    string        `yaml:"token,omitempty"`    // InfluxDB 2.x API token
+	Username string        `yaml:"username,omitempty"` // basic auth (InfluxDB 1.x, VictoriaMetrics)
+	Password string        `yaml:"password,omitempty"`
+	Interval time.Duration `yaml:"interval"` // default 1m, --interval overrides
+	Batch    int           `yaml:"batch"`    // lines per request, default 5000
+	Buffer   int           `yaml:"buffer"`   // lines kept on disk while the endpoint is down, default 100000, 0 keeps none
+}
+
+// Threshold is the warning and critical level of an alert. In YAML it is
+// either the critical level alone, with warning at 90% of it, or both:
+//
+//	disk: 90
+//	disk: {warning: 80, critical: 95}
+//
+// A critical level of 0 disables the alert.
+type Threshold struct {
+	Warning  float64 `yaml:"warning,omitempty"` // 0 is 90% of critical
+	Critical float64 `yaml:"critical"`
+}
+
+// Levels returns the effective warning and critical levels.
+func (t Threshold) Levels() (warning, critical float64) {
+	if t.Warning == 0 {
+		return t.Critical * 0.9, t.Critical
+	}
+	return t.Warning, t.Critical
+}
+
+func (t *Threshold) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		var critical float64
+		if err := node.Decode(&critical); err != nil {
+			return err
+		}
+		*t = Threshold{Critical: critical}
+		return nil
+	}
+	type plain Threshold // without this method
+	var p plain
+	if err := node.Decode(&p); err != nil {
+		return err
+	}
+	if p.Critical <= 0 && p.Warning > 0 {
+		return fmt.Errorf("line %d: critical level is required", node.Line)
+	}
+	if p.Warning > p.Critical {
+		return fmt.Errorf("line %d: warning level %g is above critical %g", node.Line, p.Warning, p.Critical)
+	}
+	*t = Threshold(p)
+	return nil
+}
+
+// MarshalYAML writes the short form when warning is the default.
+func (t Threshold) MarshalYAML() (any, error) {
+	if t.Warning == 0 {
+		return t.Critical, nil
+	}
+	type plain Threshold
+	return plain(t), nil
+}
+
+// AlertLevels overrides alert levels for one server entry. Unset levels
+// keep the global ones; mounts add to the global mounts.
+type AlertLevels struct {
+	CPU            *Threshold           `yaml:"cpu,omitempty"`
+	Memory         *Threshold           `yaml:"memory,omitempty"`
+	Swap           *Threshold           `yaml:"swap,omitempty"`
+	MemoryPressure *Threshold           `yaml:"memory_pressure,omitempty"`
+	Disk           *Threshold           `yaml:"disk,omitempty"`
+	Mounts         map[string]Threshold `yaml:"mounts,omitempty"`
+	Inodes         *Threshold           `yaml:"inodes,omitempty"`
+	Temperature    *Threshold           `yaml:"temperature,omitempty"`
+	Network        *Threshold           `yaml:"network,omitempty"`
+	NetErrors      *Threshold           `yaml:"net_errors,omitempty"`
+}
+
+// DiskLevel is the disk threshold of a mount: its alerts.mounts entry, or
+// the disk one.
+func (a *AlertConfig) DiskLevel(mount string) Threshold {
+	if t, ok := a.Mounts[mount]; ok {
+		return t
+	}
+	return a.Disk
+}
+
+// AlertsFor is the alert config of a server: alerts, with the levels of
+// its server entry applied. srv may be nil.
+func (c *Config) AlertsFor(srv *ServerConfig) *AlertConfig {
+	a := c.Alerts
+	if srv == nil || srv.Alerts == nil {
+		return &a
+	}
+	l := srv.Alerts
+	for _, o := range []struct {
+		dst *Threshold
+		src *Threshold
+	}{
+		{&a.CPU, l.CPU}, {&a.Memory, l.Memory}, {&a.Swap, l.Swap},
+		{&a.MemoryPressure, l.MemoryPressure}, {&a.Disk, l.Disk}, {&a.Inodes, l.Inodes},
+		{&a.Temperature, l.Temperature}, {&a.Network, l.Network}, {&a.NetErrors, l.NetErrors},
+	} {
+		if o.src != nil {
+			*o.dst = *o.src
+		}
+	}
+	if len(l.Mounts) > 0 {
+		a.Mounts = maps.Clone(c.Alerts.Mounts)
+		if a.Mounts == nil {
+			a.Mounts = map[string]Threshold{}
+		}
+		maps.Copy(a.Mounts, l.Mounts)
+	}
+	return &a
+}
+
+// LocalAlerts is the alert config of this machine: AlertsFor its local
+// server entry, if there is one.
+func (c *Config) LocalAlerts() *AlertConfig {
+	for i := range c.Servers {
+		if c.Servers[i].Local {
+			return c.AlertsFor(&c.Servers[i])
+		}
+	}
+	return c.AlertsFor(nil)
+}
+
+// MaintenanceWindow silences alerts on a schedule, e.g. every Sunday from
+// 03:00 for two hours while backups run. Like silences, it applies to the
+// machine whose config defines it.
+type MaintenanceWindow struct {
+	Name     string        `yaml:"name"`
+	Metrics  []string      `yaml:"metrics,omitempty"` // e.g. [disk, cpu] or [disk:/mnt/data]; empty silences all
+	Days     []string      `yaml:"days,omitempty"`    // mon, tue, ...; empty is every day
+	Start    string        `yaml:"start"`             // "03:00", local time
+	Duration time.Duration `yaml:"duration"`          // at most 24h
+	Reason   string        `yaml:"reason,omitempty"`
+
+	// Start and Days, parsed
+	parsed       bool
+	hour, minute int
+	days         [7]bool
+}
+
+// UnmarshalYAML checks the window when the config is loaded, so a typo
+// fails there rather than on every alert check.
+func (w *MaintenanceWindow) UnmarshalYAML(node *yaml.Node) error {
+	type plain MaintenanceWindow // without this method
+	var p plain
+	if err := node.Decode(&p); err != nil {
+		return err
+	}
+	*w = MaintenanceWindow(p)
+	if err := w.Parse(); err != nil {
+		return fmt.Errorf("line %d: maintenance window %q: %w", node.Line, w.Name, err)
+	}
+	return nil
+}
+
+// Parse checks Start, Duration and Days, and keeps Start and Days parsed
+// for Clock and OnDay.
+func (w *MaintenanceWindow) Parse() error {
+	start, err := time.Parse("15:04", w.Start)
+	if err != nil {
+		return fmt.Errorf("invalid start %q (use HH:MM)", w.Start)
+	}
+	if w.Duration <= 0 || w.Duration > 24*time.Hour {
+		return fmt.Errorf("duration must be between 1s and 24h, got %s", w.Duration)
+	}
+	w.days = [7]bool{}
+	for _, d := range w.Days {
+		wd, ok := parseWeekday(d)
+		if !ok {
+			return fmt.Errorf("invalid day %q (use mon, tue, ... or monday, tuesday, ...)", d)
+		}
+		w.days[wd] = true
+	}
+	w.hour, w.minute = start.Hour(), start.Minute()
+	w.parsed = true
+	return nil
+}
+
+// Parsed reports whether Parse has run.
+func (w *MaintenanceWindow) Parsed() bool {
+	return w.parsed
+}
+
+// Clock is the hour and minute the window starts at.
+func (w *MaintenanceWindow) Clock() (hour, minute int) {
+	return w.hour, w.minute
+}
+
+// OnDay reports whether the window opens on a weekday.
+func (w *MaintenanceWindow) OnDay(d time.Weekday) bool {
+	return len(w.Days) == 0 || w.days[d]
+}
+
+// parseWeekday takes a full day name or its three-letter abbreviation.
+func parseWeekday(s string) (time.Weekday, bool) {
+	s = strings.ToLower(s)
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if name := strings.ToLower(d.String()); s == name || s == name[:3] {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// WatchConfig tunes `alerts watch`. A worse status only fires once it has
+// held for For (or the For of its metric kind: cpu, memory, disk,
+// container, ...); a value only recovers once it is Hysteresis percent
+// below the level it crossed.
+type WatchConfig struct {
+	Interval   time.Duration            `yaml:"interval"`              // default 1m, --interval overrides
+	For        time.Duration            `yaml:"for"`                   // default 2m, 0 fires at once
+	ForMetrics map[string]time.Duration `yaml:"for_metrics,omitempty"` // e.g. {cpu: 5m, container: 0s}
+	Hysteresis float64                  `yaml:"hysteresis"`            // % of the level, default 5
+}
+
+// HoldFor returns how long a worse status of the given metric kind must
+// hold before it fires.
+func (w *WatchConfig) HoldFor(kind string) time.Duration {
+	if d, ok := w.ForMetrics[kind]; ok {
+		return d
+	}
+	return w.For
+}
+
+// ContainerAlertConfig holds the container rules: containers that must be
+// running, failing healthchecks, and containers that keep exiting.
+type ContainerAlertConfig struct {
+	Running       []string      `yaml:"running,omitempty"` // names that must be running
+	Unhealthy     bool          `yaml:"unhealthy"`         // alert on any unhealthy container, off by default
+	Restarts      int           `yaml:"restarts"`          // crashes within restart_window, 0 (default) disables
+	RestartWindow time.Duration `yaml:"restart_window"`    // default 10m
+}
+
+// NotifierConfig is one destination for alert notifications. Type selects
+// which fields are used:
+//
+//	webhook:        url (the alert is POSTed as JSON)
+//	ntfy:           url (default https://ntfy.sh), topic, token (optional)
+//	gotify:         url, token (application token)
+//	discord, slack: url (incoming webhook)
+//	smtp:           host, port (default 587), username, password, from, to
+type NotifierConfig struct {
+	Name     string   `yaml:"name,omitempty"` // shown in errors and test-notify; defaults to type
+	Type     string   `yaml:"type"`
+	URL      string   `yaml:"url,omitempty"`
+	Topic    string   `yaml:"topic,omitempty"`
+	Token    string   `yaml:"token,omitempty"`
+	Host     string   `yaml:"host,omitempty"`
+	Port     int      `yaml:"port,omitempty"`
+	Username string   `yaml:"username,omitempty"`
+	Password string   `yaml:"password,omitempty"`
+	From     string   `yaml:"from,omitempty"`
+	To       []string `yaml:"to,omitempty"`
 }
 
 // Resolve finds the config file path using the following priority:
@@ -65,11 +389,26 @@ func Resolve(explicit string) string {
 }
 
 func Load(path string) (*Config, error) {
+	// Swap, memory pressure, inodes, temperature, the network checks and
+	// the container rules stay off until configured, so an upgrade doesn't
+	// start new alerts.
 	cfg := &Config{
 		Alerts: AlertConfig{
-			CPU:    90,
-			Memory: 85,
-			Disk:   90,
+			CPU:    Threshold{Critical: 90},
+			Memory: Threshold{Critical: 85},
+			Disk:   Threshold{Critical: 90},
+			Containers: ContainerAlertConfig{
+				RestartWindow: 10 * time.Minute,
+			},
+			Watch: WatchConfig{
+				Interval:   time.Minute,
+				For:        2 * time.Minute,
+				Hysteresis: 5,
+			},
+			History: HistoryConfig{Retention: 30 * 24 * time.Hour},
+		},
+		Metrics: MetricsConfig{
+			Push: PushConfig{Interval: time.Minute, Batch: 5000, Buffer: 100000},
 		},
 	}
 
@@ -88,6 +427,29 @@ func Load(path string) (*Config, error) {
 	return cfg, nil
 }
 
+// LocalName names this machine in alerts and history: its local server
+// entry, else the hostname.
+func (c *Config) LocalName() string {
+	for _, srv := range c.Servers {
+		if srv.Local {
+			return srv.Name
+		}
+	}
+	if host, err := os.Hostname(); err == nil {
+		return host
+	}
+	return "localhost"
+}
+
+// Monitored is the servers `alerts watch` and the metrics history cover:
+// the configured ones, or this machine alone.
+func (c *Config) Monitored() []ServerConfig {
+	if len(c.Servers) > 0 {
+		return c.Servers
+	}
+	return []ServerConfig{{Name: c.LocalName(), Local: true}}
+}
+
 // FindServer returns the server config by name, or nil if not found.
 func (c *Config) FindServer(name string) *ServerConfig {
 	for i := range c.Servers {
@@ -114,9 +476,24 @@ func (s *ServerConfig) SSHUser() string {
 	return "root"
 }
 
+// AuthModes returns the auth methods to try, in order. Hosts that require
+// more than one, like a key and then a one-time code, list several.
+func (s *ServerConfig) AuthModes() []string {
+	var modes []string
+	for m := range strings.SplitSeq(s.AuthMode, ",") {
+		if m = strings.TrimSpace(m); m != "" {
+			modes = append(modes, m)
+		}
+	}
+	if len(modes) == 0 {
+		return []string{"key"}
+	}
+	return modes
+}
+
 // UseKeyAuth returns true if key-based auth should be used (default).
 func (s *ServerConfig) UseKeyAuth() bool {
-	return s.AuthMode != "password"
+	return slices.Contains(s.AuthModes(), "key")
 }
 
 // SSHBinPath returns the remote homebutler binary path.
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestLoadDefaults(t *testing.T) {
@@ -11,16 +12,36 @@ func TestLoadDefaults(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if cfg.Alerts.CPU != 90 {
-		t.Errorf("expected CPU threshold 90, got %f", cfg.Alerts.CPU)
+	if cfg.Alerts.CPU.Critical != 90 {
+		t.Errorf("expected CPU threshold 90, got %f", cfg.Alerts.CPU.Critical)
 	}
-	if cfg.Alerts.Memory != 85 {
-		t.Errorf("expected Memory threshold 85, got %f", cfg.Alerts.Memory)
+	if cfg.Alerts.Memory.Critical != 85 {
+		t.Errorf("expected Memory threshold 85, got %f", cfg.Alerts.Memory.Critical)
 	}
-	if cfg.Alerts.Disk != 90 {
-		t.Errorf("expected Disk threshold 90, got %f", cfg.Alerts.Disk)
+	if cfg.Alerts.Disk.Critical != 90 {
+		t.Errorf("expected Disk threshold 90, got %f", cfg.Alerts.Disk.Critical)
+	}
+	// the checks added later are off until configured
+	for name, th := range map[string]Threshold{
+		"swap": cfg.Alerts.Swap, "memory_pressure": cfg.Alerts.MemoryPressure, "inodes": cfg.Alerts.Inodes,
+		"temperature": cfg.Alerts.Temperature, "network": cfg.Alerts.Network, "net_errors": cfg.Alerts.NetErrors,
+	} {
+		if th != (Threshold{}) {
+			t.Errorf("expected %s off by default, got %+v", name, th)
+		}
+	}
+	if len(cfg.Disks.Include) != 0 || len(cfg.Disks.Exclude) != 0 {
+		t.Errorf("expected no disk filters by default, got %+v", cfg.Disks)
+	}
+	if c := cfg.Alerts.Containers; c.Unhealthy || c.Restarts != 0 || c.RestartWindow != 10*time.Minute {
+		t.Errorf("unexpected container alert defaults: %+v", c)
+	}
+	if w := cfg.Alerts.Watch; w.Interval != time.Minute || w.For != 2*time.Minute || w.Hysteresis != 5 || w.HoldFor("cpu") != 2*time.Minute {
+		t.Errorf("unexpected watch defaults: %+v", w)
+	}
+	if p := cfg.Metrics.Push; p.URL != "" || p.Interval != time.Minute || p.Batch != 5000 || p.Buffer != 100000 {
+		t.Errorf("unexpected metrics push defaults: %+v", p)
 	}
-
 }
 
 func TestLoadFromFile(t *testing.T) {
@@ -32,6 +53,28 @@ alerts:
   cpu: 80
   memory: 70
   disk: 95
+  temperature: 70
+  network: 0
+  containers:
+    running: [jellyfin, postgres]
+    unhealthy: true
+    restarts: 5
+    restart_window: 30m
+  watch:
+    for: 5m
+    for_metrics:
+      container: 0s
+disks:
+  include: ["/", "/var/lib/docker"]
+  exclude: ["/mnt/backup"]
+compose:
+  dirs: ["/opt/stacks"]
+metrics:
+  push:
+    url: http://victoria.lan:8428/write
+    interval: 30s
+ssh:
+  encrypted_keys_only: true
 wake:
   - name: nas
     mac: "AA:BB:CC:DD:EE:FF"
@@ -45,14 +88,38 @@ wake:
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if cfg.Alerts.CPU != 80 {
-		t.Errorf("expected CPU threshold 80, got %f", cfg.Alerts.CPU)
+	if cfg.Alerts.CPU.Critical != 80 {
+		t.Errorf("expected CPU threshold 80, got %f", cfg.Alerts.CPU.Critical)
+	}
+	if cfg.Alerts.Memory.Critical != 70 {
+		t.Errorf("expected Memory threshold 70, got %f", cfg.Alerts.Memory.Critical)
+	}
+	if cfg.Alerts.Disk.Critical != 95 {
+		t.Errorf("expected Disk threshold 95, got %f", cfg.Alerts.Disk.Critical)
+	}
+	if cfg.Alerts.Temperature.Critical != 70 {
+		t.Errorf("expected Temperature threshold 70, got %f", cfg.Alerts.Temperature.Critical)
+	}
+	if cfg.Alerts.Network.Critical != 0 {
+		t.Errorf("expected Network alerts disabled, got %f", cfg.Alerts.Network.Critical)
 	}
-	if cfg.Alerts.Memory != 70 {
-		t.Errorf("expected Memory threshold 70, got %f", cfg.Alerts.Memory)
+	if c := cfg.Alerts.Containers; len(c.Running) != 2 || !c.Unhealthy || c.Restarts != 5 || c.RestartWindow != 30*time.Minute {
+		t.Errorf("unexpected container alerts: %+v", c)
 	}
-	if cfg.Alerts.Disk != 95 {
-		t.Errorf("expected Disk threshold 95, got %f", cfg.Alerts.Disk)
+	if w := cfg.Alerts.Watch; w.Interval != time.Minute || w.HoldFor("cpu") != 5*time.Minute || w.HoldFor("container") != 0 {
+		t.Errorf("unexpected watch config: %+v", w)
+	}
+	if len(cfg.Disks.Include) != 2 || cfg.Disks.Include[1] != "/var/lib/docker" || len(cfg.Disks.Exclude) != 1 {
+		t.Errorf("unexpected disk filters: %+v", cfg.Disks)
+	}
+	if len(cfg.Compose.Dirs) != 1 || cfg.Compose.Dirs[0] != "/opt/stacks" {
+		t.Errorf("unexpected compose dirs: %+v", cfg.Compose)
+	}
+	if p := cfg.Metrics.Push; p.URL != "http://victoria.lan:8428/write" || p.Interval != 30*time.Second || p.Batch != 5000 {
+		t.Errorf("unexpected metrics push config: %+v", p)
+	}
+	if !cfg.SSH.EncryptedKeysOnly {
+		t.Error("expected ssh.encrypted_keys_only")
 	}
 	if len(cfg.Wake) != 1 {
 		t.Fatalf("expected 1 wake target, got %d", len(cfg.Wake))
@@ -62,6 +129,78 @@ wake:
 	}
 }
 
+func TestAlertLevels(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.yaml")
+	content := `
+alerts:
+  cpu: {warning: 70, critical: 95}
+  disk: 90
+  mounts:
+    /mnt/backup: 97
+servers:
+  - name: rpi
+    host: 192.168.1.20
+    alerts:
+      cpu: 80
+      mounts:
+        /: {warning: 60, critical: 75}
+  - name: nas
+    host: 192.168.1.10
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w, c := cfg.Alerts.CPU.Levels(); w != 70 || c != 95 {
+		t.Errorf("expected cpu 70/95, got %g/%g", w, c)
+	}
+	if w, c := cfg.Alerts.Disk.Levels(); w != 81 || c != 90 {
+		t.Errorf("a plain level should warn at 90%%, got %g/%g", w, c)
+	}
+	if got := cfg.Alerts.DiskLevel("/mnt/backup"); got.Critical != 97 {
+		t.Errorf("expected /mnt/backup at 97, got %+v", got)
+	}
+
+	rpi := cfg.AlertsFor(cfg.FindServer("rpi"))
+	if rpi.CPU != (Threshold{Critical: 80}) || rpi.Memory.Critical != 85 {
+		t.Errorf("unexpected rpi levels: cpu %+v memory %+v", rpi.CPU, rpi.Memory)
+	}
+	if rpi.DiskLevel("/").Critical != 75 || rpi.DiskLevel("/mnt/backup").Critical != 97 || rpi.DiskLevel("/home").Critical != 90 {
+		t.Errorf("unexpected rpi mounts: %+v", rpi.Mounts)
+	}
+	if len(cfg.Alerts.Mounts) != 1 {
+		t.Errorf("server mounts leaked into the global ones: %+v", cfg.Alerts.Mounts)
+	}
+	if nas := cfg.AlertsFor(cfg.FindServer("nas")); nas.CPU.Critical != 95 {
+		t.Errorf("nas has no overrides, got cpu %+v", nas.CPU)
+	}
+
+	os.WriteFile(path, []byte("alerts:\n  maintenance:\n    - {name: backups, days: [sun, Saturday], start: \"03:00\", duration: 2h}\n"), 0644)
+	cfg, err = Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w := cfg.Alerts.Maintenance[0]; !w.Parsed() || !w.OnDay(time.Saturday) || w.OnDay(time.Monday) {
+		t.Errorf("unexpected window: %+v", w)
+	}
+
+	for _, bad := range []string{
+		"alerts:\n  cpu: {warning: 95, critical: 90}\n",
+		"alerts:\n  disk: {warning: 80}\n",
+		"alerts:\n  maintenance:\n    - {name: backups, days: [monkey], start: \"03:00\", duration: 2h}\n",
+		"alerts:\n  maintenance:\n    - {name: backups, start: \"3am\", duration: 2h}\n",
+	} {
+		os.WriteFile(path, []byte(bad), 0644)
+		if _, err := Load(path); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
 func TestFindWakeTarget(t *testing.T) {
 	cfg := &Config{
 		Wake: []WakeTarget{
@@ -0,0 +1,177 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// DefaultHost is used when DOCKER_HOST is not set.
+const DefaultHost = "unix:///var/run/docker.sock"
+
+// Errors returned when the Engine API can't be reached. Callers (the TUI,
+// the web dashboard) map them to a status with ClassifyError.
+var (
+	ErrSocketMissing    = errors.New("docker socket not found (is Docker installed?)")
+	ErrPermissionDenied = errors.New("permission denied on docker socket (is your user in the docker group?)")
+	ErrDaemonDown       = errors.New("docker daemon is not running")
+)
+
+// Client talks to the Docker Engine HTTP API, served by Docker itself or
+// by Podman's compatibility layer.
+type Client struct {
+	runtime    Runtime
+	host       string // as configured, for error messages
+	socketPath string // empty for tcp hosts
+	baseURL    string
+	http       *http.Client
+	stream     *http.Client // no timeout, for following logs
+}
+
+// NewClient returns a client for the configured or detected runtime; see
+// resolveRuntime for how the API endpoint is chosen.
+func NewClient() (*Client, error) {
+	rt, host, err := resolveRuntime()
+	if err != nil {
+		return nil, err
+	}
+	c, err := newClient(host)
+	if err != nil {
+		return nil, err
+	}
+	c.runtime = rt
+	return c, nil
+}
+
+// newClient supports unix:// and tcp:// hosts. TLS and ssh:// hosts are not
+// supported; use a local socket or an SSH server entry in the config instead.
+func newClient(host string) (*Client, error) {
+	u, err := url.Parse(host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid container API host %q: %w", host, err)
+	}
+	c := &Client{runtime: dockerRuntime{}, host: host}
+	transport := &http.Transport{}
+	switch u.Scheme {
+	case "unix":
+		c.socketPath = u.Path
+		c.baseURL = "http://docker"
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", c.socketPath)
+		}
+	case "tcp", "http":
+		c.baseURL = "http://" + u.Host
+	default:
+		return nil, fmt.Errorf("unsupported container API host scheme %q (use unix:// or tcp://)", u.Scheme)
+	}
+	c.http = &http.Client{Transport: transport, Timeout: 30 * time.Second}
+	c.stream = &http.Client{Transport: transport}
+	return c, nil
+}
+
+// Runtime returns the container engine the client talks to.
+func (c *Client) Runtime() Runtime {
+	return c.runtime
+}
+
+// apiError is the JSON body the Engine API returns on 4xx/5xx.
+type apiError struct {
+	Message string `json:"message"`
+}
+
+// do sends a request and returns the response for 2xx/304, or an error
+// carrying the daemon's message otherwise. The caller closes the body.
+func (c *Client) do(method, path string, query url.Values) (*http.Response, error) {
+	return c.doContext(context.Background(), c.http, method, path, query)
+}
+
+// doContext is do with a context and a choice of http client; long-lived
+// streams use c.stream and end when ctx is canceled.
+func (c *Client) doContext(ctx context.Context, hc *http.Client, method, path string, query url.Values) (*http.Response, error) {
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, c.dialError(err)
+	}
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		var apiErr apiError
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		if json.Unmarshal(body, &apiErr) != nil || apiErr.Message == "" {
+			apiErr.Message = strings.TrimSpace(string(body))
+		}
+		return nil, fmt.Errorf("docker API %s %s: %s (HTTP %d)", method, path, apiErr.Message, resp.StatusCode)
+	}
+	return resp, nil
+}
+
+// getJSON decodes a GET response into v.
+func (c *Client) getJSON(path string, query url.Values, v any) error {
+	resp, err := c.do(http.MethodGet, path, query)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// post sends a POST with no body and discards the response.
+func (c *Client) post(path string, query url.Values) error {
+	resp, err := c.do(http.MethodPost, path, query)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// dialError maps a transport failure to one of the sentinel errors.
+func (c *Client) dialError(err error) error {
+	if c.socketPath != "" {
+		if _, statErr := os.Stat(c.socketPath); errors.Is(statErr, os.ErrNotExist) {
+			if hint := c.runtime.SocketHint(); hint != "" {
+				return fmt.Errorf("%w: %s (%s)", ErrSocketMissing, c.socketPath, hint)
+			}
+			return fmt.Errorf("%w: %s", ErrSocketMissing, c.socketPath)
+		}
+	}
+	if errors.Is(err, os.ErrPermission) || errors.Is(err, syscall.EACCES) || errors.Is(err, syscall.EPERM) {
+		return fmt.Errorf("%w: %s", ErrPermissionDenied, c.host)
+	}
+	return fmt.Errorf("%w (%s): %v", ErrDaemonDown, c.host, err)
+}
+
+// ClassifyError maps a List error to the DockerStatus values the TUI and
+// web dashboard show: "not_installed", "permission_denied" or "unavailable".
+// Errors relayed as text from a remote homebutler are matched by message.
+func ClassifyError(err error) string {
+	if err == nil {
+		return "ok"
+	}
+	msg := err.Error()
+	switch {
+	case errors.Is(err, ErrSocketMissing) || strings.Contains(msg, ErrSocketMissing.Error()):
+		return "not_installed"
+	case errors.Is(err, ErrPermissionDenied) || strings.Contains(msg, ErrPermissionDenied.Error()):
+		return "permission_denied"
+	default:
+		return "unavailable"
+	}
+}
@@ -0,0 +1,341 @@
+package docker
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeDaemon serves handler on a unix socket in a temp dir and points
+// DOCKER_HOST at it.
+func fakeDaemon(t *testing.T, handler http.Handler) *Client {
+	t.Helper()
+	sock := filepath.Join(t.TempDir(), "docker.sock")
+	ln, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Skipf("unix sockets not available: %v", err)
+	}
+	srv := httptest.NewUnstartedServer(handler)
+	srv.Listener = ln
+	srv.Start()
+	t.Cleanup(srv.Close)
+
+	t.Setenv("DOCKER_HOST", "unix://"+sock)
+	c, err := NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+const listJSON = `[
+  {"Id": "a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f90",
+   "Names": ["/nginx"], "Image": "nginx:1.25", "Created": 1700000000,
+   "Ports": [{"IP": "0.0.0.0", "PrivatePort": 80, "PublicPort": 8080, "Type": "tcp"}, {"PrivatePort": 443, "Type": "tcp"}],
+   "Labels": {"com.docker.compose.project": "web"},
+   "State": "running", "Status": "Up 4 days (healthy)"},
+  {"Id": "ffffeeeeddddccccbbbbaaaa9999888877776666555544443333222211110000",
+   "Names": ["/backup"], "Image": "restic/restic", "Created": 1700000100,
+   "Ports": [], "Labels": {}, "State": "exited", "Status": "Exited (0) 6 hours ago"}
+]`
+
+func TestClientList(t *testing.T) {
+	c := fakeDaemon(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/containers/json":
+			if r.URL.Query().Get("all") != "1" {
+				t.Errorf("expected all=1, got %q", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, listJSON)
+		case strings.HasPrefix(r.URL.Path, "/containers/a1b2"):
+			fmt.Fprint(w, `{"RestartCount": 3, "State": {"StartedAt": "2024-05-01T10:00:00.123Z", "Health": {"Status": "healthy"}}}`)
+		case strings.HasPrefix(r.URL.Path, "/containers/ffff"):
+			// never started: zero StartedAt, no healthcheck
+			fmt.Fprint(w, `{"RestartCount": 0, "State": {"StartedAt": "0001-01-01T00:00:00Z"}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	containers, err := c.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(containers) != 2 {
+		t.Fatalf("expected 2 containers, got %d", len(containers))
+	}
+	n := containers[0]
+	if n.ID != "a1b2c3d4e5f6" || len(n.FullID) != 64 || n.Name != "nginx" {
+		t.Errorf("unexpected identity: %+v", n)
+	}
+	if n.Status != "Running · 4d" || n.Health != "healthy" || n.RestartCount != 3 {
+		t.Errorf("unexpected state: status=%q health=%q restarts=%d", n.Status, n.Health, n.RestartCount)
+	}
+	if !n.Created.Equal(time.Unix(1700000000, 0)) || n.StartedAt.IsZero() {
+		t.Errorf("unexpected times: created=%v started=%v", n.Created, n.StartedAt)
+	}
+	if len(n.Ports) != 2 || n.Ports[0] != (Port{IP: "0.0.0.0", PrivatePort: 80, PublicPort: 8080, Type: "tcp"}) {
+		t.Errorf("unexpected ports: %+v", n.Ports)
+	}
+	if n.Labels["com.docker.compose.project"] != "web" {
+		t.Errorf("unexpected labels: %+v", n.Labels)
+	}
+
+	b := containers[1]
+	if b.Status != "Stopped · 6h ago" || b.Health != "" || !b.StartedAt.IsZero() {
+		t.Errorf("unexpected exited container: %+v", b)
+	}
+	if b.Ports == nil {
+		t.Error("ports should be an empty slice, not nil, so JSON has []")
+	}
+}
+
+func TestClientListHealthWithoutInspect(t *testing.T) {
+	// a container removed between list and inspect keeps the list's health
+	c := fakeDaemon(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/containers/json" {
+			fmt.Fprint(w, listJSON)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	containers, err := c.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if containers[0].Health != "healthy" || containers[0].RestartCount != 0 {
+		t.Errorf("unexpected container: %+v", containers[0])
+	}
+}
+
+func TestClientActions(t *testing.T) {
+	var calls []string
+	c := fakeDaemon(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		switch r.URL.Path {
+		case "/containers/nginx/restart":
+			w.WriteHeader(http.StatusNoContent)
+		case "/containers/nginx/stop":
+			w.WriteHeader(http.StatusNotModified) // already stopped
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "No such container: ghost"}`)
+		}
+	}))
+
+	if res, err := c.Restart("nginx"); err != nil || res.Action != "restart" || res.Status != "ok" {
+		t.Errorf("Restart = %+v, %v", res, err)
+	}
+	if _, err := c.Stop("nginx"); err != nil {
+		t.Errorf("Stop of a stopped container should succeed, got %v", err)
+	}
+	_, err := c.Restart("ghost")
+	if err == nil || !strings.Contains(err.Error(), "No such container: ghost") {
+		t.Errorf("expected daemon message in error, got %v", err)
+	}
+	if _, err := c.Stop("nginx;rm -rf /"); err == nil {
+		t.Error("expected invalid name error")
+	}
+	if len(calls) != 3 || calls[0] != "POST /containers/nginx/restart" {
+		t.Errorf("unexpected calls: %v", calls)
+	}
+}
+
+func TestClientLifecycle(t *testing.T) {
+	var calls []string
+	c := fakeDaemon(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := r.Method + " " + r.URL.Path
+		if r.URL.RawQuery != "" {
+			call += "?" + r.URL.RawQuery
+		}
+		calls = append(calls, call)
+		if r.URL.Path == "/containers/running" && r.Method == http.MethodDelete && r.URL.Query().Get("force") != "1" {
+			w.WriteHeader(http.StatusConflict)
+			fmt.Fprint(w, `{"message": "cannot remove container \"/running\": container is running: stop the container before removing or force remove"}`)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	steps := []struct {
+		run    func() (*ActionResult, error)
+		action string
+	}{
+		{func() (*ActionResult, error) { return c.Start("web") }, "start"},
+		{func() (*ActionResult, error) { return c.Pause("web") }, "pause"},
+		{func() (*ActionResult, error) { return c.Unpause("web") }, "unpause"},
+		{func() (*ActionResult, error) { return c.Kill("web", "hup") }, "kill"},
+		{func() (*ActionResult, error) { return c.Kill("web", "") }, "kill"},
+		{func() (*ActionResult, error) { return c.Remove("web", false) }, "rm"},
+		{func() (*ActionResult, error) { return c.Remove("running", true) }, "rm"},
+	}
+	for _, s := range steps {
+		res, err := s.run()
+		if err != nil || res.Action != s.action || res.Status != "ok" {
+			t.Errorf("%s = %+v, %v", s.action, res, err)
+		}
+	}
+	want := []string{
+		"POST /containers/web/start",
+		"POST /containers/web/pause",
+		"POST /containers/web/unpause",
+		"POST /containers/web/kill?signal=HUP",
+		"POST /containers/web/kill?signal=SIGKILL",
+		"DELETE /containers/web",
+		"DELETE /containers/running?force=1",
+	}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("calls:\n%s\nwant:\n%s", strings.Join(calls, "\n"), strings.Join(want, "\n"))
+	}
+
+	if _, err := c.Remove("running", false); err == nil || !strings.Contains(err.Error(), "container is running") {
+		t.Errorf("expected conflict error, got %v", err)
+	}
+	if _, err := c.Kill("web", "HUP; reboot"); err == nil {
+		t.Error("expected invalid signal error")
+	}
+	if _, err := c.Pause("../etc"); err == nil {
+		t.Error("expected invalid name error")
+	}
+}
+
+func frame(stream byte, payload string) []byte {
+	header := make([]byte, 8)
+	header[0] = stream
+	binary.BigEndian.PutUint32(header[4:], uint32(len(payload)))
+	return append(header, payload...)
+}
+
+func TestClientLogs(t *testing.T) {
+	c := fakeDaemon(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/containers/app/json":
+			fmt.Fprint(w, `{"Config": {"Tty": false}}`)
+		case "/containers/tty/json":
+			fmt.Fprint(w, `{"Config": {"Tty": true}}`)
+		case "/containers/app/logs":
+			if r.URL.Query().Get("tail") != "20" {
+				t.Errorf("expected tail=20, got %q", r.URL.RawQuery)
+			}
+			w.Write(frame(1, "started\n"))
+			w.Write(frame(2, "warning: low disk\n"))
+			w.Write(frame(1, "ready\n"))
+		case "/containers/tty/logs":
+			fmt.Fprint(w, "raw tty output\n")
+		}
+	}))
+
+	res, err := c.Logs("app", LogOptions{Tail: "20"})
+	if err != nil {
+		t.Fatalf("Logs: %v", err)
+	}
+	if res.Logs != "started\nwarning: low disk\nready" {
+		t.Errorf("unexpected demuxed logs: %q", res.Logs)
+	}
+	res, err = c.Logs("tty", LogOptions{Tail: "20"})
+	if err != nil || res.Logs != "raw tty output" {
+		t.Errorf("tty logs = %+v, %v", res, err)
+	}
+	if len(res.Entries) != 1 || res.Entries[0].Stream != "stdout" {
+		t.Errorf("tty entries = %+v", res.Entries)
+	}
+	if _, err := c.Logs("app", LogOptions{Tail: "-5"}); err == nil {
+		t.Error("expected invalid line count error")
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("socket missing", func(t *testing.T) {
+		c, _ := newClient("unix://" + filepath.Join(dir, "nope.sock"))
+		_, err := c.List()
+		if !errors.Is(err, ErrSocketMissing) || ClassifyError(err) != "not_installed" {
+			t.Errorf("expected ErrSocketMissing, got %v", err)
+		}
+	})
+
+	t.Run("daemon down", func(t *testing.T) {
+		// A socket file nobody is listening on: connection refused
+		sock := filepath.Join(dir, "stale.sock")
+		ln, err := net.Listen("unix", sock)
+		if err != nil {
+			t.Skipf("unix sockets not available: %v", err)
+		}
+		ln.(*net.UnixListener).SetUnlinkOnClose(false)
+		ln.Close()
+		c, _ := newClient("unix://" + sock)
+		_, err = c.List()
+		if !errors.Is(err, ErrDaemonDown) || ClassifyError(err) != "unavailable" {
+			t.Errorf("expected ErrDaemonDown, got %v", err)
+		}
+	})
+
+	t.Run("permission denied", func(t *testing.T) {
+		if os.Geteuid() == 0 {
+			t.Skip("root ignores socket permissions")
+		}
+		sock := filepath.Join(dir, "locked.sock")
+		ln, err := net.Listen("unix", sock)
+		if err != nil {
+			t.Skipf("unix sockets not available: %v", err)
+		}
+		defer ln.Close()
+		if err := os.Chmod(sock, 0); err != nil {
+			t.Fatal(err)
+		}
+		c, _ := newClient("unix://" + sock)
+		_, err = c.List()
+		if !errors.Is(err, ErrPermissionDenied) || ClassifyError(err) != "permission_denied" {
+			t.Errorf("expected ErrPermissionDenied, got %v", err)
+		}
+	})
+}
+
+func TestClassifyError_RemoteText(t *testing.T) {
+	// Errors from a remote homebutler arrive as text inside the SSH error.
+	remote := fmt.Errorf("[rpi] remote command failed: exit 1\n  → Output: %s: /var/run/docker.sock", ErrPermissionDenied)
+	if got := ClassifyError(remote); got != "permission_denied" {
+		t.Errorf("ClassifyError(remote) = %q, want permission_denied", got)
+	}
+	if got := ClassifyError(nil); got != "ok" {
+		t.Errorf("ClassifyError(nil) = %q, want ok", got)
+	}
+}
+
+func TestNewClient_Hosts(t *testing.T) {
+	tests := []struct {
+		host    string
+		wantErr bool
+		base    string
+	}{
+		{"unix:///var/run/docker.sock", false, "http://docker"},
+		{"tcp://10.0.0.5:2375", false, "http://10.0.0.5:2375"},
+		{"ssh://user@host", true, ""},
+		{"npipe:////./pipe/docker_engine", true, ""},
+	}
+	for _, tt := range tests {
+		c, err := newClient(tt.host)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("newClient(%q) error = %v, wantErr %v", tt.host, err, tt.wantErr)
+			continue
+		}
+		if err == nil && c.baseURL != tt.base {
+			t.Errorf("newClient(%q) baseURL = %q, want %q", tt.host, c.baseURL, tt.base)
+		}
+	}
+
+	t.Setenv("DOCKER_HOST", "")
+	c, err := NewClient()
+	if err != nil || c.socketPath != "/var/run/docker.sock" {
+		t.Errorf("default client = %+v, %v", c, err)
+	}
+}
@@ -0,0 +1,297 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Labels docker compose puts on every container it creates.
+const (
+	labelProject     = "com.docker.compose.project"
+	labelService     = "com.docker.compose.service"
+	labelWorkingDir  = "com.docker.compose.project.working_dir"
+	labelConfigFiles = "com.docker.compose.project.config_files"
+)
+
+// composeFileNames are the files docker compose looks for, in its order.
+var composeFileNames = []string{"compose.yaml", "compose.yml", "docker-compose.yml", "docker-compose.yaml"}
+
+// composeDirs are directories holding one stack per subdirectory, named
+// after the project (e.g. /opt/stacks/immich/compose.yaml). They let
+// `compose up` find a stack that has no containers to read labels from.
+var composeDirs []string
+
+// SetComposeDirs sets the stack directories from the config.
+func SetComposeDirs(dirs []string) {
+	composeDirs = dirs
+}
+
+// composeTimeout bounds a compose CLI run, so a hung pull or up can't block
+// the web and MCP handlers that wait for it. It is generous: pulls of large
+// images on a slow link take minutes.
+var composeTimeout = 15 * time.Minute
+
+// runCompose runs the runtime's compose CLI (Runtime.Compose). up, down and
+// pull need the compose file, so they go through the CLI rather than the
+// Engine API.
+var runCompose = func(command []string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), composeTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, command[0], append(command[1:], args...)...)
+	// compose's own children may hold the output open after it is killed
+	cmd.WaitDelay = 5 * time.Second
+	out, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("timed out after %s", composeTimeout)
+	}
+	return strings.TrimSpace(string(out)), err
+}
+
+// Project is a docker compose stack.
+type Project struct {
+	Name        string   `json:"name"`
+	Status      string   `json:"status"` // "running", "partial", "stopped" or "down" (no containers)
+	Running     int      `json:"running"`
+	Total       int      `json:"total"`
+	Services    []string `json:"services"`
+	WorkingDir  string   `json:"working_dir,omitempty"`
+	ConfigFiles []string `json:"config_files,omitempty"`
+}
+
+// ComposeResult holds the result of a compose action.
+type ComposeResult struct {
+	Project    string   `json:"project"`
+	Action     string   `json:"action"`
+	Status     string   `json:"status"`
+	Containers []string `json:"containers,omitempty"` // restart only
+	Output     string   `json:"output,omitempty"`     // compose CLI output
+}
+
+func Projects() ([]Project, error) {
+	c, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+	return c.Projects()
+}
+
+// Projects groups containers by their compose project label. Stacks found
+// in the configured compose dirs without any containers are listed as down.
+func (c *Client) Projects() ([]Project, error) {
+	containers, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*Project)
+	for _, ctr := range containers {
+		if ctr.Project == "" {
+			continue
+		}
+		p := byName[ctr.Project]
+		if p == nil {
+			p = &Project{Name: ctr.Project, Services: []string{}}
+			byName[ctr.Project] = p
+		}
+		p.Total++
+		if ctr.State == "running" {
+			p.Running++
+		}
+		if ctr.Service != "" && !slices.Contains(p.Services, ctr.Service) {
+			p.Services = append(p.Services, ctr.Service)
+		}
+		if p.WorkingDir == "" {
+			p.WorkingDir = ctr.Labels[labelWorkingDir]
+			p.ConfigFiles = splitConfigFiles(ctr.Labels[labelConfigFiles])
+		}
+	}
+	for _, dir := range composeDirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			if !e.IsDir() || byName[e.Name()] != nil {
+				continue
+			}
+			if file := findComposeFile(filepath.Join(dir, e.Name())); file != "" {
+				byName[e.Name()] = &Project{
+					Name:        e.Name(),
+					Services:    []string{},
+					WorkingDir:  filepath.Join(dir, e.Name()),
+					ConfigFiles: []string{file},
+				}
+			}
+		}
+	}
+
+	projects := make([]Project, 0, len(byName))
+	for _, p := range byName {
+		slices.Sort(p.Services)
+		switch {
+		case p.Total == 0:
+			p.Status = "down"
+		case p.Running == p.Total:
+			p.Status = "running"
+		case p.Running == 0:
+			p.Status = "stopped"
+		default:
+			p.Status = "partial"
+		}
+		projects = append(projects, *p)
+	}
+	slices.SortFunc(projects, func(a, b Project) int { return strings.Compare(a.Name, b.Name) })
+	return projects, nil
+}
+
+func ProjectContainers(project string) ([]Container, error) {
+	c, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+	return c.ProjectContainers(project)
+}
+
+// ProjectContainers returns the containers of one compose project, like
+// `docker compose ps -a`.
+func (c *Client) ProjectContainers(project string) ([]Container, error) {
+	if !isValidName(project) {
+		return nil, fmt.Errorf("invalid project name: %s", project)
+	}
+	containers, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+	result := []Container{}
+	for _, ctr := range containers {
+		if ctr.Project == project {
+			result = append(result, ctr)
+		}
+	}
+	return result, nil
+}
+
+func ComposeRestart(project string) (*ComposeResult, error) {
+	c, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+	return c.ComposeRestart(project)
+}
+
+// ComposeRestart restarts every container of a project through the API.
+func (c *Client) ComposeRestart(project string) (*ComposeResult, error) {
+	containers, err := c.ProjectContainers(project)
+	if err != nil {
+		return nil, err
+	}
+	if len(containers) == 0 {
+		return nil, fmt.Errorf("compose project %q has no containers", project)
+	}
+	result := &ComposeResult{Project: project, Action: "restart", Status: "ok"}
+	for _, ctr := range containers {
+		if _, err := c.Restart(ctr.Name); err != nil {
+			return nil, err
+		}
+		result.Containers = append(result.Containers, ctr.Name)
+	}
+	return result, nil
+}
+
+func ComposeUp(project string) (*ComposeResult, error) {
+	c, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+	return c.ComposeUp(project)
+}
+
+// ComposeUp runs `docker compose up -d` for a project.
+func (c *Client) ComposeUp(project string) (*ComposeResult, error) {
+	return c.composeCLI(project, "up", "up", "-d")
+}
+
+func ComposeDown(project string) (*ComposeResult, error) {
+	c, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+	return c.ComposeDown(project)
+}
+
+// ComposeDown runs `docker compose down`, removing the project's containers
+// and networks. Volumes are kept.
+func (c *Client) ComposeDown(project string) (*ComposeResult, error) {
+	return c.composeCLI(project, "down", "down")
+}
+
+func ComposePull(project string) (*ComposeResult, error) {
+	c, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+	return c.ComposePull(project)
+}
+
+// ComposePull runs `docker compose pull`. Containers keep running the old
+// images until the next up.
+func (c *Client) ComposePull(project string) (*ComposeResult, error) {
+	return c.composeCLI(project, "pull", "pull")
+}
+
+// composeCLI locates the project's compose files and runs the compose CLI
+// against them.
+func (c *Client) composeCLI(project, action string, args ...string) (*ComposeResult, error) {
+	if !isValidName(project) {
+		return nil, fmt.Errorf("invalid project name: %s", project)
+	}
+	projects, err := c.Projects()
+	if err != nil {
+		return nil, err
+	}
+	idx := slices.IndexFunc(projects, func(p Project) bool { return p.Name == project })
+	if idx < 0 {
+		return nil, fmt.Errorf("compose project %q not found (no containers, and not in compose.dirs)", project)
+	}
+	p := projects[idx]
+
+	cmdArgs := []string{"--project-name", project}
+	if p.WorkingDir != "" {
+		cmdArgs = append(cmdArgs, "--project-directory", p.WorkingDir)
+	}
+	for _, f := range p.ConfigFiles {
+		cmdArgs = append(cmdArgs, "--file", f)
+	}
+	command := c.runtime.Compose()
+	out, err := runCompose(command, append(cmdArgs, args...)...)
+	if err != nil {
+		if out == "" {
+			out = err.Error()
+		}
+		return nil, fmt.Errorf("%s %s %s failed: %s", strings.Join(command, " "), action, project, out)
+	}
+	return &ComposeResult{Project: project, Action: action, Status: "ok", Output: out}, nil
+}
+
+// splitConfigFiles splits the comma-separated config_files label.
+func splitConfigFiles(label string) []string {
+	if label == "" {
+		return nil
+	}
+	return strings.Split(label, ",")
+}
+
+func findComposeFile(dir string) string {
+	for _, name := range composeFileNames {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
@@ -0,0 +1,159 @@
+package docker
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// composeDaemon serves two containers of the "media" stack, one of them
+// exited, and one container outside of compose.
+func composeDaemon(t *testing.T, restarts *[]string) *Client {
+	t.Helper()
+	return fakeDaemon(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/containers/json":
+			fmt.Fprint(w, `[
+  {"Id": "aaaa", "Names": ["/media-jellyfin-1"], "State": "running", "Status": "Up 2 hours",
+   "Labels": {"com.docker.compose.project": "media", "com.docker.compose.service": "jellyfin",
+              "com.docker.compose.project.working_dir": "/srv/media",
+              "com.docker.compose.project.config_files": "/srv/media/compose.yaml,/srv/media/compose.override.yaml"}},
+  {"Id": "bbbb", "Names": ["/media-sonarr-1"], "State": "exited", "Status": "Exited (1) 5 minutes ago",
+   "Labels": {"com.docker.compose.project": "media", "com.docker.compose.service": "sonarr"}},
+  {"Id": "cccc", "Names": ["/portainer"], "State": "running", "Status": "Up 3 days", "Labels": {}}
+]`)
+		case strings.HasSuffix(r.URL.Path, "/restart"):
+			*restarts = append(*restarts, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			fmt.Fprint(w, `{}`)
+		}
+	}))
+}
+
+func stubCompose(t *testing.T, out string, err error) *[]string {
+	t.Helper()
+	var got []string
+	orig := runCompose
+	runCompose = func(command []string, args ...string) (string, error) {
+		got = append(slices.Clone(command), args...)
+		return out, err
+	}
+	t.Cleanup(func() { runCompose = orig })
+	return &got
+}
+
+func TestProjects(t *testing.T) {
+	dir := t.TempDir()
+	for _, stack := range []string{"media", "paperless", "notes"} {
+		os.MkdirAll(filepath.Join(dir, stack), 0755)
+	}
+	os.WriteFile(filepath.Join(dir, "paperless", "docker-compose.yml"), []byte("services: {}\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "media", "compose.yaml"), []byte("services: {}\n"), 0644)
+	SetComposeDirs([]string{dir})
+	t.Cleanup(func() { SetComposeDirs(nil) })
+
+	var restarts []string
+	c := composeDaemon(t, &restarts)
+	projects, err := c.Projects()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// notes has no compose file; media comes from labels, not the dir
+	if len(projects) != 2 {
+		t.Fatalf("expected 2 projects, got %+v", projects)
+	}
+	media, paperless := projects[0], projects[1]
+	if media.Name != "media" || media.Status != "partial" || media.Running != 1 || media.Total != 2 {
+		t.Errorf("unexpected media project: %+v", media)
+	}
+	if strings.Join(media.Services, ",") != "jellyfin,sonarr" || media.WorkingDir != "/srv/media" || len(media.ConfigFiles) != 2 {
+		t.Errorf("unexpected media details: %+v", media)
+	}
+	if paperless.Status != "down" || paperless.ConfigFiles[0] != filepath.Join(dir, "paperless", "docker-compose.yml") {
+		t.Errorf("unexpected paperless project: %+v", paperless)
+	}
+}
+
+func TestProjectContainersAndRestart(t *testing.T) {
+	var restarts []string
+	c := composeDaemon(t, &restarts)
+
+	containers, err := c.ProjectContainers("media")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(containers) != 2 || containers[0].Service != "jellyfin" || containers[1].Project != "media" {
+		t.Errorf("unexpected containers: %+v", containers)
+	}
+
+	res, err := c.ComposeRestart("media")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Containers) != 2 || len(restarts) != 2 || restarts[1] != "/containers/media-sonarr-1/restart" {
+		t.Errorf("unexpected restart: %+v, calls %v", res, restarts)
+	}
+	if _, err := c.ComposeRestart("nothing"); err == nil {
+		t.Error("expected error for a project without containers")
+	}
+	if _, err := c.ComposeRestart("media; rm -rf /"); err == nil {
+		t.Error("expected invalid project name error")
+	}
+}
+
+func TestComposeCLI(t *testing.T) {
+	var restarts []string
+	c := composeDaemon(t, &restarts)
+
+	args := stubCompose(t, "Container media-sonarr-1  Started", nil)
+	res, err := c.ComposeUp("media")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "docker compose --project-name media --project-directory /srv/media --file /srv/media/compose.yaml --file /srv/media/compose.override.yaml up -d"
+	if strings.Join(*args, " ") != want {
+		t.Errorf("compose args = %q, want %q", strings.Join(*args, " "), want)
+	}
+	if res.Action != "up" || res.Output != "Container media-sonarr-1  Started" {
+		t.Errorf("unexpected result: %+v", res)
+	}
+
+	if _, err := c.ComposeDown("ghost"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected not found error, got %v", err)
+	}
+
+	stubCompose(t, "no such service: web", fmt.Errorf("exit status 1"))
+	if _, err := c.ComposePull("media"); err == nil || !strings.Contains(err.Error(), "no such service") {
+		t.Errorf("expected compose output in error, got %v", err)
+	}
+
+	c.runtime = podmanRuntime{}
+	args = stubCompose(t, "", nil)
+	if _, err := c.ComposePull("media"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(strings.Join(*args, " "), "podman compose --project-name media") {
+		t.Errorf("podman compose args = %q", strings.Join(*args, " "))
+	}
+}
+
+func TestRunComposeTimesOut(t *testing.T) {
+	orig := composeTimeout
+	composeTimeout = 50 * time.Millisecond
+	t.Cleanup(func() { composeTimeout = orig })
+
+	start := time.Now()
+	_, err := runCompose([]string{"sleep"}, "10")
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("expected a timeout, got %v", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Errorf("a hung compose run took %s", time.Since(start))
+	}
+}
@@ -0,0 +1,450 @@
+package docker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DiskUsageInfo breaks down the disk space docker uses, like `docker system df`.
+type DiskUsageInfo struct {
+	Images           DiskUsageItem `json:"images"`
+	Containers       DiskUsageItem `json:"containers"`
+	Volumes          DiskUsageItem `json:"volumes"`
+	BuildCache       DiskUsageItem `json:"build_cache"`
+	TotalBytes       uint64        `json:"total_bytes"`
+	ReclaimableBytes uint64        `json:"reclaimable_bytes"`
+	RootDir          string        `json:"root_dir,omitempty"` // docker's data dir, e.g. /var/lib/docker
+}
+
+// DiskUsageItem is one row of DiskUsageInfo. Active counts images and volumes
+// used by a container, running containers and build cache in use.
+type DiskUsageItem struct {
+	Total            int    `json:"total"`
+	Active           int    `json:"active"`
+	SizeBytes        uint64 `json:"size_bytes"`
+	ReclaimableBytes uint64 `json:"reclaimable_bytes"`
+}
+
+// dfResponse is the subset of GET /system/df we use.
+type dfResponse struct {
+	LayersSize int64 `json:"LayersSize"`
+	Images     []struct {
+		ID         string   `json:"Id"`
+		RepoTags   []string `json:"RepoTags"`
+		Created    int64    `json:"Created"`
+		Size       int64    `json:"Size"`
+		SharedSize int64    `json:"SharedSize"` // -1 if not computed
+		Containers int64    `json:"Containers"`
+	} `json:"Images"`
+	Containers []struct {
+		ID      string   `json:"Id"`
+		Names   []string `json:"Names"`
+		Image   string   `json:"Image"`
+		ImageID string   `json:"ImageID"`
+		Created int64    `json:"Created"`
+		State   string   `json:"State"`
+		SizeRw  int64    `json:"SizeRw"`
+		Mounts  []struct {
+			Type string `json:"Type"`
+			Name string `json:"Name"`
+		} `json:"Mounts"`
+	} `json:"Containers"`
+	Volumes []struct {
+		Name      string            `json:"Name"`
+		CreatedAt time.Time         `json:"CreatedAt"`
+		Labels    map[string]string `json:"Labels"`
+		UsageData *struct {
+			Size     int64 `json:"Size"` // -1 if not computed
+			RefCount int64 `json:"RefCount"`
+		} `json:"UsageData"`
+	} `json:"Volumes"`
+	BuildCache []struct {
+		ID          string     `json:"ID"`
+		Type        string     `json:"Type"`
+		Description string     `json:"Description"`
+		InUse       bool       `json:"InUse"`
+		Shared      bool       `json:"Shared"`
+		Size        int64      `json:"Size"`
+		CreatedAt   time.Time  `json:"CreatedAt"`
+		LastUsedAt  *time.Time `json:"LastUsedAt"`
+	} `json:"BuildCache"`
+}
+
+// labelAnonymousVolume marks volumes docker created for a container's
+// VOLUME without a name (docker 23+).
+const labelAnonymousVolume = "com.docker.volume.anonymous"
+
+func DiskUsage() (*DiskUsageInfo, error) {
+	c, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+	return c.DiskUsage()
+}
+
+// DiskUsage reports the space used and reclaimable per type, computed the
+// way the docker CLI does.
+func (c *Client) DiskUsage() (*DiskUsageInfo, error) {
+	var df dfResponse
+	if err := c.getJSON("/system/df", nil, &df); err != nil {
+		return nil, err
+	}
+	du := diskUsageFromDF(&df)
+	du.RootDir = c.rootDir()
+	return du, nil
+}
+
+func diskUsageFromDF(df *dfResponse) *DiskUsageInfo {
+	du := &DiskUsageInfo{}
+
+	// Images share layers, so the total is the layer store size and only
+	// the layers unique to unused images are reclaimable.
+	var used int64
+	for _, img := range df.Images {
+		du.Images.Total++
+		if img.Containers > 0 {
+			du.Images.Active++
+			if img.SharedSize >= 0 {
+				used += img.Size - img.SharedSize
+			}
+		}
+	}
+	du.Images.SizeBytes = nonNegative(df.LayersSize)
+	du.Images.ReclaimableBytes = nonNegative(df.LayersSize - used)
+
+	for _, ctr := range df.Containers {
+		du.Containers.Total++
+		du.Containers.SizeBytes += nonNegative(ctr.SizeRw)
+		if ctr.State == "running" || ctr.State == "paused" {
+			du.Containers.Active++
+		} else {
+			du.Containers.ReclaimableBytes += nonNegative(ctr.SizeRw)
+		}
+	}
+
+	for _, v := range df.Volumes {
+		du.Volumes.Total++
+		if v.UsageData == nil {
+			continue
+		}
+		du.Volumes.SizeBytes += nonNegative(v.UsageData.Size)
+		if v.UsageData.RefCount > 0 {
+			du.Volumes.Active++
+		} else {
+			du.Volumes.ReclaimableBytes += nonNegative(v.UsageData.Size)
+		}
+	}
+
+	for _, bc := range df.BuildCache {
+		du.BuildCache.Total++
+		if bc.Shared {
+			continue
+		}
+		du.BuildCache.SizeBytes += nonNegative(bc.Size)
+		if bc.InUse {
+			du.BuildCache.Active++
+		} else {
+			du.BuildCache.ReclaimableBytes += nonNegative(bc.Size)
+		}
+	}
+
+	for _, item := range []DiskUsageItem{du.Images, du.Containers, du.Volumes, du.BuildCache} {
+		du.TotalBytes += item.SizeBytes
+		du.ReclaimableBytes += item.ReclaimableBytes
+	}
+	return du
+}
+
+// rootDir returns docker's data directory, or "" if unknown.
+func (c *Client) rootDir() string {
+	var info struct {
+		DockerRootDir string `json:"DockerRootDir"`
+	}
+	if err := c.getJSON("/info", nil, &info); err != nil {
+		return ""
+	}
+	return info.DockerRootDir
+}
+
+// LocalRootDir returns the data directory of a daemon on this machine, or
+// "" when docker is unavailable or DOCKER_HOST points elsewhere (its paths
+// would say nothing about local disks).
+func LocalRootDir() string {
+	c, err := NewClient()
+	if err != nil || c.socketPath == "" {
+		return ""
+	}
+	return c.rootDir()
+}
+
+// MountFor returns the mount point among mounts that holds path: the
+// longest one that is path or a parent of it. "" if none.
+func MountFor(path string, mounts []string) string {
+	best := ""
+	for _, m := range mounts {
+		rel, err := filepath.Rel(m, path)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, "../") {
+			continue
+		}
+		if len(m) > len(best) {
+			best = m
+		}
+	}
+	return best
+}
+
+// PruneOptions selects what Prune removes. By default that is stopped
+// containers, dangling images and unused build cache.
+type PruneOptions struct {
+	DryRun    bool
+	OlderThan time.Duration // only items created (build cache: last used) before now-OlderThan
+	All       bool          // unused tagged images and named volumes too
+	Volumes   bool          // unused volumes (anonymous ones unless All)
+}
+
+// PruneItem is something Prune removed, or would remove in a dry run.
+type PruneItem struct {
+	Type      string    `json:"type"` // "container", "image", "volume", "build_cache"
+	ID        string    `json:"id"`
+	Name      string    `json:"name,omitempty"`
+	SizeBytes uint64    `json:"size_bytes"`
+	Created   time.Time `json:"created,omitzero"`
+	Error     string    `json:"error,omitempty"` // removal failed; the rest went ahead
+}
+
+// PruneResult lists what was (or would be) removed.
+type PruneResult struct {
+	DryRun         bool        `json:"dry_run"`
+	Items          []PruneItem `json:"items"`
+	ReclaimedBytes uint64      `json:"reclaimed_bytes"` // an estimate for images and in dry runs
+}
+
+func Prune(opts PruneOptions) (*PruneResult, error) {
+	c, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+	return c.Prune(opts)
+}
+
+// Prune removes unused data. The plan comes from /system/df, and containers,
+// images and volumes are then removed one by one, so a dry run lists exactly
+// what a real run would touch. The daemon still refuses to remove anything
+// that came into use in between. Build cache goes through /build/prune.
+func (c *Client) Prune(opts PruneOptions) (*PruneResult, error) {
+	var df dfResponse
+	if err := c.getJSON("/system/df", nil, &df); err != nil {
+		return nil, err
+	}
+	items := planPrune(&df, opts, time.Now())
+	result := &PruneResult{DryRun: opts.DryRun, Items: items}
+	if opts.DryRun {
+		for _, it := range items {
+			result.ReclaimedBytes += it.SizeBytes
+		}
+		return result, nil
+	}
+
+	var buildCache []PruneItem
+	result.Items = result.Items[:0]
+	for _, it := range items {
+		if it.Type == "build_cache" {
+			buildCache = append(buildCache, it)
+			continue
+		}
+		if err := c.removeItem(it, &df); err != nil {
+			it.Error = err.Error()
+		} else {
+			result.ReclaimedBytes += it.SizeBytes
+		}
+		result.Items = append(result.Items, it)
+	}
+
+	if len(buildCache) > 0 {
+		pruned, reclaimed, err := c.pruneBuildCache(opts.OlderThan)
+		if err != nil {
+			for i := range buildCache {
+				buildCache[i].Error = err.Error()
+			}
+			result.Items = append(result.Items, buildCache...)
+		} else {
+			// Report what the daemon actually removed
+			for _, id := range pruned {
+				it := PruneItem{Type: "build_cache", ID: id}
+				if i := slices.IndexFunc(buildCache, func(p PruneItem) bool { return p.ID == id }); i >= 0 {
+					it = buildCache[i]
+				}
+				result.Items = append(result.Items, it)
+			}
+			result.ReclaimedBytes += reclaimed
+		}
+	}
+	return result, nil
+}
+
+// planPrune picks what to remove. Images and volumes only used by
+// containers that are being pruned count as unused, like `docker system
+// prune`.
+func planPrune(df *dfResponse, opts PruneOptions, now time.Time) []PruneItem {
+	cutoff := now
+	if opts.OlderThan > 0 {
+		cutoff = now.Add(-opts.OlderThan)
+	}
+	items := []PruneItem{}
+
+	prunedByImage := make(map[string]int64)
+	prunedByVolume := make(map[string]int64)
+	for _, ctr := range df.Containers {
+		created := time.Unix(ctr.Created, 0)
+		if ctr.State == "running" || ctr.State == "paused" || ctr.State == "restarting" || created.After(cutoff) {
+			continue
+		}
+		prunedByImage[ctr.ImageID]++
+		for _, m := range ctr.Mounts {
+			if m.Type == "volume" {
+				prunedByVolume[m.Name]++
+			}
+		}
+		name := ""
+		if len(ctr.Names) > 0 {
+			name = strings.TrimPrefix(ctr.Names[0], "/")
+		}
+		items = append(items, PruneItem{Type: "container", ID: shortID(ctr.ID), Name: name, SizeBytes: nonNegative(ctr.SizeRw), Created: created})
+	}
+
+	for _, img := range df.Images {
+		created := time.Unix(img.Created, 0)
+		dangling := isDangling(img.RepoTags)
+		if img.Containers-prunedByImage[img.ID] > 0 || (!dangling && !opts.All) || created.After(cutoff) {
+			continue
+		}
+		size := img.Size
+		if img.SharedSize > 0 {
+			size -= img.SharedSize
+		}
+		name := "<none>"
+		if !dangling {
+			name = strings.Join(img.RepoTags, ", ")
+		}
+		items = append(items, PruneItem{Type: "image", ID: shortID(strings.TrimPrefix(img.ID, "sha256:")), Name: name, SizeBytes: nonNegative(size), Created: created})
+	}
+
+	if opts.Volumes {
+		for _, v := range df.Volumes {
+			if v.UsageData == nil || v.UsageData.RefCount-prunedByVolume[v.Name] > 0 || v.CreatedAt.After(cutoff) {
+				continue
+			}
+			if _, anonymous := v.Labels[labelAnonymousVolume]; !anonymous && !opts.All {
+				continue
+			}
+			items = append(items, PruneItem{Type: "volume", ID: v.Name, Name: v.Name, SizeBytes: nonNegative(v.UsageData.Size), Created: v.CreatedAt})
+		}
+	}
+
+	for _, bc := range df.BuildCache {
+		lastUsed := bc.CreatedAt
+		if bc.LastUsedAt != nil {
+			lastUsed = *bc.LastUsedAt
+		}
+		if bc.InUse || lastUsed.After(cutoff) {
+			continue
+		}
+		size := nonNegative(bc.Size)
+		if bc.Shared {
+			size = 0 // its layers stay with the images sharing them
+		}
+		items = append(items, PruneItem{Type: "build_cache", ID: bc.ID, Name: bc.Description, SizeBytes: size, Created: bc.CreatedAt})
+	}
+	return items
+}
+
+// removeItem removes one planned container, image or volume.
+func (c *Client) removeItem(it PruneItem, df *dfResponse) error {
+	switch it.Type {
+	case "container":
+		return c.delete("/containers/"+url.PathEscape(it.ID), nil)
+	case "volume":
+		return c.delete("/volumes/"+url.PathEscape(it.ID), nil)
+	case "image":
+		// Untag tagged images one reference at a time, without force, so
+		// an image a new container just started using stays.
+		for _, img := range df.Images {
+			if shortID(strings.TrimPrefix(img.ID, "sha256:")) != it.ID {
+				continue
+			}
+			if isDangling(img.RepoTags) {
+				return c.delete("/images/"+img.ID, nil)
+			}
+			for _, tag := range img.RepoTags {
+				if err := c.delete("/images/"+tag, nil); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown %s %s", it.Type, it.ID)
+}
+
+func (c *Client) delete(path string, query url.Values) error {
+	resp, err := c.do(http.MethodDelete, path, query)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// pruneBuildCache removes unused build cache, optionally only entries not
+// used for a while.
+func (c *Client) pruneBuildCache(olderThan time.Duration) ([]string, uint64, error) {
+	query := url.Values{}
+	if olderThan > 0 {
+		filters, _ := json.Marshal(map[string][]string{"until": {olderThan.String()}})
+		query.Set("filters", string(filters))
+	}
+	resp, err := c.do(http.MethodPost, "/build/prune", query)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	var out struct {
+		CachesDeleted  []string `json:"CachesDeleted"`
+		SpaceReclaimed uint64   `json:"SpaceReclaimed"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, 0, err
+	}
+	return out.CachesDeleted, out.SpaceReclaimed, nil
+}
+
+// ParseAge parses an age like "7d", "12h" or "90m".
+func ParseAge(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err == nil && n > 0 {
+			return time.Duration(n) * 24 * time.Hour, nil
+		}
+	} else if d, err := time.ParseDuration(s); err == nil && d > 0 {
+		return d, nil
+	}
+	return 0, fmt.Errorf("invalid age %q (use e.g. 7d, 12h or 90m)", s)
+}
+
+func isDangling(tags []string) bool {
+	return len(tags) == 0 || (len(tags) == 1 && tags[0] == "<none>:<none>")
+}
+
+func nonNegative(n int64) uint64 {
+	if n < 0 {
+		return 0
+	}
+	return uint64(n)
+}
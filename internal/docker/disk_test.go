@@ -0,0 +1,203 @@
+package docker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// now is 2023-11-14T22:13:20Z; "old" things are from 2023-11-01
+const dfJSON = `{
+  "LayersSize": 1000,
+  "Images": [
+    {"Id": "sha256:aaaaaaaaaaaa0001", "RepoTags": ["nginx:1.25"], "Created": 1698796800, "Size": 300, "SharedSize": 100, "Containers": 1},
+    {"Id": "sha256:bbbbbbbbbbbb0002", "RepoTags": [], "Created": 1698796800, "Size": 200, "SharedSize": 0, "Containers": 0},
+    {"Id": "sha256:cccccccccccc0003", "RepoTags": ["old/app:1", "old/app:latest"], "Created": 1698796800, "Size": 400, "SharedSize": 100, "Containers": 1},
+    {"Id": "sha256:dddddddddddd0004", "RepoTags": ["<none>:<none>"], "Created": 1699990000, "Size": 50, "SharedSize": 0, "Containers": 0}
+  ],
+  "Containers": [
+    {"Id": "1111111111111111", "Names": ["/web"], "ImageID": "sha256:aaaaaaaaaaaa0001", "Created": 1698796800, "State": "running", "SizeRw": 10},
+    {"Id": "2222222222222222", "Names": ["/oneshot"], "ImageID": "sha256:cccccccccccc0003", "Created": 1698796800, "State": "exited", "SizeRw": 30,
+     "Mounts": [{"Type": "volume", "Name": "f00dcafe"}, {"Type": "bind", "Name": ""}]}
+  ],
+  "Volumes": [
+    {"Name": "f00dcafe", "CreatedAt": "2023-11-01T00:00:00Z", "Labels": {"com.docker.volume.anonymous": ""}, "UsageData": {"Size": 70, "RefCount": 1}},
+    {"Name": "pgdata", "CreatedAt": "2023-11-01T00:00:00Z", "Labels": null, "UsageData": {"Size": 500, "RefCount": 0}},
+    {"Name": "webdata", "CreatedAt": "2023-11-01T00:00:00Z", "Labels": null, "UsageData": {"Size": 5, "RefCount": 1}}
+  ],
+  "BuildCache": [
+    {"ID": "cache1", "Type": "regular", "Description": "RUN apt-get install", "InUse": false, "Shared": false, "Size": 80, "CreatedAt": "2023-11-01T00:00:00Z", "LastUsedAt": "2023-11-14T20:00:00Z"},
+    {"ID": "cache2", "Type": "regular", "InUse": true, "Shared": false, "Size": 20, "CreatedAt": "2023-11-01T00:00:00Z"},
+    {"ID": "cache3", "Type": "regular", "InUse": false, "Shared": true, "Size": 40, "CreatedAt": "2023-11-01T00:00:00Z"}
+  ]
+}`
+
+var dfNow = time.Unix(1700000000, 0)
+
+func loadDF(t *testing.T) *dfResponse {
+	t.Helper()
+	var df dfResponse
+	if err := json.Unmarshal([]byte(dfJSON), &df); err != nil {
+		t.Fatal(err)
+	}
+	return &df
+}
+
+func TestDiskUsageFromDF(t *testing.T) {
+	du := diskUsageFromDF(loadDF(t))
+	want := DiskUsageInfo{
+		// Images in use keep 200 + 300 unique bytes
+		Images:           DiskUsageItem{Total: 4, Active: 2, SizeBytes: 1000, ReclaimableBytes: 500},
+		Containers:       DiskUsageItem{Total: 2, Active: 1, SizeBytes: 40, ReclaimableBytes: 30},
+		Volumes:          DiskUsageItem{Total: 3, Active: 2, SizeBytes: 575, ReclaimableBytes: 500},
+		BuildCache:       DiskUsageItem{Total: 3, Active: 1, SizeBytes: 100, ReclaimableBytes: 80},
+		TotalBytes:       1715,
+		ReclaimableBytes: 1110,
+	}
+	if *du != want {
+		t.Errorf("diskUsageFromDF() =\n  %+v\nwant\n  %+v", *du, want)
+	}
+}
+
+func planned(items []PruneItem) []string {
+	var out []string
+	for _, it := range items {
+		out = append(out, it.Type+":"+it.Name)
+	}
+	return out
+}
+
+func TestPlanPrune(t *testing.T) {
+	df := loadDF(t)
+	tests := []struct {
+		name string
+		opts PruneOptions
+		want []string
+	}{
+		{"defaults", PruneOptions{}, []string{
+			"container:oneshot", "image:<none>", "image:<none>",
+			"build_cache:RUN apt-get install", "build_cache:",
+		}},
+		// The stopped container was the only user of old/app and the anonymous volume
+		{"all with volumes", PruneOptions{All: true, Volumes: true}, []string{
+			"container:oneshot", "image:<none>", "image:old/app:1, old/app:latest", "image:<none>",
+			"volume:f00dcafe", "volume:pgdata",
+			"build_cache:RUN apt-get install", "build_cache:",
+		}},
+		{"anonymous volumes only", PruneOptions{Volumes: true}, []string{
+			"container:oneshot", "image:<none>", "image:<none>", "volume:f00dcafe",
+			"build_cache:RUN apt-get install", "build_cache:",
+		}},
+		// Skips the image built an hour ago and the cache used two hours ago
+		{"older than a day", PruneOptions{OlderThan: 24 * time.Hour}, []string{
+			"container:oneshot", "image:<none>", "build_cache:",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := planned(planPrune(df, tt.opts, dfNow))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("planPrune() = %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+
+	// Shared cache is removed but frees nothing on its own
+	for _, it := range planPrune(df, PruneOptions{}, dfNow) {
+		if it.ID == "cache3" && it.SizeBytes != 0 {
+			t.Errorf("shared build cache should count 0 bytes, got %d", it.SizeBytes)
+		}
+	}
+}
+
+func TestClientPrune(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	c := fakeDaemon(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.URL.Path == "/system/df":
+			fmt.Fprint(w, dfJSON)
+			return
+		case r.URL.Path == "/build/prune":
+			if !strings.Contains(r.URL.Query().Get("filters"), `"until":["24h0m0s"]`) {
+				t.Errorf("expected until filter, got %q", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `{"CachesDeleted": ["cache3"], "SpaceReclaimed": 40}`)
+		case r.URL.Path == "/images/old/app:latest":
+			// Someone started using it again
+			w.WriteHeader(http.StatusConflict)
+			fmt.Fprint(w, `{"message": "image is being used by running container"}`)
+		}
+		calls = append(calls, r.Method+" "+r.URL.Path)
+	}))
+
+	dry, err := c.Prune(PruneOptions{DryRun: true, All: true, OlderThan: 24 * time.Hour})
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if len(calls) != 0 {
+		t.Fatalf("dry run must not change anything, got %v", calls)
+	}
+	// Everything in the fixture is older than a day by now
+	if !dry.DryRun || len(dry.Items) != 6 || dry.ReclaimedBytes != 30+200+300+50+80 {
+		t.Errorf("unexpected dry run: %+v", dry)
+	}
+
+	res, err := c.Prune(PruneOptions{All: true, OlderThan: 24 * time.Hour})
+	if err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	wantCalls := []string{
+		"DELETE /containers/222222222222",
+		"DELETE /images/sha256:bbbbbbbbbbbb0002",
+		"DELETE /images/old/app:1",
+		"DELETE /images/old/app:latest",
+		"DELETE /images/sha256:dddddddddddd0004",
+		"POST /build/prune",
+	}
+	if !slices.Equal(calls, wantCalls) {
+		t.Errorf("calls = %q\nwant %q", calls, wantCalls)
+	}
+	// The build cache entry is what the daemon reported, not the plan
+	if len(res.Items) != 5 || res.Items[2].Error == "" || res.Items[4].ID != "cache3" || res.ReclaimedBytes != 30+200+50+40 {
+		t.Errorf("unexpected result: %+v", res)
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	for in, want := range map[string]time.Duration{"7d": 7 * 24 * time.Hour, "12h": 12 * time.Hour, "90m": 90 * time.Minute} {
+		if got, err := ParseAge(in); err != nil || got != want {
+			t.Errorf("ParseAge(%q) = %v, %v", in, got, err)
+		}
+	}
+	for _, in := range []string{"", "d", "-1d", "0h", "week"} {
+		if _, err := ParseAge(in); err == nil {
+			t.Errorf("ParseAge(%q) should fail", in)
+		}
+	}
+}
+
+func TestMountFor(t *testing.T) {
+	mounts := []string{"/", "/var", "/var/lib/docker2", "/home"}
+	tests := map[string]string{
+		"/var/lib/docker": "/var",
+		"/home/me/docker": "/home",
+		"/opt/docker":     "/",
+		"/var":            "/var",
+	}
+	for path, want := range tests {
+		if got := MountFor(path, mounts); got != want {
+			t.Errorf("MountFor(%q) = %q, want %q", path, got, want)
+		}
+	}
+	if got := MountFor("/data", []string{"/home"}); got != "" {
+		t.Errorf("expected no mount, got %q", got)
+	}
+}
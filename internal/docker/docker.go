@@ -1,109 +1,393 @@
 package docker
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
-
-	"github.com/Higangssh/homebutler/internal/util"
+	"sync"
+	"time"
 )
 
 type Container struct {
-	ID     string `json:"id"`
-	Name   string `json:"name"`
-	Image  string `json:"image"`
-	Status string `json:"status"`
-	State  string `json:"state"`
-	Ports  string `json:"ports"`
+	ID           string            `json:"id"` // short (12 chars), as docker ps shows it
+	FullID       string            `json:"full_id"`
+	Name         string            `json:"name"`
+	Image        string            `json:"image"`
+	Status       string            `json:"status"`
+	State        string            `json:"state"`
+	Health       string            `json:"health,omitempty"` // "healthy", "unhealthy", "starting"; empty without a healthcheck
+	RestartCount int               `json:"restart_count"`
+	Created      time.Time         `json:"created"`
+	StartedAt    time.Time         `json:"started_at,omitzero"`
+	Ports        PortList          `json:"ports"`
+	Labels       map[string]string `json:"labels,omitempty"`
+	Project      string            `json:"project,omitempty"` // compose project, from labels
+	Service      string            `json:"service,omitempty"` // compose service, from labels
 }
 
-func List() ([]Container, error) {
-	// Check if docker binary exists
-	if _, lookErr := util.RunCmd("which", "docker"); lookErr != nil {
-		return nil, fmt.Errorf("docker is not installed (binary not found in PATH)")
+// Port is a published or exposed container port.
+type Port struct {
+	IP          string `json:"ip,omitempty"`
+	PrivatePort int    `json:"private_port"`
+	PublicPort  int    `json:"public_port,omitempty"` // 0 if only exposed
+	Type        string `json:"type"`
+}
+
+// String formats a port like docker ps: "0.0.0.0:80->80/tcp" or "5432/tcp".
+func (p Port) String() string {
+	if p.PublicPort == 0 {
+		return fmt.Sprintf("%d/%s", p.PrivatePort, p.Type)
+	}
+	return fmt.Sprintf("%s:%d->%d/%s", p.IP, p.PublicPort, p.PrivatePort, p.Type)
+}
+
+// PortList is the ports of a container. Besides a JSON array it decodes the
+// docker ps string older homebutler versions report, like
+// "0.0.0.0:80->80/tcp, 5432/tcp", so --server works against them.
+type PortList []Port
+
+func (l *PortList) UnmarshalJSON(data []byte) error {
+	var s string
+	if json.Unmarshal(data, &s) != nil {
+		return json.Unmarshal(data, (*[]Port)(l))
 	}
+	ports := PortList{}
+	for _, f := range strings.Split(s, ",") {
+		if f = strings.TrimSpace(f); f == "" {
+			continue
+		}
+		p, err := parsePort(f)
+		if err != nil {
+			return err
+		}
+		ports = append(ports, p...)
+	}
+	*l = ports
+	return nil
+}
 
-	out, err := util.RunCmd("docker", "ps", "-a",
-		"--format", "{{.ID}}\t{{.Names}}\t{{.Image}}\t{{.Status}}\t{{.State}}\t{{.Ports}}")
+// parsePort parses one docker ps port, expanding a range like
+// "0.0.0.0:8000-8001->8000-8001/tcp".
+func parsePort(s string) ([]Port, error) {
+	spec, typ, ok := strings.Cut(s, "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid port %q", s)
+	}
+	var ip, public string
+	private := spec
+	if host, priv, ok := strings.Cut(spec, "->"); ok {
+		private = priv
+		i := strings.LastIndex(host, ":")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid port %q", s)
+		}
+		ip, public = strings.Trim(host[:i], "[]"), host[i+1:]
+	}
+	privLo, privHi, err := portRange(private)
 	if err != nil {
-		return nil, fmt.Errorf("docker daemon is not running: %w", err)
+		return nil, fmt.Errorf("invalid port %q", s)
+	}
+	pubLo := 0
+	if public != "" {
+		if pubLo, _, err = portRange(public); err != nil {
+			return nil, fmt.Errorf("invalid port %q", s)
+		}
 	}
+	var ports []Port
+	for n := 0; n <= privHi-privLo; n++ {
+		p := Port{IP: ip, PrivatePort: privLo + n, Type: typ}
+		if pubLo > 0 {
+			p.PublicPort = pubLo + n
+		}
+		ports = append(ports, p)
+	}
+	return ports, nil
+}
 
-	containers := make([]Container, 0)
-	for _, line := range splitLines(out) {
-		if line == "" {
-			continue
+func portRange(s string) (lo, hi int, err error) {
+	a, b, isRange := strings.Cut(s, "-")
+	if lo, err = strconv.Atoi(a); err != nil {
+		return 0, 0, err
+	}
+	hi = lo
+	if isRange {
+		if hi, err = strconv.Atoi(b); err != nil {
+			return 0, 0, err
 		}
-		fields := splitTabs(line)
-		if len(fields) < 5 {
-			continue
+	}
+	if lo < 0 || hi < lo || hi > 65535 {
+		return 0, 0, fmt.Errorf("invalid range %q", s)
+	}
+	return lo, hi, nil
+}
+
+// containerSummary is an entry from GET /containers/json.
+type containerSummary struct {
+	ID      string            `json:"Id"`
+	Names   []string          `json:"Names"`
+	Image   string            `json:"Image"`
+	Created int64             `json:"Created"`
+	Ports   []apiPort         `json:"Ports"`
+	Labels  map[string]string `json:"Labels"`
+	State   string            `json:"State"`
+	Status  string            `json:"Status"`
+}
+
+type apiPort struct {
+	IP          string `json:"IP"`
+	PrivatePort int    `json:"PrivatePort"`
+	PublicPort  int    `json:"PublicPort"`
+	Type        string `json:"Type"`
+}
+
+// containerInspect is the subset of GET /containers/{id}/json we use.
+type containerInspect struct {
+	Image        string `json:"Image"` // image ID
+	RestartCount int    `json:"RestartCount"`
+	State        struct {
+		StartedAt time.Time `json:"StartedAt"`
+		Health    *struct {
+			Status string `json:"Status"`
+		} `json:"Health"`
+	} `json:"State"`
+	Config struct {
+		Tty   bool   `json:"Tty"`
+		Image string `json:"Image"` // reference as given to docker run / compose
+	} `json:"Config"`
+}
+
+func List() ([]Container, error) {
+	c, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+	return c.List()
+}
+
+// maxInspects bounds the container inspects List runs at once.
+const maxInspects = 8
+
+// List returns all containers, running or not. Health comes from the list's
+// status; restart count and start time need a per-container inspect, since
+// the list endpoint doesn't carry them, so those run in parallel.
+func (c *Client) List() ([]Container, error) {
+	var summaries []containerSummary
+	if err := c.getJSON("/containers/json", url.Values{"all": {"1"}}, &summaries); err != nil {
+		return nil, err
+	}
+
+	containers := make([]Container, len(summaries))
+	sem := make(chan struct{}, maxInspects)
+	var wg sync.WaitGroup
+	for i, s := range summaries {
+		ctr := &containers[i]
+		*ctr = Container{
+			ID:      shortID(s.ID),
+			FullID:  s.ID,
+			Image:   s.Image,
+			State:   s.State,
+			Status:  friendlyStatus(healthSuffixRe.ReplaceAllString(s.Status, ""), s.State),
+			Health:  statusHealth(s.Status),
+			Created: time.Unix(s.Created, 0).UTC(),
+			Ports:   make([]Port, 0, len(s.Ports)),
+			Labels:  s.Labels,
+			Project: s.Labels[labelProject],
+			Service: s.Labels[labelService],
 		}
-		c := Container{
-			ID:     fields[0][:12],
-			Name:   fields[1],
-			Image:  fields[2],
-			Status: friendlyStatus(fields[3], fields[4]),
-			State:  fields[4],
+		if len(s.Names) > 0 {
+			ctr.Name = strings.TrimPrefix(s.Names[0], "/")
 		}
-		if len(fields) > 5 {
-			c.Ports = fields[5]
+		for _, p := range s.Ports {
+			ctr.Ports = append(ctr.Ports, Port(p))
 		}
-		containers = append(containers, c)
+		sem <- struct{}{}
+		wg.Go(func() {
+			defer func() { <-sem }()
+			// The container may be gone by now; keep what the list gave us.
+			if info, err := c.inspect(s.ID); err == nil {
+				ctr.RestartCount = info.RestartCount
+				if !info.State.StartedAt.IsZero() && info.State.StartedAt.Year() > 1 {
+					ctr.StartedAt = info.State.StartedAt.UTC()
+				}
+				if info.State.Health != nil {
+					ctr.Health = info.State.Health.Status
+				}
+			}
+		})
 	}
+	wg.Wait()
 	return containers, nil
 }
 
+func (c *Client) inspect(id string) (*containerInspect, error) {
+	var info containerInspect
+	if err := c.getJSON("/containers/"+url.PathEscape(id)+"/json", nil, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
 // ActionResult holds the result of a docker action.
 type ActionResult struct {
 	Action    string `json:"action"`
 	Container string `json:"container"`
 	Status    string `json:"status"`
+	Signal    string `json:"signal,omitempty"` // kill only
 }
 
 func Restart(name string) (*ActionResult, error) {
-	if !isValidName(name) {
-		return nil, fmt.Errorf("invalid container name: %s", name)
-	}
-	out, err := util.RunCmd("docker", "restart", name)
+	c, err := NewClient()
 	if err != nil {
-		return nil, fmt.Errorf("failed to restart %s: %s", name, out)
+		return nil, err
 	}
-	return &ActionResult{Action: "restart", Container: name, Status: "ok"}, nil
+	return c.Restart(name)
+}
+
+func (c *Client) Restart(name string) (*ActionResult, error) {
+	return c.action(name, "restart", "restart", nil)
 }
 
 func Stop(name string) (*ActionResult, error) {
-	if !isValidName(name) {
-		return nil, fmt.Errorf("invalid container name: %s", name)
+	c, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+	return c.Stop(name)
+}
+
+// Stop stops a container. Stopping an already stopped container (HTTP 304)
+// is not an error.
+func (c *Client) Stop(name string) (*ActionResult, error) {
+	return c.action(name, "stop", "stop", nil)
+}
+
+func Start(name string) (*ActionResult, error) {
+	c, err := NewClient()
+	if err != nil {
+		return nil, err
 	}
-	out, err := util.RunCmd("docker", "stop", name)
+	return c.Start(name)
+}
+
+// Start starts a stopped container. Starting a running one (HTTP 304) is
+// not an error.
+func (c *Client) Start(name string) (*ActionResult, error) {
+	return c.action(name, "start", "start", nil)
+}
+
+func Pause(name string) (*ActionResult, error) {
+	c, err := NewClient()
 	if err != nil {
-		return nil, fmt.Errorf("failed to stop %s: %s", name, out)
+		return nil, err
 	}
-	return &ActionResult{Action: "stop", Container: name, Status: "ok"}, nil
+	return c.Pause(name)
 }
 
-// LogsResult holds docker logs output.
-type LogsResult struct {
-	Container string `json:"container"`
-	Lines     string `json:"lines"`
-	Logs      string `json:"logs"`
+func (c *Client) Pause(name string) (*ActionResult, error) {
+	return c.action(name, "pause", "pause", nil)
+}
+
+func Unpause(name string) (*ActionResult, error) {
+	c, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+	return c.Unpause(name)
+}
+
+func (c *Client) Unpause(name string) (*ActionResult, error) {
+	return c.action(name, "unpause", "unpause", nil)
+}
+
+func Kill(name, signal string) (*ActionResult, error) {
+	c, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+	return c.Kill(name, signal)
+}
+
+// Kill sends a signal to the container's main process. An empty signal
+// means SIGKILL, like `docker kill`.
+func (c *Client) Kill(name, signal string) (*ActionResult, error) {
+	signal = strings.ToUpper(signal)
+	if signal == "" {
+		signal = "SIGKILL"
+	}
+	if err := ValidateSignal(signal); err != nil {
+		return nil, err
+	}
+	result, err := c.action(name, "kill", "kill", url.Values{"signal": {signal}})
+	if err != nil {
+		return nil, err
+	}
+	result.Signal = signal
+	return result, nil
 }
 
-func Logs(name string, lines string) (*LogsResult, error) {
+func Remove(name string, force bool) (*ActionResult, error) {
+	c, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+	return c.Remove(name, force)
+}
+
+// Remove deletes a container. Without force the daemon refuses to remove a
+// running container.
+func (c *Client) Remove(name string, force bool) (*ActionResult, error) {
 	if !isValidName(name) {
 		return nil, fmt.Errorf("invalid container name: %s", name)
 	}
-	// Validate lines is a positive integer
-	for _, c := range lines {
-		if c < '0' || c > '9' {
-			return nil, fmt.Errorf("invalid line count: %s (must be a positive integer)", lines)
-		}
+	var query url.Values
+	if force {
+		query = url.Values{"force": {"1"}}
 	}
-	out, err := util.RunCmd("docker", "logs", "--tail", lines, name)
+	resp, err := c.do(http.MethodDelete, "/containers/"+name, query)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get logs for %s: %w", name, err)
+		return nil, fmt.Errorf("failed to remove %s: %w", name, err)
+	}
+	resp.Body.Close()
+	return &ActionResult{Action: "rm", Container: name, Status: "ok"}, nil
+}
+
+// action validates name and POSTs to /containers/{name}/{endpoint}.
+func (c *Client) action(name, action, endpoint string, query url.Values) (*ActionResult, error) {
+	if !isValidName(name) {
+		return nil, fmt.Errorf("invalid container name: %s", name)
 	}
-	return &LogsResult{Container: name, Lines: lines, Logs: out}, nil
+	if err := c.post("/containers/"+name+"/"+endpoint, query); err != nil {
+		return nil, fmt.Errorf("failed to %s %s: %w", action, name, err)
+	}
+	return &ActionResult{Action: action, Container: name, Status: "ok"}, nil
+}
+
+// healthSuffixRe matches the " (healthy)" suffix docker appends to the
+// status; health is reported separately.
+var healthSuffixRe = regexp.MustCompile(`\s*\((healthy|unhealthy|health: (starting))\)$`)
+
+// statusHealth reads the health from a list status: "healthy",
+// "unhealthy", "starting", or empty without a healthcheck.
+func statusHealth(status string) string {
+	m := healthSuffixRe.FindStringSubmatch(status)
+	switch {
+	case m == nil:
+		return ""
+	case m[2] != "":
+		return m[2]
+	}
+	return m[1]
+}
+
+func shortID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
 }
 
 var exitedRe = regexp.MustCompile(`(?i)exited\s*\(\d+\)\s*(.+)\s*ago`)
@@ -141,6 +425,23 @@ func shortenDuration(s string) string {
 	return s
 }
 
+// ValidateName checks a container name before it is sent to the API or,
+// for --server requests, put on a remote command line.
+func ValidateName(name string) error {
+	if !isValidName(name) {
+		return fmt.Errorf("invalid container name: %s", name)
+	}
+	return nil
+}
+
+// ValidateSignal is ValidateName for docker kill signals.
+func ValidateSignal(sig string) error {
+	if !isValidSignal(strings.ToUpper(sig)) {
+		return fmt.Errorf("invalid signal: %s", sig)
+	}
+	return nil
+}
+
 // isValidName prevents command injection by allowing only safe characters.
 func isValidName(name string) bool {
 	for _, c := range name {
@@ -152,23 +453,13 @@ func isValidName(name string) bool {
 	return len(name) > 0 && len(name) <= 128
 }
 
-func splitLines(s string) []string {
-	return split(s, '\n')
-}
-
-func splitTabs(s string) []string {
-	return split(s, '\t')
-}
-
-func split(s string, sep byte) []string {
-	var result []string
-	start := 0
-	for i := 0; i < len(s); i++ {
-		if s[i] == sep {
-			result = append(result, s[start:i])
-			start = i + 1
+// isValidSignal accepts signal names ("SIGHUP", "HUP", "SIGRTMIN+3") and
+// numbers ("9"), which is what the kill endpoint understands.
+func isValidSignal(sig string) bool {
+	for _, c := range sig {
+		if !((c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '+') { //nolint:staticcheck // readability
+			return false
 		}
 	}
-	result = append(result, s[start:])
-	return result
+	return len(sig) > 0 && len(sig) <= 16
 }
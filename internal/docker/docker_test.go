@@ -1,6 +1,10 @@
 package docker
 
-import "testing"
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
 
 func TestIsValidName(t *testing.T) {
 	tests := []struct {
@@ -36,25 +40,6 @@ func TestIsValidName(t *testing.T) {
 	}
 }
 
-func TestSplitLines(t *testing.T) {
-	input := "line1\nline2\nline3"
-	lines := splitLines(input)
-	if len(lines) != 3 {
-		t.Errorf("expected 3 lines, got %d", len(lines))
-	}
-}
-
-func TestSplitTabs(t *testing.T) {
-	input := "a\tb\tc"
-	parts := splitTabs(input)
-	if len(parts) != 3 {
-		t.Errorf("expected 3 parts, got %d", len(parts))
-	}
-	if parts[0] != "a" || parts[1] != "b" || parts[2] != "c" {
-		t.Errorf("unexpected parts: %v", parts)
-	}
-}
-
 func TestFriendlyStatus(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -116,53 +101,6 @@ func TestShortenDuration(t *testing.T) {
 	}
 }
 
-func TestSplitLinesEdgeCases(t *testing.T) {
-	// Empty string should return single empty element
-	lines := splitLines("")
-	if len(lines) != 1 || lines[0] != "" {
-		t.Errorf("splitLines(\"\") = %v, want [\"\"]", lines)
-	}
-
-	// Single line (no newline)
-	lines = splitLines("hello")
-	if len(lines) != 1 || lines[0] != "hello" {
-		t.Errorf("splitLines(\"hello\") = %v", lines)
-	}
-
-	// Trailing newline
-	lines = splitLines("a\nb\n")
-	if len(lines) != 3 {
-		t.Errorf("expected 3 elements, got %d: %v", len(lines), lines)
-	}
-}
-
-func TestSplitTabsEdgeCases(t *testing.T) {
-	// Single field (no tabs)
-	parts := splitTabs("single")
-	if len(parts) != 1 || parts[0] != "single" {
-		t.Errorf("splitTabs(\"single\") = %v", parts)
-	}
-
-	// Empty field between tabs
-	parts = splitTabs("a\t\tc")
-	if len(parts) != 3 {
-		t.Errorf("expected 3 parts, got %d: %v", len(parts), parts)
-	}
-	if parts[1] != "" {
-		t.Errorf("middle part = %q, want empty", parts[1])
-	}
-}
-
-func TestSplit(t *testing.T) {
-	result := split("a,b,c", ',')
-	if len(result) != 3 {
-		t.Errorf("expected 3 parts, got %d", len(result))
-	}
-	if result[0] != "a" || result[1] != "b" || result[2] != "c" {
-		t.Errorf("unexpected result: %v", result)
-	}
-}
-
 func TestIsValidNameMaxLength(t *testing.T) {
 	// Exactly 128 valid characters should be valid
 	name := make([]byte, 128)
@@ -190,11 +128,59 @@ func TestContainerStruct(t *testing.T) {
 		Image:  "nginx:1.25",
 		Status: "Running · 4d",
 		State:  "running",
-		Ports:  "0.0.0.0:80->80/tcp",
+		Ports:  []Port{{IP: "0.0.0.0", PrivatePort: 80, PublicPort: 80, Type: "tcp"}},
 	}
 	if c.Name != "nginx" {
 		t.Errorf("Name = %q, want %q", c.Name, "nginx")
 	}
+	if got := c.Ports[0].String(); got != "0.0.0.0:80->80/tcp" {
+		t.Errorf("Port.String() = %q, want %q", got, "0.0.0.0:80->80/tcp")
+	}
+	if got := (Port{PrivatePort: 5432, Type: "tcp"}).String(); got != "5432/tcp" {
+		t.Errorf("exposed Port.String() = %q, want %q", got, "5432/tcp")
+	}
+}
+
+func TestPortListDecodesOldString(t *testing.T) {
+	var c Container
+	old := `{"name": "web", "ports": "0.0.0.0:8080->80/tcp, :::8080->80/tcp, 5432/tcp, 0.0.0.0:9000-9001->9000-9001/udp"}`
+	if err := json.Unmarshal([]byte(old), &c); err != nil {
+		t.Fatalf("decoding the old string form: %v", err)
+	}
+	want := PortList{
+		{IP: "0.0.0.0", PrivatePort: 80, PublicPort: 8080, Type: "tcp"},
+		{IP: "::", PrivatePort: 80, PublicPort: 8080, Type: "tcp"},
+		{PrivatePort: 5432, Type: "tcp"},
+		{IP: "0.0.0.0", PrivatePort: 9000, PublicPort: 9000, Type: "udp"},
+		{IP: "0.0.0.0", PrivatePort: 9001, PublicPort: 9001, Type: "udp"},
+	}
+	if !slices.Equal(c.Ports, want) {
+		t.Errorf("ports = %+v, want %+v", c.Ports, want)
+	}
+
+	if err := json.Unmarshal([]byte(`{"ports": ""}`), &c); err != nil || c.Ports == nil || len(c.Ports) != 0 {
+		t.Errorf("empty string should be no ports, got %+v, %v", c.Ports, err)
+	}
+	if err := json.Unmarshal([]byte(`{"ports": [{"private_port": 53, "type": "udp"}]}`), &c); err != nil || !slices.Equal(c.Ports, PortList{{PrivatePort: 53, Type: "udp"}}) {
+		t.Errorf("array form: %+v, %v", c.Ports, err)
+	}
+	if err := json.Unmarshal([]byte(`{"ports": "80->"}`), &c); err == nil {
+		t.Error("expected an error for a malformed port")
+	}
+}
+
+func TestStatusHealth(t *testing.T) {
+	for status, want := range map[string]string{
+		"Up 4 days (healthy)":             "healthy",
+		"Up 2 minutes (unhealthy)":        "unhealthy",
+		"Up 3 seconds (health: starting)": "starting",
+		"Up 4 days":                       "",
+		"Exited (0) 6 hours ago":          "",
+	} {
+		if got := statusHealth(status); got != want {
+			t.Errorf("statusHealth(%q) = %q, want %q", status, got, want)
+		}
+	}
 }
 
 func TestActionResultStruct(t *testing.T) {
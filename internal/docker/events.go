@@ -0,0 +1,79 @@
+package docker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// event is the part of an Engine API event Exits needs.
+type event struct {
+	Action string `json:"Action"`
+	Actor  struct {
+		ID         string            `json:"ID"`
+		Attributes map[string]string `json:"Attributes"`
+	} `json:"Actor"`
+}
+
+// Exits counts the crashes of each local container, by name, since the
+// given time; the restart-loop alert passes now minus its restart_window.
+// A crash is an exit with a non-zero code that no docker stop, kill or
+// restart asked for. See Client.Exits.
+func Exits(since time.Time) (map[string]int, error) {
+	c, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+	return c.Exits(since)
+}
+
+// Exits counts container crashes per container name since the given time:
+// "die" events with a non-zero exit code. A die that follows a "kill" or
+// "stop" of the same container since it last started, as on docker stop
+// or docker restart, was asked for and doesn't count. The daemon keeps
+// recent events in memory, so this needs no state of its own; exits from
+// before a daemon restart are not counted.
+func (c *Client) Exits(since time.Time) (map[string]int, error) {
+	filters, _ := json.Marshal(map[string][]string{"type": {"container"}, "event": {"die", "kill", "start", "stop"}})
+	query := url.Values{
+		"since":   {strconv.FormatInt(since.Unix(), 10)},
+		"until":   {strconv.FormatInt(time.Now().Unix(), 10)},
+		"filters": {string(filters)},
+	}
+	resp, err := c.do(http.MethodGet, "/events", query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read container events: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// The events endpoint streams one JSON object after another and ends
+	// at "until".
+	exits := map[string]int{}
+	stopping := map[string]bool{} // by container ID
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var e event
+		if err := dec.Decode(&e); err != nil {
+			if errors.Is(err, io.EOF) {
+				return exits, nil
+			}
+			return nil, fmt.Errorf("failed to read container events: %w", err)
+		}
+		switch e.Action {
+		case "kill", "stop":
+			stopping[e.Actor.ID] = true
+		case "start":
+			delete(stopping, e.Actor.ID)
+		case "die":
+			code := e.Actor.Attributes["exitCode"]
+			if name := e.Actor.Attributes["name"]; name != "" && code != "" && code != "0" && !stopping[e.Actor.ID] {
+				exits[name]++
+			}
+		}
+	}
+}
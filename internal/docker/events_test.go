@@ -0,0 +1,77 @@
+package docker
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestClientExits(t *testing.T) {
+	since := time.Now().Add(-10 * time.Minute)
+	c := fakeDaemon(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/events" {
+			http.NotFound(w, r)
+			return
+		}
+		q := r.URL.Query()
+		if q.Get("since") != strconv.FormatInt(since.Unix(), 10) || q.Get("until") == "" {
+			t.Errorf("unexpected window: since=%s until=%s", q.Get("since"), q.Get("until"))
+		}
+		if q.Get("filters") != `{"event":["die","kill","start","stop"],"type":["container"]}` {
+			t.Errorf("unexpected filters: %s", q.Get("filters"))
+		}
+		for _, name := range []string{"jellyfin", "jellyfin", "backup", "jellyfin"} {
+			fmt.Fprintf(w, `{"Type":"container","Action":"die","Actor":{"ID":"%s","Attributes":{"name":%q,"exitCode":"1"}},"time":%d}`+"\n", name, name, time.Now().Unix())
+		}
+		// Events without a name don't count
+		fmt.Fprint(w, `{"Type":"container","Action":"die","Actor":{"ID":"def"}}`)
+		// nor a clean exit
+		fmt.Fprint(w, `{"Type":"container","Action":"die","Actor":{"ID":"backup","Attributes":{"name":"backup","exitCode":"0"}}}`)
+	}))
+
+	exits, err := c.Exits(since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exits) != 2 || exits["jellyfin"] != 3 || exits["backup"] != 1 {
+		t.Errorf("Exits() = %v", exits)
+	}
+}
+
+func TestClientExitsManualRestart(t *testing.T) {
+	c := fakeDaemon(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// docker restart nginx: SIGTERM, exit 143, then up again
+		for _, e := range []string{
+			`{"Action":"kill","Actor":{"ID":"n1","Attributes":{"name":"nginx","signal":"15"}}}`,
+			`{"Action":"die","Actor":{"ID":"n1","Attributes":{"name":"nginx","exitCode":"143"}}}`,
+			`{"Action":"stop","Actor":{"ID":"n1","Attributes":{"name":"nginx"}}}`,
+			`{"Action":"start","Actor":{"ID":"n1","Attributes":{"name":"nginx"}}}`,
+			// a crash after the restart still counts
+			`{"Action":"die","Actor":{"ID":"n1","Attributes":{"name":"nginx","exitCode":"1"}}}`,
+			// a stop of another container says nothing about this one
+			`{"Action":"stop","Actor":{"ID":"p1","Attributes":{"name":"plex"}}}`,
+			`{"Action":"die","Actor":{"ID":"j1","Attributes":{"name":"jellyfin","exitCode":"137"}}}`,
+		} {
+			fmt.Fprintln(w, e)
+		}
+	}))
+
+	exits, err := c.Exits(time.Now().Add(-time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exits) != 2 || exits["nginx"] != 1 || exits["jellyfin"] != 1 {
+		t.Errorf("Exits() = %v, want only the crashes", exits)
+	}
+}
+
+func TestClientExitsBadStream(t *testing.T) {
+	c := fakeDaemon(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"Action":"die"`)
+	}))
+	if _, err := c.Exits(time.Now()); err == nil {
+		t.Error("expected error for truncated event stream")
+	}
+}
@@ -0,0 +1,228 @@
+package docker
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LogLine is one line of container output.
+type LogLine struct {
+	Time   time.Time `json:"time,omitzero"`
+	Stream string    `json:"stream"` // "stdout" or "stderr"
+	Text   string    `json:"text"`
+}
+
+// LogOptions selects the lines Logs and StreamLogs return.
+type LogOptions struct {
+	Tail   string // number of lines from the end, "all" or "" for everything
+	Since  string // duration back from now ("10m") or an RFC 3339 time
+	Grep   string // regular expression a line must match
+	Follow bool   // keep streaming new lines (StreamLogs only)
+}
+
+// LogsResult holds docker logs output.
+type LogsResult struct {
+	Container string    `json:"container"`
+	Lines     string    `json:"lines"`
+	Logs      string    `json:"logs"` // Entries' text joined, for clients that only want the output
+	Entries   []LogLine `json:"entries"`
+}
+
+// ValidateLogOptions checks options that end up in a query string or a
+// remote command line.
+func ValidateLogOptions(opts LogOptions) error {
+	if opts.Tail != "" && opts.Tail != "all" {
+		for _, ch := range opts.Tail {
+			if ch < '0' || ch > '9' {
+				return fmt.Errorf("invalid line count: %s (must be a positive integer)", opts.Tail)
+			}
+		}
+	}
+	if opts.Since != "" {
+		if _, err := parseSince(opts.Since, time.Now()); err != nil {
+			return err
+		}
+	}
+	if opts.Grep != "" {
+		if _, err := regexp.Compile(opts.Grep); err != nil {
+			return fmt.Errorf("invalid grep pattern: %w", err)
+		}
+	}
+	return nil
+}
+
+func Logs(name string, opts LogOptions) (*LogsResult, error) {
+	c, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+	return c.Logs(name, opts)
+}
+
+// Logs returns a snapshot of a container's logs.
+func (c *Client) Logs(name string, opts LogOptions) (*LogsResult, error) {
+	opts.Follow = false
+	result := &LogsResult{Container: name, Lines: opts.Tail, Entries: []LogLine{}}
+	var text []string
+	err := c.StreamLogs(context.Background(), name, opts, func(l LogLine) error {
+		result.Entries = append(result.Entries, l)
+		text = append(text, l.Text)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Logs = strings.TrimSpace(strings.Join(text, "\n"))
+	return result, nil
+}
+
+func StreamLogs(ctx context.Context, name string, opts LogOptions, fn func(LogLine) error) error {
+	c, err := NewClient()
+	if err != nil {
+		return err
+	}
+	return c.StreamLogs(ctx, name, opts, fn)
+}
+
+// StreamLogs calls fn for every log line in arrival order. With Follow it
+// keeps going until the container stops or ctx is canceled; either ends the
+// stream without an error. An error from fn stops the stream and is returned.
+func (c *Client) StreamLogs(ctx context.Context, name string, opts LogOptions, fn func(LogLine) error) error {
+	if !isValidName(name) {
+		return fmt.Errorf("invalid container name: %s", name)
+	}
+	if err := ValidateLogOptions(opts); err != nil {
+		return err
+	}
+	var grep *regexp.Regexp
+	if opts.Grep != "" {
+		grep = regexp.MustCompile(opts.Grep)
+	}
+	query := url.Values{"stdout": {"1"}, "stderr": {"1"}, "timestamps": {"1"}}
+	if opts.Tail != "" {
+		query.Set("tail", opts.Tail)
+	}
+	if opts.Since != "" {
+		since, _ := parseSince(opts.Since, time.Now())
+		query.Set("since", since)
+	}
+
+	// Containers without a TTY multiplex stdout/stderr into frames.
+	info, err := c.inspect(name)
+	if err != nil {
+		return fmt.Errorf("failed to get logs for %s: %w", name, err)
+	}
+	hc := c.http
+	if opts.Follow {
+		query.Set("follow", "1")
+		hc = c.stream
+	}
+	resp, err := c.doContext(ctx, hc, http.MethodGet, "/containers/"+name+"/logs", query)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return fmt.Errorf("failed to get logs for %s: %w", name, err)
+	}
+	defer resp.Body.Close()
+
+	err = readLogLines(resp.Body, info.Config.Tty, func(stream, raw string) error {
+		l := parseLogLine(stream, raw)
+		if grep != nil && !grep.MatchString(l.Text) {
+			return nil
+		}
+		return fn(l)
+	})
+	if err != nil && ctx.Err() == nil {
+		return fmt.Errorf("failed to read logs for %s: %w", name, err)
+	}
+	return nil
+}
+
+// readLogLines splits the log stream into lines. Without a TTY each frame
+// is an 8-byte header (stream type, 3 zero bytes, big-endian payload size)
+// followed by the payload; a long line can span frames, so partial lines
+// are kept per stream until their newline arrives.
+func readLogLines(r io.Reader, tty bool, emit func(stream, line string) error) error {
+	if tty {
+		sc := bufio.NewScanner(r)
+		sc.Buffer(make([]byte, 64*1024), 1<<20)
+		for sc.Scan() {
+			if err := emit("stdout", strings.TrimSuffix(sc.Text(), "\r")); err != nil {
+				return err
+			}
+		}
+		return sc.Err()
+	}
+
+	streams := map[byte]string{1: "stdout", 2: "stderr"}
+	partial := map[byte]string{}
+	header := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			for _, s := range []byte{1, 2} {
+				if partial[s] != "" {
+					if err := emit(streams[s], partial[s]); err != nil {
+						return err
+					}
+				}
+			}
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		s := header[0]
+		if s != 2 {
+			s = 1
+		}
+		payload := make([]byte, binary.BigEndian.Uint32(header[4:]))
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return err
+		}
+		text := partial[s] + string(payload)
+		for {
+			line, rest, ok := strings.Cut(text, "\n")
+			if !ok {
+				break
+			}
+			if err := emit(streams[s], line); err != nil {
+				return err
+			}
+			text = rest
+		}
+		partial[s] = text
+	}
+}
+
+// parseLogLine splits off the timestamp docker prepends with timestamps=1.
+func parseLogLine(stream, raw string) LogLine {
+	l := LogLine{Stream: stream, Text: raw}
+	if ts, text, ok := strings.Cut(raw, " "); ok {
+		if t, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+			l.Time, l.Text = t, text
+		}
+	}
+	return l
+}
+
+// parseSince turns "10m" or an RFC 3339 time into the unix timestamp the
+// API expects.
+func parseSince(s string, now time.Time) (string, error) {
+	if d, err := time.ParseDuration(s); err == nil && d > 0 {
+		return strconv.FormatInt(now.Add(-d).Unix(), 10), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return strconv.FormatInt(t.Unix(), 10), nil
+	}
+	return "", fmt.Errorf("invalid since value %q (use a duration like 10m or a time like 2024-01-02T15:04:05Z)", s)
+}
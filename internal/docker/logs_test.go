@@ -0,0 +1,111 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadLogLines(t *testing.T) {
+	// A line split across frames, interleaved with the other stream
+	var stream []byte
+	stream = append(stream, frame(1, "hel")...)
+	stream = append(stream, frame(2, "oops\n")...)
+	stream = append(stream, frame(1, "lo\nworld\n")...)
+	stream = append(stream, frame(1, "no newline")...)
+
+	var got []string
+	err := readLogLines(strings.NewReader(string(stream)), false, func(s, line string) error {
+		got = append(got, s+":"+line)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"stderr:oops", "stdout:hello", "stdout:world", "stdout:no newline"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestParseLogLine(t *testing.T) {
+	l := parseLogLine("stderr", "2024-05-01T10:00:00.123456789Z connection refused")
+	if l.Text != "connection refused" || l.Stream != "stderr" || l.Time.Nanosecond() != 123456789 {
+		t.Errorf("unexpected line: %+v", l)
+	}
+	// No timestamp: the whole line is text
+	if l := parseLogLine("stdout", "plain text"); l.Text != "plain text" || !l.Time.IsZero() {
+		t.Errorf("unexpected line: %+v", l)
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tests := map[string]string{
+		"10m":                  "1699999400",
+		"1h30m":                "1699994600",
+		"2023-11-14T22:13:20Z": "1700000000",
+	}
+	for in, want := range tests {
+		if got, err := parseSince(in, now); err != nil || got != want {
+			t.Errorf("parseSince(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"yesterday", "-5m", "0s"} {
+		if _, err := parseSince(in, now); err == nil {
+			t.Errorf("parseSince(%q) should fail", in)
+		}
+	}
+}
+
+func TestValidateLogOptions(t *testing.T) {
+	valid := []LogOptions{{}, {Tail: "all"}, {Tail: "100", Since: "5m", Grep: "ERR(OR)?"}}
+	for _, o := range valid {
+		if err := ValidateLogOptions(o); err != nil {
+			t.Errorf("%+v: %v", o, err)
+		}
+	}
+	invalid := []LogOptions{{Tail: "10; rm"}, {Since: "soon"}, {Grep: "("}}
+	for _, o := range invalid {
+		if err := ValidateLogOptions(o); err == nil {
+			t.Errorf("%+v should be rejected", o)
+		}
+	}
+}
+
+func TestClientStreamLogs(t *testing.T) {
+	c := fakeDaemon(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/containers/app/json":
+			fmt.Fprint(w, `{"Config": {"Tty": false}}`)
+		case "/containers/app/logs":
+			q := r.URL.Query()
+			if q.Get("follow") != "1" || q.Get("timestamps") != "1" || q.Get("since") == "" {
+				t.Errorf("unexpected query %q", r.URL.RawQuery)
+			}
+			w.Write(frame(1, "2024-05-01T10:00:00Z GET /health 200\n"))
+			w.Write(frame(2, "2024-05-01T10:00:01Z ERROR db timeout\n"))
+			w.(http.Flusher).Flush()
+			// Keep the stream open like a running container would
+			<-r.Context().Done()
+		}
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var got []LogLine
+	err := c.StreamLogs(ctx, "app", LogOptions{Follow: true, Since: "10m", Grep: "ERROR"}, func(l LogLine) error {
+		got = append(got, l)
+		cancel()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamLogs: %v", err)
+	}
+	if len(got) != 1 || got[0].Stream != "stderr" || got[0].Text != "ERROR db timeout" || got[0].Time.Second() != 1 {
+		t.Errorf("unexpected lines: %+v", got)
+	}
+}
@@ -0,0 +1,134 @@
+package docker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RuntimeEnv overrides the configured runtime. Remote servers get their
+// `runtime:` setting through it.
+const RuntimeEnv = "HOMEBUTLER_RUNTIME"
+
+// Runtime is a container engine that serves the Docker Engine API. Docker
+// and Podman differ only in where the API socket lives and which CLI runs
+// compose; everything else goes through the same Client.
+type Runtime interface {
+	Name() string
+	// HostEnv is the environment variable that overrides the API endpoint.
+	HostEnv() string
+	// Hosts lists the API endpoints to try, most likely first.
+	Hosts() []string
+	// Compose is the command compose up/down/pull run, e.g. ["docker", "compose"].
+	Compose() []string
+	// SocketHint is appended to the socket-not-found error.
+	SocketHint() string
+}
+
+type dockerRuntime struct{}
+
+func (dockerRuntime) Name() string       { return "docker" }
+func (dockerRuntime) HostEnv() string    { return "DOCKER_HOST" }
+func (dockerRuntime) Hosts() []string    { return []string{DefaultHost} }
+func (dockerRuntime) Compose() []string  { return []string{"docker", "compose"} }
+func (dockerRuntime) SocketHint() string { return "" }
+
+// podmanRuntime talks to Podman's Docker-compatible API. Rootless Podman
+// serves it per user from podman.socket under $XDG_RUNTIME_DIR.
+type podmanRuntime struct{}
+
+func (podmanRuntime) Name() string    { return "podman" }
+func (podmanRuntime) HostEnv() string { return "CONTAINER_HOST" }
+
+func (podmanRuntime) Hosts() []string {
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+	if dir == "" {
+		dir = fmt.Sprintf("/run/user/%d", os.Getuid())
+	}
+	return []string{
+		"unix://" + filepath.Join(dir, "podman", "podman.sock"),
+		"unix:///run/podman/podman.sock",
+	}
+}
+
+func (podmanRuntime) Compose() []string { return []string{"podman", "compose"} }
+
+func (podmanRuntime) SocketHint() string {
+	return "start the API socket with: systemctl --user enable --now podman.socket"
+}
+
+var runtimes = []Runtime{dockerRuntime{}, podmanRuntime{}}
+
+// runtimeName is the runtime from the config; empty auto-detects.
+var runtimeName string
+
+// SetRuntime sets the runtime from the config: "docker", "podman" or ""
+// to auto-detect.
+func SetRuntime(name string) error {
+	if name != "" {
+		if _, err := lookupRuntime(name); err != nil {
+			return err
+		}
+	}
+	runtimeName = name
+	return nil
+}
+
+func lookupRuntime(name string) (Runtime, error) {
+	for _, rt := range runtimes {
+		if rt.Name() == name {
+			return rt, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown container runtime %q (use docker or podman)", name)
+}
+
+// resolveRuntime picks the runtime and API endpoint. An explicit runtime
+// ($HOMEBUTLER_RUNTIME, then the config) uses its host variable or the
+// first of its sockets that exists. Otherwise DOCKER_HOST, then
+// CONTAINER_HOST, then the first existing socket of any runtime wins,
+// falling back to the docker socket so the error names it.
+func resolveRuntime() (Runtime, string, error) {
+	name := os.Getenv(RuntimeEnv)
+	if name == "" {
+		name = runtimeName
+	}
+	if name != "" {
+		rt, err := lookupRuntime(name)
+		if err != nil {
+			return nil, "", err
+		}
+		if host := os.Getenv(rt.HostEnv()); host != "" {
+			return rt, host, nil
+		}
+		hosts := rt.Hosts()
+		return rt, firstSocket(hosts, hosts[0]), nil
+	}
+
+	for _, rt := range runtimes {
+		if host := os.Getenv(rt.HostEnv()); host != "" {
+			return rt, host, nil
+		}
+	}
+	for _, rt := range runtimes {
+		if host := firstSocket(rt.Hosts(), ""); host != "" {
+			return rt, host, nil
+		}
+	}
+	return dockerRuntime{}, DefaultHost, nil
+}
+
+// firstSocket returns the first unix:// host whose socket exists, or def.
+func firstSocket(hosts []string, def string) string {
+	for _, h := range hosts {
+		path, ok := strings.CutPrefix(h, "unix://")
+		if !ok {
+			continue
+		}
+		if _, err := os.Stat(path); err == nil {
+			return h
+		}
+	}
+	return def
+}
@@ -0,0 +1,102 @@
+package docker
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// clearRuntimeEnv isolates a test from the host's container environment.
+func clearRuntimeEnv(t *testing.T) string {
+	t.Helper()
+	for _, env := range []string{RuntimeEnv, "DOCKER_HOST", "CONTAINER_HOST"} {
+		t.Setenv(env, "")
+	}
+	dir := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", dir)
+	t.Cleanup(func() { runtimeName = "" })
+	return dir
+}
+
+func TestResolveRuntime(t *testing.T) {
+	dir := clearRuntimeEnv(t)
+	if _, err := os.Stat("/var/run/docker.sock"); err == nil {
+		t.Skip("host has a docker socket; auto-detection would find it")
+	}
+
+	rt, host, err := resolveRuntime()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rt.Name() != "docker" || host != DefaultHost {
+		t.Errorf("with no sockets got %s %s, want docker %s", rt.Name(), host, DefaultHost)
+	}
+
+	sock := filepath.Join(dir, "podman", "podman.sock")
+	os.MkdirAll(filepath.Dir(sock), 0755)
+	os.WriteFile(sock, nil, 0600)
+	rt, host, _ = resolveRuntime()
+	if rt.Name() != "podman" || host != "unix://"+sock {
+		t.Errorf("with rootless podman socket got %s %s", rt.Name(), host)
+	}
+
+	t.Setenv("DOCKER_HOST", "tcp://10.0.0.5:2375")
+	rt, host, _ = resolveRuntime()
+	if rt.Name() != "docker" || host != "tcp://10.0.0.5:2375" {
+		t.Errorf("DOCKER_HOST should win auto-detection, got %s %s", rt.Name(), host)
+	}
+
+	if err := SetRuntime("podman"); err != nil {
+		t.Fatal(err)
+	}
+	rt, host, _ = resolveRuntime()
+	if rt.Name() != "podman" || host != "unix://"+sock {
+		t.Errorf("configured podman got %s %s", rt.Name(), host)
+	}
+
+	t.Setenv("CONTAINER_HOST", "unix:///tmp/custom.sock")
+	if _, host, _ = resolveRuntime(); host != "unix:///tmp/custom.sock" {
+		t.Errorf("CONTAINER_HOST should override podman sockets, got %s", host)
+	}
+
+	t.Setenv(RuntimeEnv, "docker")
+	if rt, host, _ = resolveRuntime(); rt.Name() != "docker" || host != "tcp://10.0.0.5:2375" {
+		t.Errorf("%s should override the config, got %s %s", RuntimeEnv, rt.Name(), host)
+	}
+
+	t.Setenv(RuntimeEnv, "containerd")
+	if _, _, err := resolveRuntime(); err == nil {
+		t.Error("expected error for unknown runtime")
+	}
+}
+
+func TestSetRuntimeRejectsUnknown(t *testing.T) {
+	clearRuntimeEnv(t)
+	if err := SetRuntime("lxc"); err == nil {
+		t.Fatal("expected error")
+	}
+	if runtimeName != "" {
+		t.Errorf("runtime changed to %q after a rejected name", runtimeName)
+	}
+}
+
+func TestPodmanSocketHint(t *testing.T) {
+	dir := clearRuntimeEnv(t)
+	SetRuntime("podman")
+	c, err := NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.List()
+	if !errors.Is(err, ErrSocketMissing) {
+		t.Fatalf("expected ErrSocketMissing, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "podman.socket") || !strings.Contains(err.Error(), dir) {
+		t.Errorf("error should point at the rootless socket: %v", err)
+	}
+	if ClassifyError(err) != "not_installed" {
+		t.Errorf("ClassifyError = %s", ClassifyError(err))
+	}
+}
@@ -0,0 +1,154 @@
+package docker
+
+import (
+	"math"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// ContainerStats is a resource usage snapshot of a running container, like
+// one row of `docker stats --no-stream`. Network and block I/O are
+// cumulative byte counts since the container started.
+type ContainerStats struct {
+	ID              string  `json:"id"`
+	Name            string  `json:"name"`
+	CPUPercent      float64 `json:"cpu_percent"` // 100% = one core fully busy
+	MemUsageBytes   uint64  `json:"mem_usage_bytes"`
+	MemLimitBytes   uint64  `json:"mem_limit_bytes"`
+	MemPercent      float64 `json:"mem_percent"`
+	NetRxBytes      uint64  `json:"net_rx_bytes"`
+	NetTxBytes      uint64  `json:"net_tx_bytes"`
+	BlockReadBytes  uint64  `json:"block_read_bytes"`
+	BlockWriteBytes uint64  `json:"block_write_bytes"`
+	PIDs            uint64  `json:"pids"`
+}
+
+// statsResponse is the subset of GET /containers/{id}/stats we use.
+type statsResponse struct {
+	CPUStats    cpuStats `json:"cpu_stats"`
+	PreCPUStats cpuStats `json:"precpu_stats"`
+	MemoryStats struct {
+		Usage uint64            `json:"usage"`
+		Limit uint64            `json:"limit"`
+		Stats map[string]uint64 `json:"stats"`
+	} `json:"memory_stats"`
+	Networks map[string]struct {
+		RxBytes uint64 `json:"rx_bytes"`
+		TxBytes uint64 `json:"tx_bytes"`
+	} `json:"networks"`
+	BlkioStats struct {
+		IOServiceBytesRecursive []struct {
+			Op    string `json:"op"`
+			Value uint64 `json:"value"`
+		} `json:"io_service_bytes_recursive"`
+	} `json:"blkio_stats"`
+	PidsStats struct {
+		Current uint64 `json:"current"`
+	} `json:"pids_stats"`
+}
+
+type cpuStats struct {
+	CPUUsage struct {
+		TotalUsage  uint64   `json:"total_usage"`
+		PercpuUsage []uint64 `json:"percpu_usage"`
+	} `json:"cpu_usage"`
+	SystemUsage uint64 `json:"system_cpu_usage"`
+	OnlineCPUs  int    `json:"online_cpus"`
+}
+
+func Stats() ([]ContainerStats, error) {
+	c, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+	return c.Stats()
+}
+
+// Stats returns a snapshot for every running container. The daemon takes
+// about a second per container to sample CPU, so containers are queried in
+// parallel. Containers that stop in the meantime are left out.
+func (c *Client) Stats() ([]ContainerStats, error) {
+	var summaries []containerSummary
+	if err := c.getJSON("/containers/json", nil, &summaries); err != nil {
+		return nil, err
+	}
+
+	results := make([]*ContainerStats, len(summaries))
+	var wg sync.WaitGroup
+	for i, s := range summaries {
+		wg.Go(func() {
+			var raw statsResponse
+			if err := c.getJSON("/containers/"+url.PathEscape(s.ID)+"/stats", url.Values{"stream": {"false"}}, &raw); err != nil {
+				return
+			}
+			st := statsFromAPI(&raw)
+			st.ID = shortID(s.ID)
+			if len(s.Names) > 0 {
+				st.Name = strings.TrimPrefix(s.Names[0], "/")
+			}
+			results[i] = &st
+		})
+	}
+	wg.Wait()
+
+	stats := make([]ContainerStats, 0, len(results))
+	for _, st := range results {
+		if st != nil {
+			stats = append(stats, *st)
+		}
+	}
+	return stats, nil
+}
+
+// statsFromAPI derives the numbers the same way the docker CLI does.
+func statsFromAPI(raw *statsResponse) ContainerStats {
+	var st ContainerStats
+
+	// CPU: container time over host time between the two samples, scaled
+	// by the number of CPUs.
+	cpuDelta := float64(raw.CPUStats.CPUUsage.TotalUsage) - float64(raw.PreCPUStats.CPUUsage.TotalUsage)
+	sysDelta := float64(raw.CPUStats.SystemUsage) - float64(raw.PreCPUStats.SystemUsage)
+	cpus := raw.CPUStats.OnlineCPUs
+	if cpus == 0 {
+		cpus = len(raw.CPUStats.CPUUsage.PercpuUsage)
+	}
+	if cpuDelta > 0 && sysDelta > 0 {
+		st.CPUPercent = round2(cpuDelta / sysDelta * float64(cpus) * 100)
+	}
+
+	// Memory: page cache that can be dropped doesn't count as used.
+	// cgroup v1 reports it as total_inactive_file, v2 as inactive_file.
+	mem := raw.MemoryStats
+	st.MemUsageBytes = mem.Usage
+	inactive, ok := mem.Stats["total_inactive_file"]
+	if !ok {
+		inactive = mem.Stats["inactive_file"]
+	}
+	if inactive < mem.Usage {
+		st.MemUsageBytes = mem.Usage - inactive
+	}
+	st.MemLimitBytes = mem.Limit
+	if mem.Limit > 0 {
+		st.MemPercent = round2(float64(st.MemUsageBytes) / float64(mem.Limit) * 100)
+	}
+
+	for _, n := range raw.Networks {
+		st.NetRxBytes += n.RxBytes
+		st.NetTxBytes += n.TxBytes
+	}
+	for _, b := range raw.BlkioStats.IOServiceBytesRecursive {
+		switch strings.ToLower(b.Op) {
+		case "read":
+			st.BlockReadBytes += b.Value
+		case "write":
+			st.BlockWriteBytes += b.Value
+		}
+	}
+	st.PIDs = raw.PidsStats.Current
+	return st
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,90 @@
+package docker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// cgroup v2 host: inactive_file, lowercase blkio ops, two networks
+const statsV2JSON = `{
+  "cpu_stats": {"cpu_usage": {"total_usage": 3000000000}, "system_cpu_usage": 120000000000, "online_cpus": 4},
+  "precpu_stats": {"cpu_usage": {"total_usage": 2000000000}, "system_cpu_usage": 116000000000, "online_cpus": 4},
+  "memory_stats": {"usage": 268435456, "limit": 1073741824, "stats": {"inactive_file": 67108864, "anon": 201326592}},
+  "networks": {"eth0": {"rx_bytes": 1000, "tx_bytes": 2000}, "eth1": {"rx_bytes": 500, "tx_bytes": 0}},
+  "blkio_stats": {"io_service_bytes_recursive": [{"major": 8, "minor": 0, "op": "read", "value": 4096}, {"major": 8, "minor": 0, "op": "write", "value": 8192}]},
+  "pids_stats": {"current": 12}
+}`
+
+// cgroup v1 host: total_inactive_file, capitalized ops, percpu_usage instead of online_cpus
+const statsV1JSON = `{
+  "cpu_stats": {"cpu_usage": {"total_usage": 500, "percpu_usage": [250, 250]}, "system_cpu_usage": 2000},
+  "precpu_stats": {"cpu_usage": {"total_usage": 400}, "system_cpu_usage": 1000},
+  "memory_stats": {"usage": 1000, "limit": 4000, "stats": {"total_inactive_file": 200, "inactive_file": 50}},
+  "blkio_stats": {"io_service_bytes_recursive": [{"op": "Read", "value": 10}, {"op": "Write", "value": 20}, {"op": "Total", "value": 30}]},
+  "pids_stats": {"current": 1}
+}`
+
+func TestStatsFromAPI(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want ContainerStats
+	}{
+		{"cgroup v2", statsV2JSON, ContainerStats{
+			CPUPercent: 100, MemUsageBytes: 192 << 20, MemLimitBytes: 1 << 30, MemPercent: 18.75,
+			NetRxBytes: 1500, NetTxBytes: 2000, BlockReadBytes: 4096, BlockWriteBytes: 8192, PIDs: 12,
+		}},
+		{"cgroup v1", statsV1JSON, ContainerStats{
+			CPUPercent: 20, MemUsageBytes: 800, MemLimitBytes: 4000, MemPercent: 20,
+			BlockReadBytes: 10, BlockWriteBytes: 20, PIDs: 1,
+		}},
+		// First sample after start: precpu is empty, deltas aren't meaningful
+		{"no previous sample", `{"cpu_stats": {"cpu_usage": {"total_usage": 0}, "online_cpus": 2}, "memory_stats": {}}`, ContainerStats{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var raw statsResponse
+			if err := json.Unmarshal([]byte(tt.json), &raw); err != nil {
+				t.Fatal(err)
+			}
+			if got := statsFromAPI(&raw); got != tt.want {
+				t.Errorf("statsFromAPI() =\n  %+v\nwant\n  %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientStats(t *testing.T) {
+	c := fakeDaemon(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/containers/json":
+			if r.URL.Query().Get("all") != "" {
+				t.Errorf("stats should only list running containers, got %q", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `[{"Id": "aaaaaaaaaaaa1111", "Names": ["/web"]}, {"Id": "bbbbbbbbbbbb2222", "Names": ["/gone"]}]`)
+		case strings.HasPrefix(r.URL.Path, "/containers/aaaa"):
+			if r.URL.Query().Get("stream") != "false" {
+				t.Errorf("expected stream=false, got %q", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, statsV2JSON)
+		default:
+			// stopped between list and stats
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "No such container"}`)
+		}
+	}))
+
+	stats, err := c.Stats()
+	if err != nil {
+		t.Fatalf("Stats: %v", err)
+	}
+	if len(stats) != 1 {
+		t.Fatalf("expected 1 container (gone one skipped), got %d: %+v", len(stats), stats)
+	}
+	if stats[0].Name != "web" || stats[0].ID != "aaaaaaaaaaaa" || stats[0].CPUPercent != 100 {
+		t.Errorf("unexpected stats: %+v", stats[0])
+	}
+}
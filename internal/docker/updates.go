@@ -0,0 +1,381 @@
+package docker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ImageUpdate reports whether a running container's image is still the one
+// its tag points to in the registry.
+type ImageUpdate struct {
+	Container    string `json:"container"`
+	Image        string `json:"image"` // reference the container was created from
+	LocalDigest  string `json:"local_digest,omitempty"`
+	RemoteDigest string `json:"remote_digest,omitempty"`
+	Status       string `json:"status"` // "up_to_date", "update_available", "pinned", "local", "error"
+	Error        string `json:"error,omitempty"`
+}
+
+// registryClient talks to image registries. Tests point it at a fake one.
+var registryClient = &http.Client{Timeout: 20 * time.Second}
+
+// manifestAccept lists the manifest types we accept, index types first so
+// multi-arch images resolve to the same digest `docker pull` records.
+var manifestAccept = strings.Join([]string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}, ", ")
+
+func Updates() ([]ImageUpdate, error) {
+	c, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+	return c.Updates()
+}
+
+// Updates checks every running container. Each distinct image is looked up
+// in its registry once; failures are reported per container rather than
+// failing the whole check.
+func (c *Client) Updates() ([]ImageUpdate, error) {
+	var summaries []containerSummary
+	if err := c.getJSON("/containers/json", nil, &summaries); err != nil {
+		return nil, err
+	}
+
+	updates := make([]ImageUpdate, len(summaries))
+	localDigests := make([][]string, len(summaries))
+	for i, s := range summaries {
+		u := &updates[i]
+		if len(s.Names) > 0 {
+			u.Container = strings.TrimPrefix(s.Names[0], "/")
+		}
+		info, err := c.inspect(s.ID)
+		if err != nil {
+			u.Status, u.Error = "error", err.Error()
+			continue
+		}
+		u.Image = info.Config.Image
+		var img struct {
+			RepoDigests []string `json:"RepoDigests"`
+		}
+		if err := c.getJSON("/images/"+url.PathEscape(info.Image)+"/json", nil, &img); err != nil {
+			u.Status, u.Error = "error", err.Error()
+			continue
+		}
+		for _, rd := range img.RepoDigests {
+			if _, digest, ok := strings.Cut(rd, "@"); ok {
+				localDigests[i] = append(localDigests[i], digest)
+			}
+		}
+		switch {
+		case strings.Contains(u.Image, "@"):
+			u.Status = "pinned"
+		case len(localDigests[i]) == 0:
+			// Built locally or loaded from a tarball: nothing to compare
+			u.Status = "local"
+		}
+		if len(localDigests[i]) > 0 {
+			u.LocalDigest = localDigests[i][0]
+		}
+	}
+
+	// One registry lookup per image reference
+	insecure := c.insecureRegistries()
+	type lookup struct {
+		digest string
+		err    error
+	}
+	remote := make(map[string]*lookup)
+	for _, u := range updates {
+		if u.Status == "" {
+			remote[u.Image] = &lookup{}
+		}
+	}
+	var wg sync.WaitGroup
+	for ref, l := range remote {
+		wg.Go(func() {
+			l.digest, l.err = remoteDigest(ref, insecure)
+		})
+	}
+	wg.Wait()
+
+	for i := range updates {
+		u := &updates[i]
+		if u.Status != "" {
+			continue
+		}
+		l := remote[u.Image]
+		if l.err != nil {
+			u.Status, u.Error = "error", l.err.Error()
+			continue
+		}
+		u.RemoteDigest = l.digest
+		u.Status = "update_available"
+		for _, d := range localDigests[i] {
+			if d == l.digest {
+				u.LocalDigest = d
+				u.Status = "up_to_date"
+				break
+			}
+		}
+	}
+	return updates, nil
+}
+
+// imageRef is a parsed image reference.
+type imageRef struct {
+	registry   string // host[:port]
+	repository string
+	tag        string
+}
+
+// parseReference splits an image reference the way docker does: the first
+// path component is a registry if it has a dot or a port, or is localhost;
+// otherwise the image lives on Docker Hub, under library/ if unqualified.
+func parseReference(ref string) (imageRef, error) {
+	if ref == "" || strings.Contains(ref, "@") {
+		return imageRef{}, fmt.Errorf("cannot look up %q by tag", ref)
+	}
+	r := imageRef{registry: "registry-1.docker.io", tag: "latest"}
+	name := ref
+	if first, rest, ok := strings.Cut(ref, "/"); ok && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		r.registry, name = first, rest
+		if r.registry == "docker.io" || r.registry == "index.docker.io" {
+			r.registry = "registry-1.docker.io"
+		}
+	}
+	// A colon after the last slash separates the tag
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, r.tag = name[:i], name[i+1:]
+	}
+	if r.registry == "registry-1.docker.io" && !strings.Contains(name, "/") {
+		name = "library/" + name
+	}
+	if name == "" || r.tag == "" {
+		return imageRef{}, fmt.Errorf("invalid image reference %q", ref)
+	}
+	r.repository = name
+	return r, nil
+}
+
+// insecureRegistries are the registries docker pulls from over plain http:
+// the daemon's insecure-registries, by host or CIDR. Localhost and
+// loopback addresses always are.
+type insecureRegistries struct {
+	hosts map[string]bool
+	nets  []*net.IPNet
+}
+
+// insecureRegistries reads the daemon's insecure-registries from GET /info.
+// A runtime that doesn't report them has only the loopback ones.
+func (c *Client) insecureRegistries() insecureRegistries {
+	var info struct {
+		RegistryConfig struct {
+			InsecureRegistryCIDRs []string `json:"InsecureRegistryCIDRs"`
+			IndexConfigs          map[string]struct {
+				Secure bool `json:"Secure"`
+			} `json:"IndexConfigs"`
+		} `json:"RegistryConfig"`
+	}
+	ir := insecureRegistries{hosts: map[string]bool{}}
+	if c.getJSON("/info", nil, &info) != nil {
+		return ir
+	}
+	for _, cidr := range info.RegistryConfig.InsecureRegistryCIDRs {
+		if _, n, err := net.ParseCIDR(cidr); err == nil {
+			ir.nets = append(ir.nets, n)
+		}
+	}
+	for host, index := range info.RegistryConfig.IndexConfigs {
+		if !index.Secure {
+			ir.hosts[host] = true
+		}
+	}
+	return ir
+}
+
+// allows reports whether registry (host[:port]) may be used over plain http.
+func (ir insecureRegistries) allows(registry string) bool {
+	if ir.hosts[registry] {
+		return true
+	}
+	host := registry
+	if h, _, err := net.SplitHostPort(registry); err == nil {
+		host = h
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	if ip.IsLoopback() {
+		return true
+	}
+	for _, n := range ir.nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// RemoteDigest returns the digest the registry currently serves for an image
+// reference, using the OCI distribution API with anonymous token auth.
+// Only localhost and loopback registries may answer over plain http.
+func RemoteDigest(ref string) (string, error) {
+	return remoteDigest(ref, insecureRegistries{})
+}
+
+// remoteDigest is RemoteDigest, letting the insecure registries fall back
+// to plain http when https fails, like docker pull does. Other registries
+// that only speak http are reported as unsupported.
+func remoteDigest(ref string, insecure insecureRegistries) (string, error) {
+	r, err := parseReference(ref)
+	if err != nil {
+		return "", err
+	}
+	manifestURL := fmt.Sprintf("https://%s/v2/%s/manifests/%s", r.registry, r.repository, r.tag)
+
+	var token string
+	resp, err := manifestRequest(http.MethodHead, manifestURL, token)
+	if err != nil && insecure.allows(r.registry) {
+		manifestURL = "http" + strings.TrimPrefix(manifestURL, "https")
+		resp, err = manifestRequest(http.MethodHead, manifestURL, token)
+	} else if errors.Is(err, http.ErrSchemeMismatch) {
+		return "", fmt.Errorf("%s: unsupported registry: it only speaks plain http (add it to the daemon's insecure-registries)", r.registry)
+	}
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized {
+		if token, err = registryToken(resp.Header.Get("WWW-Authenticate")); err != nil {
+			return "", fmt.Errorf("%s: %w", r.registry, err)
+		}
+		if resp, err = manifestRequest(http.MethodHead, manifestURL, token); err != nil {
+			return "", err
+		}
+		resp.Body.Close()
+	}
+	if digest := resp.Header.Get("Docker-Content-Digest"); resp.StatusCode == http.StatusOK && digest != "" {
+		return digest, nil
+	}
+	// HEAD is free on Docker Hub's rate limit, GET isn't: only fall back
+	// when the registry didn't send a digest
+	return getManifestDigest(manifestURL, token)
+}
+
+// getManifestDigest fetches the manifest and hashes it, for registries that
+// don't send Docker-Content-Digest on HEAD.
+func getManifestDigest(manifestURL, token string) (string, error) {
+	resp, err := manifestRequest(http.MethodGet, manifestURL, token)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("registry returned %s for %s", resp.Status, manifestURL)
+	}
+	if digest := resp.Header.Get("Docker-Content-Digest"); digest != "" {
+		return digest, nil
+	}
+	h := sha256.New()
+	if _, err := io.Copy(h, io.LimitReader(resp.Body, 4<<20)); err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func manifestRequest(method, manifestURL, token string) (*http.Response, error) {
+	req, err := http.NewRequest(method, manifestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", manifestAccept)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return registryClient.Do(req)
+}
+
+// registryToken gets an anonymous pull token from the realm named in a
+// Bearer challenge:
+//
+//	Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/nginx:pull"
+func registryToken(challenge string) (string, error) {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return "", fmt.Errorf("registry requires authentication")
+	}
+	values := url.Values{}
+	var realm string
+	for _, p := range splitChallenge(params) {
+		key, val, ok := strings.Cut(p, "=")
+		if !ok {
+			continue
+		}
+		val = strings.Trim(val, `"`)
+		if key == "realm" {
+			realm = val
+		} else {
+			values.Set(key, val)
+		}
+	}
+	if realm == "" {
+		return "", fmt.Errorf("invalid auth challenge %q", challenge)
+	}
+	resp, err := registryClient.Get(realm + "?" + values.Encode())
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token request failed: %s (private image?)", resp.Status)
+	}
+	var tok struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil {
+		return "", err
+	}
+	if tok.Token != "" {
+		return tok.Token, nil
+	}
+	return tok.AccessToken, nil
+}
+
+// splitChallenge splits challenge parameters on commas outside quotes;
+// scopes can contain commas ("repository:a/b:pull,push").
+func splitChallenge(s string) []string {
+	var parts []string
+	inQuote := false
+	start := 0
+	for i, ch := range s {
+		switch ch {
+		case '"':
+			inQuote = !inQuote
+		case ',':
+			if !inQuote {
+				parts = append(parts, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, strings.TrimSpace(s[start:]))
+}
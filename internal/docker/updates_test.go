@@ -0,0 +1,239 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want imageRef
+	}{
+		{"nginx", imageRef{"registry-1.docker.io", "library/nginx", "latest"}},
+		{"nginx:1.25", imageRef{"registry-1.docker.io", "library/nginx", "1.25"}},
+		{"grafana/grafana:10.2", imageRef{"registry-1.docker.io", "grafana/grafana", "10.2"}},
+		{"docker.io/library/redis:7", imageRef{"registry-1.docker.io", "library/redis", "7"}},
+		{"ghcr.io/immich-app/immich-server:release", imageRef{"ghcr.io", "immich-app/immich-server", "release"}},
+		{"localhost:5000/app", imageRef{"localhost:5000", "app", "latest"}},
+		{"registry.lan:5000/team/app:v2", imageRef{"registry.lan:5000", "team/app", "v2"}},
+	}
+	for _, tt := range tests {
+		got, err := parseReference(tt.ref)
+		if err != nil {
+			t.Errorf("parseReference(%q): %v", tt.ref, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseReference(%q) = %+v, want %+v", tt.ref, got, tt.want)
+		}
+	}
+
+	for _, ref := range []string{"", "nginx@sha256:abc", "nginx:"} {
+		if _, err := parseReference(ref); err == nil {
+			t.Errorf("parseReference(%q) should fail", ref)
+		}
+	}
+}
+
+// fakeRegistry serves manifests over TLS behind anonymous token auth, like
+// Docker Hub. Manifests without a digest header exercise the GET fallback.
+func fakeRegistry(t *testing.T, digests map[string]string, noHeader map[string]string) string {
+	t.Helper()
+	var srv *httptest.Server
+	srv = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			if r.URL.Query().Get("scope") == "" {
+				t.Errorf("token request without scope: %q", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `{"token": "anon"}`)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer anon" {
+			repo, _, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/v2/"), "/manifests/")
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="fake",scope="repository:%s:pull"`, srv.URL, repo))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if !strings.Contains(r.Header.Get("Accept"), "application/vnd.oci.image.index.v1+json") {
+			t.Errorf("missing OCI index in Accept: %q", r.Header.Get("Accept"))
+		}
+		if d, ok := digests[r.URL.Path]; ok {
+			w.Header().Set("Docker-Content-Digest", d)
+			return
+		}
+		if body, ok := noHeader[r.URL.Path]; ok {
+			if r.Method == http.MethodGet {
+				fmt.Fprint(w, body)
+			}
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(srv.Close)
+
+	old := registryClient
+	registryClient = srv.Client()
+	t.Cleanup(func() { registryClient = old })
+	return strings.TrimPrefix(srv.URL, "https://")
+}
+
+func TestRemoteDigest(t *testing.T) {
+	host := fakeRegistry(t,
+		map[string]string{"/v2/app/manifests/1.0": "sha256:aaa"},
+		map[string]string{"/v2/plain/manifests/latest": "{}"},
+	)
+
+	got, err := RemoteDigest(host + "/app:1.0")
+	if err != nil || got != "sha256:aaa" {
+		t.Errorf("RemoteDigest(app:1.0) = %q, %v", got, err)
+	}
+	// sha256 of "{}"
+	got, err = RemoteDigest(host + "/plain")
+	if want := "sha256:44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a"; err != nil || got != want {
+		t.Errorf("RemoteDigest(plain) = %q, %v; want %q", got, err, want)
+	}
+	if _, err := RemoteDigest(host + "/missing:1"); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("expected 404 error for missing tag, got %v", err)
+	}
+}
+
+func TestRemoteDigestPlainHTTP(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/app/manifests/1.0" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Docker-Content-Digest", "sha256:plain")
+	}))
+	t.Cleanup(srv.Close)
+	// every registry host resolves to the plain http server
+	old := registryClient
+	registryClient = &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, srv.Listener.Addr().String())
+		},
+	}}
+	t.Cleanup(func() { registryClient = old })
+
+	host := strings.TrimPrefix(srv.URL, "http://")
+	if got, err := RemoteDigest(host + "/app:1.0"); err != nil || got != "sha256:plain" {
+		t.Errorf("loopback registry should fall back to http: %q, %v", got, err)
+	}
+	if got, err := RemoteDigest("localhost:5000/app:1.0"); err != nil || got != "sha256:plain" {
+		t.Errorf("localhost registry should fall back to http: %q, %v", got, err)
+	}
+	if _, err := RemoteDigest("registry.lan:5000/app:1.0"); err == nil || !strings.Contains(err.Error(), "unsupported registry") {
+		t.Errorf("expected an unsupported registry error, got %v", err)
+	}
+
+	_, lan, _ := net.ParseCIDR("192.168.1.0/24")
+	insecure := insecureRegistries{hosts: map[string]bool{"registry.lan:5000": true}, nets: []*net.IPNet{lan}}
+	for _, ref := range []string{"registry.lan:5000/app:1.0", "192.168.1.20:5000/app:1.0"} {
+		if got, err := remoteDigest(ref, insecure); err != nil || got != "sha256:plain" {
+			t.Errorf("%s is insecure, should fall back to http: %q, %v", ref, got, err)
+		}
+	}
+	if _, err := remoteDigest("192.168.2.20:5000/app:1.0", insecure); err == nil {
+		t.Error("192.168.2.20 is outside the insecure CIDR")
+	}
+}
+
+func TestClientInsecureRegistries(t *testing.T) {
+	c := fakeDaemon(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/info" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"RegistryConfig": {
+			"InsecureRegistryCIDRs": ["127.0.0.0/8", "10.0.0.0/8"],
+			"IndexConfigs": {"docker.io": {"Secure": true}, "registry.lan:5000": {"Secure": false}}}}`)
+	}))
+	ir := c.insecureRegistries()
+	for registry, want := range map[string]bool{
+		"registry.lan:5000": true,
+		"10.1.2.3:5000":     true,
+		"localhost:5000":    true,
+		"[::1]:5000":        true,
+		"docker.io":         false,
+		"ghcr.io":           false,
+		"registry.lan":      false,
+	} {
+		if got := ir.allows(registry); got != want {
+			t.Errorf("allows(%q) = %v, want %v", registry, got, want)
+		}
+	}
+}
+
+func TestClientUpdates(t *testing.T) {
+	host := fakeRegistry(t, map[string]string{
+		"/v2/web/manifests/1.0": "sha256:current",
+		"/v2/db/manifests/16":   "sha256:newer",
+	}, nil)
+
+	// name -> image reference, image ID, repo digests
+	containers := []struct{ name, ref, id, repoDigests string }{
+		{"web", host + "/web:1.0", "sha256:img1", `["` + host + `/web@sha256:old", "` + host + `/web@sha256:current"]`},
+		{"web2", host + "/web:1.0", "sha256:img1", `["` + host + `/web@sha256:current"]`},
+		{"db", host + "/db:16", "sha256:img2", `["` + host + `/db@sha256:older"]`},
+		{"built", "myapp:dev", "sha256:img3", `[]`},
+		{"pinned", host + "/web@sha256:old", "sha256:img4", `["` + host + `/web@sha256:old"]`},
+		{"gone", host + "/gone:1", "sha256:img5", `["` + host + `/gone@sha256:x"]`},
+	}
+	c := fakeDaemon(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/containers/json" {
+			var list []string
+			for _, ctr := range containers {
+				list = append(list, fmt.Sprintf(`{"Id": "%s-id", "Names": ["/%s"]}`, ctr.name, ctr.name))
+			}
+			fmt.Fprintf(w, "[%s]", strings.Join(list, ","))
+			return
+		}
+		for _, ctr := range containers {
+			switch r.URL.Path {
+			case "/containers/" + ctr.name + "-id/json":
+				fmt.Fprintf(w, `{"Image": "%s", "Config": {"Image": "%s"}}`, ctr.id, ctr.ref)
+				return
+			case "/images/" + ctr.id + "/json":
+				fmt.Fprintf(w, `{"RepoDigests": %s}`, ctr.repoDigests)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+
+	updates, err := c.Updates()
+	if err != nil {
+		t.Fatalf("Updates: %v", err)
+	}
+	want := map[string]string{
+		"web":    "up_to_date",
+		"web2":   "up_to_date",
+		"db":     "update_available",
+		"built":  "local",
+		"pinned": "pinned",
+		"gone":   "error",
+	}
+	if len(updates) != len(want) {
+		t.Fatalf("expected %d results, got %+v", len(want), updates)
+	}
+	for _, u := range updates {
+		if u.Status != want[u.Container] {
+			t.Errorf("%s: status %q, want %q (%+v)", u.Container, u.Status, want[u.Container], u)
+		}
+	}
+	if updates[0].LocalDigest != "sha256:current" {
+		t.Errorf("web should report the matching repo digest, got %q", updates[0].LocalDigest)
+	}
+	if updates[2].LocalDigest != "sha256:older" || updates[2].RemoteDigest != "sha256:newer" {
+		t.Errorf("db digests: %+v", updates[2])
+	}
+	if updates[5].Error == "" {
+		t.Error("error result should carry the registry error")
+	}
+}
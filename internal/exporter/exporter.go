@@ -0,0 +1,127 @@
+// Package exporter reads the status, containers and alerts of each server
+// for monitoring systems: as a Prometheus scrape of `homebutler serve`, or
+// pushed as InfluxDB line protocol by `homebutler metrics push`.
+package exporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Higangssh/homebutler/internal/alerts"
+	"github.com/Higangssh/homebutler/internal/config"
+	"github.com/Higangssh/homebutler/internal/docker"
+	"github.com/Higangssh/homebutler/internal/remote"
+	"github.com/Higangssh/homebutler/internal/system"
+)
+
+// Snapshot is what one server reported at a time. Containers and Alerts
+// are nil when reading them failed, e.g. on a host without docker; Err is
+// set when the status itself couldn't be read.
+type Snapshot struct {
+	Server     string
+	Time       time.Time
+	Status     *system.StatusInfo
+	Containers []docker.Container
+	Alerts     *alerts.AlertResult
+	Err        error
+}
+
+// Scrape reads a server: local entries directly, remote ones over SSH,
+// the three in parallel.
+func Scrape(cfg *config.Config, srv *config.ServerConfig, now time.Time) Snapshot {
+	snap := Snapshot{Server: srv.Name, Time: now}
+	var wg sync.WaitGroup
+	if srv.Local {
+		wg.Go(func() { snap.Status, snap.Err = system.Status() })
+		wg.Go(func() { snap.Containers, _ = docker.List() })
+		wg.Go(func() { snap.Alerts, _ = alerts.Evaluate(cfg, srv) })
+	} else {
+		wg.Go(func() { snap.Err = runJSON(srv, &snap.Status, "status", "--json") })
+		wg.Go(func() {
+			if runJSON(srv, &snap.Containers, "docker", "list", "--json") != nil {
+				snap.Containers = nil
+			}
+		})
+		wg.Go(func() { snap.Alerts, _ = alerts.Evaluate(cfg, srv) })
+	}
+	wg.Wait()
+	return snap
+}
+
+// ScrapeAll reads the servers in parallel.
+func ScrapeAll(cfg *config.Config, servers []config.ServerConfig, now time.Time) []Snapshot {
+	snaps := make([]Snapshot, len(servers))
+	var wg sync.WaitGroup
+	for i := range servers {
+		wg.Go(func() { snaps[i] = Scrape(cfg, &servers[i], now) })
+	}
+	wg.Wait()
+	return snaps
+}
+
+func runJSON(srv *config.ServerConfig, v any, args ...string) error {
+	out, err := remote.Run(srv, args...)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(out, v); err != nil {
+		return fmt.Errorf("invalid %s output: %w", args[0], err)
+	}
+	return nil
+}
+
+// scrape is Scrape, replaced in tests.
+var scrape = Scrape
+
+// Cache keeps each server's snapshot for a while, so that frequent or
+// concurrent scrapes reach a server over SSH once per TTL, not once per
+// request.
+type Cache struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+}
+
+type cacheEntry struct {
+	mu   sync.Mutex // held while the server is read, so concurrent scrapes wait for it
+	snap Snapshot
+}
+
+// NewCache returns a cache keeping snapshots for ttl.
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{ttl: ttl, entries: map[string]*cacheEntry{}}
+}
+
+// Snapshots returns a snapshot of every server, reading the ones whose
+// cached snapshot is older than the TTL in parallel.
+func (c *Cache) Snapshots(cfg *config.Config, servers []config.ServerConfig, now time.Time) []Snapshot {
+	snaps := make([]Snapshot, len(servers))
+	var wg sync.WaitGroup
+	for i := range servers {
+		srv := &servers[i]
+		e := c.entry(srv.Name)
+		wg.Go(func() {
+			e.mu.Lock()
+			defer e.mu.Unlock()
+			if e.snap.Time.IsZero() || now.Sub(e.snap.Time) >= c.ttl {
+				e.snap = scrape(cfg, srv, now)
+			}
+			snaps[i] = e.snap
+		})
+	}
+	wg.Wait()
+	return snaps
+}
+
+func (c *Cache) entry(server string) *cacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[server]
+	if !ok {
+		e = &cacheEntry{}
+		c.entries[server] = e
+	}
+	return e
+}
@@ -0,0 +1,150 @@
+package exporter
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Higangssh/homebutler/internal/alerts"
+	"github.com/Higangssh/homebutler/internal/config"
+	"github.com/Higangssh/homebutler/internal/docker"
+	"github.com/Higangssh/homebutler/internal/system"
+)
+
+var testTime = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func nasSnapshot() Snapshot {
+	return Snapshot{
+		Server: "nas",
+		Time:   testTime,
+		Status: &system.StatusInfo{
+			CPU:    system.CPUInfo{UsagePercent: 12.5, Cores: 4, Load: system.LoadAvg{Load1: 0.5}},
+			Memory: system.MemInfo{TotalBytes: 16 << 30, UsedBytes: 4 << 30, Percent: 25},
+			Disks:  []system.DiskInfo{{Mount: "/mnt/data", TotalBytes: 1000, UsedBytes: 970, Percent: 97, InodesTotal: 10, InodePercent: 10}},
+			Network: []system.NetInfo{
+				{Interface: "eth0", RxBytesPerSec: 1024, TxBytesPerSec: 512},
+			},
+		},
+		Containers: []docker.Container{
+			{Name: "plex", Image: "plexinc/pms-docker", State: "running", Health: "unhealthy", RestartCount: 2},
+			{Name: "backup", Image: "restic/restic", State: "exited"},
+		},
+		Alerts: &alerts.AlertResult{
+			CPU:    alerts.AlertItem{Status: "ok", Current: 12.5, Threshold: 90},
+			Memory: alerts.AlertItem{Status: "ok", Current: 25, Threshold: 85, Warning: 80},
+			Disks:  []alerts.DiskAlert{{Mount: "/mnt/data", Status: "critical", Current: 97, Threshold: 90, Silenced: true}},
+		},
+	}
+}
+
+func TestWritePrometheus(t *testing.T) {
+	down := Snapshot{Server: "rpi", Time: testTime, Err: errors.New("connection refused")}
+	var b strings.Builder
+	if err := WritePrometheus(&b, []Snapshot{nasSnapshot(), down}); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		"# HELP homebutler_up Whether the server's status could be read (1) or not (0).\n# TYPE homebutler_up gauge\nhomebutler_up{server=\"nas\"} 1\nhomebutler_up{server=\"rpi\"} 0\n",
+		`homebutler_cpu_usage_percent{server="nas"} 12.5`,
+		`homebutler_memory_total_bytes{server="nas"} 17179869184`,
+		`homebutler_disk_usage_percent{server="nas",mount="/mnt/data"} 97`,
+		`homebutler_network_receive_bytes_per_second{server="nas",interface="eth0"} 1024`,
+		`homebutler_container_running{server="nas",container="plex",image="plexinc/pms-docker",state="running"} 1`,
+		`homebutler_container_running{server="nas",container="backup",image="restic/restic",state="exited"} 0`,
+		`homebutler_container_healthy{server="nas",container="plex"} 0`,
+		`homebutler_alert_status{server="nas",metric="disk:/mnt/data"} 2`,
+		`homebutler_alert_silenced{server="nas",metric="disk:/mnt/data"} 1`,
+		`homebutler_alert_silenced{server="nas",metric="cpu"} 0`,
+		`homebutler_alert_threshold{server="nas",metric="memory",level="warning"} 80`,
+		// results without a warning level had it at 90% of critical
+		`homebutler_alert_threshold{server="nas",metric="cpu",level="warning"} 81`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, `homebutler_cpu_usage_percent{server="rpi"}`) {
+		t.Error("an unreachable server should only report homebutler_up")
+	}
+	if strings.Contains(out, `container_healthy{server="nas",container="backup"}`) {
+		t.Error("containers without a healthcheck should have no health sample")
+	}
+}
+
+func TestWritePrometheusUniqueSeries(t *testing.T) {
+	// two NVMe drives, named apart by the alerts check
+	snap := nasSnapshot()
+	snap.Alerts.Temperatures = []alerts.TempAlert{
+		{Sensor: "nvme/Composite@nvme0", Status: "ok", Current: 41, Threshold: 80},
+		{Sensor: "nvme/Composite@nvme1", Status: "ok", Current: 45, Threshold: 80},
+	}
+	var b strings.Builder
+	if err := WritePrometheus(&b, []Snapshot{snap}); err != nil {
+		t.Fatal(err)
+	}
+	seen := map[string]bool{}
+	for line := range strings.Lines(b.String()) {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		series, _, _ := strings.Cut(line, "} ")
+		if seen[series] {
+			t.Errorf("duplicate series %s}", series)
+		}
+		seen[series] = true
+	}
+	if !seen[`homebutler_alert_status{server="nas",metric="temperature:nvme/Composite@nvme1"`] {
+		t.Errorf("expected a series per drive:\n%s", b.String())
+	}
+}
+
+func TestLineProtocol(t *testing.T) {
+	down := Snapshot{Server: "rpi 4", Time: testTime, Err: errors.New("connection refused")}
+	lines := LineProtocol([]Snapshot{nasSnapshot(), down})
+	ts := " 1772366400000000000"
+	for _, want := range []string{
+		"homebutler,server=nas up=1" + ts,
+		"homebutler_cpu,server=nas usage_percent=12.5,iowait_percent=0,cores=4" + ts,
+		"homebutler_memory,server=nas total_bytes=17179869184,used_bytes=4294967296,usage_percent=25" + ts,
+		"homebutler_disk,server=nas,mount=/mnt/data total_bytes=1000,used_bytes=970,usage_percent=97,inodes_usage_percent=10" + ts,
+		"homebutler_container,server=nas,container=plex,image=plexinc/pms-docker,state=running running=1,restarts=2,healthy=0" + ts,
+		"homebutler_alert,server=nas,metric=disk:/mnt/data status=2,value=97,threshold=90,warning=81,silenced=1" + ts,
+		`homebutler,server=rpi\ 4 up=0` + ts,
+	} {
+		if !slices.Contains(lines, want) {
+			t.Errorf("expected line %q in:\n%s", want, strings.Join(lines, "\n"))
+		}
+	}
+}
+
+func TestEscapeLabel(t *testing.T) {
+	if got := escapeLabel("a\"b\\c\nd"); got != `a\"b\\c\nd` {
+		t.Errorf("unexpected escape: %s", got)
+	}
+}
+
+func TestCacheReadsOncePerTTL(t *testing.T) {
+	var calls atomic.Int32
+	orig := scrape
+	scrape = func(cfg *config.Config, srv *config.ServerConfig, now time.Time) Snapshot {
+		calls.Add(1)
+		return Snapshot{Server: srv.Name, Time: now}
+	}
+	defer func() { scrape = orig }()
+
+	c := NewCache(10 * time.Second)
+	servers := []config.ServerConfig{{Name: "nas"}, {Name: "rpi"}}
+	c.Snapshots(&config.Config{}, servers, testTime)
+	snaps := c.Snapshots(&config.Config{}, servers, testTime.Add(5*time.Second))
+	if calls.Load() != 2 || snaps[1].Server != "rpi" || !snaps[1].Time.Equal(testTime) {
+		t.Errorf("expected the cached snapshots, got %d reads and %+v", calls.Load(), snaps)
+	}
+	c.Snapshots(&config.Config{}, servers, testTime.Add(10*time.Second))
+	if calls.Load() != 4 {
+		t.Errorf("expected a re-read after the TTL, got %d reads", calls.Load())
+	}
+}
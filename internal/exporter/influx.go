@@ -0,0 +1,100 @@
+package exporter
+
+import (
+	"strconv"
+	"strings"
+)
+
+// LineProtocol converts the snapshots to InfluxDB line protocol with
+// nanosecond timestamps. Measurement and field names follow the
+// Prometheus ones: VictoriaMetrics, which joins them with an underscore,
+// stores homebutler_cpu usage_percent as homebutler_cpu_usage_percent.
+func LineProtocol(snaps []Snapshot) []string {
+	var lines []string
+	for i := range snaps {
+		s := &snaps[i]
+		l := lineWriter{server: s.Server, ts: strconv.FormatInt(s.Time.UnixNano(), 10)}
+		l.add("homebutler", nil, field{"up", boolValue(s.Err == nil && s.Status != nil)})
+		if st := s.Status; st != nil {
+			l.add("homebutler", nil, field{"load1", st.CPU.Load.Load1}, field{"load5", st.CPU.Load.Load5}, field{"load15", st.CPU.Load.Load15})
+			l.add("homebutler_cpu", nil, field{"usage_percent", st.CPU.UsagePercent}, field{"iowait_percent", st.CPU.IOWait}, field{"cores", float64(st.CPU.Cores)})
+			l.add("homebutler_memory", nil, field{"total_bytes", float64(st.Memory.TotalBytes)}, field{"used_bytes", float64(st.Memory.UsedBytes)}, field{"usage_percent", st.Memory.Percent})
+			l.add("homebutler_swap", nil, field{"total_bytes", float64(st.Memory.Swap.TotalBytes)}, field{"used_bytes", float64(st.Memory.Swap.UsedBytes)})
+			for _, d := range st.Disks {
+				fields := []field{{"total_bytes", float64(d.TotalBytes)}, {"used_bytes", float64(d.UsedBytes)}, {"usage_percent", d.Percent}}
+				if d.InodesTotal > 0 {
+					fields = append(fields, field{"inodes_usage_percent", d.InodePercent})
+				}
+				l.add("homebutler_disk", []string{"mount", d.Mount}, fields...)
+			}
+			for _, d := range st.DiskIO {
+				l.add("homebutler_disk", []string{"device", d.Device}, field{"read_bytes_per_second", d.ReadBytesPerSec}, field{"write_bytes_per_second", d.WriteBytesPerSec})
+			}
+			for _, n := range st.Network {
+				l.add("homebutler_network", []string{"interface", n.Interface}, field{"receive_bytes_per_second", n.RxBytesPerSec}, field{"transmit_bytes_per_second", n.TxBytesPerSec}, field{"errors_per_second", n.ErrorsPerSec})
+			}
+		}
+		for _, c := range s.Containers {
+			fields := []field{{"running", boolValue(c.State == "running")}, {"restarts", float64(c.RestartCount)}}
+			if c.Health != "" {
+				fields = append(fields, field{"healthy", boolValue(c.Health == "healthy")})
+			}
+			l.add("homebutler_container", []string{"container", c.Name, "image", c.Image, "state", c.State}, fields...)
+		}
+		if s.Alerts != nil {
+			for _, m := range s.Alerts.Metrics() {
+				warning, critical := alertLevels(m)
+				l.add("homebutler_alert", []string{"metric", m.Key}, field{"status", float64(severity(m.Status))}, field{"value", m.Current}, field{"threshold", critical}, field{"warning", warning}, field{"silenced", boolValue(m.Silenced)})
+			}
+		}
+		lines = append(lines, l.lines...)
+	}
+	return lines
+}
+
+// lineWriter builds the lines of one snapshot.
+type lineWriter struct {
+	server, ts string
+	lines      []string
+}
+
+type field struct {
+	name  string
+	value float64
+}
+
+// add writes a line; tags come in name/value pairs.
+func (l *lineWriter) add(measurement string, tags []string, fields ...field) {
+	var b strings.Builder
+	b.WriteString(measurement)
+	b.WriteString(",server=")
+	b.WriteString(escapeTag(l.server))
+	for i := 0; i+1 < len(tags); i += 2 {
+		if tags[i+1] == "" {
+			continue // empty tag values are invalid
+		}
+		b.WriteByte(',')
+		b.WriteString(tags[i])
+		b.WriteByte('=')
+		b.WriteString(escapeTag(tags[i+1]))
+	}
+	for i, f := range fields {
+		if i == 0 {
+			b.WriteByte(' ')
+		} else {
+			b.WriteByte(',')
+		}
+		b.WriteString(f.name)
+		b.WriteByte('=')
+		b.WriteString(strconv.FormatFloat(f.value, 'f', -1, 64))
+	}
+	b.WriteByte(' ')
+	b.WriteString(l.ts)
+	l.lines = append(l.lines, b.String())
+}
+
+var tagEscaper = strings.NewReplacer(`,`, `\,`, `=`, `\=`, ` `, `\ `, "\n", `\n`)
+
+func escapeTag(v string) string {
+	return tagEscaper.Replace(v)
+}
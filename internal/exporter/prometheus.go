@@ -0,0 +1,218 @@
+package exporter
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Higangssh/homebutler/internal/alerts"
+	"github.com/Higangssh/homebutler/internal/config"
+)
+
+// gauge is one metric family: its samples are added per snapshot.
+type gauge struct {
+	name, help string
+	collect    func(s *Snapshot, add func(value float64, labels ...string))
+}
+
+// gauges are the exported families. Every sample also has a server label;
+// the rest come in name/value pairs.
+var gauges = []gauge{
+	{"homebutler_up", "Whether the server's status could be read (1) or not (0).", func(s *Snapshot, add func(float64, ...string)) {
+		add(boolValue(s.Err == nil && s.Status != nil))
+	}},
+	{"homebutler_cpu_usage_percent", "CPU usage in percent.", withStatus(func(s *Snapshot, add func(float64, ...string)) {
+		add(s.Status.CPU.UsagePercent)
+	})},
+	{"homebutler_cpu_iowait_percent", "CPU time waiting for I/O in percent.", withStatus(func(s *Snapshot, add func(float64, ...string)) {
+		add(s.Status.CPU.IOWait)
+	})},
+	{"homebutler_cpu_cores", "Number of CPU cores.", withStatus(func(s *Snapshot, add func(float64, ...string)) {
+		add(float64(s.Status.CPU.Cores))
+	})},
+	{"homebutler_load1", "1-minute load average.", withStatus(func(s *Snapshot, add func(float64, ...string)) {
+		add(s.Status.CPU.Load.Load1)
+	})},
+	{"homebutler_load5", "5-minute load average.", withStatus(func(s *Snapshot, add func(float64, ...string)) {
+		add(s.Status.CPU.Load.Load5)
+	})},
+	{"homebutler_load15", "15-minute load average.", withStatus(func(s *Snapshot, add func(float64, ...string)) {
+		add(s.Status.CPU.Load.Load15)
+	})},
+	{"homebutler_memory_total_bytes", "Total memory in bytes.", withStatus(func(s *Snapshot, add func(float64, ...string)) {
+		add(float64(s.Status.Memory.TotalBytes))
+	})},
+	{"homebutler_memory_used_bytes", "Used memory in bytes.", withStatus(func(s *Snapshot, add func(float64, ...string)) {
+		add(float64(s.Status.Memory.UsedBytes))
+	})},
+	{"homebutler_memory_usage_percent", "Memory usage in percent.", withStatus(func(s *Snapshot, add func(float64, ...string)) {
+		add(s.Status.Memory.Percent)
+	})},
+	{"homebutler_swap_total_bytes", "Total swap in bytes.", withStatus(func(s *Snapshot, add func(float64, ...string)) {
+		add(float64(s.Status.Memory.Swap.TotalBytes))
+	})},
+	{"homebutler_swap_used_bytes", "Used swap in bytes.", withStatus(func(s *Snapshot, add func(float64, ...string)) {
+		add(float64(s.Status.Memory.Swap.UsedBytes))
+	})},
+	{"homebutler_disk_total_bytes", "Filesystem size in bytes.", withStatus(func(s *Snapshot, add func(float64, ...string)) {
+		for _, d := range s.Status.Disks {
+			add(float64(d.TotalBytes), "mount", d.Mount)
+		}
+	})},
+	{"homebutler_disk_used_bytes", "Used filesystem space in bytes.", withStatus(func(s *Snapshot, add func(float64, ...string)) {
+		for _, d := range s.Status.Disks {
+			add(float64(d.UsedBytes), "mount", d.Mount)
+		}
+	})},
+	{"homebutler_disk_usage_percent", "Filesystem usage in percent.", withStatus(func(s *Snapshot, add func(float64, ...string)) {
+		for _, d := range s.Status.Disks {
+			add(d.Percent, "mount", d.Mount)
+		}
+	})},
+	{"homebutler_disk_inodes_usage_percent", "Inode usage in percent.", withStatus(func(s *Snapshot, add func(float64, ...string)) {
+		for _, d := range s.Status.Disks {
+			if d.InodesTotal > 0 {
+				add(d.InodePercent, "mount", d.Mount)
+			}
+		}
+	})},
+	{"homebutler_disk_read_bytes_per_second", "Disk read rate in bytes per second.", withStatus(func(s *Snapshot, add func(float64, ...string)) {
+		for _, d := range s.Status.DiskIO {
+			add(d.ReadBytesPerSec, "device", d.Device)
+		}
+	})},
+	{"homebutler_disk_write_bytes_per_second", "Disk write rate in bytes per second.", withStatus(func(s *Snapshot, add func(float64, ...string)) {
+		for _, d := range s.Status.DiskIO {
+			add(d.WriteBytesPerSec, "device", d.Device)
+		}
+	})},
+	{"homebutler_network_receive_bytes_per_second", "Network receive rate in bytes per second.", withStatus(func(s *Snapshot, add func(float64, ...string)) {
+		for _, n := range s.Status.Network {
+			add(n.RxBytesPerSec, "interface", n.Interface)
+		}
+	})},
+	{"homebutler_network_transmit_bytes_per_second", "Network transmit rate in bytes per second.", withStatus(func(s *Snapshot, add func(float64, ...string)) {
+		for _, n := range s.Status.Network {
+			add(n.TxBytesPerSec, "interface", n.Interface)
+		}
+	})},
+	{"homebutler_network_errors_per_second", "Network errors and drops per second, both directions.", withStatus(func(s *Snapshot, add func(float64, ...string)) {
+		for _, n := range s.Status.Network {
+			add(n.ErrorsPerSec, "interface", n.Interface)
+		}
+	})},
+	{"homebutler_container_running", "Whether the container is running (1) or not (0).", func(s *Snapshot, add func(float64, ...string)) {
+		for _, c := range s.Containers {
+			add(boolValue(c.State == "running"), "container", c.Name, "image", c.Image, "state", c.State)
+		}
+	}},
+	{"homebutler_container_healthy", "Whether the container's healthcheck passes (1) or not (0); only containers with one.", func(s *Snapshot, add func(float64, ...string)) {
+		for _, c := range s.Containers {
+			if c.Health != "" {
+				add(boolValue(c.Health == "healthy"), "container", c.Name)
+			}
+		}
+	}},
+	{"homebutler_container_restarts", "Times the container was restarted.", func(s *Snapshot, add func(float64, ...string)) {
+		for _, c := range s.Containers {
+			add(float64(c.RestartCount), "container", c.Name)
+		}
+	}},
+	{"homebutler_alert_status", "Alert status: 0 ok, 1 warning, 2 critical.", alertMetrics(func(m alerts.Metric, _, _ float64, add func(float64, ...string)) {
+		add(float64(severity(m.Status)), "metric", m.Key)
+	})},
+	{"homebutler_alert_silenced", "Whether the alert is silenced (1) or not (0).", alertMetrics(func(m alerts.Metric, _, _ float64, add func(float64, ...string)) {
+		add(boolValue(m.Silenced), "metric", m.Key)
+	})},
+	{"homebutler_alert_value", "Current value of the alert's metric.", alertMetrics(func(m alerts.Metric, _, _ float64, add func(float64, ...string)) {
+		add(m.Current, "metric", m.Key)
+	})},
+	{"homebutler_alert_threshold", "Alert level of the metric.", alertMetrics(func(m alerts.Metric, warning, critical float64, add func(float64, ...string)) {
+		if warning > 0 {
+			add(warning, "metric", m.Key, "level", "warning")
+		}
+		add(critical, "metric", m.Key, "level", "critical")
+	})},
+}
+
+// withStatus skips the snapshots without a status.
+func withStatus(collect func(s *Snapshot, add func(float64, ...string))) func(s *Snapshot, add func(float64, ...string)) {
+	return func(s *Snapshot, add func(float64, ...string)) {
+		if s.Status != nil {
+			collect(s, add)
+		}
+	}
+}
+
+// alertMetrics calls collect for every metric of the snapshot's alerts.
+func alertMetrics(collect func(m alerts.Metric, warning, critical float64, add func(float64, ...string))) func(s *Snapshot, add func(float64, ...string)) {
+	return func(s *Snapshot, add func(float64, ...string)) {
+		if s.Alerts == nil {
+			return
+		}
+		for _, m := range s.Alerts.Metrics() {
+			warning, critical := alertLevels(m)
+			collect(m, warning, critical, add)
+		}
+	}
+}
+
+// alertLevels are the levels an alert metric was checked against.
+// Container rules count events rather than checking levels, so they have
+// no warning level.
+func alertLevels(m alerts.Metric) (warning, critical float64) {
+	if m.Kind() == "container" {
+		return m.Warning, m.Threshold
+	}
+	return config.Threshold{Warning: m.Warning, Critical: m.Threshold}.Levels()
+}
+
+func severity(status string) int {
+	switch status {
+	case "critical":
+		return 2
+	case "warning":
+		return 1
+	}
+	return 0
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// WritePrometheus writes the snapshots in the Prometheus text exposition
+// format, the samples of each family together.
+func WritePrometheus(w io.Writer, snaps []Snapshot) error {
+	var b strings.Builder
+	for _, g := range gauges {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s gauge\n", g.name, g.help, g.name)
+		for i := range snaps {
+			s := &snaps[i]
+			g.collect(s, func(value float64, labels ...string) {
+				b.WriteString(g.name)
+				b.WriteString(`{server="`)
+				b.WriteString(escapeLabel(s.Server))
+				b.WriteByte('"')
+				for j := 0; j+1 < len(labels); j += 2 {
+					fmt.Fprintf(&b, `,%s="%s"`, labels[j], escapeLabel(labels[j+1]))
+				}
+				b.WriteString("} ")
+				b.WriteString(strconv.FormatFloat(value, 'f', -1, 64))
+				b.WriteByte('\n')
+			})
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(v string) string {
+	return labelEscaper.Replace(v)
+}
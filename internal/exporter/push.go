@@ -0,0 +1,221 @@
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Higangssh/homebutler/internal/alerts"
+	"github.com/Higangssh/homebutler/internal/config"
+)
+
+// pushAttempts is how often a batch is sent before it stays buffered.
+const pushAttempts = 3
+
+// Pusher sends line protocol to an HTTP write endpoint in batches. What
+// it can't send is kept in a buffer file and goes first on the next push,
+// so an outage of the endpoint loses nothing up to the buffer size.
+type Pusher struct {
+	url                string
+	token              string
+	username, password string
+	batch              int
+	buffer             int
+	bufferPath         string
+	client             *http.Client
+	retryDelay         time.Duration // doubled after each failed attempt
+}
+
+// NewPusher checks the metrics.push config.
+func NewPusher(c config.PushConfig, bufferPath string) (*Pusher, error) {
+	if c.URL == "" {
+		return nil, fmt.Errorf("metrics.push.url is required")
+	}
+	if u, err := url.Parse(c.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("metrics.push: invalid url %q", c.URL)
+	}
+	p := &Pusher{
+		url:        c.URL,
+		token:      c.Token,
+		username:   c.Username,
+		password:   c.Password,
+		batch:      c.Batch,
+		buffer:     c.Buffer,
+		bufferPath: bufferPath,
+		client:     &http.Client{Timeout: 30 * time.Second},
+		retryDelay: time.Second,
+	}
+	if p.batch <= 0 {
+		p.batch = 5000
+	}
+	return p, nil
+}
+
+// DefaultBufferPath is $XDG_STATE_HOME/homebutler/push-buffer.lp, next to
+// the alert state.
+func DefaultBufferPath() string {
+	return filepath.Join(filepath.Dir(alerts.DefaultStatePath()), "push-buffer.lp")
+}
+
+// PushResult is the outcome of one push.
+type PushResult struct {
+	Sent     int    `json:"sent"`     // lines written, buffered ones included
+	Buffered int    `json:"buffered"` // lines kept for the next push
+	Dropped  int    `json:"dropped"`  // lines the endpoint rejected or the buffer had no room for
+	Error    string `json:"error,omitempty"`
+}
+
+// Push sends the buffered lines and then lines, oldest first. Once a batch
+// fails after retries, it and the rest are buffered and the error is
+// returned. A batch the endpoint rejects as invalid (4xx) is dropped, so
+// it can't block the ones after it.
+func (p *Pusher) Push(ctx context.Context, lines []string) (*PushResult, error) {
+	pending, err := p.load()
+	if err != nil {
+		return nil, err
+	}
+	pending = append(pending, lines...)
+	result := &PushResult{}
+
+	var pushErr error
+	for len(pending) > 0 {
+		n := min(p.batch, len(pending))
+		err := p.send(ctx, pending[:n])
+		var rejected *rejectedError
+		if errors.As(err, &rejected) {
+			result.Dropped += n
+			pushErr = errors.Join(pushErr, err)
+		} else if err != nil {
+			pushErr = errors.Join(pushErr, err)
+			break
+		} else {
+			result.Sent += n
+		}
+		pending = pending[n:]
+	}
+
+	if p.buffer > 0 && len(pending) > p.buffer {
+		result.Dropped += len(pending) - p.buffer
+		pending = pending[len(pending)-p.buffer:]
+	} else if p.buffer <= 0 {
+		result.Dropped += len(pending)
+		pending = nil
+	}
+	result.Buffered = len(pending)
+	if err := p.save(pending); err != nil {
+		pushErr = errors.Join(pushErr, err)
+	}
+	if pushErr != nil {
+		result.Error = pushErr.Error()
+	}
+	return result, pushErr
+}
+
+// rejectedError is an answer retrying won't change.
+type rejectedError struct {
+	status int
+	msg    string
+}
+
+func (e *rejectedError) Error() string {
+	return fmt.Sprintf("rejected: HTTP %d: %s", e.status, e.msg)
+}
+
+// send posts one batch, retrying network errors and 5xx, 408 and 429
+// answers.
+func (p *Pusher) send(ctx context.Context, lines []string) error {
+	body := []byte(strings.Join(lines, "\n") + "\n")
+	delay := p.retryDelay
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = p.post(ctx, body); err == nil {
+			return nil
+		}
+		var rejected *rejectedError
+		if errors.As(err, &rejected) || attempt == pushAttempts {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+func (p *Pusher) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if p.token != "" {
+		req.Header.Set("Authorization", "Token "+p.token)
+	} else if p.username != "" {
+		req.SetBasicAuth(p.username, p.password)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		return nil
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	text := strings.TrimSpace(string(msg))
+	switch {
+	case resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, text)
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusNotFound:
+		// a wrong token or URL: keep the lines until the config is fixed
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, text)
+	}
+	return &rejectedError{status: resp.StatusCode, msg: text}
+}
+
+// load reads the buffer file. A missing file is an empty buffer.
+func (p *Pusher) load() ([]string, error) {
+	data, err := os.ReadFile(p.bufferPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read push buffer: %w", err)
+	}
+	var lines []string
+	for line := range strings.SplitSeq(string(data), "\n") {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
+
+// save replaces the buffer file through a temporary file, or removes it
+// when nothing is left.
+func (p *Pusher) save(lines []string) error {
+	if len(lines) == 0 {
+		if err := os.Remove(p.bufferPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to clear push buffer: %w", err)
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(p.bufferPath), 0700); err != nil {
+		return fmt.Errorf("failed to save push buffer: %w", err)
+	}
+	tmp := p.bufferPath + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+		return fmt.Errorf("failed to save push buffer: %w", err)
+	}
+	return os.Rename(tmp, p.bufferPath)
+}
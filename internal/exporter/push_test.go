@@ -0,0 +1,145 @@
+package exporter
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Higangssh/homebutler/internal/config"
+)
+
+// influxStandIn answers writes with the next status of its script (204
+// once the script is used up) and records what it received.
+type influxStandIn struct {
+	mu       sync.Mutex
+	statuses []int
+	bodies   []string
+	auth     []string
+}
+
+func newStandIn(t *testing.T, statuses ...int) (*httptest.Server, *influxStandIn) {
+	t.Helper()
+	in := &influxStandIn{statuses: statuses}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		in.mu.Lock()
+		defer in.mu.Unlock()
+		status := http.StatusNoContent
+		if len(in.statuses) > 0 {
+			status, in.statuses = in.statuses[0], in.statuses[1:]
+		}
+		if status == http.StatusNoContent {
+			body, _ := io.ReadAll(r.Body)
+			in.bodies = append(in.bodies, string(body))
+			in.auth = append(in.auth, r.Header.Get("Authorization"))
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, in
+}
+
+func newTestPusher(t *testing.T, c config.PushConfig) *Pusher {
+	t.Helper()
+	if c.Batch == 0 {
+		c.Batch = 2
+	}
+	if c.Buffer == 0 {
+		c.Buffer = 100
+	}
+	p, err := NewPusher(c, filepath.Join(t.TempDir(), "buffer.lp"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.retryDelay = 0
+	return p
+}
+
+func TestPushBatches(t *testing.T) {
+	srv, in := newStandIn(t)
+	p := newTestPusher(t, config.PushConfig{URL: srv.URL + "/api/v2/write?bucket=homelab", Token: "s3cret"})
+	result, err := p.Push(context.Background(), []string{"a 1", "b 1", "c 1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Sent != 3 || result.Buffered != 0 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if len(in.bodies) != 2 || in.bodies[0] != "a 1\nb 1\n" || in.bodies[1] != "c 1\n" || in.auth[0] != "Token s3cret" {
+		t.Errorf("unexpected writes: %q %q", in.bodies, in.auth)
+	}
+}
+
+func TestPushRetries(t *testing.T) {
+	srv, in := newStandIn(t, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+	p := newTestPusher(t, config.PushConfig{URL: srv.URL, Username: "vm", Password: "pw"})
+	if result, err := p.Push(context.Background(), []string{"a 1"}); err != nil || result.Sent != 1 {
+		t.Fatalf("expected the third attempt to succeed, got %+v %v", result, err)
+	}
+	if len(in.bodies) != 1 || !strings.HasPrefix(in.auth[0], "Basic ") {
+		t.Errorf("unexpected writes: %q %q", in.bodies, in.auth)
+	}
+}
+
+func TestPushBuffersDuringOutage(t *testing.T) {
+	srv, in := newStandIn(t, 503, 503, 503)
+	p := newTestPusher(t, config.PushConfig{URL: srv.URL})
+
+	result, err := p.Push(context.Background(), []string{"a 1", "b 1", "c 1"})
+	if err == nil || result.Sent != 0 || result.Buffered != 3 {
+		t.Fatalf("expected everything buffered, got %+v %v", result, err)
+	}
+	if _, err := os.Stat(p.bufferPath); err != nil {
+		t.Fatalf("expected a buffer file: %v", err)
+	}
+
+	// back up: the buffered lines go first
+	result, err = p.Push(context.Background(), []string{"d 2"})
+	if err != nil || result.Sent != 4 || result.Buffered != 0 {
+		t.Fatalf("unexpected result after the outage: %+v %v", result, err)
+	}
+	if got := strings.Join(in.bodies, ""); got != "a 1\nb 1\nc 1\nd 2\n" {
+		t.Errorf("unexpected order: %q", got)
+	}
+	if _, err := os.Stat(p.bufferPath); !os.IsNotExist(err) {
+		t.Error("expected the buffer file removed once sent")
+	}
+}
+
+func TestPushBufferLimit(t *testing.T) {
+	srv, _ := newStandIn(t, 500, 500, 500)
+	p := newTestPusher(t, config.PushConfig{URL: srv.URL, Buffer: 2})
+	result, _ := p.Push(context.Background(), []string{"a 1", "b 1", "c 1"})
+	if result.Buffered != 2 || result.Dropped != 1 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	// the oldest lines go first
+	if lines, _ := p.load(); len(lines) != 2 || lines[0] != "b 1" {
+		t.Errorf("unexpected buffer: %q", lines)
+	}
+}
+
+func TestPushDropsRejectedBatches(t *testing.T) {
+	srv, in := newStandIn(t, http.StatusBadRequest)
+	p := newTestPusher(t, config.PushConfig{URL: srv.URL})
+	result, err := p.Push(context.Background(), []string{"bad", "line", "c 1"})
+	if err == nil || !strings.Contains(err.Error(), "HTTP 400") {
+		t.Errorf("expected the rejection reported, got %v", err)
+	}
+	if result.Dropped != 2 || result.Sent != 1 || result.Buffered != 0 || len(in.bodies) != 1 {
+		t.Errorf("expected the rejected batch dropped and the next sent, got %+v", result)
+	}
+}
+
+func TestNewPusherValidates(t *testing.T) {
+	for _, c := range []config.PushConfig{{}, {URL: "influx:8086"}, {URL: "ftp://influx/write"}} {
+		if _, err := NewPusher(c, "buffer.lp"); err == nil {
+			t.Errorf("expected error for %+v", c)
+		}
+	}
+}
@@ -2,11 +2,15 @@ package format
 
 import (
 	"fmt"
+	"math"
 	"strings"
 
 	"github.com/Higangssh/homebutler/internal/alerts"
 	"github.com/Higangssh/homebutler/internal/docker"
+	"github.com/Higangssh/homebutler/internal/exporter"
+	"github.com/Higangssh/homebutler/internal/history"
 	"github.com/Higangssh/homebutler/internal/network"
+	"github.com/Higangssh/homebutler/internal/notify"
 	"github.com/Higangssh/homebutler/internal/ports"
 	"github.com/Higangssh/homebutler/internal/system"
 )
@@ -17,13 +21,86 @@ func Status(info *system.StatusInfo) string {
 	fmt.Fprintf(&b, "🖥  %s (%s/%s)\n", info.Hostname, info.OS, info.Arch)
 	fmt.Fprintf(&b, "   Uptime:  %s\n", info.Uptime)
 	fmt.Fprintf(&b, "   CPU:     %.1f%% (%d cores)\n", info.CPU.UsagePercent, info.CPU.Cores)
+	fmt.Fprintf(&b, "            user %.1f%% · sys %.1f%% · iowait %.1f%% · steal %.1f%%\n",
+		info.CPU.User, info.CPU.System, info.CPU.IOWait, info.CPU.Steal)
+	if len(info.CPU.PerCore) > 0 {
+		cores := make([]string, len(info.CPU.PerCore))
+		for i, v := range info.CPU.PerCore {
+			cores[i] = fmt.Sprintf("%.0f%%", v)
+		}
+		fmt.Fprintf(&b, "   Cores:   %s\n", strings.Join(cores, " "))
+	}
+	fmt.Fprintf(&b, "   Load:    %.2f %.2f %.2f\n", info.CPU.Load.Load1, info.CPU.Load.Load5, info.CPU.Load.Load15)
 	fmt.Fprintf(&b, "   Memory:  %.1f / %.1f GB (%.1f%%)\n", info.Memory.UsedGB, info.Memory.TotalGB, info.Memory.Percent)
+	if info.Memory.BuffersBytes+info.Memory.CachedBytes > 0 {
+		fmt.Fprintf(&b, "            buffers %.1f GB · cached %.1f GB\n",
+			bytesToGB(info.Memory.BuffersBytes), bytesToGB(info.Memory.CachedBytes))
+	}
+	if swap := info.Memory.Swap; swap.TotalBytes > 0 {
+		fmt.Fprintf(&b, "   Swap:    %.1f / %.1f GB (%.1f%%)\n", swap.UsedGB, swap.TotalGB, swap.Percent)
+	}
+	if hp := info.Memory.HugePages; hp != nil {
+		fmt.Fprintf(&b, "   Huge:    %d / %d free (%d MB pages)\n", hp.Free, hp.Total, hp.PageBytes/(1024*1024))
+	}
+	if p := info.Pressure; p != nil {
+		fmt.Fprintf(&b, "   PSI:     cpu %s · memory %s · io %s (some, avg60)\n",
+			psiPercent(p.CPU), psiPercent(p.Memory), psiPercent(p.IO))
+	}
 	for _, d := range info.Disks {
-		fmt.Fprintf(&b, "   Disk %s: %.0f / %.0f GB (%.0f%%)\n", d.Mount, d.UsedGB, d.TotalGB, d.Percent)
+		fmt.Fprintf(&b, "   Disk %s: %.0f / %.0f GB (%.0f%%)", d.Mount, d.UsedGB, d.TotalGB, d.Percent)
+		if d.InodesTotal > 0 {
+			fmt.Fprintf(&b, " · inodes %.0f%%", d.InodePercent)
+		}
+		b.WriteString("\n")
+	}
+	for _, d := range info.DiskIO {
+		fmt.Fprintf(&b, "   IO %s: read %s · write %s · await %.1f ms · util %.0f%%\n",
+			d.Device, formatRate(d.ReadBytesPerSec), formatRate(d.WriteBytesPerSec), d.AwaitMs, d.Utilization)
+	}
+	for _, n := range info.Network {
+		link := n.State
+		if n.SpeedMbps > 0 {
+			link = fmt.Sprintf("%s, %d Mb/s, %.0f%%", n.State, n.SpeedMbps, n.Utilization)
+		}
+		fmt.Fprintf(&b, "   Net %s: ↓ %s ↑ %s (%s)", n.Interface, formatRate(n.RxBytesPerSec), formatRate(n.TxBytesPerSec), link)
+		if errs := n.RxErrors + n.TxErrors + n.RxDropped + n.TxDropped; errs > 0 {
+			fmt.Fprintf(&b, " · %d errors/drops", errs)
+		}
+		b.WriteString("\n")
 	}
 	return b.String()
 }
 
+func psiPercent(psi *system.PSI) string {
+	if psi == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", psi.Some.Avg60)
+}
+
+func bytesToGB(b uint64) float64 {
+	return float64(b) / (1024 * 1024 * 1024)
+}
+
+// formatRate formats a bytes-per-second rate with a binary unit.
+func formatRate(bps float64) string {
+	return formatBytes(bps) + "/s"
+}
+
+// formatBytes formats a byte count with a binary unit.
+func formatBytes(b float64) string {
+	units := []string{"B", "KB", "MB", "GB", "TB"}
+	i := 0
+	for b >= 1024 && i < len(units)-1 {
+		b /= 1024
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%.0f %s", b, units[i])
+	}
+	return fmt.Sprintf("%.1f %s", b, units[i])
+}
+
 // DockerList formats container list for human reading.
 func DockerList(containers []docker.Container) string {
 	if len(containers) == 0 {
@@ -37,6 +114,175 @@ func DockerList(containers []docker.Container) string {
 	return b.String()
 }
 
+// DockerStats formats container resource usage like `docker stats --no-stream`.
+func DockerStats(stats []docker.ContainerStats) string {
+	if len(stats) == 0 {
+		return "No running containers.\n"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-20s %6s  %-21s %6s  %-21s %-21s %s\n", "CONTAINER", "CPU %", "MEM USAGE / LIMIT", "MEM %", "NET I/O", "BLOCK I/O", "PIDS")
+	for _, s := range stats {
+		fmt.Fprintf(&b, "%-20s %5.1f%%  %-21s %5.1f%%  %-21s %-21s %d\n",
+			s.Name, s.CPUPercent,
+			formatBytes(float64(s.MemUsageBytes))+" / "+formatBytes(float64(s.MemLimitBytes)), s.MemPercent,
+			formatBytes(float64(s.NetRxBytes))+" / "+formatBytes(float64(s.NetTxBytes)),
+			formatBytes(float64(s.BlockReadBytes))+" / "+formatBytes(float64(s.BlockWriteBytes)),
+			s.PIDs)
+	}
+	return b.String()
+}
+
+// DockerLogs formats a logs snapshot, one LogLine per line.
+func DockerLogs(r *docker.LogsResult) string {
+	var b strings.Builder
+	if r.Lines == "" || r.Lines == "all" {
+		fmt.Fprintf(&b, "=== %s ===\n", r.Container)
+	} else {
+		fmt.Fprintf(&b, "=== %s (last %s lines) ===\n", r.Container, r.Lines)
+	}
+	for _, l := range r.Entries {
+		b.WriteString(LogLine(l))
+	}
+	return b.String()
+}
+
+// LogLine formats one log line with its local time; stderr lines are marked.
+func LogLine(l docker.LogLine) string {
+	prefix := ""
+	if !l.Time.IsZero() {
+		prefix = l.Time.Local().Format("2006-01-02 15:04:05") + " "
+	}
+	if l.Stream == "stderr" {
+		prefix += "ERR "
+	} else {
+		prefix += "    "
+	}
+	return prefix + l.Text + "\n"
+}
+
+// DockerDiskUsage formats docker disk usage like `docker system df`.
+func DockerDiskUsage(du *docker.DiskUsageInfo) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-14s %6s %7s %10s  %s\n", "TYPE", "TOTAL", "ACTIVE", "SIZE", "RECLAIMABLE")
+	rows := []struct {
+		name string
+		item docker.DiskUsageItem
+	}{
+		{"Images", du.Images},
+		{"Containers", du.Containers},
+		{"Local Volumes", du.Volumes},
+		{"Build Cache", du.BuildCache},
+	}
+	for _, r := range rows {
+		reclaimable := formatBytes(float64(r.item.ReclaimableBytes))
+		if r.item.SizeBytes > 0 {
+			reclaimable += fmt.Sprintf(" (%.0f%%)", float64(r.item.ReclaimableBytes)/float64(r.item.SizeBytes)*100)
+		}
+		fmt.Fprintf(&b, "%-14s %6d %7d %10s  %s\n", r.name, r.item.Total, r.item.Active, formatBytes(float64(r.item.SizeBytes)), reclaimable)
+	}
+	fmt.Fprintf(&b, "\nTotal %s, %s reclaimable", formatBytes(float64(du.TotalBytes)), formatBytes(float64(du.ReclaimableBytes)))
+	if du.RootDir != "" {
+		fmt.Fprintf(&b, " (data in %s)", du.RootDir)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// DockerPrune formats what prune removed or, in a dry run, would remove.
+func DockerPrune(r *docker.PruneResult) string {
+	if len(r.Items) == 0 {
+		return "Nothing to prune.\n"
+	}
+	var b strings.Builder
+	if r.DryRun {
+		b.WriteString("Dry run, nothing removed. Would remove:\n")
+	}
+	fmt.Fprintf(&b, "%-12s %-14s %10s  %s\n", "TYPE", "ID", "SIZE", "NAME")
+	failed := 0
+	for _, it := range r.Items {
+		name := it.Name
+		if it.Error != "" {
+			failed++
+			name += " ❌ " + it.Error
+		}
+		fmt.Fprintf(&b, "%-12s %-14s %10s  %s\n", it.Type, shortDigest(it.ID), formatBytes(float64(it.SizeBytes)), name)
+	}
+	b.WriteString("\n")
+	if r.DryRun {
+		fmt.Fprintf(&b, "Would free about %s. Run again without --dry-run to remove.\n", formatBytes(float64(r.ReclaimedBytes)))
+	} else {
+		fmt.Fprintf(&b, "✅ Freed about %s", formatBytes(float64(r.ReclaimedBytes)))
+		if failed > 0 {
+			fmt.Fprintf(&b, " (%d could not be removed)", failed)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// DockerUpdates formats image update checks, stale containers marked.
+func DockerUpdates(updates []docker.ImageUpdate) string {
+	if len(updates) == 0 {
+		return "No running containers.\n"
+	}
+	var b strings.Builder
+	stale := 0
+	fmt.Fprintf(&b, "%-20s %-40s %s\n", "CONTAINER", "IMAGE", "STATUS")
+	for _, u := range updates {
+		status := strings.ReplaceAll(u.Status, "_", " ")
+		switch u.Status {
+		case "update_available":
+			stale++
+			status = "⬆️  " + status + " (" + shortDigest(u.LocalDigest) + " → " + shortDigest(u.RemoteDigest) + ")"
+		case "error":
+			status += ": " + u.Error
+		}
+		fmt.Fprintf(&b, "%-20s %-40s %s\n", u.Container, u.Image, status)
+	}
+	if stale > 0 {
+		fmt.Fprintf(&b, "\n%d of %d containers running outdated images\n", stale, len(updates))
+	} else {
+		b.WriteString("\nAll images up to date\n")
+	}
+	return b.String()
+}
+
+// shortDigest trims a digest to 12 hex characters, like image IDs.
+func shortDigest(d string) string {
+	d = strings.TrimPrefix(d, "sha256:")
+	if len(d) > 12 {
+		return d[:12]
+	}
+	return d
+}
+
+// ComposeProjects formats compose projects for human reading.
+func ComposeProjects(projects []docker.Project) string {
+	if len(projects) == 0 {
+		return "No compose projects found.\n"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-20s %-9s %-10s %s\n", "PROJECT", "STATUS", "RUNNING", "SERVICES")
+	for _, p := range projects {
+		fmt.Fprintf(&b, "%-20s %-9s %-10s %s\n", p.Name, p.Status,
+			fmt.Sprintf("%d/%d", p.Running, p.Total), strings.Join(p.Services, ", "))
+	}
+	return b.String()
+}
+
+// ComposeAction formats a compose action result, with the CLI output if any.
+func ComposeAction(r *docker.ComposeResult) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "✅ compose %s: %s\n", r.Action, r.Project)
+	for _, c := range r.Containers {
+		fmt.Fprintf(&b, "   %s\n", c)
+	}
+	if r.Output != "" {
+		fmt.Fprintf(&b, "%s\n", r.Output)
+	}
+	return b.String()
+}
+
 // DockerAction formats docker restart/stop result.
 func DockerAction(action, container string) string {
 	return fmt.Sprintf("✅ %s: %s\n", action, container)
@@ -45,14 +291,228 @@ func DockerAction(action, container string) string {
 // Alerts formats alert check results for human reading.
 func Alerts(result *alerts.AlertResult) string {
 	var b strings.Builder
-	fmt.Fprintf(&b, "   CPU:    %5.1f%% (threshold: %.0f%%) %s\n", result.CPU.Current, result.CPU.Threshold, statusIcon(result.CPU.Status))
-	fmt.Fprintf(&b, "   Memory: %5.1f%% (threshold: %.0f%%) %s\n", result.Memory.Current, result.Memory.Threshold, statusIcon(result.Memory.Status))
+	fmt.Fprintf(&b, "   CPU:    %5.1f%% (threshold: %.0f%%) %s\n", result.CPU.Current, result.CPU.Threshold, alertIcon(result.CPU.Status, result.CPU.Silenced))
+	fmt.Fprintf(&b, "   Memory: %5.1f%% (threshold: %.0f%%) %s\n", result.Memory.Current, result.Memory.Threshold, alertIcon(result.Memory.Status, result.Memory.Silenced))
+	if s := result.Swap; s != nil {
+		fmt.Fprintf(&b, "   Swap:   %5.1f%% (threshold: %.0f%%) %s\n", s.Current, s.Threshold, alertIcon(s.Status, s.Silenced))
+	}
+	if p := result.MemoryPressure; p != nil {
+		fmt.Fprintf(&b, "   Memory pressure: %.1f%% stalled (threshold: %.0f%%) %s\n", p.Current, p.Threshold, alertIcon(p.Status, p.Silenced))
+	}
 	for _, d := range result.Disks {
-		fmt.Fprintf(&b, "   Disk %s: %.0f%% (threshold: %.0f%%) %s\n", d.Mount, d.Current, d.Threshold, statusIcon(d.Status))
+		fmt.Fprintf(&b, "   Disk %s: %.0f%% (threshold: %.0f%%) %s\n", d.Mount, d.Current, d.Threshold, alertIcon(d.Status, d.Silenced))
+		if d.Hint != "" {
+			fmt.Fprintf(&b, "     → %s\n", d.Hint)
+		}
+	}
+	for _, d := range result.Inodes {
+		fmt.Fprintf(&b, "   Inodes %s: %.0f%% (threshold: %.0f%%) %s\n", d.Mount, d.Current, d.Threshold, alertIcon(d.Status, d.Silenced))
+	}
+	for _, t := range result.Temperatures {
+		fmt.Fprintf(&b, "   Temp %s: %.1f°C (threshold: %.0f°C) %s\n", t.Sensor, t.Current, t.Threshold, alertIcon(t.Status, t.Silenced))
+	}
+	for _, n := range result.Network {
+		if n.Metric == "errors" {
+			fmt.Fprintf(&b, "   Net %s errors: %.1f/s (threshold: %.0f/s) %s\n", n.Interface, n.Current, n.Threshold, alertIcon(n.Status, n.Silenced))
+		} else {
+			fmt.Fprintf(&b, "   Net %s: %.0f%% (threshold: %.0f%%) %s\n", n.Interface, n.Current, n.Threshold, alertIcon(n.Status, n.Silenced))
+		}
+	}
+	for _, c := range result.Containers {
+		fmt.Fprintf(&b, "   Container %s %s\n", c.Message, alertIcon(c.Status, c.Silenced))
+	}
+	return b.String()
+}
+
+// NotifyResults formats the outcome of `alerts test-notify`.
+func NotifyResults(results []notify.Result) string {
+	var b strings.Builder
+	for _, r := range results {
+		if r.Status == "sent" {
+			fmt.Fprintf(&b, "✅ %s: sent\n", r.Notifier)
+		} else {
+			fmt.Fprintf(&b, "❌ %s: %s\n", r.Notifier, r.Error)
+		}
+	}
+	return b.String()
+}
+
+// Silences formats the active silences.
+func Silences(silences []alerts.Silence) string {
+	if len(silences) == 0 {
+		return "No active silences.\n"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-20s %-24s %-17s %s\n", "ID", "METRICS", "UNTIL", "REASON")
+	for _, s := range silences {
+		fmt.Fprintf(&b, "%-20s %-24s %-17s %s\n", s.ID, silencedMetrics(s), s.End.Local().Format("2006-01-02 15:04"), s.Reason)
+	}
+	return b.String()
+}
+
+// Silence formats a newly created silence.
+func Silence(s *alerts.Silence) string {
+	out := fmt.Sprintf("🔕 Silenced %s until %s (id %s)", silencedMetrics(*s), s.End.Local().Format("2006-01-02 15:04"), s.ID)
+	if s.Reason != "" {
+		out += ": " + s.Reason
+	}
+	return out + "\n"
+}
+
+func silencedMetrics(s alerts.Silence) string {
+	if len(s.Metrics) == 0 {
+		return "all"
+	}
+	return strings.Join(s.Metrics, ",")
+}
+
+// historyEntries is how many recent entries AlertHistory lists.
+const historyEntries = 20
+
+// AlertHistory formats recorded alerts: a summary per server and metric,
+// then the most recent entries.
+func AlertHistory(h *alerts.History) string {
+	since := h.Since.Local().Format("2006-01-02 15:04")
+	if len(h.Entries) == 0 {
+		return fmt.Sprintf("No alerts recorded since %s.\n", since)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Alerts recorded since %s:\n", since)
+	fmt.Fprintf(&b, "%-12s %-28s %8s %8s %8s  %s\n", "SERVER", "METRIC", "CRITICAL", "WARNING", "PEAK", "LAST")
+	for _, s := range h.Summary {
+		fmt.Fprintf(&b, "%-12s %-28s %8d %8d %8s  %s\n", s.Server, s.Label, s.Critical, s.Warning,
+			fmt.Sprintf("%.0f%s", s.Peak, s.Unit), s.Last.Local().Format("2006-01-02 15:04"))
+	}
+
+	entries := h.Entries
+	if len(entries) > historyEntries {
+		fmt.Fprintf(&b, "\nLast %d of %d entries (--json for all):\n", historyEntries, len(entries))
+		entries = entries[len(entries)-historyEntries:]
+	} else {
+		b.WriteString("\nEntries:\n")
+	}
+	for _, e := range entries {
+		desc := e.Message
+		if desc == "" {
+			desc = fmt.Sprintf("%s at %.0f%s (threshold %.0f%s)", e.Label, e.Value, e.Unit, e.Threshold, e.Unit)
+		}
+		fmt.Fprintf(&b, "%s %s [%s] %s\n", e.Time.Local().Format("2006-01-02 15:04:05"), alertIcon(e.Status, e.Silenced), e.Server, desc)
+	}
+	return b.String()
+}
+
+// sparkWidth is the most characters a metrics history sparkline takes.
+const sparkWidth = 60
+
+// MetricsHistory formats metrics history series: their range and a
+// sparkline each.
+func MetricsHistory(series []history.Series) string {
+	if len(series) == 0 {
+		return "No samples recorded (homebutler serve and alerts watch record them).\n"
+	}
+	var b strings.Builder
+	for _, s := range series {
+		if len(s.Points) == 0 {
+			fmt.Fprintf(&b, "%s %s: no samples recorded (homebutler serve and alerts watch record them)\n", s.Server, s.Metric)
+			continue
+		}
+		first := s.Points[0].Time.Local().Format("2006-01-02 15:04")
+		fmt.Fprintf(&b, "%s %s since %s (%d samples, %s):\n", s.Server, s.Metric, first, len(s.Points), s.Resolution)
+		fmt.Fprintf(&b, "   min %s  avg %s  max %s  last %s\n",
+			metricValue(s.Min, s.Unit), metricValue(s.Avg, s.Unit), metricValue(s.Max, s.Unit), metricValue(s.Last, s.Unit))
+		fmt.Fprintf(&b, "   %s\n", sparkline(s.Points, s.Min, s.Max))
 	}
 	return b.String()
 }
 
+func metricValue(v float64, unit string) string {
+	switch unit {
+	case "%":
+		return fmt.Sprintf("%.1f%%", v)
+	case "B/s":
+		return formatRate(v)
+	}
+	return fmt.Sprintf("%.2f", v)
+}
+
+// sparkline draws the points scaled between lo and hi, averaging
+// neighbouring points down to sparkWidth.
+func sparkline(points []history.Point, lo, hi float64) string {
+	bars := []rune("▁▂▃▄▅▆▇█")
+	width := min(len(points), sparkWidth)
+	var b strings.Builder
+	for i := range width {
+		from, to := i*len(points)/width, (i+1)*len(points)/width
+		sum := 0.0
+		for _, p := range points[from:to] {
+			sum += p.Value
+		}
+		level := 0
+		if hi > lo {
+			level = int(math.Round((sum/float64(to-from) - lo) / (hi - lo) * float64(len(bars)-1)))
+		}
+		b.WriteRune(bars[level])
+	}
+	return b.String()
+}
+
+// PushResult formats the outcome of `metrics push --once`.
+func PushResult(r *exporter.PushResult) string {
+	var b strings.Builder
+	icon := "✅"
+	if r.Error != "" {
+		icon = "❌"
+	}
+	fmt.Fprintf(&b, "%s Pushed %d lines", icon, r.Sent)
+	if r.Buffered > 0 {
+		fmt.Fprintf(&b, ", %d buffered for the next push", r.Buffered)
+	}
+	if r.Dropped > 0 {
+		fmt.Fprintf(&b, ", %d dropped", r.Dropped)
+	}
+	b.WriteString("\n")
+	if r.Error != "" {
+		fmt.Fprintf(&b, "   %s\n", r.Error)
+	}
+	return b.String()
+}
+
+// AlertEvent formats an alert status change reported by `alerts watch`.
+func AlertEvent(msg notify.Message) string {
+	return fmt.Sprintf("%s %s %s: %s\n", msg.Time.Format("2006-01-02 15:04:05"), statusIcon(msg.Status), msg.Title, msg.Body)
+}
+
+// Sensors formats temperature and fan readings for human reading.
+func Sensors(info *system.SensorsInfo) string {
+	if len(info.Temperatures) == 0 && len(info.Fans) == 0 {
+		return "No sensors found.\n"
+	}
+	var b strings.Builder
+	if len(info.Temperatures) > 0 {
+		fmt.Fprintf(&b, "%-16s %-20s %8s %8s %8s\n", "CHIP", "SENSOR", "TEMP", "HIGH", "CRIT")
+		for _, t := range info.Temperatures {
+			fmt.Fprintf(&b, "%-16s %-20s %7.1f° %8s %8s\n", t.Chip, t.Label, t.Celsius, optionalCelsius(t.High), optionalCelsius(t.Critical))
+		}
+	}
+	if len(info.Fans) > 0 {
+		if len(info.Temperatures) > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%-16s %-20s %8s\n", "CHIP", "FAN", "RPM")
+		for _, f := range info.Fans {
+			fmt.Fprintf(&b, "%-16s %-20s %8d\n", f.Chip, f.Label, f.RPM)
+		}
+	}
+	return b.String()
+}
+
+func optionalCelsius(v float64) string {
+	if v == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f°", v)
+}
+
 // Ports formats open ports for human reading.
 func Ports(openPorts []ports.PortInfo) string {
 	if len(openPorts) == 0 {
@@ -116,6 +576,14 @@ func MultiServer(results []map[string]interface{}) string {
 	return b.String()
 }
 
+// alertIcon is statusIcon, with a mark on silenced alerts.
+func alertIcon(status string, silenced bool) string {
+	if silenced {
+		return statusIcon(status) + " 🔕 silenced"
+	}
+	return statusIcon(status)
+}
+
 func statusIcon(status string) string {
 	switch status {
 	case "ok":
@@ -3,10 +3,14 @@ package format
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/Higangssh/homebutler/internal/alerts"
 	"github.com/Higangssh/homebutler/internal/docker"
+	"github.com/Higangssh/homebutler/internal/exporter"
+	"github.com/Higangssh/homebutler/internal/history"
 	"github.com/Higangssh/homebutler/internal/network"
+	"github.com/Higangssh/homebutler/internal/notify"
 	"github.com/Higangssh/homebutler/internal/ports"
 	"github.com/Higangssh/homebutler/internal/system"
 )
@@ -17,12 +21,29 @@ func TestStatus(t *testing.T) {
 		OS:       "linux",
 		Arch:     "amd64",
 		Uptime:   "1d 2h",
-		CPU:      system.CPUInfo{UsagePercent: 12.3, Cores: 8},
-		Memory:   system.MemInfo{UsedGB: 4.5, TotalGB: 16, Percent: 28.1},
-		Disks:    []system.DiskInfo{{Mount: "/", UsedGB: 30, TotalGB: 100, Percent: 30}},
+		CPU: system.CPUInfo{
+			UsagePercent: 12.3, Cores: 2, PerCore: []float64{10, 14.6},
+			IOWait: 4.2, Steal: 7.5, Load: system.LoadAvg{Load1: 0.52, Load5: 0.58, Load15: 0.59},
+		},
+		Memory: system.MemInfo{
+			UsedGB: 4.5, TotalGB: 16, Percent: 28.1, BuffersBytes: 512 << 20, CachedBytes: 3 << 30,
+			Swap:      system.SwapInfo{UsedGB: 0.5, TotalGB: 2, Percent: 25, TotalBytes: 2 << 30},
+			HugePages: &system.HugePagesInfo{Total: 8, Free: 6, PageBytes: 2 << 20},
+		},
+		Pressure: &system.PressureInfo{Memory: &system.PSI{Some: system.PSIAvg{Avg60: 4.25}}},
+		Disks:    []system.DiskInfo{{Mount: "/", UsedGB: 30, TotalGB: 100, Percent: 30, InodesTotal: 1000, InodePercent: 12}},
+		DiskIO:   []system.DiskIOInfo{{Device: "sda", ReadBytesPerSec: 1536, WriteBytesPerSec: 0, AwaitMs: 2.25, Utilization: 7}},
+		Network: []system.NetInfo{
+			{Interface: "eth0", State: "up", SpeedMbps: 1000, Utilization: 2, RxBytesPerSec: 2.5 * 1024 * 1024, TxBytesPerSec: 512, RxErrors: 3},
+			{Interface: "wlan0", State: "down"},
+		},
 	}
 	out := Status(in)
-	for _, want := range []string{"homelab-server", "linux/amd64", "CPU:", "Memory:", "Disk /:"} {
+	for _, want := range []string{"homelab-server", "linux/amd64", "CPU:", "Memory:", "Disk /:",
+		"iowait 4.2%", "steal 7.5%", "10% 15%", "Load:    0.52 0.58 0.59",
+		"buffers 0.5 GB · cached 3.0 GB", "Swap:    0.5 / 2.0 GB (25.0%)", "Huge:    6 / 8 free (2 MB pages)",
+		"PSI:     cpu - · memory 4.2% · io -", "Disk /: 30 / 100 GB (30%) · inodes 12%", "IO sda: read 1.5 KB/s · write 0 B/s · await 2.2 ms · util 7%",
+		"Net eth0: ↓ 2.5 MB/s ↑ 512 B/s (up, 1000 Mb/s, 2%) · 3 errors/drops", "Net wlan0: ↓ 0 B/s ↑ 0 B/s (down)\n"} {
 		if !strings.Contains(out, want) {
 			t.Fatalf("expected %q in output: %s", want, out)
 		}
@@ -42,14 +63,245 @@ func TestDockerListAndAction(t *testing.T) {
 	}
 }
 
+func TestDockerStats(t *testing.T) {
+	if got := DockerStats(nil); got != "No running containers.\n" {
+		t.Fatalf("unexpected empty message: %q", got)
+	}
+	out := DockerStats([]docker.ContainerStats{{
+		Name: "postgres", CPUPercent: 12.34, MemUsageBytes: 512 << 20, MemLimitBytes: 2 << 30, MemPercent: 25,
+		NetRxBytes: 1536, NetTxBytes: 100, BlockReadBytes: 3 << 30, PIDs: 9,
+	}})
+	for _, want := range []string{"postgres", " 12.3%", "512.0 MB / 2.0 GB", " 25.0%", "1.5 KB / 100 B", "3.0 GB / 0 B", " 9\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("docker stats output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestDockerLogs(t *testing.T) {
+	ts := time.Date(2024, 5, 1, 10, 0, 0, 0, time.Local)
+	out := DockerLogs(&docker.LogsResult{Container: "nginx", Lines: "50", Entries: []docker.LogLine{
+		{Time: ts, Stream: "stdout", Text: "GET / 200"},
+		{Time: ts, Stream: "stderr", Text: "upstream timed out"},
+	}})
+	for _, want := range []string{"=== nginx (last 50 lines) ===\n", "2024-05-01 10:00:00     GET / 200\n", "2024-05-01 10:00:00 ERR upstream timed out\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("docker logs output missing %q:\n%s", want, out)
+		}
+	}
+	if out := DockerLogs(&docker.LogsResult{Container: "nginx", Lines: "all"}); out != "=== nginx ===\n" {
+		t.Errorf("unexpected header: %q", out)
+	}
+}
+
+func TestDockerDiskUsage(t *testing.T) {
+	out := DockerDiskUsage(&docker.DiskUsageInfo{
+		Images:           docker.DiskUsageItem{Total: 4, Active: 2, SizeBytes: 2 << 30, ReclaimableBytes: 1 << 30},
+		Volumes:          docker.DiskUsageItem{Total: 1},
+		TotalBytes:       2 << 30,
+		ReclaimableBytes: 1 << 30,
+		RootDir:          "/var/lib/docker",
+	})
+	for _, want := range []string{"Images", "2.0 GB  1.0 GB (50%)", "Local Volumes       1       0        0 B  0 B\n", "Total 2.0 GB, 1.0 GB reclaimable (data in /var/lib/docker)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("docker df output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestDockerPrune(t *testing.T) {
+	if got := DockerPrune(&docker.PruneResult{DryRun: true}); got != "Nothing to prune.\n" {
+		t.Fatalf("unexpected empty message: %q", got)
+	}
+	items := []docker.PruneItem{
+		{Type: "container", ID: "222222222222", Name: "oneshot", SizeBytes: 2048},
+		{Type: "image", ID: "sha256:bbbbbbbbbbbb0002", Name: "<none>", SizeBytes: 1 << 20},
+	}
+	out := DockerPrune(&docker.PruneResult{DryRun: true, Items: items, ReclaimedBytes: 1<<20 + 2048})
+	for _, want := range []string{"Dry run", "oneshot", "bbbbbbbbbbbb ", "Would free about 1.0 MB"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("dry run output missing %q:\n%s", want, out)
+		}
+	}
+	items[1].Error = "conflict"
+	out = DockerPrune(&docker.PruneResult{Items: items, ReclaimedBytes: 2048})
+	if !strings.Contains(out, "Freed about 2.0 KB (1 could not be removed)") || !strings.Contains(out, "conflict") {
+		t.Errorf("unexpected prune output:\n%s", out)
+	}
+}
+
+func TestDockerUpdates(t *testing.T) {
+	if got := DockerUpdates(nil); got != "No running containers.\n" {
+		t.Fatalf("unexpected empty message: %q", got)
+	}
+	out := DockerUpdates([]docker.ImageUpdate{
+		{Container: "web", Image: "nginx:1.25", Status: "up_to_date"},
+		{Container: "db", Image: "postgres:16", Status: "update_available",
+			LocalDigest: "sha256:0123456789abcdef", RemoteDigest: "sha256:fedcba9876543210"},
+		{Container: "app", Image: "ghcr.io/me/app", Status: "error", Error: "token request failed"},
+	})
+	for _, want := range []string{"up to date", "update available (0123456789ab → fedcba987654)", "error: token request failed", "1 of 3 containers"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("docker updates output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestCompose(t *testing.T) {
+	if got := ComposeProjects(nil); got != "No compose projects found.\n" {
+		t.Fatalf("unexpected empty message: %q", got)
+	}
+	out := ComposeProjects([]docker.Project{{Name: "media", Status: "partial", Running: 1, Total: 2, Services: []string{"jellyfin", "sonarr"}}})
+	if !strings.Contains(out, "media") || !strings.Contains(out, "1/2") || !strings.Contains(out, "jellyfin, sonarr") {
+		t.Fatalf("unexpected compose ls output: %s", out)
+	}
+	out = ComposeAction(&docker.ComposeResult{Project: "media", Action: "restart", Containers: []string{"media-jellyfin-1"}})
+	if !strings.Contains(out, "compose restart: media") || !strings.Contains(out, "media-jellyfin-1") {
+		t.Fatalf("unexpected compose action output: %s", out)
+	}
+}
+
 func TestAlerts(t *testing.T) {
 	res := &alerts.AlertResult{
-		CPU:    alerts.AlertItem{Current: 10, Threshold: 90, Status: "ok"},
-		Memory: alerts.AlertItem{Current: 75, Threshold: 85, Status: "warning"},
-		Disks:  []alerts.DiskAlert{{Mount: "/", Current: 95, Threshold: 90, Status: "critical"}},
+		CPU:            alerts.AlertItem{Current: 10, Threshold: 90, Status: "ok"},
+		Memory:         alerts.AlertItem{Current: 75, Threshold: 85, Status: "warning"},
+		Swap:           &alerts.AlertItem{Current: 10, Threshold: 80, Status: "ok"},
+		MemoryPressure: &alerts.AlertItem{Current: 22.5, Threshold: 20, Status: "critical"},
+		Disks:          []alerts.DiskAlert{{Mount: "/", Current: 95, Threshold: 90, Status: "critical", Silenced: true}},
+		Inodes:         []alerts.DiskAlert{{Mount: "/var/lib/docker", Current: 91, Threshold: 90, Status: "critical"}},
+		Temperatures:   []alerts.TempAlert{{Sensor: "cpu_thermal/temp1", Current: 72.5, Threshold: 80, Status: "warning"}},
+		Network: []alerts.NetAlert{
+			{Interface: "eth0", Metric: "utilization", Current: 40, Threshold: 90, Status: "ok"},
+			{Interface: "eth0", Metric: "errors", Current: 12.5, Threshold: 10, Status: "critical"},
+		},
+		Containers: []alerts.ContainerAlert{
+			{Container: "jellyfin", Rule: "restarts", Status: "critical", Message: "jellyfin exited 3 times in 10 minutes", Current: 3, Threshold: 3},
+		},
 	}
 	out := Alerts(res)
-	for _, want := range []string{"✅", "⚠️", "🔴", "Disk /"} {
+	for _, want := range []string{"✅", "⚠️", "🔴", "Disk /: 95% (threshold: 90%) 🔴 🔕 silenced", "Temp cpu_thermal/temp1: 72.5°C", "Inodes /var/lib/docker: 91%",
+		"Swap:    10.0% (threshold: 80%)", "Memory pressure: 22.5% stalled (threshold: 20%)",
+		"Net eth0: 40% (threshold: 90%)", "Net eth0 errors: 12.5/s (threshold: 10/s)",
+		"Container jellyfin exited 3 times in 10 minutes 🔴"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output: %s", want, out)
+		}
+	}
+}
+
+func TestSilences(t *testing.T) {
+	if out := Silences(nil); out != "No active silences.\n" {
+		t.Errorf("unexpected empty output: %q", out)
+	}
+	end := time.Date(2026, 3, 1, 16, 0, 0, 0, time.Local)
+	out := Silences([]alerts.Silence{
+		{ID: "1a2b3c4d", Metrics: []string{"disk"}, Reason: "rebuilding array", End: end},
+		{ID: "maintenance:backups", End: end},
+	})
+	for _, want := range []string{"1a2b3c4d", "disk", "2026-03-01 16:00", "rebuilding array", "maintenance:backups", "all"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output: %s", want, out)
+		}
+	}
+	if out := Silence(&alerts.Silence{ID: "1a2b3c4d", Metrics: []string{"disk", "cpu"}, End: end}); out != "🔕 Silenced disk,cpu until 2026-03-01 16:00 (id 1a2b3c4d)\n" {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
+
+func TestAlertHistory(t *testing.T) {
+	since := time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local)
+	if out := AlertHistory(&alerts.History{Since: since}); out != "No alerts recorded since 2026-03-01 12:00.\n" {
+		t.Errorf("unexpected empty output: %q", out)
+	}
+	at := since.Add(time.Hour)
+	out := AlertHistory(&alerts.History{
+		Since: since,
+		Entries: []alerts.HistoryEntry{
+			{Time: at, Server: "nas", Metric: "disk:/mnt/data", Label: "Disk /mnt/data", Status: "critical", Value: 97, Threshold: 90, Unit: "%"},
+		},
+		Summary: []alerts.HistorySummary{
+			{Server: "nas", Metric: "disk:/mnt/data", Label: "Disk /mnt/data", Critical: 1, Peak: 97, Unit: "%", First: at, Last: at},
+		},
+	})
+	for _, want := range []string{"Alerts recorded since 2026-03-01 12:00", "nas", "Disk /mnt/data", "97%", "2026-03-01 13:00:00 🔴 [nas] Disk /mnt/data at 97% (threshold 90%)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestMetricsHistory(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local)
+	out := MetricsHistory([]history.Series{
+		{Server: "nas", Metric: "cpu", Unit: "%", Resolution: "1m", Min: 10, Max: 90, Avg: 50, Last: 90, Points: []history.Point{
+			{Time: at, Value: 10}, {Time: at.Add(time.Minute), Value: 50}, {Time: at.Add(2 * time.Minute), Value: 90},
+		}},
+		{Server: "nas", Metric: "disk:/mnt/data", Unit: "%", Resolution: "1m", Points: []history.Point{}},
+	})
+	for _, want := range []string{
+		"nas cpu since 2026-03-01 12:00 (3 samples, 1m):",
+		"min 10.0%  avg 50.0%  max 90.0%  last 90.0%",
+		"▁▅█",
+		"nas disk:/mnt/data: no samples recorded",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestSparklineDownsamples(t *testing.T) {
+	points := make([]history.Point, 3*sparkWidth)
+	for i := range points {
+		points[i].Value = float64(i)
+	}
+	line := []rune(sparkline(points, 0, float64(len(points)-1)))
+	if len(line) != sparkWidth || line[0] != '▁' || line[len(line)-1] != '█' {
+		t.Errorf("unexpected sparkline %q", string(line))
+	}
+}
+
+func TestPushResult(t *testing.T) {
+	if out := PushResult(&exporter.PushResult{Sent: 120}); out != "✅ Pushed 120 lines\n" {
+		t.Errorf("unexpected output: %q", out)
+	}
+	out := PushResult(&exporter.PushResult{Buffered: 40, Error: "HTTP 503: unavailable"})
+	if out != "❌ Pushed 0 lines, 40 buffered for the next push\n   HTTP 503: unavailable\n" {
+		t.Errorf("unexpected failure output: %q", out)
+	}
+}
+
+func TestNotifyResults(t *testing.T) {
+	out := NotifyResults([]notify.Result{
+		{Notifier: "ntfy", Status: "sent"},
+		{Notifier: "mail", Status: "failed", Error: "dial tcp: connection refused"},
+	})
+	if out != "✅ ntfy: sent\n❌ mail: dial tcp: connection refused\n" {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
+
+func TestAlertEvent(t *testing.T) {
+	out := AlertEvent(notify.Message{
+		Status: "critical",
+		Title:  "[nas] Disk /mnt/data critical",
+		Body:   "Disk /mnt/data at 97% (threshold 90%)",
+		Time:   time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	})
+	if out != "2026-03-01 12:00:00 🔴 [nas] Disk /mnt/data critical: Disk /mnt/data at 97% (threshold 90%)\n" {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
+
+func TestSensors(t *testing.T) {
+	if got := Sensors(&system.SensorsInfo{}); got != "No sensors found.\n" {
+		t.Fatalf("unexpected empty sensors: %q", got)
+	}
+	out := Sensors(&system.SensorsInfo{
+		Temperatures: []system.TempSensor{{Chip: "coretemp", Label: "Package id 0", Celsius: 54, Critical: 100}},
+		Fans:         []system.FanSensor{{Chip: "nct6775", Label: "CPU Fan", RPM: 1245}},
+	})
+	for _, want := range []string{"coretemp", "Package id 0", "54.0°", "100°", "CPU Fan", "1245"} {
 		if !strings.Contains(out, want) {
 			t.Fatalf("expected %q in output: %s", want, out)
 		}
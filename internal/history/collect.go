@@ -0,0 +1,47 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Higangssh/homebutler/internal/config"
+	"github.com/Higangssh/homebutler/internal/remote"
+	"github.com/Higangssh/homebutler/internal/system"
+)
+
+// Collect reads the status of the servers in parallel and records it:
+// local entries directly, remote ones over SSH. The errors are by index,
+// nil for the servers that were recorded.
+func Collect(dir string, servers []config.ServerConfig, now time.Time) []error {
+	errs := make([]error, len(servers))
+	var wg sync.WaitGroup
+	for i := range servers {
+		srv := &servers[i]
+		wg.Go(func() {
+			info, err := status(srv)
+			if err == nil {
+				err = Record(dir, srv.Name, FromStatus(info, now))
+			}
+			errs[i] = err
+		})
+	}
+	wg.Wait()
+	return errs
+}
+
+func status(srv *config.ServerConfig) (*system.StatusInfo, error) {
+	if srv.Local {
+		return system.Status()
+	}
+	out, err := remote.Run(srv, "status", "--json")
+	if err != nil {
+		return nil, err
+	}
+	var info system.StatusInfo
+	if err := json.Unmarshal(out, &info); err != nil {
+		return nil, fmt.Errorf("invalid status output: %w", err)
+	}
+	return &info, nil
+}
@@ -0,0 +1,313 @@
+// Package history keeps a time series of each server's status on disk:
+// a sample a minute for the last day, and hourly averages of those for the
+// last month.
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Higangssh/homebutler/internal/system"
+	"github.com/Higangssh/homebutler/internal/util"
+)
+
+const (
+	minuteRetention = 24 * time.Hour
+	hourRetention   = 30 * 24 * time.Hour
+)
+
+// Sample is one status reading, or the average of an hour of them.
+type Sample struct {
+	Time   time.Time          `json:"t"`
+	CPU    float64            `json:"cpu"`
+	Memory float64            `json:"mem"`
+	Swap   float64            `json:"swap,omitempty"`
+	Load   float64            `json:"load,omitempty"`
+	Disks  map[string]float64 `json:"disks,omitempty"`  // usage % per mount
+	NetRx  float64            `json:"net_rx,omitempty"` // bytes/s, all interfaces
+	NetTx  float64            `json:"net_tx,omitempty"`
+}
+
+// FromStatus takes the sampled values of a status.
+func FromStatus(info *system.StatusInfo, t time.Time) Sample {
+	s := Sample{
+		Time:   t,
+		CPU:    info.CPU.UsagePercent,
+		Memory: info.Memory.Percent,
+		Swap:   info.Memory.Swap.Percent,
+		Load:   info.CPU.Load.Load1,
+		Disks:  make(map[string]float64, len(info.Disks)),
+	}
+	for _, d := range info.Disks {
+		s.Disks[d.Mount] = d.Percent
+	}
+	for _, n := range info.Network {
+		s.NetRx += n.RxBytesPerSec
+		s.NetTx += n.TxBytesPerSec
+	}
+	return s
+}
+
+// series is the file of one server.
+type series struct {
+	Minutes []Sample `json:"minutes"` // oldest first
+	Hours   []Sample `json:"hours"`
+}
+
+// DefaultDir is $XDG_STATE_HOME/homebutler/metrics, or
+// ~/.local/state/homebutler/metrics.
+func DefaultDir() string {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "homebutler-metrics"
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "homebutler", "metrics")
+}
+
+// fileName keeps a server name from leaving the directory.
+func fileName(dir, server string) string {
+	name := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.' {
+			return r
+		}
+		return '_'
+	}, server)
+	return filepath.Join(dir, strings.TrimLeft(name, ".")+".json")
+}
+
+func load(path string) (*series, error) {
+	s := &series{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metrics history: %w", err)
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("failed to parse metrics history %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Record adds a sample of a server. Samples within the same minute replace
+// each other; an hour is averaged once a sample of the next one arrives.
+// serve and alerts watch may record the same server at once, so the file
+// is locked from load to save.
+func Record(dir, server string, sample Sample) error {
+	path := fileName(dir, server)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to save metrics history: %w", err)
+	}
+	unlock, err := util.LockFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to save metrics history: %w", err)
+	}
+	defer unlock()
+	s, err := load(path)
+	if err != nil {
+		return err
+	}
+	s.add(sample)
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := util.WriteFileAtomic(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to save metrics history: %w", err)
+	}
+	return nil
+}
+
+func (s *series) add(sample Sample) {
+	sample.Time = sample.Time.UTC().Truncate(time.Minute)
+	if n := len(s.Minutes); n > 0 {
+		last := s.Minutes[n-1].Time
+		switch {
+		case sample.Time.Equal(last):
+			s.Minutes[n-1] = sample
+			return
+		case sample.Time.Before(last):
+			return // the clock went back
+		}
+		if hour := last.Truncate(time.Hour); sample.Time.Truncate(time.Hour).After(hour) {
+			s.Hours = append(s.Hours, average(hour, s.Minutes))
+		}
+	}
+	s.Minutes = append(s.Minutes, sample)
+	s.Minutes = since(s.Minutes, sample.Time.Add(-minuteRetention))
+	s.Hours = since(s.Hours, sample.Time.Add(-hourRetention))
+}
+
+// average is the mean of the samples within the hour starting at hour.
+func average(hour time.Time, samples []Sample) Sample {
+	avg := Sample{Time: hour, Disks: map[string]float64{}}
+	n := 0
+	disks := map[string]int{}
+	for _, s := range samples {
+		if s.Time.Truncate(time.Hour) != hour {
+			continue
+		}
+		n++
+		avg.CPU += s.CPU
+		avg.Memory += s.Memory
+		avg.Swap += s.Swap
+		avg.Load += s.Load
+		avg.NetRx += s.NetRx
+		avg.NetTx += s.NetTx
+		for mount, v := range s.Disks {
+			avg.Disks[mount] += v
+			disks[mount]++
+		}
+	}
+	if n > 0 {
+		f := float64(n)
+		avg.CPU, avg.Memory, avg.Swap, avg.Load = avg.CPU/f, avg.Memory/f, avg.Swap/f, avg.Load/f
+		avg.NetRx, avg.NetTx = avg.NetRx/f, avg.NetTx/f
+	}
+	for mount, c := range disks {
+		avg.Disks[mount] /= float64(c)
+	}
+	return avg
+}
+
+// since drops the samples before cutoff.
+func since(samples []Sample, cutoff time.Time) []Sample {
+	i := slices.IndexFunc(samples, func(s Sample) bool { return !s.Time.Before(cutoff) })
+	if i < 0 {
+		return samples[:0]
+	}
+	return samples[i:]
+}
+
+// Metrics lists the metric names Query takes; "disk" is every mount,
+// "disk:/mnt/data" one.
+var Metrics = []string{"cpu", "memory", "swap", "load", "disk", "net_rx", "net_tx"}
+
+// ValidMetric reports whether Query takes the metric name.
+func ValidMetric(metric string) bool {
+	kind, _, _ := strings.Cut(metric, ":")
+	return slices.Contains(Metrics, kind) && (kind == "disk" || kind == metric)
+}
+
+// Point is one value of a Series.
+type Point struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+// Series is one metric of one server over time.
+type Series struct {
+	Server     string  `json:"server"`
+	Metric     string  `json:"metric"` // "cpu", "disk:/mnt/data"
+	Unit       string  `json:"unit"`
+	Resolution string  `json:"resolution"` // "1m" or "1h"
+	Points     []Point `json:"points"`
+	Min        float64 `json:"min"`
+	Max        float64 `json:"max"`
+	Avg        float64 `json:"avg"`
+	Last       float64 `json:"last"`
+}
+
+// Query returns a metric of a server since a time: minute samples when
+// they reach back that far, else hourly ones.
+func Query(dir, server, metric string, from, now time.Time) ([]Series, error) {
+	if !ValidMetric(metric) {
+		return nil, fmt.Errorf("unknown metric %q (use %s, or disk:<mount>)", metric, strings.Join(Metrics, ", "))
+	}
+	s, err := load(fileName(dir, server))
+	if err != nil {
+		return nil, err
+	}
+	samples, resolution := s.Minutes, "1m"
+	if from.Before(now.Add(-minuteRetention)) {
+		samples, resolution = s.Hours, "1h"
+	}
+	return Select(server, metric, resolution, since(samples, from)), nil
+}
+
+// Select takes a valid metric out of samples. "disk" gives a series per
+// mount.
+func Select(server, metric, resolution string, samples []Sample) []Series {
+	kind, mount, _ := strings.Cut(metric, ":")
+	if kind != "disk" {
+		return []Series{newSeries(server, metric, resolution, samples, func(s Sample) (float64, bool) { return value(s, kind), true })}
+	}
+	mounts := []string{mount}
+	if mount == "" {
+		seen := map[string]bool{}
+		for _, s := range samples {
+			for m := range s.Disks {
+				seen[m] = true
+			}
+		}
+		mounts = slices.Sorted(maps.Keys(seen))
+	}
+	out := []Series{}
+	for _, m := range mounts {
+		out = append(out, newSeries(server, "disk:"+m, resolution, samples, func(s Sample) (float64, bool) {
+			v, ok := s.Disks[m]
+			return v, ok
+		}))
+	}
+	return out
+}
+
+func value(s Sample, kind string) float64 {
+	switch kind {
+	case "cpu":
+		return s.CPU
+	case "memory":
+		return s.Memory
+	case "swap":
+		return s.Swap
+	case "load":
+		return s.Load
+	case "net_rx":
+		return s.NetRx
+	}
+	return s.NetTx
+}
+
+func newSeries(server, metric, resolution string, samples []Sample, get func(Sample) (float64, bool)) Series {
+	out := Series{Server: server, Metric: metric, Unit: unit(metric), Resolution: resolution, Points: []Point{}}
+	sum := 0.0
+	for _, s := range samples {
+		v, ok := get(s)
+		if !ok {
+			continue
+		}
+		if len(out.Points) == 0 || v < out.Min {
+			out.Min = v
+		}
+		out.Max = max(out.Max, v)
+		sum += v
+		out.Points = append(out.Points, Point{Time: s.Time, Value: v})
+	}
+	if n := len(out.Points); n > 0 {
+		out.Avg = sum / float64(n)
+		out.Last = out.Points[n-1].Value
+	}
+	return out
+}
+
+func unit(metric string) string {
+	switch {
+	case metric == "load":
+		return ""
+	case strings.HasPrefix(metric, "net_"):
+		return "B/s"
+	}
+	return "%"
+}
@@ -0,0 +1,140 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var testTime = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func TestRecordAndQuery(t *testing.T) {
+	dir := t.TempDir()
+	for i, cpu := range []float64{10, 20, 30} {
+		if err := Record(dir, "nas", Sample{Time: testTime.Add(time.Duration(i) * time.Minute), CPU: cpu}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// a second sample in the same minute replaces the first
+	Record(dir, "nas", Sample{Time: testTime.Add(2*time.Minute + 30*time.Second), CPU: 60})
+
+	series, err := Query(dir, "nas", "cpu", testTime.Add(-time.Hour), testTime.Add(3*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := series[0]
+	if len(series) != 1 || len(s.Points) != 3 || s.Resolution != "1m" || s.Unit != "%" {
+		t.Fatalf("unexpected series: %+v", series)
+	}
+	if s.Min != 10 || s.Max != 60 || s.Avg != 30 || s.Last != 60 {
+		t.Errorf("unexpected stats: min %v max %v avg %v last %v", s.Min, s.Max, s.Avg, s.Last)
+	}
+
+	// another server has its own file
+	if series, _ := Query(dir, "rpi", "cpu", testTime.Add(-time.Hour), testTime); len(series[0].Points) != 0 {
+		t.Errorf("expected no rpi samples, got %+v", series)
+	}
+}
+
+func TestRecordConcurrently(t *testing.T) {
+	dir := t.TempDir()
+	// serve and alerts watch both record; the file must stay whole
+	var wg sync.WaitGroup
+	for i := range 30 {
+		wg.Go(func() {
+			if err := Record(dir, "nas", Sample{Time: testTime.Add(time.Duration(i) * time.Minute), CPU: 10}); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+	wg.Wait()
+	if s, err := load(fileName(dir, "nas")); err != nil || len(s.Minutes) == 0 {
+		t.Fatalf("unexpected history %+v, %v", s, err)
+	}
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		if strings.HasSuffix(e.Name(), ".tmp") {
+			t.Errorf("temporary file left behind: %s", e.Name())
+		}
+	}
+}
+
+func TestHourlyAverages(t *testing.T) {
+	s := &series{}
+	for i := range 90 {
+		s.add(Sample{Time: testTime.Add(time.Duration(i) * time.Minute), CPU: float64(i % 60), Disks: map[string]float64{"/": 50}})
+	}
+	if len(s.Hours) != 1 {
+		t.Fatalf("expected the first hour averaged, got %d hours", len(s.Hours))
+	}
+	h := s.Hours[0]
+	if !h.Time.Equal(testTime) || h.CPU != 29.5 || h.Disks["/"] != 50 {
+		t.Errorf("unexpected hour: %+v", h)
+	}
+
+	// a day on, the minutes are gone but the hours remain
+	s.add(Sample{Time: testTime.Add(26 * time.Hour)})
+	if len(s.Minutes) != 1 || len(s.Hours) != 2 {
+		t.Errorf("expected 1 minute and 2 hours, got %d and %d", len(s.Minutes), len(s.Hours))
+	}
+	s.add(Sample{Time: testTime.Add(31 * 24 * time.Hour)})
+	if len(s.Hours) != 1 {
+		t.Errorf("expected the month-old hours dropped, got %+v", s.Hours)
+	}
+}
+
+func TestQueryUsesHoursBeyondADay(t *testing.T) {
+	dir := t.TempDir()
+	for i := range 3 * 60 {
+		Record(dir, "nas", Sample{Time: testTime.Add(time.Duration(i) * time.Minute), Memory: 40})
+	}
+	now := testTime.Add(3 * time.Hour)
+	series, err := Query(dir, "nas", "memory", now.Add(-7*24*time.Hour), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := series[0]; s.Resolution != "1h" || len(s.Points) != 2 || s.Avg != 40 {
+		t.Errorf("unexpected hourly series: %+v", s)
+	}
+}
+
+func TestQueryDisks(t *testing.T) {
+	dir := t.TempDir()
+	Record(dir, "nas", Sample{Time: testTime, Disks: map[string]float64{"/": 30, "/mnt/data": 80}})
+	Record(dir, "nas", Sample{Time: testTime.Add(time.Minute), Disks: map[string]float64{"/": 31, "/mnt/data": 81}})
+
+	series, err := Query(dir, "nas", "disk", testTime.Add(-time.Hour), testTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(series) != 2 || series[0].Metric != "disk:/" || series[1].Metric != "disk:/mnt/data" || series[1].Last != 81 {
+		t.Errorf("unexpected disk series: %+v", series)
+	}
+	series, _ = Query(dir, "nas", "disk:/mnt/data", testTime.Add(-time.Hour), testTime)
+	if len(series) != 1 || len(series[0].Points) != 2 {
+		t.Errorf("unexpected /mnt/data series: %+v", series)
+	}
+}
+
+func TestQueryRejectsUnknownMetrics(t *testing.T) {
+	for _, metric := range []string{"temperature", "cpu:0", ""} {
+		if _, err := Query(t.TempDir(), "nas", metric, testTime, testTime); err == nil || !strings.Contains(err.Error(), "unknown metric") {
+			t.Errorf("%q: expected unknown metric error, got %v", metric, err)
+		}
+	}
+}
+
+func TestFileNameStaysInDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := Record(dir, "../../etc/nas", Sample{Time: testTime}); err != nil {
+		t.Fatal(err)
+	}
+	// the file and its lock
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 2 || filepath.Dir(fileName(dir, "../../etc/nas")) != dir {
+		t.Errorf("expected the files in %s, got %v", dir, entries)
+	}
+}
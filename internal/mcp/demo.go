@@ -1,6 +1,14 @@
 package mcp
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/Higangssh/homebutler/internal/alerts"
+	"github.com/Higangssh/homebutler/internal/history"
+)
 
 func (s *Server) executeDemoTool(name string, args map[string]any) (any, error) {
 	server := stringArg(args, "server")
@@ -8,8 +16,22 @@ func (s *Server) executeDemoTool(name string, args map[string]any) (any, error)
 	switch name {
 	case "system_status":
 		return demoStatus(server), nil
+	case "sensors":
+		return demoSensors(server), nil
 	case "docker_list":
 		return demoDocker(server), nil
+	case "docker_stats":
+		return demoDockerStats(server), nil
+	case "docker_updates":
+		return demoDockerUpdates(server), nil
+	case "docker_df":
+		return demoDockerDiskUsage(), nil
+	case "docker_prune":
+		opts, err := pruneOptions(args)
+		if err != nil {
+			return nil, err
+		}
+		return demoDockerPrune(opts.DryRun), nil
 	case "docker_restart":
 		cname, ok := requireString(args, "name")
 		if !ok {
@@ -22,6 +44,43 @@ func (s *Server) executeDemoTool(name string, args map[string]any) (any, error)
 			return nil, fmt.Errorf("missing required parameter: name")
 		}
 		return map[string]any{"action": "stop", "container": cname, "status": "stopped"}, nil
+	case "docker_start", "docker_pause", "docker_unpause", "docker_kill", "docker_rm":
+		cname, ok := requireString(args, "name")
+		if !ok {
+			return nil, fmt.Errorf("missing required parameter: name")
+		}
+		result := map[string]any{"action": strings.TrimPrefix(name, "docker_"), "container": cname, "status": "ok"}
+		if name == "docker_kill" {
+			signal := strings.ToUpper(stringArg(args, "signal"))
+			if signal == "" {
+				signal = "SIGKILL"
+			}
+			result["signal"] = signal
+		}
+		return result, nil
+	case "compose_list":
+		return demoComposeProjects(server), nil
+	case "compose_ps", "compose_restart", "compose_up", "compose_down", "compose_pull":
+		project, ok := requireString(args, "project")
+		if !ok {
+			return nil, fmt.Errorf("missing required parameter: project")
+		}
+		containers := demoComposeContainers(server, project)
+		if name == "compose_ps" {
+			return containers, nil
+		}
+		if len(containers) == 0 {
+			return nil, fmt.Errorf("compose project %q not found", project)
+		}
+		result := map[string]any{"project": project, "action": strings.TrimPrefix(name, "compose_"), "status": "ok"}
+		if name == "compose_restart" {
+			names := make([]string, len(containers))
+			for i, c := range containers {
+				names[i] = c["name"].(string)
+			}
+			result["containers"] = names
+		}
+		return result, nil
 	case "docker_logs":
 		cname, ok := requireString(args, "name")
 		if !ok {
@@ -40,6 +99,34 @@ func (s *Server) executeDemoTool(name string, args map[string]any) (any, error)
 		return demoNetworkScan(), nil
 	case "alerts":
 		return demoAlerts(server), nil
+	case "silence_list":
+		return demoSilences(server), nil
+	case "alert_history":
+		q, err := historyOptions(args)
+		if err != nil {
+			return nil, err
+		}
+		return demoAlertHistory(q), nil
+	case "metrics_history":
+		metric, from, err := metricsOptions(args)
+		if err != nil {
+			return nil, err
+		}
+		return demoMetricsHistory(server, metric, from), nil
+	case "silence_create":
+		metrics, d, err := silenceOptions(args)
+		if err != nil {
+			return nil, err
+		}
+		now := time.Now().UTC().Truncate(time.Second)
+		return map[string]any{"id": "d3m0a1b2", "metrics": metrics, "reason": stringArg(args, "reason"), "start": now, "end": now.Add(d)}, nil
+	case "silence_expire":
+		id, ok := requireString(args, "id")
+		if !ok {
+			return nil, fmt.Errorf("missing required parameter: id")
+		}
+		now := time.Now().UTC().Truncate(time.Second)
+		return map[string]any{"id": id, "start": now.Add(-time.Hour), "end": now}, nil
 	default:
 		return nil, fmt.Errorf("unknown tool: %s", name)
 	}
@@ -82,38 +169,190 @@ func demoStatus(server string) map[string]any {
 	}
 }
 
+func demoSensors(server string) map[string]any {
+	switch server {
+	case "nas-box":
+		return map[string]any{
+			"temperatures": []map[string]any{
+				{"chip": "coretemp", "label": "Package id 0", "celsius": 44.0, "high": 80.0, "critical": 100.0},
+				{"chip": "drivetemp", "label": "temp1", "celsius": 38.0, "high": 55.0, "critical": 70.0},
+			},
+			"fans": []map[string]any{
+				{"chip": "it8613", "label": "fan1", "rpm": 820},
+			},
+		}
+	case "raspberry-pi":
+		return map[string]any{
+			"temperatures": []map[string]any{
+				{"chip": "cpu_thermal", "label": "temp1", "celsius": 61.3},
+			},
+			"fans": []map[string]any{},
+		}
+	default:
+		return map[string]any{
+			"temperatures": []map[string]any{
+				{"chip": "coretemp", "label": "Package id 0", "celsius": 54.0, "high": 80.0, "critical": 100.0},
+				{"chip": "nvme", "label": "Composite", "celsius": 41.9, "high": 81.8, "critical": 84.8},
+			},
+			"fans": []map[string]any{
+				{"chip": "nct6775", "label": "CPU Fan", "rpm": 1245},
+			},
+		}
+	}
+}
+
 func demoDocker(server string) map[string]any {
 	switch server {
 	case "nas-box":
 		return map[string]any{
 			"available": true,
 			"containers": []map[string]any{
-				{"id": "aa11bb22cc33", "name": "samba", "image": "dperson/samba:latest", "status": "Up 12 days", "state": "running", "ports": "445/tcp"},
-				{"id": "dd44ee55ff66", "name": "plex", "image": "plexinc/pms-docker:latest", "status": "Up 12 days", "state": "running", "ports": "0.0.0.0:32400->32400/tcp"},
+				{"id": "aa11bb22cc33", "name": "samba", "image": "dperson/samba:latest", "status": "Up 12 days", "state": "running", "ports": []map[string]any{{"private_port": 445, "type": "tcp"}}},
+				{"id": "dd44ee55ff66", "name": "plex", "image": "plexinc/pms-docker:latest", "status": "Up 12 days", "state": "running", "ports": []map[string]any{{"ip": "0.0.0.0", "private_port": 32400, "public_port": 32400, "type": "tcp"}}},
 			},
 		}
 	case "raspberry-pi":
 		return map[string]any{
 			"available": true,
 			"containers": []map[string]any{
-				{"id": "pi11pi22pi33", "name": "pihole", "image": "pihole/pihole:latest", "status": "Up 28 days", "state": "running", "ports": "0.0.0.0:53->53/tcp, 0.0.0.0:80->80/tcp"},
+				{"id": "pi11pi22pi33", "name": "pihole", "image": "pihole/pihole:latest", "status": "Up 28 days", "state": "running", "ports": []map[string]any{{"ip": "0.0.0.0", "private_port": 53, "public_port": 53, "type": "tcp"}, {"ip": "0.0.0.0", "private_port": 80, "public_port": 80, "type": "tcp"}}},
 			},
 		}
 	default:
 		return map[string]any{
 			"available": true,
 			"containers": []map[string]any{
-				{"id": "a1b2c3d4e5f6", "name": "nginx", "image": "nginx:1.25-alpine", "status": "Up 4 days", "state": "running", "ports": "0.0.0.0:80->80/tcp, 0.0.0.0:443->443/tcp"},
-				{"id": "b2c3d4e5f6a1", "name": "postgres", "image": "postgres:16", "status": "Up 4 days", "state": "running", "ports": "5432/tcp"},
-				{"id": "c3d4e5f6a1b2", "name": "redis", "image": "redis:7-alpine", "status": "Up 4 days", "state": "running", "ports": "6379/tcp"},
-				{"id": "d4e5f6a1b2c3", "name": "grafana", "image": "grafana/grafana:10.2", "status": "Up 3 days", "state": "running", "ports": "0.0.0.0:3000->3000/tcp"},
-				{"id": "e5f6a1b2c3d4", "name": "prometheus", "image": "prom/prometheus:v2.48", "status": "Up 3 days", "state": "running", "ports": "0.0.0.0:9090->9090/tcp"},
-				{"id": "f6a1b2c3d4e5", "name": "backup", "image": "restic/restic:0.16", "status": "Exited (0) 6h ago", "state": "exited", "ports": ""},
+				{"id": "a1b2c3d4e5f6", "name": "nginx", "image": "nginx:1.25-alpine", "status": "Up 4 days", "state": "running", "ports": []map[string]any{{"ip": "0.0.0.0", "private_port": 80, "public_port": 80, "type": "tcp"}, {"ip": "0.0.0.0", "private_port": 443, "public_port": 443, "type": "tcp"}}},
+				{"id": "b2c3d4e5f6a1", "name": "postgres", "image": "postgres:16", "status": "Up 4 days", "state": "running", "ports": []map[string]any{{"private_port": 5432, "type": "tcp"}}},
+				{"id": "c3d4e5f6a1b2", "name": "redis", "image": "redis:7-alpine", "status": "Up 4 days", "state": "running", "ports": []map[string]any{{"private_port": 6379, "type": "tcp"}}},
+				{"id": "d4e5f6a1b2c3", "name": "grafana", "image": "grafana/grafana:10.2", "status": "Up 3 days", "state": "running", "ports": []map[string]any{{"ip": "0.0.0.0", "private_port": 3000, "public_port": 3000, "type": "tcp"}}},
+				{"id": "e5f6a1b2c3d4", "name": "prometheus", "image": "prom/prometheus:v2.48", "status": "Up 3 days", "state": "running", "ports": []map[string]any{{"ip": "0.0.0.0", "private_port": 9090, "public_port": 9090, "type": "tcp"}}},
+				{"id": "f6a1b2c3d4e5", "name": "backup", "image": "restic/restic:0.16", "status": "Exited (0) 6h ago", "state": "exited", "ports": []map[string]any{}},
 			},
 		}
 	}
 }
 
+func demoDockerStats(server string) []map[string]any {
+	const mb uint64 = 1 << 20
+	switch server {
+	case "nas-box":
+		return []map[string]any{
+			{"id": "aa11bb22cc33", "name": "samba", "cpu_percent": 0.4, "mem_usage_bytes": 38 * mb, "mem_limit_bytes": 16384 * mb, "mem_percent": 0.23, "net_rx_bytes": 912 * mb, "net_tx_bytes": 20480 * mb, "block_read_bytes": 40960 * mb, "block_write_bytes": 1024 * mb, "pids": 6},
+			{"id": "dd44ee55ff66", "name": "plex", "cpu_percent": 35.8, "mem_usage_bytes": 1843 * mb, "mem_limit_bytes": 16384 * mb, "mem_percent": 11.25, "net_rx_bytes": 64 * mb, "net_tx_bytes": 8192 * mb, "block_read_bytes": 12288 * mb, "block_write_bytes": 512 * mb, "pids": 48},
+		}
+	case "raspberry-pi":
+		return []map[string]any{
+			{"id": "pi11pi22pi33", "name": "pihole", "cpu_percent": 1.2, "mem_usage_bytes": 96 * mb, "mem_limit_bytes": 4096 * mb, "mem_percent": 2.34, "net_rx_bytes": 310 * mb, "net_tx_bytes": 295 * mb, "block_read_bytes": 45 * mb, "block_write_bytes": 210 * mb, "pids": 14},
+		}
+	default:
+		return []map[string]any{
+			{"id": "a1b2c3d4e5f6", "name": "nginx", "cpu_percent": 0.8, "mem_usage_bytes": 12 * mb, "mem_limit_bytes": 32768 * mb, "mem_percent": 0.04, "net_rx_bytes": 1536 * mb, "net_tx_bytes": 6144 * mb, "block_read_bytes": 8 * mb, "block_write_bytes": 0, "pids": 5},
+			{"id": "b2c3d4e5f6a1", "name": "postgres", "cpu_percent": 4.6, "mem_usage_bytes": 2150 * mb, "mem_limit_bytes": 32768 * mb, "mem_percent": 6.56, "net_rx_bytes": 420 * mb, "net_tx_bytes": 980 * mb, "block_read_bytes": 3200 * mb, "block_write_bytes": 7800 * mb, "pids": 17},
+			{"id": "c3d4e5f6a1b2", "name": "redis", "cpu_percent": 0.3, "mem_usage_bytes": 64 * mb, "mem_limit_bytes": 32768 * mb, "mem_percent": 0.2, "net_rx_bytes": 210 * mb, "net_tx_bytes": 190 * mb, "block_read_bytes": 2 * mb, "block_write_bytes": 96 * mb, "pids": 6},
+			{"id": "d4e5f6a1b2c3", "name": "grafana", "cpu_percent": 1.1, "mem_usage_bytes": 180 * mb, "mem_limit_bytes": 32768 * mb, "mem_percent": 0.55, "net_rx_bytes": 88 * mb, "net_tx_bytes": 350 * mb, "block_read_bytes": 120 * mb, "block_write_bytes": 40 * mb, "pids": 21},
+			{"id": "e5f6a1b2c3d4", "name": "prometheus", "cpu_percent": 2.9, "mem_usage_bytes": 740 * mb, "mem_limit_bytes": 32768 * mb, "mem_percent": 2.26, "net_rx_bytes": 1200 * mb, "net_tx_bytes": 150 * mb, "block_read_bytes": 600 * mb, "block_write_bytes": 4300 * mb, "pids": 14},
+		}
+	}
+}
+
+func demoDockerUpdates(server string) []map[string]any {
+	switch server {
+	case "nas-box":
+		return []map[string]any{
+			{"container": "samba", "image": "dperson/samba:latest", "local_digest": "sha256:5c1b9e8a7d3f2e6b4a0c9d8e7f6a5b4c3d2e1f0a9b8c7d6e5f4a3b2c1d0e9f8a", "remote_digest": "sha256:5c1b9e8a7d3f2e6b4a0c9d8e7f6a5b4c3d2e1f0a9b8c7d6e5f4a3b2c1d0e9f8a", "status": "up_to_date"},
+			{"container": "plex", "image": "plexinc/pms-docker:latest", "local_digest": "sha256:8e2f4a6c8b0d2e4f6a8c0b2d4e6f8a0c2b4d6e8f0a2c4b6d8e0f2a4c6b8d0e2f", "remote_digest": "sha256:1a3c5e7b9d1f3a5c7e9b1d3f5a7c9e1b3d5f7a9c1e3b5d7f9a1c3e5b7d9f1a3c", "status": "update_available"},
+		}
+	case "raspberry-pi":
+		return []map[string]any{
+			{"container": "pihole", "image": "pihole/pihole:latest", "local_digest": "sha256:9f8e7d6c5b4a39281706f5e4d3c2b1a09f8e7d6c5b4a39281706f5e4d3c2b1a0", "remote_digest": "sha256:2b4d6f8a0c2e4b6d8f0a2c4e6b8d0f2a4c6e8b0d2f4a6c8e0b2d4f6a8c0e2b4d", "status": "update_available"},
+		}
+	default:
+		return []map[string]any{
+			{"container": "nginx", "image": "nginx:1.25-alpine", "local_digest": "sha256:3e2f1a0b9c8d7e6f5a4b3c2d1e0f9a8b7c6d5e4f3a2b1c0d9e8f7a6b5c4d3e2f", "remote_digest": "sha256:3e2f1a0b9c8d7e6f5a4b3c2d1e0f9a8b7c6d5e4f3a2b1c0d9e8f7a6b5c4d3e2f", "status": "up_to_date"},
+			{"container": "postgres", "image": "postgres:16", "local_digest": "sha256:7a6b5c4d3e2f1a0b9c8d7e6f5a4b3c2d1e0f9a8b7c6d5e4f3a2b1c0d9e8f7a6b", "remote_digest": "sha256:c4d3e2f1a0b9c8d7e6f5a4b3c2d1e0f9a8b7c6d5e4f3a2b1c0d9e8f7a6b5c4d3", "status": "update_available"},
+			{"container": "redis", "image": "redis:7-alpine", "local_digest": "sha256:0d9e8f7a6b5c4d3e2f1a0b9c8d7e6f5a4b3c2d1e0f9a8b7c6d5e4f3a2b1c0d9e", "remote_digest": "sha256:0d9e8f7a6b5c4d3e2f1a0b9c8d7e6f5a4b3c2d1e0f9a8b7c6d5e4f3a2b1c0d9e", "status": "up_to_date"},
+			{"container": "grafana", "image": "grafana/grafana:10.2", "local_digest": "sha256:6f5a4b3c2d1e0f9a8b7c6d5e4f3a2b1c0d9e8f7a6b5c4d3e2f1a0b9c8d7e6f5a", "remote_digest": "sha256:6f5a4b3c2d1e0f9a8b7c6d5e4f3a2b1c0d9e8f7a6b5c4d3e2f1a0b9c8d7e6f5a", "status": "up_to_date"},
+			{"container": "prometheus", "image": "prom/prometheus:v2.48", "local_digest": "sha256:b7c6d5e4f3a2b1c0d9e8f7a6b5c4d3e2f1a0b9c8d7e6f5a4b3c2d1e0f9a8b7c6", "remote_digest": "sha256:b7c6d5e4f3a2b1c0d9e8f7a6b5c4d3e2f1a0b9c8d7e6f5a4b3c2d1e0f9a8b7c6", "status": "up_to_date"},
+		}
+	}
+}
+
+func demoDockerDiskUsage() map[string]any {
+	const mb uint64 = 1 << 20
+	return map[string]any{
+		"images":            map[string]any{"total": 14, "active": 5, "size_bytes": 6840 * mb, "reclaimable_bytes": 3920 * mb},
+		"containers":        map[string]any{"total": 7, "active": 5, "size_bytes": 310 * mb, "reclaimable_bytes": 85 * mb},
+		"volumes":           map[string]any{"total": 9, "active": 6, "size_bytes": 12400 * mb, "reclaimable_bytes": 1150 * mb},
+		"build_cache":       map[string]any{"total": 42, "active": 0, "size_bytes": 2260 * mb, "reclaimable_bytes": 2260 * mb},
+		"total_bytes":       21810 * mb,
+		"reclaimable_bytes": 7415 * mb,
+		"root_dir":          "/var/lib/docker",
+	}
+}
+
+func demoDockerPrune(dryRun bool) map[string]any {
+	const mb uint64 = 1 << 20
+	return map[string]any{
+		"dry_run": dryRun,
+		"items": []map[string]any{
+			{"type": "container", "id": "f6a1b2c3d4e5", "name": "backup", "size_bytes": 85 * mb, "created": "2026-02-20T08:00:00Z"},
+			{"type": "image", "id": "sha256:9a8b7c6d5e4f", "name": "<none>:<none>", "size_bytes": 1480 * mb, "created": "2026-01-30T11:12:00Z"},
+			{"type": "image", "id": "sha256:1b2c3d4e5f6a", "name": "<none>:<none>", "size_bytes": 2440 * mb, "created": "2026-01-12T09:40:00Z"},
+			{"type": "build_cache", "id": "build-cache", "size_bytes": 2260 * mb},
+		},
+		"reclaimed_bytes": 6265 * mb,
+	}
+}
+
+// demoComposeServices maps demo container names to their compose project
+// and service.
+var demoComposeServices = map[string][2]string{
+	"nginx":      {"web", "nginx"},
+	"postgres":   {"web", "db"},
+	"redis":      {"web", "cache"},
+	"grafana":    {"monitoring", "grafana"},
+	"prometheus": {"monitoring", "prometheus"},
+	"samba":      {"nas", "samba"},
+	"plex":       {"nas", "plex"},
+	"pihole":     {"pihole", "pihole"},
+}
+
+func demoComposeProjects(server string) []map[string]any {
+	switch server {
+	case "nas-box":
+		return []map[string]any{
+			{"name": "nas", "status": "running", "running": 2, "total": 2, "services": []string{"plex", "samba"}, "working_dir": "/opt/stacks/nas"},
+		}
+	case "raspberry-pi":
+		return []map[string]any{
+			{"name": "pihole", "status": "running", "running": 1, "total": 1, "services": []string{"pihole"}, "working_dir": "/home/pi/pihole"},
+		}
+	default:
+		return []map[string]any{
+			{"name": "monitoring", "status": "running", "running": 2, "total": 2, "services": []string{"grafana", "prometheus"}, "working_dir": "/opt/stacks/monitoring"},
+			{"name": "web", "status": "running", "running": 3, "total": 3, "services": []string{"cache", "db", "nginx"}, "working_dir": "/opt/stacks/web"},
+		}
+	}
+}
+
+func demoComposeContainers(server, project string) []map[string]any {
+	result := []map[string]any{}
+	for _, c := range demoDocker(server)["containers"].([]map[string]any) {
+		svc, ok := demoComposeServices[c["name"].(string)]
+		if !ok || svc[0] != project {
+			continue
+		}
+		ctr := make(map[string]any, len(c)+2)
+		for k, v := range c {
+			ctr[k] = v
+		}
+		ctr["project"], ctr["service"] = svc[0], svc[1]
+		result = append(result, ctr)
+	}
+	return result
+}
+
 func demoLogs(container string) map[string]any {
 	logs := map[string]string{
 		"nginx":    "2026/02/27 14:25:01 [notice] 1#1: start worker process 29\n2026/02/27 14:28:33 192.168.1.5 - - \"GET /api/health HTTP/1.1\" 200 2\n2026/02/27 14:29:01 192.168.1.10 - - \"GET / HTTP/1.1\" 200 612\n2026/02/27 14:30:15 192.168.1.20 - - \"GET /dashboard HTTP/1.1\" 304 0",
@@ -167,23 +406,33 @@ func demoAlerts(server string) map[string]any {
 	switch server {
 	case "nas-box":
 		return map[string]any{
+			"status": "warning",
 			"cpu":    map[string]any{"status": "ok", "current": 5.2, "threshold": 90.0},
 			"memory": map[string]any{"status": "ok", "current": 42.5, "threshold": 85.0},
 			"disks": []map[string]any{
 				{"mount": "/", "status": "ok", "current": 26.7, "threshold": 90.0},
 				{"mount": "/mnt/storage", "status": "warning", "current": 62.0, "threshold": 70.0},
 			},
+			"containers": []map[string]any{
+				{"container": "samba", "rule": "running", "status": "ok", "message": "samba is running"},
+				{"container": "plex", "rule": "restarts", "status": "critical", "message": "plex exited 3 times in 10 minutes", "current": 3.0, "threshold": 3.0, "silenced": true},
+			},
 		}
 	case "raspberry-pi":
 		return map[string]any{
+			"status": "warning",
 			"cpu":    map[string]any{"status": "ok", "current": 12.1, "threshold": 90.0},
 			"memory": map[string]any{"status": "ok", "current": 52.5, "threshold": 85.0},
 			"disks": []map[string]any{
 				{"mount": "/", "status": "ok", "current": 28.1, "threshold": 90.0},
 			},
+			"temperatures": []map[string]any{
+				{"sensor": "cpu_thermal/temp1", "status": "warning", "current": 73.5, "threshold": 80.0},
+			},
 		}
 	default:
 		return map[string]any{
+			"status": "warning",
 			"cpu":    map[string]any{"status": "ok", "current": 23.4, "threshold": 90.0},
 			"memory": map[string]any{"status": "ok", "current": 38.8, "threshold": 85.0},
 			"disks": []map[string]any{
@@ -193,3 +442,66 @@ func demoAlerts(server string) map[string]any {
 		}
 	}
 }
+
+// demoAlertHistory is the nas-box disk filling up over the last days.
+func demoAlertHistory(q alerts.HistoryQuery) *alerts.History {
+	now := time.Now().UTC().Truncate(time.Minute)
+	entries := []alerts.HistoryEntry{}
+	for i, v := range []float64{86, 88, 91, 93, 92, 89} {
+		status := "warning"
+		if v >= 90 {
+			status = "critical"
+		}
+		e := alerts.HistoryEntry{
+			Time: now.Add(time.Duration(i-6) * 6 * time.Hour), Server: "nas-box", Metric: "disk:/mnt/data", Label: "Disk /mnt/data",
+			Status: status, Value: v, Threshold: 90, Unit: "%",
+		}
+		if q.Matches(e) {
+			entries = append(entries, e)
+		}
+	}
+	return &alerts.History{Since: q.Since, Entries: entries, Summary: alerts.Summarize(entries)}
+}
+
+// demoMetricsHistory is the demo status of a server following a daily
+// cycle, with its data disk slowly filling.
+func demoMetricsHistory(server, metric string, from time.Time) []history.Series {
+	if server == "" {
+		server = "homelab-server"
+	}
+	now := time.Now().UTC().Truncate(time.Minute)
+	if oldest := now.Add(-30 * 24 * time.Hour); from.Before(oldest) {
+		from = oldest
+	}
+	step, resolution := time.Minute, "1m"
+	if from.Before(now.Add(-24 * time.Hour)) {
+		step, resolution = time.Hour, "1h"
+	}
+	cpu, mem, load, data, mount := 23.4, 38.8, 1.42, 87.0, "/mnt/data"
+	switch server {
+	case "homelab-server":
+	case "nas-box":
+		cpu, mem, load, data, mount = 5.2, 42.5, 0.31, 62.0, "/mnt/storage"
+	default:
+		return history.Select(server, metric, resolution, nil)
+	}
+	var samples []history.Sample
+	for t := from.UTC().Truncate(step).Add(step); !t.After(now); t = t.Add(step) {
+		day := math.Sin(2 * math.Pi * float64(t.Hour()*60+t.Minute()) / (24 * 60))
+		samples = append(samples, history.Sample{
+			Time: t, CPU: cpu * (1 + 0.4*day), Memory: mem * (1 + 0.05*day), Load: load * (1 + 0.4*day),
+			Disks: map[string]float64{"/": 30, mount: data - 0.3*now.Sub(t).Hours()/24},
+			NetRx: 4718592 * (1 + 0.6*day), NetTx: 1153434 * (1 + 0.6*day),
+		})
+	}
+	return history.Select(server, metric, resolution, samples)
+}
+
+func demoSilences(server string) []map[string]any {
+	if server != "nas-box" {
+		return []map[string]any{}
+	}
+	return []map[string]any{
+		{"id": "3f9c2a1b", "metrics": []string{"container:restarts:plex"}, "reason": "waiting for the transcoder fix", "start": "2026-02-27T09:00:00Z", "end": "2026-02-28T09:00:00Z"},
+	}
+}
@@ -4,12 +4,13 @@ import (
 	"testing"
 
 	"github.com/Higangssh/homebutler/internal/config"
+	"github.com/Higangssh/homebutler/internal/history"
 )
 
 func TestExecuteDemoTool_Basic(t *testing.T) {
 	s := NewServer(&config.Config{}, "dev", true)
 
-	cases := []string{"system_status", "docker_list", "open_ports", "network_scan", "alerts"}
+	cases := []string{"system_status", "sensors", "docker_list", "docker_stats", "docker_updates", "docker_df", "compose_list", "open_ports", "network_scan", "alerts", "silence_list", "alert_history"}
 	for _, tool := range cases {
 		res, err := s.executeDemoTool(tool, map[string]any{"server": "homelab-server"})
 		if err != nil {
@@ -33,9 +34,65 @@ func TestExecuteDemoTool_RequiredArgs(t *testing.T) {
 	if _, err := s.executeDemoTool("docker_logs", nil); err == nil {
 		t.Fatal("expected error for missing docker_logs name")
 	}
+	for _, tool := range []string{"docker_start", "docker_pause", "docker_unpause", "docker_kill", "docker_rm"} {
+		if _, err := s.executeDemoTool(tool, nil); err == nil {
+			t.Fatalf("expected error for missing %s name", tool)
+		}
+	}
 	if _, err := s.executeDemoTool("wake", nil); err == nil {
 		t.Fatal("expected error for missing wake target")
 	}
+	if _, err := s.executeDemoTool("metrics_history", nil); err == nil {
+		t.Fatal("expected error for missing metrics_history metric")
+	}
+}
+
+func TestExecuteDemoTool_MetricsHistory(t *testing.T) {
+	s := NewServer(&config.Config{}, "dev", true)
+	got, err := s.executeDemoTool("metrics_history", map[string]any{"metric": "disk", "server": "nas-box", "since": "7d"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	series := got.([]history.Series)
+	if len(series) != 2 || series[1].Metric != "disk:/mnt/storage" || series[1].Resolution != "1h" || series[1].Last <= series[1].Points[0].Value {
+		t.Errorf("unexpected demo history: %+v", series)
+	}
+}
+
+func TestExecuteDemoTool_DockerKill(t *testing.T) {
+	s := NewServer(&config.Config{}, "dev", true)
+	got, err := s.executeDemoTool("docker_kill", map[string]any{"name": "nginx-proxy", "signal": "hup"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := got.(map[string]any)
+	if result["action"] != "kill" || result["signal"] != "HUP" {
+		t.Errorf("unexpected result: %v", result)
+	}
+}
+
+func TestExecuteDemoTool_Compose(t *testing.T) {
+	s := NewServer(&config.Config{}, "dev", true)
+	got, err := s.executeDemoTool("compose_ps", map[string]any{"project": "monitoring"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if containers := got.([]map[string]any); len(containers) != 2 || containers[0]["project"] != "monitoring" {
+		t.Errorf("unexpected compose_ps result: %v", got)
+	}
+	got, err = s.executeDemoTool("compose_restart", map[string]any{"project": "web"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := got.(map[string]any)["containers"].([]string); len(names) != 3 {
+		t.Errorf("unexpected compose_restart result: %v", got)
+	}
+	if _, err := s.executeDemoTool("compose_up", map[string]any{"project": "ghost"}); err == nil {
+		t.Error("expected error for unknown demo project")
+	}
+	if _, err := s.executeDemoTool("compose_down", nil); err == nil {
+		t.Error("expected error for missing project")
+	}
 }
 
 func TestExecuteDemoTool_Unknown(t *testing.T) {
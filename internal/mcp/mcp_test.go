@@ -5,13 +5,16 @@ import (
 	"encoding/json"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/Higangssh/homebutler/internal/alerts"
 	"github.com/Higangssh/homebutler/internal/config"
+	"github.com/Higangssh/homebutler/internal/history"
 )
 
 func newTestServer() (*Server, *bytes.Buffer) {
 	cfg := &config.Config{
-		Alerts: config.AlertConfig{CPU: 90, Memory: 85, Disk: 90},
+		Alerts: config.AlertConfig{CPU: config.Threshold{Critical: 90}, Memory: config.Threshold{Critical: 85}, Disk: config.Threshold{Critical: 90}},
 		Wake: []config.WakeTarget{
 			{Name: "nas", MAC: "AA:BB:CC:DD:EE:FF", Broadcast: "192.168.1.255"},
 		},
@@ -95,20 +98,41 @@ func TestToolsList(t *testing.T) {
 		t.Fatalf("unmarshal toolsListResult: %v", err)
 	}
 
-	if len(list.Tools) != 9 {
-		t.Errorf("expected 9 tools, got %d", len(list.Tools))
+	if len(list.Tools) != 30 {
+		t.Errorf("expected 30 tools, got %d", len(list.Tools))
 	}
 
 	expectedTools := map[string]bool{
-		"system_status":  false,
-		"docker_list":    false,
-		"docker_restart": false,
-		"docker_stop":    false,
-		"docker_logs":    false,
-		"wake":           false,
-		"open_ports":     false,
-		"network_scan":   false,
-		"alerts":         false,
+		"system_status":   false,
+		"sensors":         false,
+		"docker_list":     false,
+		"docker_stats":    false,
+		"docker_updates":  false,
+		"docker_restart":  false,
+		"docker_stop":     false,
+		"docker_start":    false,
+		"docker_pause":    false,
+		"docker_unpause":  false,
+		"docker_kill":     false,
+		"docker_rm":       false,
+		"docker_df":       false,
+		"docker_prune":    false,
+		"docker_logs":     false,
+		"compose_list":    false,
+		"compose_ps":      false,
+		"compose_restart": false,
+		"compose_up":      false,
+		"compose_down":    false,
+		"compose_pull":    false,
+		"wake":            false,
+		"open_ports":      false,
+		"network_scan":    false,
+		"alerts":          false,
+		"silence_list":    false,
+		"silence_create":  false,
+		"silence_expire":  false,
+		"alert_history":   false,
+		"metrics_history": false,
 	}
 
 	for _, tool := range list.Tools {
@@ -287,10 +311,23 @@ func TestEmptyLines(t *testing.T) {
 func TestToolDefinitionsHaveRequiredFields(t *testing.T) {
 	tools := toolDefinitions()
 	requireMap := map[string][]string{
-		"docker_restart": {"name"},
-		"docker_stop":    {"name"},
-		"docker_logs":    {"name"},
-		"wake":           {"target"},
+		"docker_restart":  {"name"},
+		"docker_stop":     {"name"},
+		"docker_start":    {"name"},
+		"docker_pause":    {"name"},
+		"docker_unpause":  {"name"},
+		"docker_kill":     {"name"},
+		"docker_rm":       {"name"},
+		"docker_logs":     {"name"},
+		"compose_ps":      {"project"},
+		"compose_restart": {"project"},
+		"compose_up":      {"project"},
+		"compose_down":    {"project"},
+		"compose_pull":    {"project"},
+		"wake":            {"target"},
+		"silence_create":  {"duration"},
+		"silence_expire":  {"id"},
+		"metrics_history": {"metric"},
 	}
 
 	for _, tool := range tools {
@@ -339,3 +376,91 @@ func TestStringArg(t *testing.T) {
 		t.Errorf("stringArg(nil, key) = %q, want empty", v)
 	}
 }
+
+func TestPruneOptions(t *testing.T) {
+	opts, err := pruneOptions(map[string]any{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !opts.DryRun {
+		t.Error("docker_prune should default to a dry run")
+	}
+
+	opts, err = pruneOptions(map[string]any{"dry_run": false, "older_than": "7d", "all": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.DryRun || !opts.All || opts.Volumes || opts.OlderThan != 7*24*time.Hour {
+		t.Errorf("unexpected options: %+v", opts)
+	}
+
+	if _, err := pruneOptions(map[string]any{"older_than": "soon; rm -rf /"}); err == nil {
+		t.Error("expected error for invalid older_than")
+	}
+}
+
+func TestSilenceOptions(t *testing.T) {
+	metrics, d, err := silenceOptions(map[string]any{"duration": "4h", "metric": "disk, cpu"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(metrics) != 2 || metrics[1] != "cpu" || d != 4*time.Hour {
+		t.Errorf("unexpected options: %v %v", metrics, d)
+	}
+	if _, _, err := silenceOptions(map[string]any{"metric": "disk"}); err == nil {
+		t.Error("expected error without duration")
+	}
+	if _, _, err := silenceOptions(map[string]any{"duration": "forever"}); err == nil {
+		t.Error("expected error for invalid duration")
+	}
+}
+
+func TestExecuteRemoteValidatesSilenceID(t *testing.T) {
+	s, _ := newTestServer()
+	srv := &config.ServerConfig{Name: "rpi", Host: "192.0.2.1"}
+	if _, err := s.executeRemote(srv, "silence_expire", map[string]any{"id": "x; reboot"}); err == nil || !strings.Contains(err.Error(), "invalid silence id") {
+		t.Errorf("expected invalid id error, got %v", err)
+	}
+}
+
+func TestAlertHistoryFiltersByServer(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	s, _ := newTestServer()
+	s.cfg.Servers = []config.ServerConfig{{Name: "rpi", Host: "192.0.2.1"}}
+	disk := []alerts.Metric{{Key: "disk:/", Label: "Disk /", Status: "critical", Current: 95, Threshold: 90}}
+	alerts.RecordHistory(alerts.DefaultHistoryPath(), "rpi", disk, time.Now(), time.Hour)
+	alerts.RecordHistory(alerts.DefaultHistoryPath(), "nas", disk, time.Now(), time.Hour)
+
+	// rpi is remote, but its history is kept here: no SSH
+	result, err := s.executeTool("alert_history", map[string]any{"server": "rpi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h := result.(*alerts.History); len(h.Entries) != 1 || h.Entries[0].Server != "rpi" {
+		t.Errorf("unexpected history: %+v", h)
+	}
+	if _, err := historyOptions(map[string]any{"since": "soon"}); err == nil {
+		t.Error("expected error for invalid since")
+	}
+}
+
+func TestMetricsHistoryReadsLocalStore(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	s, _ := newTestServer()
+	s.cfg.Servers = []config.ServerConfig{{Name: "rpi", Host: "192.0.2.1"}}
+	history.Record(history.DefaultDir(), "rpi", history.Sample{Time: time.Now().Add(-time.Minute), Memory: 64})
+
+	// rpi is remote, but its history is kept here: no SSH
+	result, err := s.executeTool("metrics_history", map[string]any{"server": "rpi", "metric": "memory", "since": "1h"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if series := result.([]history.Series); len(series) != 1 || series[0].Last != 64 || series[0].Unit != "%" {
+		t.Errorf("unexpected history: %+v", series)
+	}
+	for _, args := range []map[string]any{{}, {"metric": "temperature"}, {"metric": "cpu", "since": "soon"}} {
+		if _, err := s.executeTool("metrics_history", args); err == nil {
+			t.Errorf("expected error for %v", args)
+		}
+	}
+}
@@ -7,11 +7,13 @@ import (
 	"io"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Higangssh/homebutler/internal/alerts"
 	"github.com/Higangssh/homebutler/internal/config"
 	"github.com/Higangssh/homebutler/internal/docker"
+	"github.com/Higangssh/homebutler/internal/history"
 	"github.com/Higangssh/homebutler/internal/network"
 	"github.com/Higangssh/homebutler/internal/ports"
 	"github.com/Higangssh/homebutler/internal/remote"
@@ -198,8 +200,9 @@ func (s *Server) executeTool(name string, args map[string]any) (any, error) {
 
 	server := stringArg(args, "server")
 
-	// Route to remote if server is specified and not local
-	if server != "" {
+	// Route to remote if server is specified and not local. The alert and
+	// metrics history of every server is kept here, so there it is a filter.
+	if server != "" && name != "alert_history" && name != "metrics_history" {
 		srv := s.cfg.FindServer(server)
 		if srv == nil {
 			return nil, fmt.Errorf("server %q not found in config", server)
@@ -212,8 +215,14 @@ func (s *Server) executeTool(name string, args map[string]any) (any, error) {
 	switch name {
 	case "system_status":
 		return system.Status()
+	case "sensors":
+		return system.Sensors()
 	case "docker_list":
 		return docker.List()
+	case "docker_stats":
+		return docker.Stats()
+	case "docker_updates":
+		return docker.Updates()
 	case "docker_restart":
 		cname, ok := requireString(args, "name")
 		if !ok {
@@ -226,16 +235,73 @@ func (s *Server) executeTool(name string, args map[string]any) (any, error) {
 			return nil, fmt.Errorf("missing required parameter: name")
 		}
 		return docker.Stop(cname)
-	case "docker_logs":
+	case "docker_start":
+		cname, ok := requireString(args, "name")
+		if !ok {
+			return nil, fmt.Errorf("missing required parameter: name")
+		}
+		return docker.Start(cname)
+	case "docker_pause":
+		cname, ok := requireString(args, "name")
+		if !ok {
+			return nil, fmt.Errorf("missing required parameter: name")
+		}
+		return docker.Pause(cname)
+	case "docker_unpause":
+		cname, ok := requireString(args, "name")
+		if !ok {
+			return nil, fmt.Errorf("missing required parameter: name")
+		}
+		return docker.Unpause(cname)
+	case "docker_kill":
+		cname, ok := requireString(args, "name")
+		if !ok {
+			return nil, fmt.Errorf("missing required parameter: name")
+		}
+		return docker.Kill(cname, stringArg(args, "signal"))
+	case "docker_rm":
 		cname, ok := requireString(args, "name")
 		if !ok {
 			return nil, fmt.Errorf("missing required parameter: name")
 		}
-		lines := "50"
-		if v := stringArg(args, "lines"); v != "" {
-			lines = v
+		return docker.Remove(cname, boolArg(args, "force"))
+	case "docker_df":
+		return docker.DiskUsage()
+	case "docker_prune":
+		opts, err := pruneOptions(args)
+		if err != nil {
+			return nil, err
+		}
+		return docker.Prune(opts)
+	case "compose_list":
+		return docker.Projects()
+	case "compose_ps":
+		project, ok := requireString(args, "project")
+		if !ok {
+			return nil, fmt.Errorf("missing required parameter: project")
+		}
+		return docker.ProjectContainers(project)
+	case "compose_restart", "compose_up", "compose_down", "compose_pull":
+		project, ok := requireString(args, "project")
+		if !ok {
+			return nil, fmt.Errorf("missing required parameter: project")
+		}
+		switch name {
+		case "compose_restart":
+			return docker.ComposeRestart(project)
+		case "compose_up":
+			return docker.ComposeUp(project)
+		case "compose_down":
+			return docker.ComposeDown(project)
+		default:
+			return docker.ComposePull(project)
+		}
+	case "docker_logs":
+		cname, ok := requireString(args, "name")
+		if !ok {
+			return nil, fmt.Errorf("missing required parameter: name")
 		}
-		return docker.Logs(cname, lines)
+		return docker.Logs(cname, logOptions(args))
 	case "wake":
 		target, ok := requireString(args, "target")
 		if !ok {
@@ -258,34 +324,150 @@ func (s *Server) executeTool(name string, args map[string]any) (any, error) {
 	case "network_scan":
 		return network.ScanWithTimeout(30 * time.Second)
 	case "alerts":
-		return alerts.Check(&s.cfg.Alerts)
+		return alerts.Evaluate(s.cfg, nil)
+	case "silence_list":
+		return alerts.ActiveSilences(alerts.DefaultSilencePath(), s.cfg.Alerts.Maintenance, time.Now())
+	case "silence_create":
+		metrics, d, err := silenceOptions(args)
+		if err != nil {
+			return nil, err
+		}
+		return alerts.AddSilence(alerts.DefaultSilencePath(), metrics, stringArg(args, "reason"), d, time.Now())
+	case "silence_expire":
+		id, ok := requireString(args, "id")
+		if !ok {
+			return nil, fmt.Errorf("missing required parameter: id")
+		}
+		return alerts.ExpireSilence(alerts.DefaultSilencePath(), id, time.Now())
+	case "alert_history":
+		q, err := historyOptions(args)
+		if err != nil {
+			return nil, err
+		}
+		return alerts.QueryHistory(alerts.DefaultHistoryPath(), q)
+	case "metrics_history":
+		metric, from, err := metricsOptions(args)
+		if err != nil {
+			return nil, err
+		}
+		if server == "" {
+			server = s.cfg.LocalName()
+		}
+		return history.Query(history.DefaultDir(), server, metric, from, time.Now())
 	default:
 		return nil, fmt.Errorf("unknown tool: %s", name)
 	}
 }
 
 func (s *Server) executeRemote(srv *config.ServerConfig, tool string, args map[string]any) (any, error) {
+	// Arguments end up on the remote command line
+	for _, key := range []string{"name", "project"} {
+		if v := stringArg(args, key); v != "" {
+			if err := docker.ValidateName(v); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if sig := stringArg(args, "signal"); sig != "" {
+		if err := docker.ValidateSignal(sig); err != nil {
+			return nil, err
+		}
+	}
+	if id := stringArg(args, "id"); id != "" {
+		if err := alerts.ValidateSilenceID(id); err != nil {
+			return nil, err
+		}
+	}
+
 	// Build remote command args
 	var remoteArgs []string
 	switch tool {
 	case "system_status":
 		remoteArgs = []string{"status", "--json"}
+	case "sensors":
+		remoteArgs = []string{"sensors", "--json"}
 	case "docker_list":
 		remoteArgs = []string{"docker", "list", "--json"}
+	case "docker_stats":
+		remoteArgs = []string{"docker", "stats", "--json"}
+	case "docker_updates":
+		remoteArgs = []string{"docker", "updates", "--json"}
 	case "docker_restart":
 		remoteArgs = []string{"docker", "restart", stringArg(args, "name"), "--json"}
 	case "docker_stop":
 		remoteArgs = []string{"docker", "stop", stringArg(args, "name"), "--json"}
+	case "docker_start":
+		remoteArgs = []string{"docker", "start", stringArg(args, "name"), "--json"}
+	case "docker_pause":
+		remoteArgs = []string{"docker", "pause", stringArg(args, "name"), "--json"}
+	case "docker_unpause":
+		remoteArgs = []string{"docker", "unpause", stringArg(args, "name"), "--json"}
+	case "docker_kill":
+		remoteArgs = []string{"docker", "kill", stringArg(args, "name"), "--json"}
+		if v := stringArg(args, "signal"); v != "" {
+			remoteArgs = append(remoteArgs, "--signal", v)
+		}
+	case "docker_rm":
+		remoteArgs = []string{"docker", "rm", stringArg(args, "name"), "--json"}
+		if boolArg(args, "force") {
+			remoteArgs = append(remoteArgs, "--force")
+		}
+	case "docker_df":
+		remoteArgs = []string{"docker", "df", "--json"}
+	case "docker_prune":
+		opts, err := pruneOptions(args)
+		if err != nil {
+			return nil, err
+		}
+		remoteArgs = []string{"docker", "prune", "--json"}
+		if opts.DryRun {
+			remoteArgs = append(remoteArgs, "--dry-run")
+		}
+		if opts.OlderThan > 0 {
+			remoteArgs = append(remoteArgs, "--older-than", opts.OlderThan.String())
+		}
+		if opts.All {
+			remoteArgs = append(remoteArgs, "--all-images")
+		}
+		if opts.Volumes {
+			remoteArgs = append(remoteArgs, "--volumes")
+		}
+	case "compose_list":
+		remoteArgs = []string{"compose", "ls", "--json"}
+	case "compose_ps", "compose_restart", "compose_up", "compose_down", "compose_pull":
+		remoteArgs = []string{"compose", strings.TrimPrefix(tool, "compose_"), stringArg(args, "project"), "--json"}
 	case "docker_logs":
-		lines := "50"
-		if v := stringArg(args, "lines"); v != "" {
-			lines = v
+		opts := logOptions(args)
+		if err := docker.ValidateLogOptions(opts); err != nil {
+			return nil, err
+		}
+		remoteArgs = []string{"docker", "logs", stringArg(args, "name"), opts.Tail, "--json"}
+		if opts.Since != "" {
+			remoteArgs = append(remoteArgs, "--since", opts.Since)
+		}
+		if opts.Grep != "" {
+			remoteArgs = append(remoteArgs, "--grep", opts.Grep)
 		}
-		remoteArgs = []string{"docker", "logs", stringArg(args, "name"), lines, "--json"}
 	case "open_ports":
 		remoteArgs = []string{"ports", "--json"}
 	case "alerts":
-		remoteArgs = []string{"alerts", "--json"}
+		return alerts.Evaluate(s.cfg, srv)
+	case "silence_list":
+		remoteArgs = []string{"alerts", "silence", "list", "--json"}
+	case "silence_create":
+		metrics, d, err := silenceOptions(args)
+		if err != nil {
+			return nil, err
+		}
+		remoteArgs = []string{"alerts", "silence", "--json", "--for", d.String()}
+		if len(metrics) > 0 {
+			remoteArgs = append(remoteArgs, "--metric", strings.Join(metrics, ","))
+		}
+		if v := stringArg(args, "reason"); v != "" {
+			remoteArgs = append(remoteArgs, "--reason", v)
+		}
+	case "silence_expire":
+		remoteArgs = []string{"alerts", "silence", "expire", stringArg(args, "id"), "--json"}
 	default:
 		return nil, fmt.Errorf("tool %q not supported for remote execution", tool)
 	}
@@ -348,6 +530,110 @@ func requireString(args map[string]any, key string) (string, bool) {
 	return v, v != ""
 }
 
+// boolArg accepts a JSON boolean or a "true"/"false" string.
+func boolArg(args map[string]any, key string) bool {
+	switch v := args[key].(type) {
+	case bool:
+		return v
+	case string:
+		b, _ := strconv.ParseBool(v)
+		return b
+	default:
+		return false
+	}
+}
+
+// logOptions reads the docker_logs arguments. Like the CLI, it shows the
+// last 50 lines unless a since window or line count is given.
+func logOptions(args map[string]any) docker.LogOptions {
+	opts := docker.LogOptions{
+		Tail:  "50",
+		Since: stringArg(args, "since"),
+		Grep:  stringArg(args, "grep"),
+	}
+	if opts.Since != "" {
+		opts.Tail = "all"
+	}
+	if v := stringArg(args, "lines"); v != "" {
+		opts.Tail = v
+	}
+	return opts
+}
+
+// pruneOptions reads the docker_prune arguments. Unlike the CLI it is a
+// dry run unless dry_run is explicitly false, so a model has to ask twice.
+func pruneOptions(args map[string]any) (docker.PruneOptions, error) {
+	_, set := args["dry_run"]
+	opts := docker.PruneOptions{
+		DryRun:  !set || boolArg(args, "dry_run"),
+		All:     boolArg(args, "all"),
+		Volumes: boolArg(args, "volumes"),
+	}
+	if v := stringArg(args, "older_than"); v != "" {
+		age, err := docker.ParseAge(v)
+		if err != nil {
+			return opts, err
+		}
+		opts.OlderThan = age
+	}
+	return opts, nil
+}
+
+// silenceOptions reads the silence_create arguments: a required duration
+// and optional comma-separated metrics.
+func silenceOptions(args map[string]any) ([]string, time.Duration, error) {
+	v, ok := requireString(args, "duration")
+	if !ok {
+		return nil, 0, fmt.Errorf("missing required parameter: duration")
+	}
+	d, err := docker.ParseAge(v)
+	if err != nil {
+		return nil, 0, err
+	}
+	var metrics []string
+	for _, m := range strings.Split(stringArg(args, "metric"), ",") {
+		if m = strings.TrimSpace(m); m != "" {
+			metrics = append(metrics, m)
+		}
+	}
+	return metrics, d, nil
+}
+
+// historyOptions reads the since (default 24h), server and metric arguments
+// of alert_history.
+func historyOptions(args map[string]any) (alerts.HistoryQuery, error) {
+	since := stringArg(args, "since")
+	if since == "" {
+		since = "24h"
+	}
+	d, err := docker.ParseAge(since)
+	if err != nil {
+		return alerts.HistoryQuery{}, err
+	}
+	return alerts.HistoryQuery{Since: time.Now().Add(-d), Server: stringArg(args, "server"), Metric: stringArg(args, "metric")}, nil
+}
+
+// metricsOptions reads the metric and since (default 24h) arguments of
+// metrics_history.
+func metricsOptions(args map[string]any) (metric string, from time.Time, err error) {
+	metric, ok := requireString(args, "metric")
+	if !ok {
+		return "", time.Time{}, fmt.Errorf("missing required parameter: metric")
+	}
+	if !history.ValidMetric(metric) {
+		return "", time.Time{}, fmt.Errorf("unknown metric %q (use %s, or disk:<mount>)", metric, strings.Join(history.Metrics, ", "))
+	}
+	since := stringArg(args, "since")
+	if since == "" {
+		since = "24h"
+	}
+	d, err := docker.ParseAge(since)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return metric, time.Now().Add(-d), nil
+}
+
 func toolDefinitions() []toolDef {
 	return []toolDef{
 		{
@@ -360,6 +646,16 @@ func toolDefinitions() []toolDef {
 				},
 			},
 		},
+		{
+			Name:        "sensors",
+			Description: "Read hardware temperatures (with high/critical trip points) and fan speeds from hwmon/thermal sensors",
+			InputSchema: inputSchema{
+				Type: "object",
+				Properties: map[string]propDef{
+					"server": {Type: "string", Description: "Remote server name from config (optional, runs locally if omitted)"},
+				},
+			},
+		},
 		{
 			Name:        "docker_list",
 			Description: "List Docker containers with their status, image, and ports",
@@ -370,6 +666,26 @@ func toolDefinitions() []toolDef {
 				},
 			},
 		},
+		{
+			Name:        "docker_stats",
+			Description: "Get CPU, memory, network and block I/O usage of running Docker containers",
+			InputSchema: inputSchema{
+				Type: "object",
+				Properties: map[string]propDef{
+					"server": {Type: "string", Description: "Remote server name from config (optional, runs locally if omitted)"},
+				},
+			},
+		},
+		{
+			Name:        "docker_updates",
+			Description: "Check which running containers use an image older than the one their tag points to in the registry",
+			InputSchema: inputSchema{
+				Type: "object",
+				Properties: map[string]propDef{
+					"server": {Type: "string", Description: "Remote server name from config (optional, runs locally if omitted)"},
+				},
+			},
+		},
 		{
 			Name:        "docker_restart",
 			Description: "Restart a Docker container by name",
@@ -394,6 +710,92 @@ func toolDefinitions() []toolDef {
 				Required: []string{"name"},
 			},
 		},
+		{
+			Name:        "docker_start",
+			Description: "Start a stopped Docker container by name",
+			InputSchema: inputSchema{
+				Type: "object",
+				Properties: map[string]propDef{
+					"name":   {Type: "string", Description: "Container name to start"},
+					"server": {Type: "string", Description: "Remote server name from config (optional, runs locally if omitted)"},
+				},
+				Required: []string{"name"},
+			},
+		},
+		{
+			Name:        "docker_pause",
+			Description: "Pause all processes in a Docker container",
+			InputSchema: inputSchema{
+				Type: "object",
+				Properties: map[string]propDef{
+					"name":   {Type: "string", Description: "Container name to pause"},
+					"server": {Type: "string", Description: "Remote server name from config (optional, runs locally if omitted)"},
+				},
+				Required: []string{"name"},
+			},
+		},
+		{
+			Name:        "docker_unpause",
+			Description: "Resume a paused Docker container",
+			InputSchema: inputSchema{
+				Type: "object",
+				Properties: map[string]propDef{
+					"name":   {Type: "string", Description: "Container name to unpause"},
+					"server": {Type: "string", Description: "Remote server name from config (optional, runs locally if omitted)"},
+				},
+				Required: []string{"name"},
+			},
+		},
+		{
+			Name:        "docker_kill",
+			Description: "Send a signal to a Docker container (default: SIGKILL)",
+			InputSchema: inputSchema{
+				Type: "object",
+				Properties: map[string]propDef{
+					"name":   {Type: "string", Description: "Container name to signal"},
+					"signal": {Type: "string", Description: "Signal name or number, e.g. SIGHUP, TERM, 9 (default: SIGKILL)"},
+					"server": {Type: "string", Description: "Remote server name from config (optional, runs locally if omitted)"},
+				},
+				Required: []string{"name"},
+			},
+		},
+		{
+			Name:        "docker_rm",
+			Description: "Remove a Docker container by name",
+			InputSchema: inputSchema{
+				Type: "object",
+				Properties: map[string]propDef{
+					"name":   {Type: "string", Description: "Container name to remove"},
+					"force":  {Type: "boolean", Description: "Stop and remove the container if it is running (default: false)"},
+					"server": {Type: "string", Description: "Remote server name from config (optional, runs locally if omitted)"},
+				},
+				Required: []string{"name"},
+			},
+		},
+		{
+			Name:        "docker_df",
+			Description: "Show disk space used by Docker images, containers, volumes and build cache, and how much is reclaimable",
+			InputSchema: inputSchema{
+				Type: "object",
+				Properties: map[string]propDef{
+					"server": {Type: "string", Description: "Remote server name from config (optional, runs locally if omitted)"},
+				},
+			},
+		},
+		{
+			Name:        "docker_prune",
+			Description: "Free disk space by removing stopped containers, dangling images and unused build cache. Lists what would be removed unless dry_run is false",
+			InputSchema: inputSchema{
+				Type: "object",
+				Properties: map[string]propDef{
+					"dry_run":    {Type: "boolean", Description: "Only list what would be removed (default: true)"},
+					"older_than": {Type: "string", Description: "Only remove things older than this, e.g. 7d or 12h"},
+					"all":        {Type: "boolean", Description: "Also remove unused tagged images and unused named volumes (default: false)"},
+					"volumes":    {Type: "boolean", Description: "Also remove unused volumes; anonymous ones only unless all is set (default: false)"},
+					"server":     {Type: "string", Description: "Remote server name from config (optional, runs locally if omitted)"},
+				},
+			},
+		},
 		{
 			Name:        "docker_logs",
 			Description: "Get logs from a Docker container",
@@ -401,12 +803,84 @@ func toolDefinitions() []toolDef {
 				Type: "object",
 				Properties: map[string]propDef{
 					"name":   {Type: "string", Description: "Container name to get logs from"},
-					"lines":  {Type: "string", Description: "Number of log lines to return (default: 50)"},
+					"lines":  {Type: "string", Description: "Number of log lines to return (default: 50, or all with since)"},
+					"since":  {Type: "string", Description: "Only lines newer than a duration (e.g. 10m, 2h) or RFC 3339 time"},
+					"grep":   {Type: "string", Description: "Only lines matching this regular expression"},
 					"server": {Type: "string", Description: "Remote server name from config (optional, runs locally if omitted)"},
 				},
 				Required: []string{"name"},
 			},
 		},
+		{
+			Name:        "compose_list",
+			Description: "List Docker Compose projects with their services and how many containers are running",
+			InputSchema: inputSchema{
+				Type: "object",
+				Properties: map[string]propDef{
+					"server": {Type: "string", Description: "Remote server name from config (optional, runs locally if omitted)"},
+				},
+			},
+		},
+		{
+			Name:        "compose_ps",
+			Description: "List the containers of a Docker Compose project",
+			InputSchema: inputSchema{
+				Type: "object",
+				Properties: map[string]propDef{
+					"project": {Type: "string", Description: "Compose project name"},
+					"server":  {Type: "string", Description: "Remote server name from config (optional, runs locally if omitted)"},
+				},
+				Required: []string{"project"},
+			},
+		},
+		{
+			Name:        "compose_restart",
+			Description: "Restart every container of a Docker Compose project",
+			InputSchema: inputSchema{
+				Type: "object",
+				Properties: map[string]propDef{
+					"project": {Type: "string", Description: "Compose project to restart"},
+					"server":  {Type: "string", Description: "Remote server name from config (optional, runs locally if omitted)"},
+				},
+				Required: []string{"project"},
+			},
+		},
+		{
+			Name:        "compose_up",
+			Description: "Bring up a Docker Compose project (docker compose up -d)",
+			InputSchema: inputSchema{
+				Type: "object",
+				Properties: map[string]propDef{
+					"project": {Type: "string", Description: "Compose project to start"},
+					"server":  {Type: "string", Description: "Remote server name from config (optional, runs locally if omitted)"},
+				},
+				Required: []string{"project"},
+			},
+		},
+		{
+			Name:        "compose_down",
+			Description: "Stop and remove the containers and networks of a Docker Compose project (volumes are kept)",
+			InputSchema: inputSchema{
+				Type: "object",
+				Properties: map[string]propDef{
+					"project": {Type: "string", Description: "Compose project to take down"},
+					"server":  {Type: "string", Description: "Remote server name from config (optional, runs locally if omitted)"},
+				},
+				Required: []string{"project"},
+			},
+		},
+		{
+			Name:        "compose_pull",
+			Description: "Pull newer images for a Docker Compose project (takes effect on the next compose_up)",
+			InputSchema: inputSchema{
+				Type: "object",
+				Properties: map[string]propDef{
+					"project": {Type: "string", Description: "Compose project to pull images for"},
+					"server":  {Type: "string", Description: "Remote server name from config (optional, runs locally if omitted)"},
+				},
+				Required: []string{"project"},
+			},
+		},
 		{
 			Name:        "wake",
 			Description: "Send a Wake-on-LAN magic packet to wake a machine",
@@ -438,7 +912,7 @@ func toolDefinitions() []toolDef {
 		},
 		{
 			Name:        "alerts",
-			Description: "Check resource alerts for CPU, memory, and disk usage against configured thresholds",
+			Description: "Check resource alerts for CPU, memory, disk usage and temperature against configured thresholds",
 			InputSchema: inputSchema{
 				Type: "object",
 				Properties: map[string]propDef{
@@ -446,5 +920,66 @@ func toolDefinitions() []toolDef {
 				},
 			},
 		},
+		{
+			Name:        "silence_list",
+			Description: "List active alert silences, including open maintenance windows",
+			InputSchema: inputSchema{
+				Type: "object",
+				Properties: map[string]propDef{
+					"server": {Type: "string", Description: "Remote server name from config (optional, runs locally if omitted)"},
+				},
+			},
+		},
+		{
+			Name:        "silence_create",
+			Description: "Silence alerts for a while, e.g. during maintenance. Silenced alerts are still reported but marked silenced, don't count toward the overall status and don't notify",
+			InputSchema: inputSchema{
+				Type: "object",
+				Properties: map[string]propDef{
+					"duration": {Type: "string", Description: "How long to silence, e.g. 30m, 4h or 2d"},
+					"metric":   {Type: "string", Description: "Comma-separated metrics: kinds like disk or cpu, or keys like disk:/mnt/data or container:restarts:plex (default: all)"},
+					"reason":   {Type: "string", Description: "Why the alerts are silenced"},
+					"server":   {Type: "string", Description: "Remote server name from config (optional, runs locally if omitted)"},
+				},
+				Required: []string{"duration"},
+			},
+		},
+		{
+			Name:        "silence_expire",
+			Description: "End an alert silence early",
+			InputSchema: inputSchema{
+				Type: "object",
+				Properties: map[string]propDef{
+					"id":     {Type: "string", Description: "Silence ID from silence_list"},
+					"server": {Type: "string", Description: "Remote server name from config (optional, runs locally if omitted)"},
+				},
+				Required: []string{"id"},
+			},
+		},
+		{
+			Name:        "alert_history",
+			Description: "Query the alerts recorded by every alert check: each warning or critical evaluation, with a count per server and metric. Answers questions like how often a disk went critical this week",
+			InputSchema: inputSchema{
+				Type: "object",
+				Properties: map[string]propDef{
+					"since":  {Type: "string", Description: "How far back, e.g. 24h or 7d (default: 24h)"},
+					"server": {Type: "string", Description: "Only this server (optional, default: all servers)"},
+					"metric": {Type: "string", Description: "Only this metric kind or key, e.g. disk or disk:/mnt/data (optional)"},
+				},
+			},
+		},
+		{
+			Name:        "metrics_history",
+			Description: "Get a metric over time from the history homebutler serve and alerts watch record: 1-minute samples for the last day, hourly averages for 30 days, with min, average, max and last. Answers questions like whether CPU was high overnight or how fast a disk fills",
+			InputSchema: inputSchema{
+				Type: "object",
+				Properties: map[string]propDef{
+					"metric": {Type: "string", Description: "cpu, memory, swap, load, disk (every mount), disk:<mount>, net_rx or net_tx"},
+					"since":  {Type: "string", Description: "How far back, e.g. 6h or 7d (default: 24h)"},
+					"server": {Type: "string", Description: "Server name from config (optional, default: this machine)"},
+				},
+				Required: []string{"metric"},
+			},
+		},
 	}
 }
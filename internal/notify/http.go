@@ -0,0 +1,171 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Higangssh/homebutler/internal/config"
+)
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// post sends body to u and fails on a non-2xx answer, quoting the start of
+// the response so misconfigured tokens are easy to spot.
+func post(ctx context.Context, u, contentType string, body []byte, header http.Header) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+func postJSON(ctx context.Context, u string, v any, header http.Header) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return post(ctx, u, "application/json", body, header)
+}
+
+// requireURL checks that an http(s) URL is set.
+func requireURL(raw string) error {
+	if raw == "" {
+		return fmt.Errorf("url is required")
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid url %q", raw)
+	}
+	return nil
+}
+
+// webhook POSTs the Message as JSON.
+type webhook struct {
+	name, url string
+}
+
+func newWebhook(name string, c config.NotifierConfig) (*webhook, error) {
+	if err := requireURL(c.URL); err != nil {
+		return nil, err
+	}
+	return &webhook{name: name, url: c.URL}, nil
+}
+
+func (w *webhook) Name() string { return w.name }
+
+func (w *webhook) Send(ctx context.Context, msg Message) error {
+	return postJSON(ctx, w.url, msg, nil)
+}
+
+// ntfy publishes to a topic; the title, priority and tags go in headers.
+type ntfy struct {
+	name, url, token string
+}
+
+func newNtfy(name string, c config.NotifierConfig) (*ntfy, error) {
+	server := c.URL
+	if server == "" {
+		server = "https://ntfy.sh"
+	}
+	if err := requireURL(server); err != nil {
+		return nil, err
+	}
+	if c.Topic == "" {
+		return nil, fmt.Errorf("topic is required")
+	}
+	return &ntfy{name: name, url: strings.TrimSuffix(server, "/") + "/" + url.PathEscape(c.Topic), token: c.Token}, nil
+}
+
+func (n *ntfy) Name() string { return n.name }
+
+func (n *ntfy) Send(ctx context.Context, msg Message) error {
+	priority, tag := "default", "white_check_mark"
+	switch msg.Status {
+	case "critical":
+		priority, tag = "urgent", "rotating_light"
+	case "warning":
+		priority, tag = "high", "warning"
+	case "test":
+		tag = "bell"
+	}
+	// ntfy turns tags into emoji; the title goes in a header, so no emoji there
+	header := http.Header{}
+	header.Set("Title", msg.Title)
+	header.Set("Priority", priority)
+	header.Set("Tags", tag)
+	if n.token != "" {
+		header.Set("Authorization", "Bearer "+n.token)
+	}
+	return post(ctx, n.url, "text/plain; charset=utf-8", []byte(msg.Body), header)
+}
+
+// gotify posts to an application's message endpoint.
+type gotify struct {
+	name, url, token string
+}
+
+func newGotify(name string, c config.NotifierConfig) (*gotify, error) {
+	if err := requireURL(c.URL); err != nil {
+		return nil, err
+	}
+	if c.Token == "" {
+		return nil, fmt.Errorf("token is required (a Gotify application token)")
+	}
+	return &gotify{name: name, url: strings.TrimSuffix(c.URL, "/") + "/message", token: c.Token}, nil
+}
+
+func (g *gotify) Name() string { return g.name }
+
+func (g *gotify) Send(ctx context.Context, msg Message) error {
+	priority := 2
+	switch msg.Status {
+	case "critical":
+		priority = 8
+	case "warning":
+		priority = 5
+	}
+	header := http.Header{}
+	header.Set("X-Gotify-Key", g.token)
+	return postJSON(ctx, g.url, map[string]any{"title": msg.heading(), "message": msg.Body, "priority": priority}, header)
+}
+
+// chat posts to a Discord or Slack incoming webhook.
+type chat struct {
+	name, kind, url string
+}
+
+func newChat(name string, c config.NotifierConfig) (*chat, error) {
+	if err := requireURL(c.URL); err != nil {
+		return nil, err
+	}
+	return &chat{name: name, kind: c.Type, url: c.URL}, nil
+}
+
+func (c *chat) Name() string { return c.name }
+
+func (c *chat) Send(ctx context.Context, msg Message) error {
+	if c.kind == "discord" {
+		return postJSON(ctx, c.url, map[string]string{"content": "**" + msg.heading() + "**\n" + msg.Body}, nil)
+	}
+	return postJSON(ctx, c.url, map[string]string{"text": "*" + msg.heading() + "*\n" + msg.Body}, nil)
+}
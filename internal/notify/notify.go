@@ -0,0 +1,145 @@
+// Package notify sends alert transitions to webhooks, push services and
+// email.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Higangssh/homebutler/internal/alerts"
+	"github.com/Higangssh/homebutler/internal/config"
+)
+
+// Message is one alert notification. Webhooks receive it as JSON; the
+// other notifiers render Title and Body.
+type Message struct {
+	Server    string    `json:"server"`
+	Metric    string    `json:"metric"` // alerts.Metric key, e.g. "disk:/mnt/data"
+	Status    string    `json:"status"` // "ok", "warning", "critical", or "test"
+	Previous  string    `json:"previous,omitempty"`
+	Title     string    `json:"title"`
+	Body      string    `json:"body"`
+	Current   float64   `json:"current"`
+	Threshold float64   `json:"threshold"`
+	Time      time.Time `json:"time"`
+}
+
+// Notifier delivers messages to one destination.
+type Notifier interface {
+	Name() string
+	Send(ctx context.Context, msg Message) error
+}
+
+// New builds the notifiers from the alerts.notify config.
+func New(cfgs []config.NotifierConfig) ([]Notifier, error) {
+	notifiers := make([]Notifier, 0, len(cfgs))
+	for i, c := range cfgs {
+		name := c.Name
+		if name == "" {
+			name = c.Type
+		}
+		var n Notifier
+		var err error
+		switch c.Type {
+		case "webhook":
+			n, err = newWebhook(name, c)
+		case "ntfy":
+			n, err = newNtfy(name, c)
+		case "gotify":
+			n, err = newGotify(name, c)
+		case "discord", "slack":
+			n, err = newChat(name, c)
+		case "smtp":
+			n, err = newSMTP(name, c)
+		default:
+			err = fmt.Errorf("unknown type %q (use webhook, ntfy, gotify, discord, slack or smtp)", c.Type)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("alerts.notify[%d] (%s): %w", i, name, err)
+		}
+		notifiers = append(notifiers, n)
+	}
+	return notifiers, nil
+}
+
+// Send delivers msg to every notifier. One failing destination doesn't
+// stop the others; their errors are joined.
+func Send(ctx context.Context, notifiers []Notifier, msg Message) error {
+	var errs []error
+	for _, n := range notifiers {
+		if err := n.Send(ctx, msg); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", n.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Result is the outcome of sending to one notifier.
+type Result struct {
+	Notifier string `json:"notifier"`
+	Status   string `json:"status"` // "sent" or "failed"
+	Error    string `json:"error,omitempty"`
+}
+
+// SendEach delivers msg to every notifier and reports each outcome, for
+// `alerts test-notify`.
+func SendEach(ctx context.Context, notifiers []Notifier, msg Message) []Result {
+	results := make([]Result, len(notifiers))
+	for i, n := range notifiers {
+		results[i] = Result{Notifier: n.Name(), Status: "sent"}
+		if err := n.Send(ctx, msg); err != nil {
+			results[i].Status, results[i].Error = "failed", err.Error()
+		}
+	}
+	return results
+}
+
+// FromTransition renders an alert transition:
+// "[nas] Disk /mnt/data critical" / "Disk /mnt/data at 97% (threshold 90%)".
+func FromTransition(server string, t alerts.Transition, now time.Time) Message {
+	title := fmt.Sprintf("[%s] %s %s", server, t.Label, t.Status)
+	body := t.Describe()
+	if t.Status == "ok" {
+		title = fmt.Sprintf("[%s] %s recovered", server, t.Label)
+		body = fmt.Sprintf("%s (was %s)", body, t.From)
+	}
+	return Message{
+		Server:    server,
+		Metric:    t.Key,
+		Status:    t.Status,
+		Previous:  t.From,
+		Title:     title,
+		Body:      body,
+		Current:   t.Current,
+		Threshold: t.Threshold,
+		Time:      now,
+	}
+}
+
+// Test is the message `alerts test-notify` sends.
+func Test(server string, now time.Time) Message {
+	return Message{
+		Server: server,
+		Status: "test",
+		Title:  fmt.Sprintf("[%s] homebutler test notification", server),
+		Body:   "If you can read this, alert notifications reach this destination.",
+		Time:   now,
+	}
+}
+
+// heading is the title with a status emoji, for destinations that show
+// the title as text.
+func (m Message) heading() string {
+	emoji := "✅"
+	switch m.Status {
+	case "critical":
+		emoji = "🔴"
+	case "warning":
+		emoji = "⚠️"
+	case "test":
+		emoji = "🔔"
+	}
+	return emoji + " " + m.Title
+}
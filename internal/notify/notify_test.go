@@ -0,0 +1,257 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Higangssh/homebutler/internal/alerts"
+	"github.com/Higangssh/homebutler/internal/config"
+)
+
+var testTime = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func criticalMessage() Message {
+	return FromTransition("nas", alerts.Transition{
+		Metric: alerts.Metric{Key: "disk:/mnt/data", Label: "Disk /mnt/data", Status: "critical", Current: 97, Threshold: 90, Unit: "%"},
+		From:   "warning",
+	}, testTime)
+}
+
+// capture records the last request a notifier made.
+type capture struct {
+	path   string
+	header http.Header
+	body   []byte
+}
+
+func captureServer(t *testing.T, status int) (*httptest.Server, *capture) {
+	t.Helper()
+	got := &capture{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got.path, got.header = r.URL.Path, r.Header
+		got.body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+		if status >= 400 {
+			fmt.Fprint(w, "invalid token")
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, got
+}
+
+func newOne(t *testing.T, c config.NotifierConfig) Notifier {
+	t.Helper()
+	ns, err := New([]config.NotifierConfig{c})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ns[0]
+}
+
+func TestFromTransition(t *testing.T) {
+	msg := criticalMessage()
+	if msg.Title != "[nas] Disk /mnt/data critical" || msg.Body != "Disk /mnt/data at 97% (threshold 90%)" {
+		t.Errorf("unexpected message: %q / %q", msg.Title, msg.Body)
+	}
+	if msg.Metric != "disk:/mnt/data" || msg.Previous != "warning" {
+		t.Errorf("unexpected metadata: %+v", msg)
+	}
+
+	ok := FromTransition("nas", alerts.Transition{
+		Metric: alerts.Metric{Key: "cpu", Label: "CPU", Status: "ok", Current: 20, Threshold: 90, Unit: "%"},
+		From:   "critical",
+	}, testTime)
+	if ok.Title != "[nas] CPU recovered" || ok.Body != "CPU at 20% (threshold 90%) (was critical)" {
+		t.Errorf("unexpected recovery: %q / %q", ok.Title, ok.Body)
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	srv, got := captureServer(t, http.StatusOK)
+	n := newOne(t, config.NotifierConfig{Type: "webhook", URL: srv.URL + "/hook"})
+	if err := n.Send(context.Background(), criticalMessage()); err != nil {
+		t.Fatal(err)
+	}
+	var msg Message
+	if err := json.Unmarshal(got.body, &msg); err != nil {
+		t.Fatalf("webhook body is not a Message: %v", err)
+	}
+	if got.path != "/hook" || msg.Server != "nas" || msg.Status != "critical" || msg.Current != 97 {
+		t.Errorf("unexpected webhook: %s %+v", got.path, msg)
+	}
+}
+
+func TestNtfy(t *testing.T) {
+	srv, got := captureServer(t, http.StatusOK)
+	n := newOne(t, config.NotifierConfig{Type: "ntfy", URL: srv.URL, Topic: "homelab", Token: "tk_123"})
+	if err := n.Send(context.Background(), criticalMessage()); err != nil {
+		t.Fatal(err)
+	}
+	if got.path != "/homelab" || string(got.body) != "Disk /mnt/data at 97% (threshold 90%)" {
+		t.Errorf("unexpected ntfy request: %s %q", got.path, got.body)
+	}
+	h := got.header
+	if h.Get("Title") != "[nas] Disk /mnt/data critical" || h.Get("Priority") != "urgent" || h.Get("Tags") != "rotating_light" || h.Get("Authorization") != "Bearer tk_123" {
+		t.Errorf("unexpected ntfy headers: %v", h)
+	}
+}
+
+func TestGotify(t *testing.T) {
+	srv, got := captureServer(t, http.StatusOK)
+	n := newOne(t, config.NotifierConfig{Type: "gotify", URL: srv.URL + "/", Token: "AbC"})
+	if err := n.Send(context.Background(), criticalMessage()); err != nil {
+		t.Fatal(err)
+	}
+	var body struct {
+		Title    string `json:"title"`
+		Message  string `json:"message"`
+		Priority int    `json:"priority"`
+	}
+	json.Unmarshal(got.body, &body)
+	if got.path != "/message" || got.header.Get("X-Gotify-Key") != "AbC" || body.Priority != 8 || body.Title != "🔴 [nas] Disk /mnt/data critical" {
+		t.Errorf("unexpected gotify request: %s %v %+v", got.path, got.header, body)
+	}
+}
+
+func TestChatWebhooks(t *testing.T) {
+	for _, tt := range []struct{ kind, field, prefix string }{
+		{"discord", "content", "**🔴 [nas]"},
+		{"slack", "text", "*🔴 [nas]"},
+	} {
+		srv, got := captureServer(t, http.StatusNoContent)
+		n := newOne(t, config.NotifierConfig{Type: tt.kind, URL: srv.URL})
+		if err := n.Send(context.Background(), criticalMessage()); err != nil {
+			t.Fatalf("%s: %v", tt.kind, err)
+		}
+		var body map[string]string
+		json.Unmarshal(got.body, &body)
+		if !strings.HasPrefix(body[tt.field], tt.prefix) || !strings.Contains(body[tt.field], "at 97%") {
+			t.Errorf("%s: unexpected body %s", tt.kind, got.body)
+		}
+	}
+}
+
+func TestSendReportsFailures(t *testing.T) {
+	bad, _ := captureServer(t, http.StatusUnauthorized)
+	good, got := captureServer(t, http.StatusOK)
+	ns, err := New([]config.NotifierConfig{
+		{Name: "phone", Type: "ntfy", URL: bad.URL, Topic: "x"},
+		{Type: "webhook", URL: good.URL},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = Send(context.Background(), ns, Test("nas", testTime))
+	if err == nil || !strings.Contains(err.Error(), "phone: HTTP 401: invalid token") {
+		t.Errorf("expected the ntfy failure, got %v", err)
+	}
+	if got.body == nil {
+		t.Error("a failing notifier should not stop the others")
+	}
+
+	results := SendEach(context.Background(), ns, Test("nas", testTime))
+	if len(results) != 2 || results[0].Status != "failed" || results[0].Notifier != "phone" || results[1].Status != "sent" || results[1].Notifier != "webhook" {
+		t.Errorf("unexpected results: %+v", results)
+	}
+}
+
+func TestNewValidates(t *testing.T) {
+	tests := []config.NotifierConfig{
+		{Type: "pager"},
+		{Type: "webhook"},
+		{Type: "webhook", URL: "ftp://example.com"},
+		{Type: "ntfy"},
+		{Type: "gotify", URL: "http://gotify.lan"},
+		{Type: "smtp", Host: "mail.lan"},
+	}
+	for _, c := range tests {
+		if _, err := New([]config.NotifierConfig{c}); err == nil {
+			t.Errorf("expected error for %+v", c)
+		}
+	}
+}
+
+// fakeSMTP accepts one message and hands back the DATA section.
+func fakeSMTP(t *testing.T) (host string, port int, data <-chan string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	out := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(s string) { fmt.Fprintf(conn, "%s\r\n", s) }
+		reply("220 fake ESMTP")
+		var body strings.Builder
+		inData := false
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if inData {
+				if line == ".\r\n" {
+					inData = false
+					out <- body.String()
+					reply("250 queued")
+					continue
+				}
+				body.WriteString(line)
+				continue
+			}
+			switch cmd := strings.ToUpper(strings.Fields(line)[0]); cmd {
+			case "EHLO":
+				reply("250-fake\r\n250 AUTH PLAIN")
+			case "AUTH":
+				reply("235 ok")
+			case "DATA":
+				inData = true
+				reply("354 go ahead")
+			case "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+	}()
+	addr := ln.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port, out
+}
+
+func TestSMTP(t *testing.T) {
+	host, port, data := fakeSMTP(t)
+	n := newOne(t, config.NotifierConfig{
+		Type: "smtp", Host: host, Port: port, Username: "alerts", Password: "secret",
+		From: "homebutler@lan", To: []string{"me@example.com", "ops@example.com"},
+	})
+	if err := n.Send(context.Background(), criticalMessage()); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case email := <-data:
+		for _, want := range []string{"To: me@example.com, ops@example.com", "Subject: =?utf-8?q?", "Disk /mnt/data at 97% (threshold 90%)"} {
+			if !strings.Contains(email, want) {
+				t.Errorf("expected %q in email:\n%s", want, email)
+			}
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no email received")
+	}
+}
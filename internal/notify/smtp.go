@@ -0,0 +1,122 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Higangssh/homebutler/internal/config"
+)
+
+// smtpNotifier sends plain-text email. Port 465 uses implicit TLS; other
+// ports upgrade with STARTTLS when the server offers it.
+type smtpNotifier struct {
+	name               string
+	host               string
+	port               int
+	username, password string
+	from               string
+	to                 []string
+}
+
+func newSMTP(name string, c config.NotifierConfig) (*smtpNotifier, error) {
+	if c.Host == "" {
+		return nil, fmt.Errorf("host is required")
+	}
+	if c.From == "" || len(c.To) == 0 {
+		return nil, fmt.Errorf("from and to are required")
+	}
+	port := c.Port
+	if port == 0 {
+		port = 587
+	}
+	return &smtpNotifier{
+		name:     name,
+		host:     c.Host,
+		port:     port,
+		username: c.Username,
+		password: c.Password,
+		from:     c.From,
+		to:       c.To,
+	}, nil
+}
+
+func (s *smtpNotifier) Name() string { return s.name }
+
+func (s *smtpNotifier) Send(ctx context.Context, msg Message) error {
+	addr := net.JoinHostPort(s.host, strconv.Itoa(s.port))
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	var conn net.Conn
+	var err error
+	if s.port == 465 {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: s.host}}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(30 * time.Second))
+
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if s.port != 465 {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+				return fmt.Errorf("STARTTLS: %w", err)
+			}
+		}
+	}
+	if s.username != "" {
+		// PlainAuth refuses to send the password unencrypted, except to localhost
+		if err := client.Auth(smtp.PlainAuth("", s.username, s.password, s.host)); err != nil {
+			return fmt.Errorf("auth: %w", err)
+		}
+	}
+	if err := client.Mail(s.from); err != nil {
+		return err
+	}
+	for _, rcpt := range s.to {
+		if err := client.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("recipient %s: %w", rcpt, err)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(s.email(msg)); err != nil {
+		w.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// email renders the message with its headers.
+func (s *smtpNotifier) email(msg Message) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", s.from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(s.to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.heading()))
+	fmt.Fprintf(&b, "Date: %s\r\n", msg.Time.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
@@ -0,0 +1,334 @@
+package remote
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/Higangssh/homebutler/internal/config"
+	"github.com/charmbracelet/x/term"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+var authModeNames = []string{"key", "agent", "password", "keyboard-interactive"}
+
+// encryptedKeysOnly refuses key files without a passphrase
+// (ssh.encrypted_keys_only).
+var encryptedKeysOnly bool
+
+// SetEncryptedKeysOnly sets the key policy from the config.
+func SetEncryptedKeysOnly(on bool) {
+	encryptedKeysOnly = on
+}
+
+// prompt asks the user for a secret, or an answer when echo is set. It is
+// nil when nobody can be asked.
+var (
+	promptMu sync.Mutex
+	prompt   = terminalPrompt
+)
+
+// DisablePrompts makes auth that needs the user fail instead of asking,
+// for while the TUI owns the terminal.
+func DisablePrompts() {
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	prompt = nil
+}
+
+var errNoTerminal = errors.New("no terminal to ask on")
+
+// stdin reads echoed answers. It is shared, so what one prompt's reader
+// buffered past its line isn't lost to the next; ask serializes its use.
+var stdin = bufio.NewReader(os.Stdin)
+
+func terminalPrompt(question string, echo bool) (string, error) {
+	if !term.IsTerminal(os.Stdin.Fd()) {
+		return "", errNoTerminal
+	}
+	fmt.Fprint(os.Stderr, question)
+	if echo {
+		line, err := stdin.ReadString('\n')
+		return strings.TrimRight(line, "\r\n"), err
+	}
+	secret, err := term.ReadPassword(os.Stdin.Fd())
+	fmt.Fprintln(os.Stderr)
+	return string(secret), err
+}
+
+// ask prompts one question at a time, so servers dialed in parallel don't
+// mix up their questions.
+func ask(question string, echo bool) (string, error) {
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	if prompt == nil {
+		return "", errNoTerminal
+	}
+	return prompt(question, echo)
+}
+
+// authMethods builds the server's auth modes. Keys and agent keys go into
+// one publickey method, as the SSH client tries each method only once.
+// done releases the agent connection once the dial is over.
+func authMethods(server *config.ServerConfig) (methods []ssh.AuthMethod, done func(), err error) {
+	var signers []ssh.Signer
+	var closers []io.Closer
+	done = func() {
+		for _, c := range closers {
+			c.Close()
+		}
+	}
+	for _, mode := range server.AuthModes() {
+		if !slices.Contains(authModeNames, mode) {
+			return nil, nil, fmt.Errorf("[%s] unknown auth mode %q\n  → Use %s, or several separated by commas", server.Name, mode, strings.Join(authModeNames, ", "))
+		}
+	}
+	publicKey := -1
+	addPublicKey := func() {
+		if publicKey < 0 {
+			publicKey = len(methods)
+			methods = append(methods, nil)
+		}
+	}
+	for _, mode := range server.AuthModes() {
+		switch mode {
+		case "key":
+			keys, err := keySigners(server)
+			if err != nil {
+				done()
+				return nil, nil, fmt.Errorf("[%s] failed to load SSH key (%s): %w\n  → Check the key path in your config: ~/.config/homebutler/config.yaml", server.Name, keyLabel(server.KeyFile), err)
+			}
+			signers = append(signers, keys...)
+			addPublicKey()
+		case "agent":
+			conn, err := dialAgent()
+			if err != nil {
+				done()
+				return nil, nil, fmt.Errorf("[%s] cannot reach the SSH agent: %w\n  → Start ssh-agent and add your key: ssh-add", server.Name, err)
+			}
+			closers = append(closers, conn)
+			keys, err := agent.NewClient(conn).Signers()
+			if err != nil {
+				done()
+				return nil, nil, fmt.Errorf("[%s] failed to list SSH agent keys: %w", server.Name, err)
+			}
+			signers = append(signers, keys...)
+			addPublicKey()
+		case "password":
+			if server.Password == "" {
+				done()
+				return nil, nil, fmt.Errorf("[%s] no SSH credentials configured\n  → Add 'key' or 'password' to this server in ~/.config/homebutler/config.yaml", server.Name)
+			}
+			methods = append(methods, ssh.Password(server.Password))
+		case "keyboard-interactive":
+			methods = append(methods, ssh.KeyboardInteractive(challenge(server)))
+		}
+	}
+	if publicKey >= 0 {
+		methods[publicKey] = ssh.PublicKeys(signers...)
+	}
+	return methods, done, nil
+}
+
+func dialAgent() (net.Conn, error) {
+	sock := os.Getenv("SSH_AUTH_SOCK")
+	if sock == "" {
+		return nil, errors.New("SSH_AUTH_SOCK is not set")
+	}
+	return net.Dial("unix", sock)
+}
+
+// challenge answers keyboard-interactive prompts: a password prompt with
+// the configured password if there is one, anything else (like a one-time
+// code) by asking the user.
+func challenge(server *config.ServerConfig) ssh.KeyboardInteractiveChallenge {
+	return func(name, instruction string, questions []string, echos []bool) ([]string, error) {
+		answers := make([]string, len(questions))
+		for i, q := range questions {
+			if !echos[i] && server.Password != "" && strings.Contains(strings.ToLower(q), "password") {
+				answers[i] = server.Password
+				continue
+			}
+			if i == 0 && instruction != "" {
+				q = instruction + "\n" + q
+			}
+			answer, err := ask(fmt.Sprintf("[%s] %s", server.Name, q), echos[i])
+			if err != nil {
+				return nil, fmt.Errorf("keyboard-interactive: %w", err)
+			}
+			answers[i] = answer
+		}
+		return answers, nil
+	}
+}
+
+// keys caches unlocked keys by path, so a passphrase is asked once per
+// process however often the server is redialed.
+var (
+	keysMu sync.Mutex
+	keys   = map[string]ssh.Signer{}
+)
+
+// keySigners loads the server's key, unlocking it if it is encrypted. A
+// certificate for it comes first, as OpenSSH offers it.
+func keySigners(server *config.ServerConfig) ([]ssh.Signer, error) {
+	path, err := keyPath(server.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := loadKey(server, path)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := loadCert(server.Cert, path, signer)
+	if err != nil {
+		return nil, err
+	}
+	if cert != nil {
+		return []ssh.Signer{cert, signer}, nil
+	}
+	return []ssh.Signer{signer}, nil
+}
+
+// keyPath expands the configured key, or finds a default one.
+func keyPath(keyFile string) (string, error) {
+	if keyFile != "" {
+		return expandHome(keyFile), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot determine home directory: %w", err)
+	}
+	for _, name := range []string{"id_ed25519", "id_rsa"} {
+		path := filepath.Join(home, ".ssh", name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("no SSH key found (tried ~/.ssh/id_ed25519, ~/.ssh/id_rsa). Specify key path in config or use auth: agent")
+}
+
+func keyLabel(keyFile string) string {
+	if keyFile == "" {
+		return "default"
+	}
+	return keyFile
+}
+
+func expandHome(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		home, _ := os.UserHomeDir()
+		return filepath.Join(home, path[2:])
+	}
+	return path
+}
+
+func loadKey(server *config.ServerConfig, path string) (ssh.Signer, error) {
+	keysMu.Lock()
+	defer keysMu.Unlock()
+	if signer, ok := keys[path]; ok {
+		return signer, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := ssh.ParsePrivateKey(data)
+	var missing *ssh.PassphraseMissingError
+	switch {
+	case errors.As(err, &missing):
+		if signer, err = unlockKey(server, path, data); err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, err
+	case encryptedKeysOnly:
+		return nil, fmt.Errorf("%s has no passphrase, which ssh.encrypted_keys_only forbids\n  → Add one: ssh-keygen -p -f %s, or use auth: agent", path, path)
+	}
+	keys[path] = signer
+	return signer, nil
+}
+
+// unlockKey decrypts a key with the passphrase from passphrase_env or
+// passphrase_cmd, else asks for it up to three times.
+func unlockKey(server *config.ServerConfig, path string, data []byte) (ssh.Signer, error) {
+	if server.PassphraseEnv != "" || server.PassphraseCmd != "" {
+		passphrase, err := configuredPassphrase(server)
+		if err != nil {
+			return nil, err
+		}
+		signer, err := ssh.ParsePrivateKeyWithPassphrase(data, []byte(passphrase))
+		if err != nil {
+			return nil, fmt.Errorf("cannot unlock %s: %w", path, err)
+		}
+		return signer, nil
+	}
+	var err error
+	for range 3 {
+		var passphrase string
+		passphrase, err = ask(fmt.Sprintf("Enter passphrase for %s: ", path), false)
+		if err != nil {
+			return nil, fmt.Errorf("%s is encrypted and %w\n  → Set passphrase_env or passphrase_cmd for this server, or use auth: agent", path, err)
+		}
+		var signer ssh.Signer
+		if signer, err = ssh.ParsePrivateKeyWithPassphrase(data, []byte(passphrase)); err == nil {
+			return signer, nil
+		}
+	}
+	return nil, fmt.Errorf("cannot unlock %s: %w", path, err)
+}
+
+func configuredPassphrase(server *config.ServerConfig) (string, error) {
+	if server.PassphraseEnv != "" {
+		passphrase, ok := os.LookupEnv(server.PassphraseEnv)
+		if !ok {
+			return "", fmt.Errorf("passphrase_env: %s is not set", server.PassphraseEnv)
+		}
+		return passphrase, nil
+	}
+	out, err := exec.Command("sh", "-c", server.PassphraseCmd).Output()
+	if err != nil {
+		return "", fmt.Errorf("passphrase_cmd failed: %w", err)
+	}
+	// like a password manager's output, only the first line counts
+	line, _, _ := strings.Cut(string(out), "\n")
+	return strings.TrimSuffix(line, "\r"), nil
+}
+
+// loadCert returns a signer for the certificate of the key: the configured
+// one, or <key>-cert.pub when it exists. It is nil without either.
+func loadCert(certFile, keyPath string, signer ssh.Signer) (ssh.Signer, error) {
+	path := expandHome(certFile)
+	if certFile == "" {
+		path = keyPath + "-cert.pub"
+	}
+	data, err := os.ReadFile(path)
+	if certFile == "" && errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read certificate: %w", err)
+	}
+	pub, _, _, _, err := ssh.ParseAuthorizedKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate %s: %w", path, err)
+	}
+	cert, ok := pub.(*ssh.Certificate)
+	if !ok {
+		return nil, fmt.Errorf("%s is a public key, not a certificate", path)
+	}
+	certSigner, err := ssh.NewCertSigner(cert, signer)
+	if err != nil {
+		return nil, fmt.Errorf("certificate %s: %w", path, err)
+	}
+	return certSigner, nil
+}
@@ -0,0 +1,255 @@
+package remote
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Higangssh/homebutler/internal/config"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// isolateAuth gives a test its own key cache, prompt and policy.
+func isolateAuth(t *testing.T, ask func(question string, echo bool) (string, error)) {
+	t.Helper()
+	savedKeys, savedPrompt, savedPolicy := keys, prompt, encryptedKeysOnly
+	keys, prompt = map[string]ssh.Signer{}, ask
+	t.Cleanup(func() { keys, prompt, encryptedKeysOnly = savedKeys, savedPrompt, savedPolicy })
+}
+
+// writeKey writes a new key, encrypted when passphrase isn't empty.
+func writeKey(t *testing.T, passphrase string) (string, ssh.Signer) {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var block *pem.Block
+	if passphrase == "" {
+		block, err = ssh.MarshalPrivateKey(key, "")
+	} else {
+		block, err = ssh.MarshalPrivateKeyWithPassphrase(key, "", []byte(passphrase))
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "id_ed25519")
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0600); err != nil {
+		t.Fatal(err)
+	}
+	signer, _ := ssh.NewSignerFromKey(key)
+	return path, signer
+}
+
+func samePublicKey(a, b ssh.PublicKey) bool {
+	return bytes.Equal(a.Marshal(), b.Marshal())
+}
+
+func TestEncryptedKeyFromEnv(t *testing.T) {
+	isolateAuth(t, nil)
+	path, want := writeKey(t, "hunter2")
+	t.Setenv("NAS_PASSPHRASE", "hunter2")
+
+	signers, err := keySigners(&config.ServerConfig{KeyFile: path, PassphraseEnv: "NAS_PASSPHRASE"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(signers) != 1 || !samePublicKey(signers[0].PublicKey(), want.PublicKey()) {
+		t.Errorf("unexpected signers: %v", signers)
+	}
+
+	_, err = keySigners(&config.ServerConfig{KeyFile: path, PassphraseCmd: "echo wrong"})
+	if err != nil {
+		t.Errorf("an unlocked key should be reused, got %v", err)
+	}
+	keys = map[string]ssh.Signer{}
+	if _, err = keySigners(&config.ServerConfig{KeyFile: path, PassphraseCmd: "echo wrong"}); err == nil {
+		t.Error("expected the wrong passphrase to fail")
+	}
+	if _, err = keySigners(&config.ServerConfig{KeyFile: path, PassphraseCmd: "printf 'hunter2\\nuser: me'"}); err != nil {
+		t.Errorf("passphrase_cmd: %v", err)
+	}
+}
+
+func TestEncryptedKeyPrompt(t *testing.T) {
+	var asked []string
+	isolateAuth(t, func(question string, echo bool) (string, error) {
+		asked = append(asked, question)
+		if len(asked) == 1 {
+			return "typo", nil
+		}
+		return "hunter2", nil
+	})
+	path, _ := writeKey(t, "hunter2")
+	server := &config.ServerConfig{KeyFile: path}
+
+	for range 2 {
+		if _, err := keySigners(server); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(asked) != 2 || !strings.Contains(asked[0], path) {
+		t.Errorf("expected one retry and no prompt once unlocked, got %q", asked)
+	}
+
+	keys = map[string]ssh.Signer{}
+	DisablePrompts()
+	_, err := keySigners(server)
+	if err == nil || !strings.Contains(err.Error(), "passphrase_env") {
+		t.Errorf("expected a hint when there is no terminal, got %v", err)
+	}
+}
+
+func TestEncryptedKeysOnly(t *testing.T) {
+	isolateAuth(t, nil)
+	plain, _ := writeKey(t, "")
+	SetEncryptedKeysOnly(true)
+
+	_, err := keySigners(&config.ServerConfig{KeyFile: plain})
+	if err == nil || !strings.Contains(err.Error(), "encrypted_keys_only") {
+		t.Errorf("expected the unencrypted key to be refused, got %v", err)
+	}
+	encrypted, _ := writeKey(t, "hunter2")
+	t.Setenv("NAS_PASSPHRASE", "hunter2")
+	if _, err := keySigners(&config.ServerConfig{KeyFile: encrypted, PassphraseEnv: "NAS_PASSPHRASE"}); err != nil {
+		t.Errorf("encrypted key: %v", err)
+	}
+}
+
+func TestUnknownAuthMode(t *testing.T) {
+	_, _, err := authMethods(&config.ServerConfig{Name: "nas", AuthMode: "key,totp"})
+	if err == nil || !strings.Contains(err.Error(), `unknown auth mode "totp"`) {
+		t.Errorf("expected an unknown mode error, got %v", err)
+	}
+}
+
+// testTarget points server at a test server, in a throwaway home where its
+// host key gets trusted on first use.
+func testTarget(t *testing.T, s *sshServer, server config.ServerConfig) *config.ServerConfig {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	host, port, _ := net.SplitHostPort(s.addr)
+	server.Name, server.Host, server.User = "nas", host, "homelab"
+	server.Port, _ = strconv.Atoi(port)
+	return &server
+}
+
+// dialTest connects to a test server the way Run does.
+func dialTest(t *testing.T, s *sshServer, server config.ServerConfig) (*ssh.Client, error) {
+	t.Helper()
+	return connect(testTarget(t, s, server))
+}
+
+func TestCertificateAndOneTimeCode(t *testing.T) {
+	var asked []string
+	isolateAuth(t, func(question string, echo bool) (string, error) {
+		asked = append(asked, question)
+		return "123456", nil
+	})
+
+	// a CA signs the user key; the host wants the certificate, then a code
+	_, caKey, _ := ed25519.GenerateKey(rand.Reader)
+	ca, _ := ssh.NewSignerFromKey(caKey)
+	path, signer := writeKey(t, "")
+	cert := &ssh.Certificate{
+		Key:             signer.PublicKey(),
+		CertType:        ssh.UserCert,
+		ValidPrincipals: []string{"homelab"},
+		ValidBefore:     ssh.CertTimeInfinity,
+	}
+	if err := cert.SignCert(rand.Reader, ca); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path+"-cert.pub", ssh.MarshalAuthorizedKey(cert), 0644); err != nil {
+		t.Fatal(err)
+	}
+	checker := &ssh.CertChecker{IsUserAuthority: func(auth ssh.PublicKey) bool {
+		return samePublicKey(auth, ca.PublicKey())
+	}}
+	code := ssh.ServerAuthCallbacks{
+		KeyboardInteractiveCallback: func(conn ssh.ConnMetadata, client ssh.KeyboardInteractiveChallenge) (*ssh.Permissions, error) {
+			answers, err := client("", "", []string{"Verification code: "}, []bool{false})
+			if err != nil || len(answers) != 1 || answers[0] != "123456" {
+				return nil, errors.New("wrong code")
+			}
+			return nil, nil
+		},
+	}
+	s := newSSHServer(t, &ssh.ServerConfig{
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if _, err := checker.Authenticate(conn, key); err != nil {
+				return nil, err
+			}
+			return nil, &ssh.PartialSuccessError{Next: code}
+		},
+	})
+
+	client, err := dialTest(t, s, config.ServerConfig{KeyFile: path, AuthMode: "key,keyboard-interactive"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.Close()
+	if len(asked) != 1 || asked[0] != "[nas] Verification code: " {
+		t.Errorf("unexpected prompts: %q", asked)
+	}
+
+	// without the code the host lets nobody in
+	if client, err := dialTest(t, s, config.ServerConfig{KeyFile: path}); err == nil {
+		client.Close()
+		t.Error("expected the key alone to be refused")
+	}
+}
+
+func TestAgentAuth(t *testing.T) {
+	isolateAuth(t, nil)
+	_, key, _ := ed25519.GenerateKey(rand.Reader)
+	keyring := agent.NewKeyring()
+	if err := keyring.Add(agent.AddedKey{PrivateKey: key}); err != nil {
+		t.Fatal(err)
+	}
+	sock := filepath.Join(t.TempDir(), "agent.sock")
+	ln, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go agent.ServeAgent(keyring, conn)
+		}
+	}()
+	t.Setenv("SSH_AUTH_SOCK", sock)
+
+	signer, _ := ssh.NewSignerFromKey(key)
+	s := newSSHServer(t, &ssh.ServerConfig{
+		PublicKeyCallback: func(conn ssh.ConnMetadata, k ssh.PublicKey) (*ssh.Permissions, error) {
+			if !samePublicKey(k, signer.PublicKey()) {
+				return nil, errors.New("unknown key")
+			}
+			return nil, nil
+		},
+	})
+	client, err := dialTest(t, s, config.ServerConfig{AuthMode: "agent"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.Close()
+
+	t.Setenv("SSH_AUTH_SOCK", "")
+	if _, err := dialTest(t, s, config.ServerConfig{AuthMode: "agent"}); err == nil || !strings.Contains(err.Error(), "SSH_AUTH_SOCK") {
+		t.Errorf("expected a missing agent error, got %v", err)
+	}
+}
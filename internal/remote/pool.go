@@ -0,0 +1,264 @@
+package remote
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Higangssh/homebutler/internal/config"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// idleTimeout is how long a pooled connection stays open unused.
+	idleTimeout = 5 * time.Minute
+	// keepaliveInterval is how often a pooled connection is probed; one that
+	// doesn't answer within dialTimeout is closed.
+	keepaliveInterval = 15 * time.Second
+	// maxSessions caps the sessions open at once on one connection; OpenSSH
+	// refuses more than 10 by default.
+	maxSessions = 8
+)
+
+// pool is shared by everything in the process that runs remote commands:
+// the TUI, the web and MCP servers and the --all fan-out.
+var pool = newPool(idleTimeout, connect)
+
+// Close closes every pooled connection. Commands run after it dial again.
+func Close() {
+	pool.close()
+}
+
+// Connect dials the pooled connections of the remote servers ahead of use,
+// so passphrases and one-time codes are asked for before the TUI takes the
+// terminal. A server that fails is left for its first command to report.
+func Connect(servers []config.ServerConfig) {
+	var wg sync.WaitGroup
+	for i := range servers {
+		if servers[i].Local {
+			continue
+		}
+		wg.Go(func() {
+			c := pool.conn(&servers[i])
+			if client, err := pool.acquire(c, &servers[i]); err == nil {
+				c.release(client, pool.idle, false)
+			}
+		})
+	}
+	wg.Wait()
+}
+
+// connPool keeps one authenticated connection per server and opens a
+// session on it for each command, instead of a handshake per command.
+// A connection that turns out dead is redialed; one left unused for idle
+// is closed.
+type connPool struct {
+	idle      time.Duration
+	keepalive time.Duration
+	timeout   time.Duration // for a session slot, a session and a keepalive reply
+	dial      func(*config.ServerConfig) (*ssh.Client, error)
+	mu        sync.Mutex
+	conns     map[string]*pooledConn
+}
+
+type pooledConn struct {
+	sessions chan struct{} // one token per open session
+
+	mu     sync.Mutex // held while dialing, so concurrent callers share one dial
+	client *ssh.Client
+	users  int
+	timer  *time.Timer
+}
+
+func newPool(idle time.Duration, dial func(*config.ServerConfig) (*ssh.Client, error)) *connPool {
+	return &connPool{idle: idle, keepalive: keepaliveInterval, timeout: dialTimeout, dial: dial, conns: map[string]*pooledConn{}}
+}
+
+// poolKey tells servers apart by everything a connection depends on, so an
+// edited config entry gets a fresh connection.
+func poolKey(server *config.ServerConfig) string {
+	return server.Name + "\x00" + server.SSHUser() + "@" + server.Host + ":" + strconv.Itoa(server.SSHPort()) +
+		"\x00" + server.AuthMode + "\x00" + server.KeyFile + "\x00" + server.Cert +
+		"\x00" + server.PassphraseEnv + "\x00" + server.PassphraseCmd + "\x00" + server.Password
+}
+
+func (p *connPool) conn(server *config.ServerConfig) *pooledConn {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	key := poolKey(server)
+	c, ok := p.conns[key]
+	if !ok {
+		c = &pooledConn{sessions: make(chan struct{}, maxSessions)}
+		p.conns[key] = c
+	}
+	return c
+}
+
+// session opens a session on the server's connection, dialing it first if
+// needed. done closes the session and must be called once it has ended.
+func (p *connPool) session(server *config.ServerConfig) (session *ssh.Session, done func(), err error) {
+	c := p.conn(server)
+	select {
+	case c.sessions <- struct{}{}:
+	case <-time.After(p.timeout):
+		return nil, nil, fmt.Errorf("[%s] all %d SSH sessions busy for %s\n  → Too many commands at once on this server; try again shortly", server.Name, maxSessions, p.timeout)
+	}
+	for retried := false; ; retried = true {
+		client, err := p.acquire(c, server)
+		if err != nil {
+			<-c.sessions
+			return nil, nil, err // connect() already returns detailed error messages
+		}
+		session, err := newSession(client, p.timeout)
+		if err == nil {
+			return session, func() {
+				session.Close()
+				<-c.sessions
+				c.release(client, p.idle, false)
+			}, nil
+		}
+		// The server refusing a channel says nothing about the connection;
+		// anything else, a timeout too, means it died since its last use.
+		var openErr *ssh.OpenChannelError
+		dead := !errors.As(err, &openErr)
+		c.release(client, p.idle, dead)
+		if !dead || retried {
+			<-c.sessions
+			return nil, nil, fmt.Errorf("[%s] failed to open SSH session: %w\n  → Check if the server is accepting new connections", server.Name, err)
+		}
+	}
+}
+
+// newSession opens a session, giving up after timeout: on a server that
+// stopped answering it would wait for good.
+func newSession(client *ssh.Client, timeout time.Duration) (*ssh.Session, error) {
+	type result struct {
+		session *ssh.Session
+		err     error
+	}
+	opened := make(chan result, 1)
+	go func() {
+		session, err := client.NewSession()
+		opened <- result{session, err}
+	}()
+	select {
+	case r := <-opened:
+		return r.session, r.err
+	case <-time.After(timeout):
+		go func() {
+			if r := <-opened; r.session != nil {
+				r.session.Close()
+			}
+		}()
+		return nil, fmt.Errorf("no answer within %s", timeout)
+	}
+}
+
+// acquire returns the server's connection, dialing it when there is none.
+// A new connection gets a keepalive for as long as it is open.
+func (p *connPool) acquire(c *pooledConn, server *config.ServerConfig) (*ssh.Client, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+	if c.client == nil {
+		client, err := p.dial(server)
+		if err != nil {
+			return nil, err
+		}
+		c.client = client
+		go c.keepalive(client, p.keepalive, p.timeout)
+	}
+	c.users++
+	return c.client, nil
+}
+
+// keepalive probes the connection every interval until it is closed, and
+// closes it when the server stops answering. Sessions stuck on it then
+// fail, and the next command dials again.
+func (c *pooledConn) keepalive(client *ssh.Client, interval, timeout time.Duration) {
+	closed := make(chan struct{})
+	go func() {
+		client.Wait()
+		close(closed)
+	}()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-closed:
+			return
+		case <-ticker.C:
+			if !alive(client, timeout) {
+				c.mu.Lock()
+				if c.client == client {
+					c.client = nil
+				}
+				c.mu.Unlock()
+				client.Close()
+				return
+			}
+		}
+	}
+}
+
+// release hands back a connection acquire returned. A dead one is closed
+// so the next acquire dials again; the last user arms the idle timer.
+func (c *pooledConn) release(client *ssh.Client, idle time.Duration, dead bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.users--
+	if dead && c.client == client {
+		c.client.Close()
+		c.client = nil
+	}
+	if c.users == 0 && c.client != nil {
+		c.timer = time.AfterFunc(idle, c.closeIdle)
+	}
+}
+
+func (c *pooledConn) closeIdle() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.users == 0 && c.client != nil {
+		c.client.Close()
+		c.client = nil
+	}
+}
+
+func (p *connPool) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, c := range p.conns {
+		c.mu.Lock()
+		if c.timer != nil {
+			c.timer.Stop()
+			c.timer = nil
+		}
+		if c.client != nil {
+			c.client.Close()
+			c.client = nil
+		}
+		c.mu.Unlock()
+	}
+}
+
+// alive reports whether the server still answers on the connection. A
+// request it doesn't know still gets a reply, so only the error counts.
+func alive(client *ssh.Client, timeout time.Duration) bool {
+	done := make(chan error, 1)
+	go func() {
+		_, _, err := client.SendRequest("keepalive@openssh.com", true, nil)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		return err == nil
+	case <-time.After(timeout):
+		return false
+	}
+}
@@ -0,0 +1,312 @@
+package remote
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Higangssh/homebutler/internal/config"
+	"golang.org/x/crypto/ssh"
+)
+
+// sshServer is an in-process SSH server that answers every command with
+// "ok", except "hang", which says "started" and runs until it gets a
+// signal. It counts the connections it accepted and can drop them all or
+// stop reading from them. A nil config lets every client in.
+type sshServer struct {
+	addr     string
+	accepted atomic.Int32
+	closed   chan struct{} // one value per connection that ended
+	signals  chan string   // the signals that ended a command
+	stopped  chan struct{} // closed when the test ends
+
+	mu     sync.Mutex
+	conns  []*ssh.ServerConn
+	stalls []*stallConn
+}
+
+// stallConn stops reading, like a host that froze, once stalled is set.
+type stallConn struct {
+	net.Conn
+	stalled atomic.Bool
+	stopped chan struct{}
+}
+
+func (c *stallConn) Read(b []byte) (int, error) {
+	if c.stalled.Load() {
+		<-c.stopped
+		return 0, io.EOF
+	}
+	return c.Conn.Read(b)
+}
+
+func newSSHServer(t *testing.T, cfg *ssh.ServerConfig) *sshServer {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg == nil {
+		cfg = &ssh.ServerConfig{NoClientAuth: true}
+	}
+	cfg.AddHostKey(signer)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+	s := &sshServer{addr: ln.Addr().String(), closed: make(chan struct{}, 16), signals: make(chan string, 16), stopped: make(chan struct{})}
+	t.Cleanup(func() {
+		ln.Close()
+		close(s.stopped)
+	})
+	go func() {
+		for {
+			nc, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(nc, cfg)
+		}
+	}()
+	return s
+}
+
+func (s *sshServer) serve(nc net.Conn, cfg *ssh.ServerConfig) {
+	sc := &stallConn{Conn: nc, stopped: s.stopped}
+	conn, chans, reqs, err := ssh.NewServerConn(sc, cfg)
+	if err != nil {
+		nc.Close()
+		return
+	}
+	s.accepted.Add(1)
+	s.mu.Lock()
+	s.conns = append(s.conns, conn)
+	s.stalls = append(s.stalls, sc)
+	s.mu.Unlock()
+	go ssh.DiscardRequests(reqs)
+	go func() {
+		for nc := range chans {
+			ch, reqs, err := nc.Accept()
+			if err != nil {
+				continue
+			}
+			go func() {
+				for req := range reqs {
+					req.Reply(req.Type == "exec", nil)
+					switch {
+					case req.Type == "exec" && bytes.HasSuffix(req.Payload, []byte("hang")):
+						ch.Write([]byte("started\n"))
+					case req.Type == "exec":
+						ch.Write([]byte("ok\n"))
+						ch.SendRequest("exit-status", false, binary.BigEndian.AppendUint32(nil, 0))
+						ch.Close()
+					case req.Type == "signal":
+						s.signals <- string(req.Payload[4:]) // after the name's length
+						ch.SendRequest("exit-status", false, binary.BigEndian.AppendUint32(nil, 143))
+						ch.Close()
+					}
+				}
+			}()
+		}
+	}()
+	conn.Wait()
+	s.closed <- struct{}{}
+}
+
+// drop closes every connection from the server side.
+func (s *sshServer) drop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.conns {
+		c.Close()
+	}
+	s.conns = nil
+}
+
+// stall stops the server reading from the connections it has.
+func (s *sshServer) stall() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.stalls {
+		c.stalled.Store(true)
+	}
+}
+
+func (s *sshServer) pool(idle time.Duration) *connPool {
+	return newPool(idle, func(*config.ServerConfig) (*ssh.Client, error) {
+		return ssh.Dial("tcp", s.addr, &ssh.ClientConfig{User: "test", HostKeyCallback: ssh.InsecureIgnoreHostKey()})
+	})
+}
+
+func runOn(t *testing.T, p *connPool, server *config.ServerConfig) {
+	t.Helper()
+	session, done, err := p.session(server)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer done()
+	out, err := session.Output("homebutler status")
+	if err != nil || string(out) != "ok\n" {
+		t.Errorf("unexpected output %q, %v", out, err)
+	}
+}
+
+func TestPoolReusesConnection(t *testing.T) {
+	s := newSSHServer(t, nil)
+	p := s.pool(time.Minute)
+	defer p.close()
+	server := &config.ServerConfig{Name: "nas", Host: "127.0.0.1"}
+
+	for range 3 {
+		runOn(t, p, server)
+	}
+	var wg sync.WaitGroup
+	for range 3 * maxSessions {
+		wg.Go(func() { runOn(t, p, server) })
+	}
+	wg.Wait()
+	if n := s.accepted.Load(); n != 1 {
+		t.Errorf("expected one connection, got %d", n)
+	}
+
+	// another server gets its own
+	runOn(t, p, &config.ServerConfig{Name: "pi", Host: "127.0.0.1"})
+	if n := s.accepted.Load(); n != 2 {
+		t.Errorf("expected a second connection, got %d", n)
+	}
+}
+
+func TestPoolRedialsDeadConnection(t *testing.T) {
+	s := newSSHServer(t, nil)
+	p := s.pool(time.Minute)
+	defer p.close()
+	server := &config.ServerConfig{Name: "nas", Host: "127.0.0.1"}
+
+	runOn(t, p, server)
+	s.drop()
+	<-s.closed
+	runOn(t, p, server)
+	if n := s.accepted.Load(); n != 2 {
+		t.Errorf("expected a reconnect, got %d connections", n)
+	}
+}
+
+func TestPoolClosesIdleConnection(t *testing.T) {
+	s := newSSHServer(t, nil)
+	p := s.pool(20 * time.Millisecond)
+	defer p.close()
+	server := &config.ServerConfig{Name: "nas", Host: "127.0.0.1"}
+
+	runOn(t, p, server)
+	select {
+	case <-s.closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("idle connection was not closed")
+	}
+	runOn(t, p, server)
+	if n := s.accepted.Load(); n != 2 {
+		t.Errorf("expected a new connection after the idle close, got %d", n)
+	}
+}
+
+func TestPoolUnresponsiveServer(t *testing.T) {
+	s := newSSHServer(t, nil)
+	p := s.pool(time.Minute)
+	p.keepalive, p.timeout = time.Hour, 200*time.Millisecond
+	defer p.close()
+	server := &config.ServerConfig{Name: "nas", Host: "127.0.0.1"}
+	c := p.conn(server)
+	client := func() *ssh.Client {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return c.client
+	}
+
+	// a session that gets no answer gives up on the connection
+	runOn(t, p, server)
+	s.stall()
+	alive(client(), time.Second) // the read under way still gets through
+	p.keepalive = 20 * time.Millisecond
+	runOn(t, p, server)
+	if n := s.accepted.Load(); n != 2 {
+		t.Errorf("expected a new connection, got %d", n)
+	}
+
+	// the keepalive gives up on it with no command running
+	s.stall()
+	for deadline := time.Now().Add(5 * time.Second); client() != nil; time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("the stalled connection was not closed")
+		}
+	}
+	runOn(t, p, server)
+	if n := s.accepted.Load(); n != 3 {
+		t.Errorf("expected a new connection, got %d", n)
+	}
+}
+
+func TestRunTimesOut(t *testing.T) {
+	s := newSSHServer(t, nil)
+	p := s.pool(time.Minute)
+	p.timeout = 100 * time.Millisecond
+	defer p.close()
+	server := &config.ServerConfig{Name: "nas", Host: "127.0.0.1"}
+
+	session, done, err := p.session(server)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = run(session, "hang", 50*time.Millisecond)
+	done()
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("expected a timeout, got %v", err)
+	}
+	// the connection outlives the command
+	runOn(t, p, server)
+	if n := s.accepted.Load(); n != 1 {
+		t.Errorf("expected the connection to be kept, got %d", n)
+	}
+
+	// with every session taken, the next one waits only so long
+	for range maxSessions {
+		_, done, err := p.session(server)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer done()
+	}
+	if _, _, err := p.session(server); err == nil || !strings.Contains(err.Error(), "busy") {
+		t.Errorf("expected busy sessions, got %v", err)
+	}
+}
+
+func TestRunTimeout(t *testing.T) {
+	tests := []struct {
+		args []string
+		want time.Duration
+	}{
+		{[]string{"status", "--json"}, commandTimeout},
+		{[]string{"compose", "ps", "--json"}, commandTimeout},
+		{[]string{"compose", "up", "monitoring"}, slowCommandTimeout},
+		{[]string{"docker", "prune", "--json"}, slowCommandTimeout},
+		{[]string{"docker", "restart", "plex"}, commandTimeout},
+	}
+	for _, tt := range tests {
+		if got := runTimeout(tt.args); got != tt.want {
+			t.Errorf("runTimeout(%v) = %s, want %s", tt.args, got, tt.want)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"compress/gzip"
 	"runtime"
+	"slices"
 	"testing"
 
 	"github.com/Higangssh/homebutler/internal/config"
@@ -159,6 +160,25 @@ func TestServerConfigPasswordAuthNoPassword(t *testing.T) {
 	}
 }
 
+func TestServerConfigAuthModes(t *testing.T) {
+	tests := []struct {
+		auth string
+		want []string
+		key  bool
+	}{
+		{"", []string{"key"}, true},
+		{"agent", []string{"agent"}, false},
+		{"key, keyboard-interactive", []string{"key", "keyboard-interactive"}, true},
+		{"agent,password", []string{"agent", "password"}, false},
+	}
+	for _, tt := range tests {
+		s := &config.ServerConfig{AuthMode: tt.auth}
+		if got := s.AuthModes(); !slices.Equal(got, tt.want) || s.UseKeyAuth() != tt.key {
+			t.Errorf("auth %q: got %v (key %v)", tt.auth, got, s.UseKeyAuth())
+		}
+	}
+}
+
 // --- Config FindServer tests ---
 
 func TestFindServer(t *testing.T) {
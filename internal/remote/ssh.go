@@ -1,8 +1,11 @@
 package remote
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"os"
 	"path/filepath"
@@ -17,49 +20,145 @@ import (
 const (
 	dialTimeout    = 10 * time.Second
 	commandTimeout = 30 * time.Second
+	// slowCommandTimeout is for commands that pull images or walk all of
+	// them; it outlasts the compose CLI's own timeout, so that one reports.
+	slowCommandTimeout = 16 * time.Minute
+	// stopTimeout is how long a streamed command gets to exit on SIGTERM.
+	stopTimeout = 5 * time.Second
 )
 
-// Run executes a homebutler command on a remote server via SSH.
-// It expects homebutler to be installed on the remote host.
+// Run executes a homebutler command on a remote server via SSH, on the
+// server's pooled connection. It expects homebutler to be installed on the
+// remote host.
 func Run(server *config.ServerConfig, args ...string) ([]byte, error) {
+	session, done, err := pool.session(server)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	out, err := run(session, remoteCommand(server, args), runTimeout(args))
+	if err != nil {
+		return nil, fmt.Errorf("[%s] remote command failed: %w\n  → Output: %s\n  → Check if homebutler is installed on the remote server: homebutler deploy %s", server.Name, err, strings.TrimSpace(string(out)), server.Name)
+	}
+
+	return out, nil
+}
+
+// runTimeout is how long a command may run: commandTimeout, or longer for
+// compose actions, docker prune and the registry checks of docker updates.
+func runTimeout(args []string) time.Duration {
+	switch {
+	case len(args) > 1 && args[0] == "compose" && args[1] != "ps",
+		len(args) > 1 && args[0] == "docker" && (args[1] == "prune" || args[1] == "updates"):
+		return slowCommandTimeout
+	}
+	return commandTimeout
+}
+
+// run runs a command in a session. When it outlasts timeout the session is
+// closed, leaving the connection to other commands.
+func run(session *ssh.Session, command string, timeout time.Duration) ([]byte, error) {
+	type result struct {
+		out []byte
+		err error
+	}
+	finished := make(chan result, 1)
+	go func() {
+		out, err := session.CombinedOutput(command)
+		finished <- result{out, err}
+	}()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case r := <-finished:
+		return r.out, r.err
+	case <-timer.C:
+		session.Signal(ssh.SIGTERM)
+		session.Close()
+		return nil, fmt.Errorf("timed out after %s", timeout)
+	}
+}
+
+// Stream runs a homebutler command on a remote server and copies its stdout
+// to w as it arrives, for commands that keep running like `docker logs
+// --follow`. It returns when the command exits or ctx is canceled.
+// It dials a connection of its own, closed once the command has been told
+// to stop.
+func Stream(ctx context.Context, server *config.ServerConfig, w io.Writer, args ...string) error {
 	client, err := connect(server)
 	if err != nil {
-		return nil, err // connect() already returns detailed error messages
+		return err
 	}
 	defer client.Close()
 
 	session, err := client.NewSession()
 	if err != nil {
-		return nil, fmt.Errorf("[%s] failed to open SSH session: %w\n  → Check if the server is accepting new connections", server.Name, err)
+		return fmt.Errorf("[%s] failed to open SSH session: %w\n  → Check if the server is accepting new connections", server.Name, err)
 	}
 	defer session.Close()
 
-	// Try configured bin path, then common locations
-	binPath := server.SSHBinPath()
-	cmd := fmt.Sprintf("export PATH=$HOME/.local/bin:$HOME/bin:$HOME/go/bin:/opt/homebrew/bin:/usr/local/bin:/usr/local/sbin:/snap/bin:$PATH; %s %s", binPath, strings.Join(args, " "))
-	out, err := session.CombinedOutput(cmd)
-	if err != nil {
-		return nil, fmt.Errorf("[%s] remote command failed: %w\n  → Output: %s\n  → Check if homebutler is installed on the remote server: homebutler deploy %s", server.Name, err, strings.TrimSpace(string(out)), server.Name)
+	var stderr bytes.Buffer
+	session.Stdout = w
+	session.Stderr = &stderr
+	if err := session.Start(remoteCommand(server, args)); err != nil {
+		return fmt.Errorf("[%s] failed to start remote command: %w", server.Name, err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- session.Wait() }()
+	select {
+	case <-ctx.Done():
+		// Without a terminal, sshd doesn't hang up the command when the
+		// connection closes; it has to be stopped first.
+		session.Signal(ssh.SIGTERM)
+		select {
+		case <-done:
+		case <-time.After(stopTimeout):
+			client.Close()
+			<-done
+		}
+		return nil
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("[%s] remote command failed: %w\n  → Output: %s\n  → Check if homebutler is installed on the remote server: homebutler deploy %s", server.Name, err, strings.TrimSpace(stderr.String()), server.Name)
+		}
+		return nil
 	}
+}
 
-	return out, nil
+// remoteCommand builds the shell command line for a homebutler invocation,
+// trying the configured bin path first, then common locations.
+func remoteCommand(server *config.ServerConfig, args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = shellQuote(a)
+	}
+	env := ""
+	if server.Runtime != "" {
+		// The remote homebutler reads its own config; pass the runtime set here
+		env = "HOMEBUTLER_RUNTIME=" + shellQuote(server.Runtime) + " "
+	}
+	// exec, so a signal to the session reaches homebutler, not the shell
+	return fmt.Sprintf("export PATH=$HOME/.local/bin:$HOME/bin:$HOME/go/bin:/opt/homebrew/bin:/usr/local/bin:/usr/local/sbin:/snap/bin:$PATH; %sexec %s %s", env, server.SSHBinPath(), strings.Join(quoted, " "))
 }
 
-func connect(server *config.ServerConfig) (*ssh.Client, error) {
-	var authMethods []ssh.AuthMethod
+// shellQuote single-quotes an argument unless it is plainly safe, so values
+// like grep patterns reach the remote homebutler as one argument.
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./:=,+@%", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
 
-	if server.UseKeyAuth() {
-		signer, err := loadKey(server.KeyFile)
-		if err != nil {
-			return nil, fmt.Errorf("[%s] failed to load SSH key (%s): %w\n  → Check the key_file path in your config: ~/.config/homebutler/config.yaml", server.Name, server.KeyFile, err)
-		}
-		authMethods = append(authMethods, ssh.PublicKeys(signer))
-	} else {
-		if server.Password == "" {
-			return nil, fmt.Errorf("[%s] no SSH credentials configured\n  → Add 'key_file' or 'password' to this server in ~/.config/homebutler/config.yaml", server.Name)
-		}
-		authMethods = append(authMethods, ssh.Password(server.Password))
+func connect(server *config.ServerConfig) (*ssh.Client, error) {
+	methods, done, err := authMethods(server)
+	if err != nil {
+		return nil, err
 	}
+	defer done()
 
 	hostKeyCallback, err := newKnownHostsCallback()
 	if err != nil {
@@ -68,7 +167,7 @@ func connect(server *config.ServerConfig) (*ssh.Client, error) {
 
 	cfg := &ssh.ClientConfig{
 		User:            server.SSHUser(),
-		Auth:            authMethods,
+		Auth:            methods,
 		HostKeyCallback: hostKeyCallback,
 		Timeout:         dialTimeout,
 	}
@@ -92,19 +191,10 @@ func connect(server *config.ServerConfig) (*ssh.Client, error) {
 					"  → If you trust this change: homebutler trust %s --reset\n"+
 					"  → If unexpected: do NOT connect and investigate", server.Name, addr, server.Name)
 			}
-			// Unknown host — TOFU: auto-add to known_hosts and retry
-			if tofuErr := tofuConnect(addr, cfg); tofuErr == nil {
-				// Reload known_hosts and retry
-				newCb, cbErr := newKnownHostsCallback()
-				if cbErr == nil {
-					retryCfg := *cfg
-					retryCfg.HostKeyCallback = newCb
-					retryClient, retryErr := ssh.Dial("tcp", addr, &retryCfg)
-					if retryErr != nil {
-						return nil, fmt.Errorf("[%s] connected but failed to establish session after registering host key (%s): %w\n  → Try again, or manually: homebutler trust %s", server.Name, addr, retryErr, server.Name)
-					}
-					return retryClient, nil
-				}
+			// Unknown host — TOFU: auto-add to known_hosts and keep the
+			// connection, so auth (and any one-time code) happens once
+			if tofuClient, tofuErr := tofuConnect(addr, cfg); tofuErr == nil {
+				return tofuClient, nil
 			}
 			return nil, fmt.Errorf("[%s] failed to auto-register host key for %s\n  → Register manually: homebutler trust %s\n  → Check SSH connectivity: ssh %s@%s -p %d",
 				server.Name, addr, server.Name, server.SSHUser(), server.Host, server.SSHPort())
@@ -151,11 +241,11 @@ func newKnownHostsCallback() (ssh.HostKeyCallback, error) {
 }
 
 // tofuConnect performs Trust On First Use: connects to get the host key,
-// then adds it to known_hosts automatically.
-func tofuConnect(addr string, cfg *ssh.ClientConfig) error {
+// then adds it to known_hosts automatically and returns the connection.
+func tofuConnect(addr string, cfg *ssh.ClientConfig) (*ssh.Client, error) {
 	path, err := knownHostsPath()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Connect with a callback that captures the host key
@@ -168,53 +258,41 @@ func tofuConnect(addr string, cfg *ssh.ClientConfig) error {
 
 	client, err := ssh.Dial("tcp", addr, &captureCfg)
 	if err != nil {
-		return fmt.Errorf("TOFU dial failed: %w", err)
+		return nil, fmt.Errorf("TOFU dial failed: %w", err)
 	}
-	client.Close()
 
 	if hostKey == nil {
-		return fmt.Errorf("no host key captured")
+		client.Close()
+		return nil, fmt.Errorf("no host key captured")
 	}
 
 	// Write to known_hosts
 	line := knownhosts.Line([]string{addr}, hostKey)
 	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
 	if err != nil {
-		return fmt.Errorf("cannot write known_hosts: %w", err)
+		client.Close()
+		return nil, fmt.Errorf("cannot write known_hosts: %w", err)
 	}
 	defer f.Close()
 	if _, err := fmt.Fprintln(f, line); err != nil {
-		return err
+		client.Close()
+		return nil, err
 	}
 
-	return nil
+	return client, nil
 }
 
 // TrustServer connects to a server, displays its host key fingerprint,
 // and adds it to known_hosts if the user confirms.
 func TrustServer(server *config.ServerConfig, confirm func(fingerprint string) bool) error {
-	var authMethods []ssh.AuthMethod
-	if server.UseKeyAuth() {
-		signer, err := loadKey(server.KeyFile)
-		if err != nil {
-			return fmt.Errorf("load SSH key: %w", err)
-		}
-		authMethods = append(authMethods, ssh.PublicKeys(signer))
-	} else {
-		if server.Password == "" {
-			return fmt.Errorf("password auth selected but no password configured for %s", server.Name)
-		}
-		authMethods = append(authMethods, ssh.Password(server.Password))
-	}
-
 	addr := fmt.Sprintf("%s:%d", server.Host, server.SSHPort())
 	var serverKey ssh.PublicKey
 
 	// Connect with a callback that captures the host key but always fails,
-	// so we can show the fingerprint before committing.
+	// so we can show the fingerprint before committing. That happens
+	// before auth, so no credentials are needed.
 	captureCfg := &ssh.ClientConfig{
 		User: server.SSHUser(),
-		Auth: authMethods,
 		HostKeyCallback: func(hostname string, remote net.Addr, key ssh.PublicKey) error {
 			serverKey = key
 			return fmt.Errorf("key captured") // intentional: we just want the key
@@ -299,39 +377,3 @@ func RemoveHostKeys(server *config.ServerConfig) error {
 
 	return os.WriteFile(khPath, []byte(strings.Join(kept, "\n")), 0600)
 }
-
-func loadKey(keyFile string) (ssh.Signer, error) {
-	if keyFile == "" {
-		// Try default key locations
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return nil, fmt.Errorf("cannot determine home directory: %w", err)
-		}
-		defaults := []string{
-			filepath.Join(home, ".ssh", "id_ed25519"),
-			filepath.Join(home, ".ssh", "id_rsa"),
-		}
-		for _, path := range defaults {
-			if signer, err := readKey(path); err == nil {
-				return signer, nil
-			}
-		}
-		return nil, fmt.Errorf("no SSH key found (tried ~/.ssh/id_ed25519, ~/.ssh/id_rsa). Specify key path in config or use auth: password")
-	}
-
-	// Expand ~ prefix
-	if strings.HasPrefix(keyFile, "~/") {
-		home, _ := os.UserHomeDir()
-		keyFile = filepath.Join(home, keyFile[2:])
-	}
-
-	return readKey(keyFile)
-}
-
-func readKey(path string) (ssh.Signer, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, err
-	}
-	return ssh.ParsePrivateKey(data)
-}
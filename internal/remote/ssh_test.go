@@ -1,12 +1,16 @@
 package remote
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/Higangssh/homebutler/internal/config"
 	"golang.org/x/crypto/ssh/knownhosts"
 )
 
@@ -135,3 +139,63 @@ func TestKnownHostsKeyError(t *testing.T) {
 		t.Error("mismatch KeyError should have Want entries")
 	}
 }
+
+func TestShellQuote(t *testing.T) {
+	tests := map[string]string{
+		"docker":           "docker",
+		"--since":          "--since",
+		"10m":              "10m",
+		"ERROR|WARN":       "'ERROR|WARN'",
+		"it's":             `'it'\''s'`,
+		"a b":              "'a b'",
+		"":                 "''",
+		"$(reboot)":        "'$(reboot)'",
+		"2024-01-02T15:04": "2024-01-02T15:04",
+	}
+	for in, want := range tests {
+		if got := shellQuote(in); got != want {
+			t.Errorf("shellQuote(%q) = %s, want %s", in, got, want)
+		}
+	}
+}
+
+func TestRemoteCommandRuntime(t *testing.T) {
+	srv := &config.ServerConfig{Name: "rpi"}
+	if cmd := remoteCommand(srv, []string{"docker", "list"}); strings.Contains(cmd, "HOMEBUTLER_RUNTIME") || !strings.HasSuffix(cmd, "; exec homebutler docker list") {
+		t.Errorf("unexpected command without runtime: %s", cmd)
+	}
+	srv.Runtime = "podman"
+	if cmd := remoteCommand(srv, []string{"docker", "list"}); !strings.HasSuffix(cmd, "; HOMEBUTLER_RUNTIME=podman exec homebutler docker list") {
+		t.Errorf("runtime not passed to the remote command: %s", cmd)
+	}
+}
+
+func TestStreamStopsRemoteCommand(t *testing.T) {
+	isolateAuth(t, nil)
+	s := newSSHServer(t, nil)
+	key, _ := writeKey(t, "")
+	server := testTarget(t, s, config.ServerConfig{KeyFile: key})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pr, pw := io.Pipe()
+	streamed := make(chan error, 1)
+	go func() { streamed <- Stream(ctx, server, pw, "docker", "logs", "plex", "--follow", "hang") }()
+
+	buf := make([]byte, 64)
+	if n, err := pr.Read(buf); err != nil || string(buf[:n]) != "started\n" {
+		t.Fatalf("unexpected output %q, %v", buf[:n], err)
+	}
+	cancel()
+	if err := <-streamed; err != nil {
+		t.Errorf("expected no error on cancel, got %v", err)
+	}
+	select {
+	case sig := <-s.signals:
+		if sig != "TERM" {
+			t.Errorf("expected SIGTERM, got %s", sig)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("the remote command was left running")
+	}
+}
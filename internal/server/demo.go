@@ -2,7 +2,19 @@ package server
 
 import (
 	"encoding/json"
+	"fmt"
+	"math"
 	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Higangssh/homebutler/internal/alerts"
+	"github.com/Higangssh/homebutler/internal/docker"
+	"github.com/Higangssh/homebutler/internal/exporter"
+	"github.com/Higangssh/homebutler/internal/history"
 )
 
 // demoServerName returns the server name from the ?server query param.
@@ -35,17 +47,39 @@ func (s *Server) demoStatus(w http.ResponseWriter, r *http.Request) {
 			"uptime":   "4d 12h",
 			"time":     "2026-02-27T14:30:00Z",
 			"cpu": map[string]any{
-				"usage_percent": 23.4,
-				"cores":         8,
+				"usage_percent":  23.4,
+				"cores":          8,
+				"per_core":       []float64{31.2, 18.5, 42.0, 12.3, 25.1, 19.8, 22.4, 16.0},
+				"user_percent":   16.1,
+				"system_percent": 5.2,
+				"iowait_percent": 1.8,
+				"steal_percent":  0.3,
+				"load":           map[string]any{"load1": 1.42, "load5": 1.18, "load15": 0.97},
 			},
 			"memory": map[string]any{
 				"total_gb":      32.0,
 				"used_gb":       12.4,
 				"usage_percent": 38.8,
+				"buffers_bytes": 612368384,
+				"cached_bytes":  uint64(14173392896),
+				"swap":          map[string]any{"total_gb": 8.0, "used_gb": 0.25, "usage_percent": 3.12, "total_bytes": uint64(8589934592), "used_bytes": 268435456},
+			},
+			"pressure": map[string]any{
+				"cpu":    map[string]any{"some": map[string]any{"avg10": 2.14, "avg60": 1.87, "avg300": 1.52}, "full": map[string]any{"avg10": 0.0, "avg60": 0.0, "avg300": 0.0}},
+				"memory": map[string]any{"some": map[string]any{"avg10": 0.0, "avg60": 0.0, "avg300": 0.0}, "full": map[string]any{"avg10": 0.0, "avg60": 0.0, "avg300": 0.0}},
+				"io":     map[string]any{"some": map[string]any{"avg10": 0.85, "avg60": 1.12, "avg300": 0.94}, "full": map[string]any{"avg10": 0.31, "avg60": 0.44, "avg300": 0.38}},
 			},
 			"disks": []map[string]any{
-				{"mount": "/", "total_gb": 500.0, "used_gb": 187.5, "usage_percent": 37.5},
-				{"mount": "/mnt/data", "total_gb": 2000.0, "used_gb": 1740.0, "usage_percent": 87.0},
+				{"mount": "/", "total_gb": 500.0, "used_gb": 187.5, "usage_percent": 37.5, "inodes_total": 32768000, "inodes_used": 1245184, "inodes_percent": 3.8},
+				{"mount": "/mnt/data", "total_gb": 2000.0, "used_gb": 1740.0, "usage_percent": 87.0, "inodes_total": 122101760, "inodes_used": 96460390, "inodes_percent": 79.0},
+			},
+			"disk_io": []map[string]any{
+				{"device": "nvme0n1", "read_bytes_per_sec": 2516582.0, "write_bytes_per_sec": 8912896.0, "reads_per_sec": 142.0, "writes_per_sec": 388.0, "await_ms": 0.42, "util_percent": 6.5},
+				{"device": "sda", "read_bytes_per_sec": 524288.0, "write_bytes_per_sec": 0.0, "reads_per_sec": 8.0, "writes_per_sec": 0.0, "await_ms": 7.8, "util_percent": 3.1},
+			},
+			"network": []map[string]any{
+				{"interface": "eth0", "state": "up", "speed_mbps": 1000, "rx_bytes_per_sec": 4718592.0, "tx_bytes_per_sec": 1153434.0, "utilization_percent": 3.77, "rx_errors": 0, "tx_errors": 0, "rx_dropped": 12, "tx_dropped": 0, "errors_per_sec": 0.0},
+				{"interface": "docker0", "state": "up", "speed_mbps": 0, "rx_bytes_per_sec": 52428.0, "tx_bytes_per_sec": 314572.0, "utilization_percent": 0.0, "rx_errors": 0, "tx_errors": 0, "rx_dropped": 0, "tx_dropped": 0, "errors_per_sec": 0.0},
 			},
 		})
 	case "nas-box":
@@ -56,17 +90,33 @@ func (s *Server) demoStatus(w http.ResponseWriter, r *http.Request) {
 			"uptime":   "12d 3h",
 			"time":     "2026-02-27T14:30:00Z",
 			"cpu": map[string]any{
-				"usage_percent": 5.2,
-				"cores":         4,
+				"usage_percent":  5.2,
+				"cores":          4,
+				"per_core":       []float64{4.1, 6.8, 3.9, 6.0},
+				"user_percent":   2.9,
+				"system_percent": 1.4,
+				"iowait_percent": 0.9,
+				"steal_percent":  0.0,
+				"load":           map[string]any{"load1": 0.31, "load5": 0.27, "load15": 0.22},
 			},
 			"memory": map[string]any{
 				"total_gb":      16.0,
 				"used_gb":       6.8,
 				"usage_percent": 42.5,
+				"buffers_bytes": 268435456,
+				"cached_bytes":  uint64(7516192768),
+				"swap":          map[string]any{"total_gb": 0.0, "used_gb": 0.0, "usage_percent": 0.0, "total_bytes": 0, "used_bytes": 0},
+				"hugepages":     map[string]any{"total": 512, "free": 512, "page_bytes": 2097152},
 			},
 			"disks": []map[string]any{
-				{"mount": "/", "total_gb": 120.0, "used_gb": 32.0, "usage_percent": 26.7},
-				{"mount": "/mnt/storage", "total_gb": 8000.0, "used_gb": 4960.0, "usage_percent": 62.0},
+				{"mount": "/", "total_gb": 120.0, "used_gb": 32.0, "usage_percent": 26.7, "inodes_total": 7864320, "inodes_used": 412876, "inodes_percent": 5.2},
+				{"mount": "/mnt/storage", "total_gb": 8000.0, "used_gb": 4960.0, "usage_percent": 62.0, "inodes_total": 0, "inodes_used": 0, "inodes_percent": 0.0},
+			},
+			"disk_io": []map[string]any{
+				{"device": "sda", "read_bytes_per_sec": 89128960.0, "write_bytes_per_sec": 1048576.0, "reads_per_sec": 712.0, "writes_per_sec": 16.0, "await_ms": 9.6, "util_percent": 71.2},
+			},
+			"network": []map[string]any{
+				{"interface": "eth0", "state": "up", "speed_mbps": 2500, "rx_bytes_per_sec": 88080384.0, "tx_bytes_per_sec": 2097152.0, "utilization_percent": 28.18, "rx_errors": 0, "tx_errors": 0, "rx_dropped": 0, "tx_dropped": 0, "errors_per_sec": 0.0},
 			},
 		})
 	case "raspberry-pi":
@@ -77,16 +127,32 @@ func (s *Server) demoStatus(w http.ResponseWriter, r *http.Request) {
 			"uptime":   "28d 7h",
 			"time":     "2026-02-27T14:30:00Z",
 			"cpu": map[string]any{
-				"usage_percent": 12.1,
-				"cores":         4,
+				"usage_percent":  12.1,
+				"cores":          4,
+				"per_core":       []float64{15.3, 9.7, 13.2, 10.2},
+				"user_percent":   8.4,
+				"system_percent": 3.1,
+				"iowait_percent": 0.6,
+				"steal_percent":  0.0,
+				"load":           map[string]any{"load1": 0.58, "load5": 0.49, "load15": 0.44},
 			},
 			"memory": map[string]any{
 				"total_gb":      4.0,
 				"used_gb":       2.1,
 				"usage_percent": 52.5,
+				"buffers_bytes": 67108864,
+				"cached_bytes":  1288490188,
+				"swap":          map[string]any{"total_gb": 0.5, "used_gb": 0.21, "usage_percent": 42.0, "total_bytes": 536870912, "used_bytes": 225485783},
 			},
 			"disks": []map[string]any{
-				{"mount": "/", "total_gb": 64.0, "used_gb": 18.0, "usage_percent": 28.1},
+				{"mount": "/", "total_gb": 64.0, "used_gb": 18.0, "usage_percent": 28.1, "inodes_total": 3907584, "inodes_used": 270336, "inodes_percent": 6.9},
+			},
+			"disk_io": []map[string]any{
+				{"device": "mmcblk0", "read_bytes_per_sec": 20480.0, "write_bytes_per_sec": 65536.0, "reads_per_sec": 2.0, "writes_per_sec": 9.0, "await_ms": 3.4, "util_percent": 1.2},
+			},
+			"network": []map[string]any{
+				{"interface": "eth0", "state": "up", "speed_mbps": 1000, "rx_bytes_per_sec": 184320.0, "tx_bytes_per_sec": 98304.0, "utilization_percent": 0.15, "rx_errors": 0, "tx_errors": 0, "rx_dropped": 0, "tx_dropped": 0, "errors_per_sec": 0.0},
+				{"interface": "wlan0", "state": "down", "speed_mbps": 0, "rx_bytes_per_sec": 0.0, "tx_bytes_per_sec": 0.0, "utilization_percent": 0.0, "rx_errors": 0, "tx_errors": 0, "rx_dropped": 0, "tx_dropped": 0, "errors_per_sec": 0.0},
 			},
 		})
 	default:
@@ -94,6 +160,43 @@ func (s *Server) demoStatus(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// demoSensors returns realistic demo temperature and fan data.
+func (s *Server) demoSensors(w http.ResponseWriter, r *http.Request) {
+	name := demoServerName(r)
+
+	switch name {
+	case "":
+		writeJSON(w, map[string]any{
+			"temperatures": []map[string]any{
+				{"chip": "coretemp", "label": "Package id 0", "celsius": 54.0, "high": 80.0, "critical": 100.0},
+				{"chip": "nvme", "label": "Composite", "celsius": 41.9, "high": 81.8, "critical": 84.8},
+			},
+			"fans": []map[string]any{
+				{"chip": "nct6775", "label": "CPU Fan", "rpm": 1245},
+			},
+		})
+	case "nas-box":
+		writeJSON(w, map[string]any{
+			"temperatures": []map[string]any{
+				{"chip": "coretemp", "label": "Package id 0", "celsius": 44.0, "high": 80.0, "critical": 100.0},
+				{"chip": "drivetemp", "label": "temp1", "celsius": 38.0, "high": 55.0, "critical": 70.0},
+			},
+			"fans": []map[string]any{
+				{"chip": "it8613", "label": "fan1", "rpm": 820},
+			},
+		})
+	case "raspberry-pi":
+		writeJSON(w, map[string]any{
+			"temperatures": []map[string]any{
+				{"chip": "cpu_thermal", "label": "temp1", "celsius": 61.3},
+			},
+			"fans": []map[string]any{},
+		})
+	default:
+		demoOfflineError(w, name)
+	}
+}
+
 // demoDocker returns realistic demo container data.
 func (s *Server) demoDocker(w http.ResponseWriter, r *http.Request) {
 	name := demoServerName(r)
@@ -103,27 +206,27 @@ func (s *Server) demoDocker(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, map[string]any{
 			"available": true,
 			"containers": []map[string]any{
-				{"id": "a1b2c3d4e5f6", "name": "nginx", "image": "nginx:1.25-alpine", "status": "Up 4 days", "state": "running", "ports": "0.0.0.0:80->80/tcp, 0.0.0.0:443->443/tcp"},
-				{"id": "b2c3d4e5f6a1", "name": "postgres", "image": "postgres:16", "status": "Up 4 days", "state": "running", "ports": "5432/tcp"},
-				{"id": "c3d4e5f6a1b2", "name": "redis", "image": "redis:7-alpine", "status": "Up 4 days", "state": "running", "ports": "6379/tcp"},
-				{"id": "d4e5f6a1b2c3", "name": "grafana", "image": "grafana/grafana:10.2", "status": "Up 3 days", "state": "running", "ports": "0.0.0.0:3000->3000/tcp"},
-				{"id": "e5f6a1b2c3d4", "name": "prometheus", "image": "prom/prometheus:v2.48", "status": "Up 3 days", "state": "running", "ports": "0.0.0.0:9090->9090/tcp"},
-				{"id": "f6a1b2c3d4e5", "name": "backup", "image": "restic/restic:0.16", "status": "Stopped · 6h ago", "state": "exited", "ports": ""},
+				{"id": "a1b2c3d4e5f6", "name": "nginx", "image": "nginx:1.25-alpine", "status": "Up 4 days", "state": "running", "ports": []map[string]any{{"ip": "0.0.0.0", "private_port": 80, "public_port": 80, "type": "tcp"}, {"ip": "0.0.0.0", "private_port": 443, "public_port": 443, "type": "tcp"}}},
+				{"id": "b2c3d4e5f6a1", "name": "postgres", "image": "postgres:16", "status": "Up 4 days", "state": "running", "ports": []map[string]any{{"private_port": 5432, "type": "tcp"}}},
+				{"id": "c3d4e5f6a1b2", "name": "redis", "image": "redis:7-alpine", "status": "Up 4 days", "state": "running", "ports": []map[string]any{{"private_port": 6379, "type": "tcp"}}},
+				{"id": "d4e5f6a1b2c3", "name": "grafana", "image": "grafana/grafana:10.2", "status": "Up 3 days", "state": "running", "ports": []map[string]any{{"ip": "0.0.0.0", "private_port": 3000, "public_port": 3000, "type": "tcp"}}},
+				{"id": "e5f6a1b2c3d4", "name": "prometheus", "image": "prom/prometheus:v2.48", "status": "Up 3 days", "state": "running", "ports": []map[string]any{{"ip": "0.0.0.0", "private_port": 9090, "public_port": 9090, "type": "tcp"}}},
+				{"id": "f6a1b2c3d4e5", "name": "backup", "image": "restic/restic:0.16", "status": "Stopped · 6h ago", "state": "exited", "ports": []map[string]any{}},
 			},
 		})
 	case "nas-box":
 		writeJSON(w, map[string]any{
 			"available": true,
 			"containers": []map[string]any{
-				{"id": "aa11bb22cc33", "name": "samba", "image": "dperson/samba:latest", "status": "Up 12 days", "state": "running", "ports": "445/tcp"},
-				{"id": "dd44ee55ff66", "name": "plex", "image": "plexinc/pms-docker:latest", "status": "Up 12 days", "state": "running", "ports": "0.0.0.0:32400->32400/tcp"},
+				{"id": "aa11bb22cc33", "name": "samba", "image": "dperson/samba:latest", "status": "Up 12 days", "state": "running", "ports": []map[string]any{{"private_port": 445, "type": "tcp"}}},
+				{"id": "dd44ee55ff66", "name": "plex", "image": "plexinc/pms-docker:latest", "status": "Up 12 days", "state": "running", "ports": []map[string]any{{"ip": "0.0.0.0", "private_port": 32400, "public_port": 32400, "type": "tcp"}}},
 			},
 		})
 	case "raspberry-pi":
 		writeJSON(w, map[string]any{
 			"available": true,
 			"containers": []map[string]any{
-				{"id": "pi11pi22pi33", "name": "pihole", "image": "pihole/pihole:latest", "status": "Up 28 days", "state": "running", "ports": "0.0.0.0:53->53/tcp, 0.0.0.0:80->80/tcp"},
+				{"id": "pi11pi22pi33", "name": "pihole", "image": "pihole/pihole:latest", "status": "Up 28 days", "state": "running", "ports": []map[string]any{{"ip": "0.0.0.0", "private_port": 53, "public_port": 53, "type": "tcp"}, {"ip": "0.0.0.0", "private_port": 80, "public_port": 80, "type": "tcp"}}},
 			},
 		})
 	default:
@@ -131,6 +234,177 @@ func (s *Server) demoDocker(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// demoDockerUpdates returns demo image update checks.
+func (s *Server) demoDockerUpdates(w http.ResponseWriter, r *http.Request) {
+	name := demoServerName(r)
+
+	switch name {
+	case "":
+		writeJSON(w, []map[string]any{
+			{"container": "nginx", "image": "nginx:1.25-alpine", "local_digest": "sha256:3e2f1a0b9c8d", "remote_digest": "sha256:3e2f1a0b9c8d", "status": "up_to_date"},
+			{"container": "postgres", "image": "postgres:16", "local_digest": "sha256:7a6b5c4d3e2f", "remote_digest": "sha256:c4d3e2f1a0b9", "status": "update_available"},
+			{"container": "redis", "image": "redis:7-alpine", "local_digest": "sha256:0d9e8f7a6b5c", "remote_digest": "sha256:0d9e8f7a6b5c", "status": "up_to_date"},
+			{"container": "grafana", "image": "grafana/grafana:10.2", "local_digest": "sha256:6f5a4b3c2d1e", "remote_digest": "sha256:6f5a4b3c2d1e", "status": "up_to_date"},
+			{"container": "prometheus", "image": "prom/prometheus:v2.48", "local_digest": "sha256:b7c6d5e4f3a2", "remote_digest": "sha256:b7c6d5e4f3a2", "status": "up_to_date"},
+		})
+	case "nas-box":
+		writeJSON(w, []map[string]any{
+			{"container": "samba", "image": "dperson/samba:latest", "local_digest": "sha256:5c1b9e8a7d3f", "remote_digest": "sha256:5c1b9e8a7d3f", "status": "up_to_date"},
+			{"container": "plex", "image": "plexinc/pms-docker:latest", "local_digest": "sha256:8e2f4a6c8b0d", "remote_digest": "sha256:1a3c5e7b9d1f", "status": "update_available"},
+		})
+	case "raspberry-pi":
+		writeJSON(w, []map[string]any{
+			{"container": "pihole", "image": "pihole/pihole:latest", "local_digest": "sha256:9f8e7d6c5b4a", "remote_digest": "sha256:2b4d6f8a0c2e", "status": "update_available"},
+		})
+	default:
+		demoOfflineError(w, name)
+	}
+}
+
+// demoDockerDiskUsage returns demo docker disk usage.
+func (s *Server) demoDockerDiskUsage(w http.ResponseWriter, r *http.Request) {
+	name := demoServerName(r)
+	const mb uint64 = 1 << 20
+
+	switch name {
+	case "":
+		writeJSON(w, map[string]any{
+			"images":            map[string]any{"total": 14, "active": 5, "size_bytes": 6840 * mb, "reclaimable_bytes": 3920 * mb},
+			"containers":        map[string]any{"total": 7, "active": 5, "size_bytes": 310 * mb, "reclaimable_bytes": 85 * mb},
+			"volumes":           map[string]any{"total": 9, "active": 6, "size_bytes": 12400 * mb, "reclaimable_bytes": 1150 * mb},
+			"build_cache":       map[string]any{"total": 42, "active": 0, "size_bytes": 2260 * mb, "reclaimable_bytes": 2260 * mb},
+			"total_bytes":       21810 * mb,
+			"reclaimable_bytes": 7415 * mb,
+			"root_dir":          "/var/lib/docker",
+		})
+	case "nas-box":
+		writeJSON(w, map[string]any{
+			"images":            map[string]any{"total": 3, "active": 2, "size_bytes": 1890 * mb, "reclaimable_bytes": 420 * mb},
+			"containers":        map[string]any{"total": 2, "active": 2, "size_bytes": 40 * mb, "reclaimable_bytes": 0},
+			"volumes":           map[string]any{"total": 2, "active": 2, "size_bytes": 860 * mb, "reclaimable_bytes": 0},
+			"build_cache":       map[string]any{"total": 0, "active": 0, "size_bytes": 0, "reclaimable_bytes": 0},
+			"total_bytes":       2790 * mb,
+			"reclaimable_bytes": 420 * mb,
+			"root_dir":          "/var/lib/docker",
+		})
+	case "raspberry-pi":
+		writeJSON(w, map[string]any{
+			"images":            map[string]any{"total": 2, "active": 1, "size_bytes": 310 * mb, "reclaimable_bytes": 95 * mb},
+			"containers":        map[string]any{"total": 1, "active": 1, "size_bytes": 12 * mb, "reclaimable_bytes": 0},
+			"volumes":           map[string]any{"total": 2, "active": 2, "size_bytes": 150 * mb, "reclaimable_bytes": 0},
+			"build_cache":       map[string]any{"total": 0, "active": 0, "size_bytes": 0, "reclaimable_bytes": 0},
+			"total_bytes":       472 * mb,
+			"reclaimable_bytes": 95 * mb,
+			"root_dir":          "/var/lib/docker",
+		})
+	default:
+		demoOfflineError(w, name)
+	}
+}
+
+// demoDockerPrune pretends to prune; nothing is removed in demo mode.
+func (s *Server) demoDockerPrune(w http.ResponseWriter, r *http.Request) {
+	name := demoServerName(r)
+	if name != "" && name != "nas-box" && name != "raspberry-pi" {
+		demoOfflineError(w, name)
+		return
+	}
+	opts, err := pruneQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	const mb uint64 = 1 << 20
+	items := []map[string]any{
+		{"type": "container", "id": "f6a1b2c3d4e5", "name": "backup", "size_bytes": 85 * mb, "created": "2026-02-20T08:00:00Z"},
+		{"type": "image", "id": "sha256:9a8b7c6d5e4f", "name": "<none>:<none>", "size_bytes": 1480 * mb, "created": "2026-01-30T11:12:00Z"},
+		{"type": "image", "id": "sha256:1b2c3d4e5f6a", "name": "<none>:<none>", "size_bytes": 2440 * mb, "created": "2026-01-12T09:40:00Z"},
+		{"type": "build_cache", "id": "build-cache", "size_bytes": 2260 * mb},
+	}
+	writeJSON(w, map[string]any{"dry_run": opts.DryRun, "items": items, "reclaimed_bytes": 6265 * mb})
+}
+
+// demoLogLines are demo log lines per container, stderr ones prefixed "!".
+var demoLogLines = map[string][]string{
+	"nginx": {
+		`192.168.1.5 - - "GET /api/health HTTP/1.1" 200 2`,
+		`192.168.1.10 - - "GET / HTTP/1.1" 200 612`,
+		`!2026/02/27 14:29:40 [error] 29#29: *118 upstream timed out (110: Connection timed out) while reading response header from upstream`,
+		`192.168.1.20 - - "GET /dashboard HTTP/1.1" 304 0`,
+	},
+	"postgres": {
+		`!LOG:  database system is ready to accept connections`,
+		`!LOG:  checkpoint starting: time`,
+		`!ERROR:  relation "sessions_old" does not exist at character 15`,
+		`!LOG:  checkpoint complete: wrote 42 buffers (0.3%)`,
+	},
+	"pihole": {
+		`query[A] github.com from 192.168.1.23`,
+		`forwarded github.com to 1.1.1.1`,
+		`gravity blocked ads.example.net for 192.168.1.31`,
+	},
+}
+
+// demoDockerLogs streams demo log lines as server-sent events, like
+// handleDockerLogs. Following adds a line every two seconds.
+func (s *Server) demoDockerLogs(w http.ResponseWriter, r *http.Request) {
+	name := demoServerName(r)
+	if name != "" && name != "nas-box" && name != "raspberry-pi" {
+		demoOfflineError(w, name)
+		return
+	}
+	container := r.PathValue("name")
+	opts := logQuery(r)
+	if err := docker.ValidateLogOptions(opts); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var grep *regexp.Regexp
+	if opts.Grep != "" {
+		grep = regexp.MustCompile(opts.Grep)
+	}
+	lines, ok := demoLogLines[container]
+	if !ok {
+		lines = []string{"container " + container + " started"}
+	}
+	sse, ok := newSSEWriter(w)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+
+	now := time.Now().UTC()
+	send := func(i int, at time.Time) error {
+		l := docker.LogLine{Time: at, Stream: "stdout", Text: lines[i%len(lines)]}
+		if text, ok := strings.CutPrefix(l.Text, "!"); ok {
+			l.Stream, l.Text = "stderr", text
+		}
+		if grep != nil && !grep.MatchString(l.Text) {
+			return nil
+		}
+		return sse.send("", l)
+	}
+	for i := range lines {
+		send(i, now.Add(time.Duration(i-len(lines))*time.Minute))
+	}
+	if !opts.Follow {
+		sse.send("end", map[string]any{})
+		return
+	}
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+	for i := 0; ; i++ {
+		select {
+		case <-r.Context().Done():
+			return
+		case t := <-ticker.C:
+			if send(i, t.UTC()) != nil {
+				return
+			}
+		}
+	}
+}
+
 // demoProcesses returns realistic demo process data.
 func (s *Server) demoProcesses(w http.ResponseWriter, r *http.Request) {
 	name := demoServerName(r)
@@ -176,35 +450,200 @@ func (s *Server) demoAlerts(w http.ResponseWriter, r *http.Request) {
 	switch name {
 	case "":
 		writeJSON(w, map[string]any{
+			"status": "warning",
 			"cpu":    map[string]any{"status": "ok", "current": 23.4, "threshold": 90.0},
 			"memory": map[string]any{"status": "ok", "current": 38.8, "threshold": 85.0},
 			"disks": []map[string]any{
 				{"mount": "/", "status": "ok", "current": 37.5, "threshold": 90.0},
 				{"mount": "/mnt/data", "status": "warning", "current": 87.0, "threshold": 90.0},
 			},
+			"inodes": []map[string]any{
+				{"mount": "/", "status": "ok", "current": 3.8, "threshold": 90.0},
+				{"mount": "/mnt/data", "status": "ok", "current": 79.0, "threshold": 90.0},
+			},
+			"network": []map[string]any{
+				{"interface": "eth0", "metric": "utilization", "status": "ok", "current": 3.77, "threshold": 90.0},
+				{"interface": "eth0", "metric": "errors", "status": "ok", "current": 0.0, "threshold": 10.0},
+			},
 		})
 	case "nas-box":
 		writeJSON(w, map[string]any{
+			"status": "warning",
 			"cpu":    map[string]any{"status": "ok", "current": 5.2, "threshold": 90.0},
 			"memory": map[string]any{"status": "ok", "current": 42.5, "threshold": 85.0},
 			"disks": []map[string]any{
 				{"mount": "/", "status": "ok", "current": 26.7, "threshold": 90.0},
 				{"mount": "/mnt/storage", "status": "warning", "current": 62.0, "threshold": 70.0},
 			},
+			"containers": []map[string]any{
+				{"container": "samba", "rule": "running", "status": "ok", "message": "samba is running"},
+				{"container": "plex", "rule": "restarts", "status": "critical", "message": "plex exited 3 times in 10 minutes", "current": 3.0, "threshold": 3.0, "silenced": true},
+			},
 		})
 	case "raspberry-pi":
 		writeJSON(w, map[string]any{
+			"status": "ok",
 			"cpu":    map[string]any{"status": "ok", "current": 12.1, "threshold": 90.0},
 			"memory": map[string]any{"status": "ok", "current": 52.5, "threshold": 85.0},
 			"disks": []map[string]any{
 				{"mount": "/", "status": "ok", "current": 28.1, "threshold": 90.0},
 			},
+			"temperatures": []map[string]any{
+				{"sensor": "cpu_thermal/temp1", "status": "ok", "current": 61.3, "threshold": 80.0},
+			},
 		})
 	default:
 		demoOfflineError(w, name)
 	}
 }
 
+// demoSilences returns demo silences: nas-box has its plex restart loop
+// silenced.
+func (s *Server) demoSilences(w http.ResponseWriter, r *http.Request) {
+	name := demoServerName(r)
+
+	switch name {
+	case "", "raspberry-pi":
+		writeJSON(w, []map[string]any{})
+	case "nas-box":
+		writeJSON(w, []map[string]any{
+			{"id": "3f9c2a1b", "metrics": []string{"container:restarts:plex"}, "reason": "waiting for the transcoder fix", "start": "2026-02-27T09:00:00Z", "end": "2026-02-28T09:00:00Z"},
+		})
+	default:
+		demoOfflineError(w, name)
+	}
+}
+
+func (s *Server) demoSilenceCreate(w http.ResponseWriter, r *http.Request) {
+	metrics, d, reason, err := silenceQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	writeJSON(w, map[string]any{"id": "d3m0a1b2", "metrics": metrics, "reason": reason, "start": now, "end": now.Add(d)})
+}
+
+func (s *Server) demoSilenceExpire(w http.ResponseWriter, r *http.Request) {
+	now := time.Now().UTC().Truncate(time.Second)
+	writeJSON(w, map[string]any{"id": r.PathValue("id"), "start": now.Add(-time.Hour), "end": now})
+}
+
+// demoAlertHistory returns a demo alert history: the nas-box disk filling
+// up over the last days.
+func (s *Server) demoAlertHistory(w http.ResponseWriter, r *http.Request) {
+	q, err := historyQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	now := time.Now().UTC().Truncate(time.Minute)
+	var entries []alerts.HistoryEntry
+	for i, v := range []float64{86, 88, 91, 93, 92, 89} {
+		status := "warning"
+		if v >= 90 {
+			status = "critical"
+		}
+		entries = append(entries, alerts.HistoryEntry{
+			Time: now.Add(time.Duration(i-6) * 6 * time.Hour), Server: "nas-box", Metric: "disk:/mnt/data", Label: "Disk /mnt/data",
+			Status: status, Value: v, Threshold: 90, Unit: "%",
+		})
+	}
+	entries = append(entries, alerts.HistoryEntry{
+		Time: now.Add(-3 * time.Hour), Server: "homelab-server", Metric: "cpu", Label: "CPU", Status: "warning", Value: 83.5, Threshold: 90, Unit: "%",
+	})
+
+	slices.SortFunc(entries, func(a, b alerts.HistoryEntry) int { return a.Time.Compare(b.Time) })
+	selected := []alerts.HistoryEntry{}
+	for _, e := range entries {
+		if q.Matches(e) {
+			selected = append(selected, e)
+		}
+	}
+	writeJSON(w, &alerts.History{Since: q.Since, Entries: selected, Summary: alerts.Summarize(selected)})
+}
+
+// demoMetricsHistory returns a demo metrics history: the demo status of
+// each server following a daily cycle, with the data disks slowly filling.
+func (s *Server) demoMetricsHistory(w http.ResponseWriter, r *http.Request) {
+	server, metric, from, err := metricsQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if server == "" {
+		server = "homelab-server"
+	}
+	now := time.Now().UTC().Truncate(time.Minute)
+	if oldest := now.Add(-30 * 24 * time.Hour); from.Before(oldest) {
+		from = oldest
+	}
+	step, resolution := time.Minute, "1m"
+	if from.Before(now.Add(-24 * time.Hour)) {
+		step, resolution = time.Hour, "1h"
+	}
+
+	var base history.Sample
+	switch server {
+	case "homelab-server":
+		base = history.Sample{CPU: 23.4, Memory: 38.8, Swap: 3.12, Load: 1.42, Disks: map[string]float64{"/": 37.5, "/mnt/data": 87}, NetRx: 4718592, NetTx: 1153434}
+	case "nas-box":
+		base = history.Sample{CPU: 5.2, Memory: 42.5, Load: 0.31, Disks: map[string]float64{"/": 26.7, "/mnt/storage": 62}, NetRx: 1048576, NetTx: 209715}
+	}
+	var samples []history.Sample
+	if base.Disks != nil {
+		for t := from.UTC().Truncate(step).Add(step); !t.After(now); t = t.Add(step) {
+			day := math.Sin(2 * math.Pi * float64(t.Hour()*60+t.Minute()) / (24 * 60))
+			ago := now.Sub(t).Hours() / 24
+			sample := history.Sample{
+				Time: t, CPU: base.CPU * (1 + 0.4*day), Memory: base.Memory * (1 + 0.05*day), Swap: base.Swap,
+				Load: base.Load * (1 + 0.4*day), NetRx: base.NetRx * (1 + 0.6*day), NetTx: base.NetTx * (1 + 0.6*day),
+				Disks: map[string]float64{},
+			}
+			for mount, v := range base.Disks {
+				if mount != "/" {
+					v -= 0.3 * ago // filling up
+				}
+				sample.Disks[mount] = v
+			}
+			samples = append(samples, sample)
+		}
+	}
+	writeJSON(w, history.Select(server, metric, resolution, samples))
+}
+
+// demoMetrics serves /metrics from the demo status, containers and alerts
+// of a few servers, one of them offline.
+func (s *Server) demoMetrics(w http.ResponseWriter, r *http.Request) {
+	now := time.Now()
+	var snaps []exporter.Snapshot
+	for _, name := range []string{"homelab-server", "nas-box", "raspberry-pi", "backup-nas"} {
+		snap := exporter.Snapshot{Server: name, Time: now}
+		if snap.Err = demoRecord(s.demoStatus, name, &snap.Status); snap.Err != nil {
+			snaps = append(snaps, snap)
+			continue
+		}
+		var docker struct {
+			Containers []docker.Container `json:"containers"`
+		}
+		demoRecord(s.demoDocker, name, &docker)
+		snap.Containers = docker.Containers
+		demoRecord(s.demoAlerts, name, &snap.Alerts)
+		snaps = append(snaps, snap)
+	}
+	writeMetrics(w, snaps)
+}
+
+// demoRecord calls a demo handler for a server and decodes its response.
+func demoRecord(handler http.HandlerFunc, server string, v any) error {
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest("GET", "/?server="+server, nil))
+	if w.Code != http.StatusOK {
+		return fmt.Errorf("server %s is offline", server)
+	}
+	return json.Unmarshal(w.Body.Bytes(), v)
+}
+
 // demoPorts returns realistic demo ports data.
 func (s *Server) demoPorts(w http.ResponseWriter, r *http.Request) {
 	name := demoServerName(r)
@@ -237,6 +676,108 @@ func (s *Server) demoPorts(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// demoDockerAction simulates a container action.
+func (s *Server) demoDockerAction(w http.ResponseWriter, r *http.Request) {
+	action := r.PathValue("action")
+	if !dockerActions[action] {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("unknown docker action %q", action))
+		return
+	}
+	result := map[string]any{
+		"action":    action,
+		"container": r.PathValue("name"),
+		"status":    "ok",
+	}
+	if action == "kill" {
+		signal := strings.ToUpper(r.URL.Query().Get("signal"))
+		if signal == "" {
+			signal = "SIGKILL"
+		}
+		result["signal"] = signal
+	}
+	writeJSON(w, result)
+}
+
+// demoComposeStacks holds the demo compose projects per server, with the
+// containers (from demoDocker) that belong to each service.
+var demoComposeStacks = map[string][]map[string]any{
+	"": {
+		{"name": "monitoring", "status": "running", "running": 2, "total": 2, "services": []string{"grafana", "prometheus"}, "working_dir": "/opt/stacks/monitoring",
+			"containers": map[string]string{"grafana": "grafana", "prometheus": "prometheus"}},
+		{"name": "web", "status": "running", "running": 3, "total": 3, "services": []string{"cache", "db", "nginx"}, "working_dir": "/opt/stacks/web",
+			"containers": map[string]string{"nginx": "nginx", "postgres": "db", "redis": "cache"}},
+	},
+	"nas-box": {
+		{"name": "nas", "status": "running", "running": 2, "total": 2, "services": []string{"plex", "samba"}, "working_dir": "/opt/stacks/nas",
+			"containers": map[string]string{"plex": "plex", "samba": "samba"}},
+	},
+	"raspberry-pi": {
+		{"name": "pihole", "status": "running", "running": 1, "total": 1, "services": []string{"pihole"}, "working_dir": "/home/pi/pihole",
+			"containers": map[string]string{"pihole": "pihole"}},
+	},
+}
+
+// demoCompose returns the demo compose projects.
+func (s *Server) demoCompose(w http.ResponseWriter, r *http.Request) {
+	name := demoServerName(r)
+	stacks, ok := demoComposeStacks[name]
+	if !ok {
+		demoOfflineError(w, name)
+		return
+	}
+	projects := make([]map[string]any, len(stacks))
+	for i, p := range stacks {
+		projects[i] = map[string]any{}
+		for k, v := range p {
+			if k != "containers" {
+				projects[i][k] = v
+			}
+		}
+	}
+	writeJSON(w, projects)
+}
+
+// demoComposeStack finds a demo project, or nil.
+func demoComposeStack(server, project string) map[string]any {
+	for _, p := range demoComposeStacks[server] {
+		if p["name"] == project {
+			return p
+		}
+	}
+	return nil
+}
+
+// demoComposePs returns the containers of a demo compose project.
+func (s *Server) demoComposePs(w http.ResponseWriter, r *http.Request) {
+	project := r.PathValue("project")
+	stack := demoComposeStack(demoServerName(r), project)
+	if stack == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("compose project %q not found", project))
+		return
+	}
+	containers := []map[string]any{}
+	for name, service := range stack["containers"].(map[string]string) {
+		containers = append(containers, map[string]any{
+			"name": name, "state": "running", "status": "Up 4 days", "project": project, "service": service,
+		})
+	}
+	writeJSON(w, containers)
+}
+
+// demoComposeAction simulates a compose action.
+func (s *Server) demoComposeAction(w http.ResponseWriter, r *http.Request) {
+	project, action := r.PathValue("project"), r.PathValue("action")
+	if !composeActions[action] {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("unknown compose action %q", action))
+		return
+	}
+	if demoComposeStack(demoServerName(r), project) == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("compose project %q not found", project))
+		return
+	}
+	writeJSON(w, map[string]any{"project": project, "action": action, "status": "ok"})
+}
+
 // demoWake returns realistic demo WoL targets.
 func (s *Server) demoWake(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, []map[string]any{
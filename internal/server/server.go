@@ -1,17 +1,24 @@
 package server
 
 import (
+	"bytes"
+	"context"
 	"embed"
 	"encoding/json"
 	"fmt"
 	"io/fs"
 	"log"
+	"mime"
 	"net/http"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/Higangssh/homebutler/internal/alerts"
 	"github.com/Higangssh/homebutler/internal/config"
 	"github.com/Higangssh/homebutler/internal/docker"
+	"github.com/Higangssh/homebutler/internal/exporter"
+	"github.com/Higangssh/homebutler/internal/history"
 	"github.com/Higangssh/homebutler/internal/ports"
 	"github.com/Higangssh/homebutler/internal/remote"
 	"github.com/Higangssh/homebutler/internal/system"
@@ -21,13 +28,19 @@ import (
 //go:embed all:web_dist
 var webFS embed.FS
 
+// scrapeTTL is how long /metrics reuses what it read from a server, so
+// several Prometheus servers or a short scrape interval don't reach every
+// server over SSH on each request.
+const scrapeTTL = 10 * time.Second
+
 // Server is the HTTP server for the homebutler web dashboard.
 type Server struct {
-	cfg  *config.Config
-	host string
-	port int
-	demo bool
-	mux  *http.ServeMux
+	cfg     *config.Config
+	host    string
+	port    int
+	demo    bool
+	mux     *http.ServeMux
+	scrapes *exporter.Cache
 }
 
 // New creates a new Server with the given config, host, and port.
@@ -36,7 +49,7 @@ func New(cfg *config.Config, host string, port int, demo ...bool) *Server {
 	if host == "" {
 		host = "127.0.0.1"
 	}
-	s := &Server{cfg: cfg, host: host, port: port, demo: d, mux: http.NewServeMux()}
+	s := &Server{cfg: cfg, host: host, port: port, demo: d, mux: http.NewServeMux(), scrapes: exporter.NewCache(scrapeTTL)}
 	s.routes()
 	return s
 }
@@ -54,6 +67,7 @@ func (s *Server) Run() error {
 		fmt.Printf("homebutler dashboard (DEMO MODE): %s\n", displayAddr)
 	} else {
 		fmt.Printf("homebutler dashboard: %s\n", displayAddr)
+		go s.collectHistory()
 	}
 	err := http.ListenAndServe(addr, s.mux)
 	if err != nil && strings.Contains(err.Error(), "address already in use") {
@@ -62,27 +76,77 @@ func (s *Server) Run() error {
 	return err
 }
 
+// collectHistory records the metrics history of every server each minute,
+// for the dashboard charts. A failing server is reported once.
+func (s *Server) collectHistory() {
+	servers := s.cfg.Monitored()
+	failing := make([]string, len(servers))
+	for {
+		for i, err := range history.Collect(history.DefaultDir(), servers, time.Now()) {
+			msg := ""
+			if err != nil {
+				msg = err.Error()
+			}
+			if msg != failing[i] && msg != "" {
+				fmt.Fprintf(os.Stderr, "warning: %s: metrics history: %s\n", servers[i].Name, msg)
+			}
+			failing[i] = msg
+		}
+		time.Sleep(time.Minute)
+	}
+}
+
 func (s *Server) routes() {
 	if s.demo {
 		s.mux.HandleFunc("GET /api/status", s.cors(s.demoStatus))
+		s.mux.HandleFunc("GET /api/sensors", s.cors(s.demoSensors))
 		s.mux.HandleFunc("GET /api/docker", s.cors(s.demoDocker))
+		s.mux.HandleFunc("GET /api/docker/updates", s.cors(s.demoDockerUpdates))
+		s.mux.HandleFunc("GET /api/docker/df", s.cors(s.demoDockerDiskUsage))
+		s.mux.HandleFunc("POST /api/docker/prune", s.cors(s.guard(s.demoDockerPrune)))
+		s.mux.HandleFunc("POST /api/docker/{name}/{action}", s.cors(s.guard(s.demoDockerAction)))
+		s.mux.HandleFunc("GET /api/docker/{name}/logs", s.cors(s.demoDockerLogs))
+		s.mux.HandleFunc("GET /api/compose", s.cors(s.demoCompose))
+		s.mux.HandleFunc("GET /api/compose/{project}", s.cors(s.demoComposePs))
+		s.mux.HandleFunc("POST /api/compose/{project}/{action}", s.cors(s.guard(s.demoComposeAction)))
 		s.mux.HandleFunc("GET /api/processes", s.cors(s.demoProcesses))
 		s.mux.HandleFunc("GET /api/alerts", s.cors(s.demoAlerts))
+		s.mux.HandleFunc("GET /api/alerts/silences", s.cors(s.demoSilences))
+		s.mux.HandleFunc("POST /api/alerts/silences", s.cors(s.guard(s.demoSilenceCreate)))
+		s.mux.HandleFunc("POST /api/alerts/silences/{id}/expire", s.cors(s.guard(s.demoSilenceExpire)))
+		s.mux.HandleFunc("GET /api/alerts/history", s.cors(s.demoAlertHistory))
+		s.mux.HandleFunc("GET /api/history", s.cors(s.demoMetricsHistory))
 		s.mux.HandleFunc("GET /api/ports", s.cors(s.demoPorts))
 		s.mux.HandleFunc("GET /api/wake", s.cors(s.demoWake))
 		s.mux.HandleFunc("POST /api/wake/{name}", s.cors(s.demoWakeSend))
 		s.mux.HandleFunc("GET /api/servers", s.cors(s.demoServers))
 		s.mux.HandleFunc("GET /api/servers/{name}/status", s.cors(s.demoServerStatus))
+		s.mux.HandleFunc("GET /metrics", s.demoMetrics)
 	} else {
 		s.mux.HandleFunc("GET /api/status", s.cors(s.handleStatus))
+		s.mux.HandleFunc("GET /api/sensors", s.cors(s.handleSensors))
 		s.mux.HandleFunc("GET /api/docker", s.cors(s.handleDocker))
+		s.mux.HandleFunc("GET /api/docker/updates", s.cors(s.handleDockerUpdates))
+		s.mux.HandleFunc("GET /api/docker/df", s.cors(s.handleDockerDiskUsage))
+		s.mux.HandleFunc("POST /api/docker/prune", s.cors(s.guard(s.handleDockerPrune)))
+		s.mux.HandleFunc("POST /api/docker/{name}/{action}", s.cors(s.guard(s.handleDockerAction)))
+		s.mux.HandleFunc("GET /api/docker/{name}/logs", s.cors(s.handleDockerLogs))
+		s.mux.HandleFunc("GET /api/compose", s.cors(s.handleCompose))
+		s.mux.HandleFunc("GET /api/compose/{project}", s.cors(s.handleComposePs))
+		s.mux.HandleFunc("POST /api/compose/{project}/{action}", s.cors(s.guard(s.handleComposeAction)))
 		s.mux.HandleFunc("GET /api/processes", s.cors(s.handleProcesses))
 		s.mux.HandleFunc("GET /api/alerts", s.cors(s.handleAlerts))
+		s.mux.HandleFunc("GET /api/alerts/silences", s.cors(s.handleSilences))
+		s.mux.HandleFunc("POST /api/alerts/silences", s.cors(s.guard(s.handleSilenceCreate)))
+		s.mux.HandleFunc("POST /api/alerts/silences/{id}/expire", s.cors(s.guard(s.handleSilenceExpire)))
+		s.mux.HandleFunc("GET /api/alerts/history", s.cors(s.handleAlertHistory))
+		s.mux.HandleFunc("GET /api/history", s.cors(s.handleMetricsHistory))
 		s.mux.HandleFunc("GET /api/ports", s.cors(s.handlePorts))
 		s.mux.HandleFunc("GET /api/wake", s.cors(s.handleWakeList))
 		s.mux.HandleFunc("POST /api/wake/{name}", s.cors(s.handleWakeSend))
 		s.mux.HandleFunc("GET /api/servers", s.cors(s.handleServers))
 		s.mux.HandleFunc("GET /api/servers/{name}/status", s.cors(s.handleServerStatus))
+		s.mux.HandleFunc("GET /metrics", s.handleMetrics)
 	}
 	s.mux.HandleFunc("OPTIONS /api/", s.handleOptions)
 
@@ -92,14 +156,44 @@ func (s *Server) routes() {
 
 func (s *Server) cors(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		origin := r.Header.Get("Origin")
-		if origin != "" {
-			allowed := fmt.Sprintf("http://%s:%d", s.host, s.port)
-			if origin == allowed || origin == fmt.Sprintf("http://localhost:%d", s.port) || origin == fmt.Sprintf("http://127.0.0.1:%d", s.port) {
-				w.Header().Set("Access-Control-Allow-Origin", origin)
-				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-				w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-			}
+		s.setCORS(w, r)
+		next(w, r)
+	}
+}
+
+// allowedOrigin reports whether origin is the dashboard itself.
+func (s *Server) allowedOrigin(origin string) bool {
+	return origin == fmt.Sprintf("http://%s:%d", s.host, s.port) ||
+		origin == fmt.Sprintf("http://localhost:%d", s.port) ||
+		origin == fmt.Sprintf("http://127.0.0.1:%d", s.port)
+}
+
+func (s *Server) setCORS(w http.ResponseWriter, r *http.Request) {
+	if origin := r.Header.Get("Origin"); origin != "" && s.allowedOrigin(origin) {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, "+guardHeader)
+	}
+}
+
+// guardHeader must be on requests that change something, unless they are
+// JSON. Either makes a browser send a preflight first, which cors only
+// answers for the dashboard's own origins.
+const guardHeader = "X-Homebutler"
+
+// guard refuses requests that change something when another site's page
+// may have sent them: a foreign Origin, or a form-like request that needs
+// no preflight.
+func (s *Server) guard(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if origin := r.Header.Get("Origin"); origin != "" && !s.allowedOrigin(origin) {
+			writeError(w, http.StatusForbidden, "cross-origin request refused")
+			return
+		}
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if mediaType != "application/json" && r.Header.Get(guardHeader) == "" {
+			writeError(w, http.StatusForbidden, "missing "+guardHeader+" header")
+			return
 		}
 		next(w, r)
 	}
@@ -136,15 +230,7 @@ func (s *Server) forwardRemote(w http.ResponseWriter, srv *config.ServerConfig,
 }
 
 func (s *Server) handleOptions(w http.ResponseWriter, r *http.Request) {
-	origin := r.Header.Get("Origin")
-	if origin != "" {
-		allowed := fmt.Sprintf("http://%s:%d", s.host, s.port)
-		if origin == allowed || origin == fmt.Sprintf("http://localhost:%d", s.port) || origin == fmt.Sprintf("http://127.0.0.1:%d", s.port) {
-			w.Header().Set("Access-Control-Allow-Origin", origin)
-			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-		}
-	}
+	s.setCORS(w, r)
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -161,6 +247,20 @@ func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, info)
 }
 
+func (s *Server) handleSensors(w http.ResponseWriter, r *http.Request) {
+	if srv, ok := s.isRemoteRequest(r); ok {
+		s.forwardRemote(w, srv, "sensors", "--json")
+		return
+	}
+	sensors, err := system.Sensors()
+	if err != nil {
+		// No sensor support (e.g. macOS): report an empty set, like docker does
+		writeJSON(w, &system.SensorsInfo{Temperatures: []system.TempSensor{}, Fans: []system.FanSensor{}})
+		return
+	}
+	writeJSON(w, sensors)
+}
+
 func (s *Server) handleDocker(w http.ResponseWriter, r *http.Request) {
 	if srv, ok := s.isRemoteRequest(r); ok {
 		s.forwardRemote(w, srv, "docker", "list", "--json")
@@ -169,9 +269,16 @@ func (s *Server) handleDocker(w http.ResponseWriter, r *http.Request) {
 	containers, err := docker.List()
 	if err != nil {
 		// Return empty list with unavailable status instead of raw error
+		message := "Docker is not available"
+		switch docker.ClassifyError(err) {
+		case "not_installed":
+			message = "No Docker or Podman socket found"
+		case "permission_denied":
+			message = "Permission denied on the Docker socket"
+		}
 		writeJSON(w, map[string]any{
 			"available":  false,
-			"message":    "Docker is not available",
+			"message":    message,
 			"containers": []any{},
 		})
 		return
@@ -182,6 +289,306 @@ func (s *Server) handleDocker(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// handleDockerUpdates checks running containers against their registries.
+// It can take a few seconds, so the dashboard only calls it on demand.
+func (s *Server) handleDockerUpdates(w http.ResponseWriter, r *http.Request) {
+	if srv, ok := s.isRemoteRequest(r); ok {
+		s.forwardRemote(w, srv, "docker", "updates", "--json")
+		return
+	}
+	updates, err := docker.Updates()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, updates)
+}
+
+// handleDockerDiskUsage reports the space used by images, containers,
+// volumes and build cache.
+func (s *Server) handleDockerDiskUsage(w http.ResponseWriter, r *http.Request) {
+	if srv, ok := s.isRemoteRequest(r); ok {
+		s.forwardRemote(w, srv, "docker", "df", "--json")
+		return
+	}
+	du, err := docker.DiskUsage()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, du)
+}
+
+// pruneQuery reads prune options from the query string: dry_run=true,
+// older_than=7d, all=true and volumes=true.
+func pruneQuery(r *http.Request) (docker.PruneOptions, error) {
+	q := r.URL.Query()
+	opts := docker.PruneOptions{
+		DryRun:  q.Get("dry_run") == "true",
+		All:     q.Get("all") == "true",
+		Volumes: q.Get("volumes") == "true",
+	}
+	if v := q.Get("older_than"); v != "" {
+		age, err := docker.ParseAge(v)
+		if err != nil {
+			return opts, err
+		}
+		opts.OlderThan = age
+	}
+	return opts, nil
+}
+
+// handleDockerPrune removes stopped containers, dangling images and unused
+// build cache, or with ?dry_run=true lists what would go.
+func (s *Server) handleDockerPrune(w http.ResponseWriter, r *http.Request) {
+	opts, err := pruneQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if srv, ok := s.isRemoteRequest(r); ok {
+		args := []string{"docker", "prune", "--json"}
+		if opts.DryRun {
+			args = append(args, "--dry-run")
+		}
+		if opts.OlderThan > 0 {
+			args = append(args, "--older-than", opts.OlderThan.String())
+		}
+		if opts.All {
+			args = append(args, "--all-images")
+		}
+		if opts.Volumes {
+			args = append(args, "--volumes")
+		}
+		s.forwardRemote(w, srv, args...)
+		return
+	}
+	result, err := docker.Prune(opts)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, result)
+}
+
+// logQuery reads log options from the query string: tail, since, grep and
+// follow=1. Without tail or since it returns the last 100 lines.
+func logQuery(r *http.Request) docker.LogOptions {
+	q := r.URL.Query()
+	opts := docker.LogOptions{
+		Tail:   q.Get("tail"),
+		Since:  q.Get("since"),
+		Grep:   q.Get("grep"),
+		Follow: q.Get("follow") == "1" || q.Get("follow") == "true",
+	}
+	if opts.Tail == "" && opts.Since == "" {
+		opts.Tail = "100"
+	}
+	return opts
+}
+
+// handleDockerLogs streams container logs as server-sent events: one
+// message per line (a docker.LogLine), then an "end" event, or an "error"
+// event if the stream fails. With follow=1 it runs until the client leaves.
+func (s *Server) handleDockerLogs(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	opts := logQuery(r)
+	if err := docker.ValidateName(name); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := docker.ValidateLogOptions(opts); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	sse, ok := newSSEWriter(w)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+
+	var err error
+	if srv, ok := s.isRemoteRequest(r); ok {
+		err = streamRemoteLogs(r.Context(), sse, srv, name, opts)
+	} else {
+		err = docker.StreamLogs(r.Context(), name, opts, func(l docker.LogLine) error {
+			return sse.send("", l)
+		})
+	}
+	if r.Context().Err() != nil {
+		return
+	}
+	if err != nil {
+		sse.send("error", map[string]string{"error": err.Error()})
+		return
+	}
+	sse.send("end", map[string]any{})
+}
+
+// streamRemoteLogs relays logs from a remote homebutler. Following uses an
+// SSH stream of NDJSON lines; a snapshot is one `docker logs --json` call.
+func streamRemoteLogs(ctx context.Context, sse *sseWriter, srv *config.ServerConfig, name string, opts docker.LogOptions) error {
+	args := []string{"docker", "logs", name}
+	if opts.Tail != "" {
+		args = append(args, opts.Tail)
+	}
+	args = append(args, "--json")
+	if opts.Since != "" {
+		args = append(args, "--since", opts.Since)
+	}
+	if opts.Grep != "" {
+		args = append(args, "--grep", opts.Grep)
+	}
+	if opts.Follow {
+		return remote.Stream(ctx, srv, &ndjsonEvents{sse: sse}, append(args, "--follow")...)
+	}
+
+	out, err := remote.Run(srv, args...)
+	if err != nil {
+		return err
+	}
+	var result docker.LogsResult
+	if err := json.Unmarshal(out, &result); err != nil {
+		return fmt.Errorf("invalid response from remote server")
+	}
+	for _, l := range result.Entries {
+		if err := sse.send("", l); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dockerActions are the container actions accepted by POST /api/docker/{name}/{action}.
+var dockerActions = map[string]bool{
+	"start": true, "stop": true, "restart": true, "pause": true, "unpause": true, "kill": true, "rm": true,
+}
+
+// handleDockerAction runs a container action. kill takes ?signal=, rm takes ?force=true.
+func (s *Server) handleDockerAction(w http.ResponseWriter, r *http.Request) {
+	name, action := r.PathValue("name"), r.PathValue("action")
+	if !dockerActions[action] {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("unknown docker action %q", action))
+		return
+	}
+	if err := docker.ValidateName(name); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	signal := r.URL.Query().Get("signal")
+	if signal != "" {
+		if err := docker.ValidateSignal(signal); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	force := r.URL.Query().Get("force") == "true"
+
+	if srv, ok := s.isRemoteRequest(r); ok {
+		args := []string{"docker", action, name, "--json"}
+		if action == "kill" && signal != "" {
+			args = append(args, "--signal", signal)
+		}
+		if action == "rm" && force {
+			args = append(args, "--force")
+		}
+		s.forwardRemote(w, srv, args...)
+		return
+	}
+
+	var result *docker.ActionResult
+	var err error
+	switch action {
+	case "start":
+		result, err = docker.Start(name)
+	case "stop":
+		result, err = docker.Stop(name)
+	case "restart":
+		result, err = docker.Restart(name)
+	case "pause":
+		result, err = docker.Pause(name)
+	case "unpause":
+		result, err = docker.Unpause(name)
+	case "kill":
+		result, err = docker.Kill(name, signal)
+	case "rm":
+		result, err = docker.Remove(name, force)
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, result)
+}
+
+func (s *Server) handleCompose(w http.ResponseWriter, r *http.Request) {
+	if srv, ok := s.isRemoteRequest(r); ok {
+		s.forwardRemote(w, srv, "compose", "ls", "--json")
+		return
+	}
+	projects, err := docker.Projects()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, projects)
+}
+
+func (s *Server) handleComposePs(w http.ResponseWriter, r *http.Request) {
+	project := r.PathValue("project")
+	if err := docker.ValidateName(project); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if srv, ok := s.isRemoteRequest(r); ok {
+		s.forwardRemote(w, srv, "compose", "ps", project, "--json")
+		return
+	}
+	containers, err := docker.ProjectContainers(project)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, containers)
+}
+
+// composeActions are the actions accepted by POST /api/compose/{project}/{action}.
+var composeActions = map[string]bool{"restart": true, "up": true, "down": true, "pull": true}
+
+func (s *Server) handleComposeAction(w http.ResponseWriter, r *http.Request) {
+	project, action := r.PathValue("project"), r.PathValue("action")
+	if !composeActions[action] {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("unknown compose action %q", action))
+		return
+	}
+	if err := docker.ValidateName(project); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if srv, ok := s.isRemoteRequest(r); ok {
+		s.forwardRemote(w, srv, "compose", action, project, "--json")
+		return
+	}
+
+	var result *docker.ComposeResult
+	var err error
+	switch action {
+	case "restart":
+		result, err = docker.ComposeRestart(project)
+	case "up":
+		result, err = docker.ComposeUp(project)
+	case "down":
+		result, err = docker.ComposeDown(project)
+	case "pull":
+		result, err = docker.ComposePull(project)
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, result)
+}
+
 func (s *Server) handleProcesses(w http.ResponseWriter, r *http.Request) {
 	if srv, ok := s.isRemoteRequest(r); ok {
 		s.forwardRemote(w, srv, "processes", "--json")
@@ -197,10 +604,15 @@ func (s *Server) handleProcesses(w http.ResponseWriter, r *http.Request) {
 
 func (s *Server) handleAlerts(w http.ResponseWriter, r *http.Request) {
 	if srv, ok := s.isRemoteRequest(r); ok {
-		s.forwardRemote(w, srv, "alerts", "--json")
+		result, err := alerts.Evaluate(s.cfg, srv)
+		if err != nil {
+			writeError(w, http.StatusBadGateway, err.Error())
+			return
+		}
+		writeJSON(w, result)
 		return
 	}
-	result, err := alerts.Check(&s.cfg.Alerts)
+	result, err := alerts.Evaluate(s.cfg, nil)
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -208,6 +620,163 @@ func (s *Server) handleAlerts(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, result)
 }
 
+func (s *Server) handleSilences(w http.ResponseWriter, r *http.Request) {
+	if srv, ok := s.isRemoteRequest(r); ok {
+		s.forwardRemote(w, srv, "alerts", "silence", "list", "--json")
+		return
+	}
+	silences, err := alerts.ActiveSilences(alerts.DefaultSilencePath(), s.cfg.Alerts.Maintenance, time.Now())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, silences)
+}
+
+// silenceQuery reads a new silence from the query string: for=4h
+// (required), metric=disk,cpu (default: all) and reason.
+func silenceQuery(r *http.Request) (metrics []string, d time.Duration, reason string, err error) {
+	q := r.URL.Query()
+	if q.Get("for") == "" {
+		return nil, 0, "", fmt.Errorf("missing for (e.g. for=4h)")
+	}
+	d, err = docker.ParseAge(q.Get("for"))
+	if err != nil {
+		return nil, 0, "", err
+	}
+	for _, m := range strings.Split(q.Get("metric"), ",") {
+		if m = strings.TrimSpace(m); m != "" {
+			metrics = append(metrics, m)
+		}
+	}
+	return metrics, d, q.Get("reason"), nil
+}
+
+func (s *Server) handleSilenceCreate(w http.ResponseWriter, r *http.Request) {
+	metrics, d, reason, err := silenceQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if srv, ok := s.isRemoteRequest(r); ok {
+		args := []string{"alerts", "silence", "--json", "--for", d.String()}
+		if len(metrics) > 0 {
+			args = append(args, "--metric", strings.Join(metrics, ","))
+		}
+		if reason != "" {
+			args = append(args, "--reason", reason)
+		}
+		s.forwardRemote(w, srv, args...)
+		return
+	}
+	silence, err := alerts.AddSilence(alerts.DefaultSilencePath(), metrics, reason, d, time.Now())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, silence)
+}
+
+func (s *Server) handleSilenceExpire(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if err := alerts.ValidateSilenceID(id); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if srv, ok := s.isRemoteRequest(r); ok {
+		s.forwardRemote(w, srv, "alerts", "silence", "expire", id, "--json")
+		return
+	}
+	silence, err := alerts.ExpireSilence(alerts.DefaultSilencePath(), id, time.Now())
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeJSON(w, silence)
+}
+
+// historyQuery parses ?since=7d&server=nas&metric=disk. The history of
+// every server is kept here, so server filters rather than forwards.
+func historyQuery(r *http.Request) (alerts.HistoryQuery, error) {
+	q := r.URL.Query()
+	since := q.Get("since")
+	if since == "" {
+		since = "24h"
+	}
+	d, err := docker.ParseAge(since)
+	if err != nil {
+		return alerts.HistoryQuery{}, err
+	}
+	return alerts.HistoryQuery{Since: time.Now().Add(-d), Server: q.Get("server"), Metric: q.Get("metric")}, nil
+}
+
+func (s *Server) handleAlertHistory(w http.ResponseWriter, r *http.Request) {
+	q, err := historyQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	h, err := alerts.QueryHistory(alerts.DefaultHistoryPath(), q)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, h)
+}
+
+// metricsQuery reads ?metric=cpu&since=24h&server=nas. The server is ""
+// when not given.
+func metricsQuery(r *http.Request) (server, metric string, from time.Time, err error) {
+	q := r.URL.Query()
+	metric = q.Get("metric")
+	if metric == "" {
+		return "", "", time.Time{}, fmt.Errorf("missing metric (use %s, or disk:<mount>)", strings.Join(history.Metrics, ", "))
+	}
+	if !history.ValidMetric(metric) {
+		return "", "", time.Time{}, fmt.Errorf("unknown metric %q", metric)
+	}
+	since := q.Get("since")
+	if since == "" {
+		since = "24h"
+	}
+	d, err := docker.ParseAge(since)
+	if err != nil {
+		return "", "", time.Time{}, err
+	}
+	return q.Get("server"), metric, time.Now().Add(-d), nil
+}
+
+// handleMetricsHistory serves the metrics history this dashboard records.
+// Like the alert history, the server parameter selects from it rather than
+// asking the server.
+func (s *Server) handleMetricsHistory(w http.ResponseWriter, r *http.Request) {
+	server, metric, from, err := metricsQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if server == "" {
+		server = s.cfg.LocalName()
+	}
+	series, err := history.Query(history.DefaultDir(), server, metric, from, time.Now())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, series)
+}
+
+// handleMetrics is the Prometheus scrape endpoint: the status, containers
+// and alerts of every configured server, labelled by server.
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	writeMetrics(w, s.scrapes.Snapshots(s.cfg, s.cfg.Monitored(), time.Now()))
+}
+
+func writeMetrics(w http.ResponseWriter, snaps []exporter.Snapshot) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	exporter.WritePrometheus(w, snaps)
+}
+
 func (s *Server) handlePorts(w http.ResponseWriter, r *http.Request) {
 	if srv, ok := s.isRemoteRequest(r); ok {
 		s.forwardRemote(w, srv, "ports", "--json")
@@ -365,6 +934,71 @@ func writeError(w http.ResponseWriter, code int, msg string) {
 	json.NewEncoder(w).Encode(map[string]string{"error": msg})
 }
 
+// sseWriter writes server-sent events, flushing after each one.
+type sseWriter struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+}
+
+func newSSEWriter(w http.ResponseWriter) (*sseWriter, bool) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return nil, false
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	return &sseWriter{w: w, flusher: flusher}, true
+}
+
+// send writes data as JSON. An empty event name makes it a plain message,
+// which EventSource delivers to onmessage.
+func (s *sseWriter) send(event string, data any) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return s.sendRaw(event, b)
+}
+
+func (s *sseWriter) sendRaw(event string, data []byte) error {
+	if event != "" {
+		if _, err := fmt.Fprintf(s.w, "event: %s\n", event); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(s.w, "data: %s\n\n", data); err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	return nil
+}
+
+// ndjsonEvents turns NDJSON written by a remote command into one message
+// per line.
+type ndjsonEvents struct {
+	sse *sseWriter
+	buf []byte
+}
+
+func (n *ndjsonEvents) Write(p []byte) (int, error) {
+	n.buf = append(n.buf, p...)
+	for {
+		i := bytes.IndexByte(n.buf, '\n')
+		if i < 0 {
+			break
+		}
+		if line := bytes.TrimSpace(n.buf[:i]); len(line) > 0 {
+			if err := n.sse.sendRaw("", line); err != nil {
+				return 0, err
+			}
+		}
+		n.buf = n.buf[i+1:]
+	}
+	return len(p), nil
+}
+
 const fallbackHTML = `<!DOCTYPE html>
 <html>
 <head><title>homebutler</title></head>
@@ -4,9 +4,13 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/Higangssh/homebutler/internal/alerts"
 	"github.com/Higangssh/homebutler/internal/config"
+	"github.com/Higangssh/homebutler/internal/history"
 )
 
 func testServer() *Server {
@@ -18,14 +22,14 @@ func testServer() *Server {
 		Wake: []config.WakeTarget{
 			{Name: "test-pc", MAC: "AA:BB:CC:DD:EE:FF"},
 		},
-		Alerts: config.AlertConfig{CPU: 90, Memory: 85, Disk: 90},
+		Alerts: config.AlertConfig{CPU: config.Threshold{Critical: 90}, Memory: config.Threshold{Critical: 85}, Disk: config.Threshold{Critical: 90}},
 	}
 	return New(cfg, "127.0.0.1", 8080)
 }
 
 func testDemoServer() *Server {
 	cfg := &config.Config{
-		Alerts: config.AlertConfig{CPU: 90, Memory: 85, Disk: 90},
+		Alerts: config.AlertConfig{CPU: config.Threshold{Critical: 90}, Memory: config.Threshold{Critical: 85}, Disk: config.Threshold{Critical: 90}},
 	}
 	return New(cfg, "127.0.0.1", 8080, true)
 }
@@ -55,6 +59,28 @@ func TestStatusEndpoint(t *testing.T) {
 	}
 }
 
+func TestSensorsEndpoint(t *testing.T) {
+	srv := testServer()
+	req := httptest.NewRequest("GET", "/api/sensors", nil)
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if _, ok := result["temperatures"].([]any); !ok {
+		t.Fatal("expected temperatures array (possibly empty)")
+	}
+	if _, ok := result["fans"].([]any); !ok {
+		t.Fatal("expected fans array (possibly empty)")
+	}
+}
+
 func TestProcessesEndpoint(t *testing.T) {
 	srv := testServer()
 	req := httptest.NewRequest("GET", "/api/processes", nil)
@@ -99,6 +125,127 @@ func TestAlertsEndpoint(t *testing.T) {
 	}
 }
 
+func TestAlertHistoryEndpoint(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	now := time.Now()
+	disk := []alerts.Metric{{Key: "disk:/mnt/data", Label: "Disk /mnt/data", Status: "critical", Current: 97, Threshold: 90, Unit: "%"}}
+	alerts.RecordHistory(alerts.DefaultHistoryPath(), "nas", disk, now.Add(-48*time.Hour), 30*24*time.Hour)
+	alerts.RecordHistory(alerts.DefaultHistoryPath(), "nas", disk, now.Add(-time.Hour), 30*24*time.Hour)
+	alerts.RecordHistory(alerts.DefaultHistoryPath(), "rpi", disk, now, 30*24*time.Hour)
+	srv := testServer()
+
+	get := func(url string) (int, alerts.History) {
+		w := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(w, httptest.NewRequest("GET", url, nil))
+		var h alerts.History
+		json.Unmarshal(w.Body.Bytes(), &h)
+		return w.Code, h
+	}
+	if code, h := get("/api/alerts/history"); code != http.StatusOK || len(h.Entries) != 2 {
+		t.Errorf("default since 24h: expected 2 entries, got %d %+v", code, h.Entries)
+	}
+	// server filters the history kept here rather than forwarding
+	if _, h := get("/api/alerts/history?since=7d&server=nas&metric=disk"); len(h.Entries) != 2 || len(h.Summary) != 1 || h.Summary[0].Critical != 2 {
+		t.Errorf("unexpected nas history: %+v", h)
+	}
+	if code, _ := get("/api/alerts/history?since=soon"); code != http.StatusBadRequest {
+		t.Errorf("invalid since: expected 400, got %d", code)
+	}
+}
+
+func TestMetricsHistoryEndpoint(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	now := time.Now()
+	for i, cpu := range []float64{10, 30, 20} {
+		history.Record(history.DefaultDir(), "nas", history.Sample{Time: now.Add(time.Duration(i-3) * time.Minute), CPU: cpu})
+	}
+	srv := testServer()
+
+	get := func(url string) (int, []history.Series) {
+		w := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(w, httptest.NewRequest("GET", url, nil))
+		var series []history.Series
+		json.Unmarshal(w.Body.Bytes(), &series)
+		return w.Code, series
+	}
+	code, series := get("/api/history?metric=cpu&server=nas&since=1h")
+	if code != http.StatusOK || len(series) != 1 || len(series[0].Points) != 3 || series[0].Max != 30 || series[0].Last != 20 {
+		t.Errorf("unexpected nas cpu history: %d %+v", code, series)
+	}
+	for _, url := range []string{"/api/history", "/api/history?metric=temperature", "/api/history?metric=cpu&since=soon"} {
+		if code, _ := get(url); code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", url, code)
+		}
+	}
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	srv := New(&config.Config{Servers: []config.ServerConfig{{Name: "myserver", Local: true}}}, "127.0.0.1", 8080)
+
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/plain; version=0.0.4") {
+		t.Fatalf("unexpected response: %d %s", w.Code, w.Header().Get("Content-Type"))
+	}
+	body := w.Body.String()
+	for _, want := range []string{"# TYPE homebutler_up gauge", `homebutler_up{server="myserver"} 1`, `homebutler_cpu_cores{server="myserver"}`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q in /metrics:\n%s", want, body)
+		}
+	}
+}
+
+func TestSilenceEndpoints(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	srv := testServer()
+	do := func(method, url string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, nil)
+		if method == "POST" {
+			req = post(url)
+		}
+		w := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("POST", "/api/alerts/silences?for=4h&metric=disk,cpu&reason=rebuilding+array")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var created struct {
+		ID      string   `json:"id"`
+		Metrics []string `json:"metrics"`
+		Reason  string   `json:"reason"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &created)
+	if created.ID == "" || len(created.Metrics) != 2 || created.Reason != "rebuilding array" {
+		t.Fatalf("unexpected silence: %s", w.Body.String())
+	}
+
+	var list []map[string]any
+	json.Unmarshal(do("GET", "/api/alerts/silences").Body.Bytes(), &list)
+	if len(list) != 1 || list[0]["id"] != created.ID {
+		t.Fatalf("expected the new silence in the list, got %v", list)
+	}
+
+	if w := do("POST", "/api/alerts/silences/"+created.ID+"/expire"); w.Code != http.StatusOK {
+		t.Fatalf("expire: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := do("POST", "/api/alerts/silences/"+created.ID+"/expire"); w.Code != http.StatusNotFound {
+		t.Errorf("expiring twice: expected 404, got %d", w.Code)
+	}
+	if w := do("GET", "/api/alerts/silences"); strings.TrimSpace(w.Body.String()) != "[]" {
+		t.Errorf("expected an empty list, got %s", w.Body.String())
+	}
+	if w := do("POST", "/api/alerts/silences?metric=disk"); w.Code != http.StatusBadRequest {
+		t.Errorf("missing for: expected 400, got %d", w.Code)
+	}
+	if w := do("POST", "/api/alerts/silences/a%3Bb/expire"); w.Code != http.StatusBadRequest {
+		t.Errorf("invalid id: expected 400, got %d", w.Code)
+	}
+}
+
 func TestServersEndpoint(t *testing.T) {
 	srv := testServer()
 	req := httptest.NewRequest("GET", "/api/servers", nil)
@@ -204,6 +351,113 @@ func TestDockerEndpointReturnsJSON(t *testing.T) {
 	}
 }
 
+// post is a request from the dashboard to an endpoint that changes
+// something.
+func post(path string) *http.Request {
+	req := httptest.NewRequest("POST", path, nil)
+	req.Header.Set(guardHeader, "1")
+	return req
+}
+
+func TestGuardRefusesCrossSiteRequests(t *testing.T) {
+	srv := testDemoServer()
+	for _, path := range []string{
+		"/api/docker/db/rm?force=true",
+		"/api/docker/prune?volumes=true&all=true",
+		"/api/compose/monitoring/down",
+		"/api/alerts/silences?for=2h",
+		"/api/alerts/silences/abc123/expire",
+	} {
+		// a page elsewhere: a no-cors POST carries its own Origin
+		req := post(path)
+		req.Header.Set("Origin", "http://evil.com")
+		w := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(w, req)
+		if w.Code != http.StatusForbidden {
+			t.Errorf("POST %s from a foreign origin: expected 403, got %d", path, w.Code)
+		}
+
+		// a simple request, which needs no preflight
+		req = httptest.NewRequest("POST", path, strings.NewReader("force=true"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w = httptest.NewRecorder()
+		srv.Handler().ServeHTTP(w, req)
+		if w.Code != http.StatusForbidden {
+			t.Errorf("POST %s without the header: expected 403, got %d", path, w.Code)
+		}
+
+		// the dashboard itself
+		req = httptest.NewRequest("POST", path, nil)
+		req.Header.Set("Origin", "http://127.0.0.1:8080")
+		req.Header.Set("Content-Type", "application/json")
+		w = httptest.NewRecorder()
+		srv.Handler().ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Errorf("POST %s from the dashboard: expected 200, got %d: %s", path, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestDockerActionValidation(t *testing.T) {
+	srv := testServer()
+	tests := []struct {
+		name string
+		path string
+		want int
+	}{
+		{"unknown action", "/api/docker/nginx/explode", http.StatusNotFound},
+		{"invalid name", "/api/docker/bad%3Bname/stop", http.StatusBadRequest},
+		{"invalid name remote", "/api/docker/bad%20name/rm?server=remote1", http.StatusBadRequest},
+		{"invalid signal", "/api/docker/nginx/kill?signal=HUP%3Breboot", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := post(tt.path)
+			w := httptest.NewRecorder()
+			srv.Handler().ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Fatalf("expected %d, got %d: %s", tt.want, w.Code, w.Body.String())
+			}
+			if !json.Valid(w.Body.Bytes()) {
+				t.Fatalf("response is not valid JSON: %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestDockerLogsValidation(t *testing.T) {
+	srv := testServer()
+	for _, path := range []string{
+		"/api/docker/bad%3Bname/logs",
+		"/api/docker/nginx/logs?tail=10%3Breboot",
+		"/api/docker/nginx/logs?since=soon",
+		"/api/docker/nginx/logs?grep=%28&server=remote1",
+	} {
+		req := httptest.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("GET %s: expected 400, got %d", path, w.Code)
+		}
+	}
+}
+
+func TestComposeActionValidation(t *testing.T) {
+	srv := testServer()
+	for path, want := range map[string]int{
+		"/api/compose/web/explode":                    http.StatusNotFound,
+		"/api/compose/bad%3Bname/up":                  http.StatusBadRequest,
+		"/api/compose/bad%20name/down?server=remote1": http.StatusBadRequest,
+	} {
+		req := post(path)
+		w := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(w, req)
+		if w.Code != want {
+			t.Errorf("POST %s: expected %d, got %d", path, want, w.Code)
+		}
+	}
+}
+
 func TestFrontendFallback(t *testing.T) {
 	srv := testServer()
 	req := httptest.NewRequest("GET", "/", nil)
@@ -282,6 +536,26 @@ func TestDemoStatusEndpoint(t *testing.T) {
 	}
 }
 
+func TestDemoSensorsEndpoint(t *testing.T) {
+	srv := testDemoServer()
+	req := httptest.NewRequest("GET", "/api/sensors?server=raspberry-pi", nil)
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	temps, ok := result["temperatures"].([]any)
+	if !ok || len(temps) != 1 {
+		t.Fatalf("expected 1 demo temperature for raspberry-pi, got %v", result["temperatures"])
+	}
+}
+
 func TestDemoDockerEndpoint(t *testing.T) {
 	srv := testDemoServer()
 	req := httptest.NewRequest("GET", "/api/docker", nil)
@@ -324,6 +598,23 @@ func TestDemoProcessesEndpoint(t *testing.T) {
 	}
 }
 
+func TestDemoContainerAlerts(t *testing.T) {
+	srv := testDemoServer()
+	req := httptest.NewRequest("GET", "/api/alerts?server=nas-box", nil)
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+
+	var result struct {
+		Containers []map[string]any `json:"containers"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(result.Containers) != 2 || result.Containers[1]["message"] != "plex exited 3 times in 10 minutes" {
+		t.Fatalf("unexpected container alerts: %v", result.Containers)
+	}
+}
+
 func TestDemoAlertsEndpoint(t *testing.T) {
 	srv := testDemoServer()
 	req := httptest.NewRequest("GET", "/api/alerts", nil)
@@ -408,6 +699,140 @@ func TestDemoWakeSendEndpoint(t *testing.T) {
 	}
 }
 
+func TestDemoDockerActionEndpoint(t *testing.T) {
+	srv := testDemoServer()
+	req := post("/api/docker/nginx-proxy/kill?signal=hup")
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	var result map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if result["action"] != "kill" || result["container"] != "nginx-proxy" || result["signal"] != "HUP" {
+		t.Fatalf("unexpected result: %v", result)
+	}
+}
+
+func TestDemoDockerLogsEndpoint(t *testing.T) {
+	srv := testDemoServer()
+	req := httptest.NewRequest("GET", "/api/docker/nginx/logs?grep=timed", nil)
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+
+	if ct := w.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("expected text/event-stream, got %q", ct)
+	}
+	events := strings.Split(strings.TrimSpace(w.Body.String()), "\n\n")
+	if len(events) != 2 {
+		t.Fatalf("expected one line and an end event, got:\n%s", w.Body.String())
+	}
+	var line map[string]any
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(events[0], "data: ")), &line); err != nil {
+		t.Fatalf("invalid JSON in %q: %v", events[0], err)
+	}
+	if line["stream"] != "stderr" || !strings.Contains(line["text"].(string), "upstream timed out") {
+		t.Errorf("unexpected line: %v", line)
+	}
+	if !strings.HasPrefix(events[1], "event: end\n") {
+		t.Errorf("expected end event, got %q", events[1])
+	}
+}
+
+func TestDemoDockerUpdatesEndpoint(t *testing.T) {
+	srv := testDemoServer()
+	req := httptest.NewRequest("GET", "/api/docker/updates?server=raspberry-pi", nil)
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	var updates []map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &updates); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(updates) != 1 || updates[0]["container"] != "pihole" || updates[0]["status"] != "update_available" {
+		t.Fatalf("unexpected updates: %v", updates)
+	}
+}
+
+func TestDemoDockerDiskEndpoints(t *testing.T) {
+	srv := testDemoServer()
+
+	req := httptest.NewRequest("GET", "/api/docker/df", nil)
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+	var du map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &du); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if du["root_dir"] != "/var/lib/docker" || du["reclaimable_bytes"] == nil {
+		t.Fatalf("unexpected disk usage: %v", du)
+	}
+
+	req = post("/api/docker/prune?dry_run=true&older_than=7d")
+	w = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+	var result map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if result["dry_run"] != true || len(result["items"].([]any)) == 0 {
+		t.Fatalf("unexpected prune result: %v", result)
+	}
+
+	req = post("/api/docker/prune?older_than=forever")
+	w = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid older_than, got %d", w.Code)
+	}
+}
+
+func TestDemoComposeEndpoints(t *testing.T) {
+	srv := testDemoServer()
+
+	req := httptest.NewRequest("GET", "/api/compose?server=nas-box", nil)
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+	var projects []map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &projects); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(projects) != 1 || projects[0]["name"] != "nas" || projects[0]["containers"] != nil {
+		t.Fatalf("unexpected projects: %v", projects)
+	}
+
+	req = httptest.NewRequest("GET", "/api/compose/web", nil)
+	w = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+	var containers []map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &containers); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(containers) != 3 {
+		t.Fatalf("expected 3 containers in web, got %d", len(containers))
+	}
+
+	req = post("/api/compose/monitoring/pull")
+	w = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"action":"pull"`) {
+		t.Fatalf("unexpected pull response: %d %s", w.Code, w.Body.String())
+	}
+
+	req = post("/api/compose/ghost/up")
+	w = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown project, got %d", w.Code)
+	}
+}
+
 func TestDemoServersEndpoint(t *testing.T) {
 	srv := testDemoServer()
 	req := httptest.NewRequest("GET", "/api/servers", nil)
@@ -605,6 +1030,97 @@ func TestDemoAlertsWithServerParam(t *testing.T) {
 	}
 }
 
+func TestDemoAlertHistory(t *testing.T) {
+	srv := testDemoServer()
+
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/api/alerts/history?since=7d&server=nas-box", nil))
+	var h struct {
+		Entries []map[string]any `json:"entries"`
+		Summary []struct {
+			Server   string `json:"server"`
+			Critical int    `json:"critical"`
+		} `json:"summary"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &h); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(h.Entries) == 0 || len(h.Summary) != 1 || h.Summary[0].Server != "nas-box" || h.Summary[0].Critical == 0 {
+		t.Errorf("unexpected demo history: %s", w.Body.String())
+	}
+}
+
+func TestDemoMetricsHistory(t *testing.T) {
+	srv := testDemoServer()
+
+	for _, tt := range []struct {
+		url        string
+		series     int
+		resolution string
+	}{
+		{"/api/history?metric=cpu", 1, "1m"},
+		{"/api/history?metric=disk&server=nas-box&since=7d", 2, "1h"},
+	} {
+		w := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(w, httptest.NewRequest("GET", tt.url, nil))
+		var series []history.Series
+		if err := json.Unmarshal(w.Body.Bytes(), &series); err != nil {
+			t.Fatalf("%s: invalid JSON: %v", tt.url, err)
+		}
+		if len(series) != tt.series || series[0].Resolution != tt.resolution || len(series[0].Points) == 0 {
+			t.Errorf("%s: unexpected demo history: %s", tt.url, w.Body.String())
+		}
+	}
+}
+
+func TestDemoMetrics(t *testing.T) {
+	srv := testDemoServer()
+
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	body := w.Body.String()
+	for _, want := range []string{
+		`homebutler_up{server="homelab-server"} 1`,
+		`homebutler_up{server="backup-nas"} 0`,
+		`homebutler_disk_usage_percent{server="homelab-server",mount="/mnt/data"} 87`,
+		`homebutler_container_running{server="homelab-server",container="backup",image="restic/restic:0.16",state="exited"} 0`,
+		`homebutler_alert_status{server="nas-box",metric="container:restarts:plex"} 2`,
+		`homebutler_alert_silenced{server="nas-box",metric="container:restarts:plex"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q in demo /metrics:\n%s", want, body)
+		}
+	}
+}
+
+func TestDemoSilenceEndpoints(t *testing.T) {
+	srv := testDemoServer()
+
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/api/alerts/silences?server=nas-box", nil))
+	var list []map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || len(list) != 1 {
+		t.Fatalf("expected one nas-box silence, got %s", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/api/alerts?server=nas-box", nil))
+	var alerts struct {
+		Status     string           `json:"status"`
+		Containers []map[string]any `json:"containers"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &alerts)
+	if alerts.Status != "warning" || alerts.Containers[1]["silenced"] != true {
+		t.Errorf("the silenced plex alert should not make nas-box critical: %s", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, post("/api/alerts/silences?for=2h&metric=cpu"))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"metrics":["cpu"]`) {
+		t.Errorf("unexpected create response %d: %s", w.Code, w.Body.String())
+	}
+}
+
 func TestDemoPortsWithServerParam(t *testing.T) {
 	srv := testDemoServer()
 
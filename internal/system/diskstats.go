@@ -0,0 +1,132 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DiskIOInfo holds throughput and latency for one block device.
+type DiskIOInfo struct {
+	Device           string  `json:"device"`
+	ReadBytesPerSec  float64 `json:"read_bytes_per_sec"`
+	WriteBytesPerSec float64 `json:"write_bytes_per_sec"`
+	ReadsPerSec      float64 `json:"reads_per_sec"`
+	WritesPerSec     float64 `json:"writes_per_sec"`
+	AwaitMs          float64 `json:"await_ms"`     // average time per completed I/O, queueing included
+	Utilization      float64 `json:"util_percent"` // time the device was busy
+}
+
+// diskCounters holds cumulative counters from /proc/diskstats for one device.
+type diskCounters struct {
+	reads, readSectors, readMs    uint64
+	writes, writeSectors, writeMs uint64
+	ioMs                          uint64
+}
+
+// diskstats sector counts are always 512-byte units, whatever the device's
+// real sector size.
+const diskSectorSize = 512
+
+// getDiskIO samples /proc/diskstats twice, like getNetwork, and reports
+// per-device rates. Linux only.
+func getDiskIO() []DiskIOInfo {
+	if runtime.GOOS != "linux" {
+		return nil
+	}
+	s1 := readDiskStats()
+	start := time.Now()
+	time.Sleep(sampleInterval)
+	s2 := readDiskStats()
+	if s1 == nil || s2 == nil {
+		return nil
+	}
+	return diskRates(s1, s2, time.Since(start).Seconds())
+}
+
+func readDiskStats() map[string]diskCounters {
+	data, err := readProcFile("diskstats")
+	if err != nil {
+		return nil
+	}
+	return parseDiskStats(string(data))
+}
+
+// parseDiskStats parses /proc/diskstats:
+// major minor name reads merged sectors ms writes merged sectors ms in_flight io_ms weighted_ms ...
+func parseDiskStats(data string) map[string]diskCounters {
+	result := make(map[string]diskCounters)
+	for _, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 14 {
+			continue
+		}
+		var v [11]uint64
+		valid := true
+		for i := range v {
+			n, err := strconv.ParseUint(fields[i+3], 10, 64)
+			if err != nil {
+				valid = false
+				break
+			}
+			v[i] = n
+		}
+		if !valid {
+			continue
+		}
+		result[fields[2]] = diskCounters{
+			reads: v[0], readSectors: v[2], readMs: v[3],
+			writes: v[4], writeSectors: v[6], writeMs: v[7],
+			ioMs: v[9],
+		}
+	}
+	return result
+}
+
+// diskRates turns two counter samples into per-device DiskIOInfo, sorted by name.
+func diskRates(s1, s2 map[string]diskCounters, secs float64) []DiskIOInfo {
+	if secs <= 0 {
+		return nil
+	}
+	var names []string
+	for name := range s2 {
+		if _, ok := s1[name]; ok && isWholeDisk(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	devices := make([]DiskIOInfo, 0, len(names))
+	for _, name := range names {
+		a, b := s1[name], s2[name]
+		ios := counterDelta(a.reads, b.reads) + counterDelta(a.writes, b.writes)
+		d := DiskIOInfo{
+			Device:           name,
+			ReadBytesPerSec:  round2(counterDelta(a.readSectors, b.readSectors) * diskSectorSize / secs),
+			WriteBytesPerSec: round2(counterDelta(a.writeSectors, b.writeSectors) * diskSectorSize / secs),
+			ReadsPerSec:      round2(counterDelta(a.reads, b.reads) / secs),
+			WritesPerSec:     round2(counterDelta(a.writes, b.writes) / secs),
+			Utilization:      round2(min(counterDelta(a.ioMs, b.ioMs)/(secs*1000)*100, 100)),
+		}
+		if ios > 0 {
+			d.AwaitMs = round2((counterDelta(a.readMs, b.readMs) + counterDelta(a.writeMs, b.writeMs)) / ios)
+		}
+		devices = append(devices, d)
+	}
+	return devices
+}
+
+// isWholeDisk reports whether a diskstats entry is a whole block device
+// rather than a partition: only whole devices appear in /sys/block.
+// Loop and ramdisk devices are skipped.
+func isWholeDisk(name string) bool {
+	if strings.HasPrefix(name, "loop") || strings.HasPrefix(name, "ram") {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(sysRoot, "block", name))
+	return err == nil
+}
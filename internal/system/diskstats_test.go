@@ -0,0 +1,80 @@
+package system
+
+import (
+	"testing"
+)
+
+const diskStatsSample = `   7       0 loop0 120 0 2048 10 0 0 0 0 0 20 10 0 0 0 0
+   8       0 sda 1000 50 80000 2000 500 20 40000 3000 0 4000 5000 0 0 0 0
+   8       1 sda1 900 50 70000 1800 500 20 40000 3000 0 3800 4800 0 0 0 0
+ 259       0 nvme0n1 20000 0 400000 8000 10000 0 300000 12000 1 9000 20000
+ 259       1 nvme0n1p1 bogus line
+`
+
+func TestParseDiskStats(t *testing.T) {
+	got := parseDiskStats(diskStatsSample)
+	if len(got) != 4 {
+		t.Fatalf("expected 4 devices, got %d: %+v", len(got), got)
+	}
+	want := diskCounters{reads: 1000, readSectors: 80000, readMs: 2000, writes: 500, writeSectors: 40000, writeMs: 3000, ioMs: 4000}
+	if got["sda"] != want {
+		t.Errorf("sda = %+v, want %+v", got["sda"], want)
+	}
+	// older kernels only have the first 14 fields
+	if got["nvme0n1"].ioMs != 9000 {
+		t.Errorf("nvme0n1 ioMs = %d, want 9000", got["nvme0n1"].ioMs)
+	}
+}
+
+func TestDiskRates(t *testing.T) {
+	fakeSys(t, map[string]string{
+		"block/sda/size":      "1000",
+		"block/sda/sda1/size": "900",
+		"block/loop0/size":    "10",
+		"block/nvme0n1/size":  "2000",
+	})
+
+	s1 := map[string]diskCounters{
+		"sda":     {reads: 100, readSectors: 1000, readMs: 100, writes: 50, writeSectors: 500, writeMs: 200, ioMs: 1000},
+		"sda1":    {reads: 100},
+		"loop0":   {reads: 1},
+		"nvme0n1": {writes: 10, writeMs: 50},
+	}
+	s2 := map[string]diskCounters{
+		"sda":     {reads: 150, readSectors: 1000 + 4096, readMs: 200, writes: 100, writeSectors: 500 + 2048, writeMs: 500, ioMs: 1250},
+		"sda1":    {reads: 150},
+		"loop0":   {reads: 2},
+		"nvme0n1": {writes: 5, writeMs: 10},
+	}
+
+	devs := diskRates(s1, s2, 0.5)
+	if len(devs) != 2 {
+		t.Fatalf("expected sda and nvme0n1 (partitions and loop skipped), got %+v", devs)
+	}
+	if devs[0].Device != "nvme0n1" || devs[0].WritesPerSec != 0 || devs[0].AwaitMs != 0 {
+		t.Errorf("counter reset should report zero, got %+v", devs[0])
+	}
+	sda := devs[1]
+	// 4096 sectors * 512 bytes over 0.5s = 4 MiB/s
+	if sda.ReadBytesPerSec != 4*1024*1024 || sda.WriteBytesPerSec != 2*1024*1024 {
+		t.Errorf("throughput = %f/%f", sda.ReadBytesPerSec, sda.WriteBytesPerSec)
+	}
+	if sda.ReadsPerSec != 100 || sda.WritesPerSec != 100 {
+		t.Errorf("iops = %f/%f, want 100/100", sda.ReadsPerSec, sda.WritesPerSec)
+	}
+	// (100ms read + 300ms write) / 100 I/Os
+	if sda.AwaitMs != 4 {
+		t.Errorf("await = %f, want 4", sda.AwaitMs)
+	}
+	// 250ms busy out of 500ms
+	if sda.Utilization != 50 {
+		t.Errorf("utilization = %f, want 50", sda.Utilization)
+	}
+}
+
+func TestReadDiskStats_Missing(t *testing.T) {
+	fakeProc(t, nil)
+	if got := readDiskStats(); got != nil {
+		t.Errorf("expected nil, got %+v", got)
+	}
+}
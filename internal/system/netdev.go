@@ -0,0 +1,147 @@
+package system
+
+import (
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NetInfo holds throughput and error counters for one network interface.
+type NetInfo struct {
+	Interface     string  `json:"interface"`
+	State         string  `json:"state"`      // operstate: "up", "down", "unknown"
+	SpeedMbps     int     `json:"speed_mbps"` // 0 if the driver doesn't report it (wifi, virtual)
+	RxBytesPerSec float64 `json:"rx_bytes_per_sec"`
+	TxBytesPerSec float64 `json:"tx_bytes_per_sec"`
+	Utilization   float64 `json:"utilization_percent"` // busiest direction vs. link speed
+	RxErrors      uint64  `json:"rx_errors"`
+	TxErrors      uint64  `json:"tx_errors"`
+	RxDropped     uint64  `json:"rx_dropped"`
+	TxDropped     uint64  `json:"tx_dropped"`
+	ErrorsPerSec  float64 `json:"errors_per_sec"` // errors + drops, both directions, during the sample
+}
+
+// netCounters holds cumulative counters from /proc/net/dev for one interface.
+type netCounters struct {
+	rxBytes, rxErrs, rxDrop uint64
+	txBytes, txErrs, txDrop uint64
+}
+
+func (c netCounters) faults() uint64 {
+	return c.rxErrs + c.rxDrop + c.txErrs + c.txDrop
+}
+
+// getNetwork samples /proc/net/dev twice, like getCPU does with /proc/stat,
+// and reports per-interface rates. Linux only.
+func getNetwork() []NetInfo {
+	if runtime.GOOS != "linux" {
+		return nil
+	}
+	s1 := readNetDev()
+	start := time.Now()
+	time.Sleep(sampleInterval)
+	s2 := readNetDev()
+	if s1 == nil || s2 == nil {
+		return nil
+	}
+	return netRates(s1, s2, time.Since(start).Seconds())
+}
+
+func readNetDev() map[string]netCounters {
+	data, err := readProcFile("net/dev")
+	if err != nil {
+		return nil
+	}
+	return parseNetDev(string(data))
+}
+
+// parseNetDev parses /proc/net/dev. The first two lines are headers;
+// each following line is "iface: rx(8 fields) tx(8 fields)".
+func parseNetDev(data string) map[string]netCounters {
+	result := make(map[string]netCounters)
+	for _, line := range strings.Split(data, "\n") {
+		name, rest, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(rest)
+		if len(fields) < 16 {
+			continue
+		}
+		var v [16]uint64
+		valid := true
+		for i := range v {
+			n, err := strconv.ParseUint(fields[i], 10, 64)
+			if err != nil {
+				valid = false
+				break
+			}
+			v[i] = n
+		}
+		if !valid {
+			continue
+		}
+		result[strings.TrimSpace(name)] = netCounters{
+			rxBytes: v[0], rxErrs: v[2], rxDrop: v[3],
+			txBytes: v[8], txErrs: v[10], txDrop: v[11],
+		}
+	}
+	return result
+}
+
+// netRates turns two counter samples into per-interface NetInfo, sorted by name.
+func netRates(s1, s2 map[string]netCounters, secs float64) []NetInfo {
+	if secs <= 0 {
+		return nil
+	}
+	var names []string
+	for name := range s2 {
+		if _, ok := s1[name]; ok && isRelevantInterface(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	nets := make([]NetInfo, 0, len(names))
+	for _, name := range names {
+		a, b := s1[name], s2[name]
+		n := NetInfo{
+			Interface:     name,
+			State:         readSysString(filepath.Join(sysRoot, "class", "net", name, "operstate")),
+			RxBytesPerSec: round2(counterDelta(a.rxBytes, b.rxBytes) / secs),
+			TxBytesPerSec: round2(counterDelta(a.txBytes, b.txBytes) / secs),
+			RxErrors:      b.rxErrs,
+			TxErrors:      b.txErrs,
+			RxDropped:     b.rxDrop,
+			TxDropped:     b.txDrop,
+			ErrorsPerSec:  round2(counterDelta(a.faults(), b.faults()) / secs),
+		}
+		if n.State == "" {
+			n.State = "unknown"
+		}
+		// speed is -1 (or unreadable) for links that are down or don't report it
+		if speed, ok := readSysInt(filepath.Join(sysRoot, "class", "net", name, "speed")); ok && speed > 0 {
+			n.SpeedMbps = int(speed)
+			linkBytes := float64(speed) * 1e6 / 8
+			n.Utilization = round2(min(max(n.RxBytesPerSec, n.TxBytesPerSec)/linkBytes*100, 100))
+		}
+		nets = append(nets, n)
+	}
+	return nets
+}
+
+// counterDelta returns b-a, treating a counter reset or wrap as zero.
+func counterDelta(a, b uint64) float64 {
+	if b < a {
+		return 0
+	}
+	return float64(b - a)
+}
+
+// isRelevantInterface skips loopback and per-container veth pairs.
+func isRelevantInterface(name string) bool {
+	return name != "lo" && !strings.HasPrefix(name, "veth")
+}
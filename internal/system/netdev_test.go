@@ -0,0 +1,102 @@
+package system
+
+import (
+	"testing"
+)
+
+const netDevSample = `Inter-|   Receive                                                |  Transmit
+ face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+    lo: 1000000    5000    0    0    0     0          0         0  1000000    5000    0    0    0     0       0          0
+  eth0: 5000000   40000    3    1    0     0          0        12  2000000   30000    0    2    0     0       0          0
+vethab12:  100     1    0    0    0     0          0         0      200     2    0    0    0     0       0          0
+ wlan0:garbage
+`
+
+func TestParseNetDev(t *testing.T) {
+	got := parseNetDev(netDevSample)
+	if len(got) != 3 {
+		t.Fatalf("expected 3 interfaces, got %d: %+v", len(got), got)
+	}
+	eth := got["eth0"]
+	want := netCounters{rxBytes: 5000000, rxErrs: 3, rxDrop: 1, txBytes: 2000000, txDrop: 2}
+	if eth != want {
+		t.Errorf("eth0 = %+v, want %+v", eth, want)
+	}
+	if _, ok := got["wlan0"]; ok {
+		t.Error("malformed line should be skipped")
+	}
+}
+
+func TestNetRates(t *testing.T) {
+	fakeSys(t, map[string]string{
+		"class/net/eth0/operstate":  "up",
+		"class/net/eth0/speed":      "100",
+		"class/net/wlan0/operstate": "up",
+		"class/net/wlan0/speed":     "-1",
+	})
+
+	s1 := map[string]netCounters{
+		"lo":    {rxBytes: 0},
+		"eth0":  {rxBytes: 1000, txBytes: 1000, rxErrs: 1},
+		"wlan0": {rxBytes: 0},
+		"usb0":  {},
+	}
+	s2 := map[string]netCounters{
+		"lo":    {rxBytes: 1 << 20},
+		"eth0":  {rxBytes: 1000 + 6_250_000, txBytes: 1000 + 1_250_000, rxErrs: 3, txDrop: 2},
+		"wlan0": {rxBytes: 500},
+		"usb0":  {},
+	}
+
+	nets := netRates(s1, s2, 0.5)
+	if len(nets) != 3 {
+		t.Fatalf("expected eth0, usb0, wlan0, got %+v", nets)
+	}
+	eth := nets[0]
+	if eth.Interface != "eth0" || eth.State != "up" || eth.SpeedMbps != 100 {
+		t.Errorf("unexpected eth0: %+v", eth)
+	}
+	if eth.RxBytesPerSec != 12_500_000 || eth.TxBytesPerSec != 2_500_000 {
+		t.Errorf("rates = %f/%f", eth.RxBytesPerSec, eth.TxBytesPerSec)
+	}
+	// 12.5 MB/s on a 100 Mbit link is full saturation
+	if eth.Utilization != 100 {
+		t.Errorf("utilization = %f, want 100", eth.Utilization)
+	}
+	// 2 new errors + 2 new drops over half a second
+	if eth.ErrorsPerSec != 8 || eth.RxErrors != 3 || eth.TxDropped != 2 {
+		t.Errorf("unexpected error counters: %+v", eth)
+	}
+
+	if nets[1].Interface != "usb0" || nets[1].State != "unknown" {
+		t.Errorf("missing operstate should be unknown: %+v", nets[1])
+	}
+	wlan := nets[2]
+	if wlan.SpeedMbps != 0 || wlan.Utilization != 0 || wlan.RxBytesPerSec != 1000 {
+		t.Errorf("unknown speed should leave utilization at 0: %+v", wlan)
+	}
+}
+
+func TestNetRates_CounterReset(t *testing.T) {
+	fakeSys(t, nil)
+	s1 := map[string]netCounters{"eth0": {rxBytes: 5000, rxErrs: 10}}
+	s2 := map[string]netCounters{"eth0": {rxBytes: 100}}
+	nets := netRates(s1, s2, 1)
+	if len(nets) != 1 || nets[0].RxBytesPerSec != 0 || nets[0].ErrorsPerSec != 0 {
+		t.Errorf("counter reset should report zero, got %+v", nets)
+	}
+}
+
+func TestReadNetDev(t *testing.T) {
+	fakeProc(t, map[string]string{"net/dev": netDevSample})
+	if got := readNetDev(); len(got) != 3 {
+		t.Errorf("expected 3 interfaces, got %+v", got)
+	}
+}
+
+func TestReadNetDev_Missing(t *testing.T) {
+	fakeProc(t, nil)
+	if got := readNetDev(); got != nil {
+		t.Errorf("expected nil, got %+v", got)
+	}
+}
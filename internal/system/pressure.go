@@ -0,0 +1,102 @@
+package system
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// PressureInfo holds pressure stall information (PSI) from /proc/pressure.
+// Each resource is nil when the kernel doesn't provide it (PSI needs
+// Linux 4.20+ and can be disabled with psi=0).
+type PressureInfo struct {
+	CPU    *PSI `json:"cpu,omitempty"`
+	Memory *PSI `json:"memory,omitempty"`
+	IO     *PSI `json:"io,omitempty"`
+}
+
+// PSI holds the share of wall time (%) that some or all non-idle tasks were
+// stalled on a resource, averaged over 10s, 60s and 300s.
+type PSI struct {
+	Some PSIAvg `json:"some"`
+	Full PSIAvg `json:"full"`
+}
+
+// PSIAvg is one "some" or "full" line.
+type PSIAvg struct {
+	Avg10  float64 `json:"avg10"`
+	Avg60  float64 `json:"avg60"`
+	Avg300 float64 `json:"avg300"`
+}
+
+// getPressure reads /proc/pressure/{cpu,memory,io}. Returns nil when none
+// are available.
+func getPressure() *PressureInfo {
+	if runtime.GOOS != "linux" {
+		return nil
+	}
+	p := &PressureInfo{
+		CPU:    readPSI("cpu"),
+		Memory: readPSI("memory"),
+		IO:     readPSI("io"),
+	}
+	if p.CPU == nil && p.Memory == nil && p.IO == nil {
+		return nil
+	}
+	return p
+}
+
+func readPSI(resource string) *PSI {
+	data, err := readProcFile("pressure/" + resource)
+	if err != nil {
+		return nil
+	}
+	return parsePSI(string(data))
+}
+
+// parsePSI parses a /proc/pressure file:
+//
+//	some avg10=0.31 avg60=0.12 avg300=0.04 total=12345
+//	full avg10=0.00 avg60=0.00 avg300=0.00 total=0
+func parsePSI(data string) *PSI {
+	var psi PSI
+	found := false
+	for _, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+		var avg *PSIAvg
+		switch fields[0] {
+		case "some":
+			avg = &psi.Some
+		case "full":
+			avg = &psi.Full
+		default:
+			continue
+		}
+		for _, f := range fields[1:] {
+			key, val, ok := strings.Cut(f, "=")
+			if !ok {
+				continue
+			}
+			v, err := strconv.ParseFloat(val, 64)
+			if err != nil {
+				continue
+			}
+			switch key {
+			case "avg10":
+				avg.Avg10 = v
+			case "avg60":
+				avg.Avg60 = v
+			case "avg300":
+				avg.Avg300 = v
+			}
+		}
+		found = true
+	}
+	if !found {
+		return nil
+	}
+	return &psi
+}
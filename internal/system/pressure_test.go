@@ -0,0 +1,55 @@
+package system
+
+import (
+	"testing"
+)
+
+func TestParsePSI(t *testing.T) {
+	psi := parsePSI(`some avg10=1.25 avg60=0.50 avg300=0.10 total=123456
+full avg10=0.40 avg60=0.20 avg300=0.05 total=4567
+`)
+	if psi == nil {
+		t.Fatal("expected PSI, got nil")
+	}
+	if psi.Some != (PSIAvg{Avg10: 1.25, Avg60: 0.5, Avg300: 0.1}) {
+		t.Errorf("some = %+v", psi.Some)
+	}
+	if psi.Full.Avg60 != 0.2 {
+		t.Errorf("full avg60 = %f, want 0.2", psi.Full.Avg60)
+	}
+}
+
+func TestParsePSI_SomeOnly(t *testing.T) {
+	// cpu pressure has no "full" line before Linux 5.13
+	psi := parsePSI("some avg10=3.00 avg60=2.00 avg300=1.00 total=99\n")
+	if psi == nil || psi.Some.Avg10 != 3 || psi.Full != (PSIAvg{}) {
+		t.Errorf("unexpected PSI: %+v", psi)
+	}
+	if parsePSI("garbage\n") != nil {
+		t.Error("expected nil for unparseable input")
+	}
+}
+
+func TestGetPressure(t *testing.T) {
+	fakeProc(t, map[string]string{
+		"pressure/memory": "some avg10=12.00 avg60=8.00 avg300=2.00 total=1\nfull avg10=6.00 avg60=4.00 avg300=1.00 total=1\n",
+		"pressure/io":     "some avg10=0.00 avg60=0.00 avg300=0.00 total=0\nfull avg10=0.00 avg60=0.00 avg300=0.00 total=0\n",
+	})
+	p := getPressure()
+	if p == nil {
+		t.Fatal("expected pressure info")
+	}
+	if p.CPU != nil {
+		t.Errorf("missing cpu file should leave CPU nil, got %+v", p.CPU)
+	}
+	if p.Memory == nil || p.Memory.Some.Avg60 != 8 || p.IO == nil {
+		t.Errorf("unexpected pressure: %+v", p)
+	}
+}
+
+func TestGetPressure_Unsupported(t *testing.T) {
+	fakeProc(t, nil)
+	if p := getPressure(); p != nil {
+		t.Errorf("expected nil without /proc/pressure, got %+v", p)
+	}
+}
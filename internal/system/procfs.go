@@ -0,0 +1,294 @@
+package system
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// procRoot is the procfs mount point. Tests point it at a fake tree.
+var procRoot = "/proc"
+
+// statfs reports filesystem usage for a mount point. Replaced in tests.
+var statfs = statfsUsage
+
+// fsUsage holds raw filesystem capacity in bytes, as reported by statfs.
+type fsUsage struct {
+	total uint64
+	free  uint64 // free blocks including those reserved for root
+	avail uint64 // free blocks available to unprivileged users
+
+	files     uint64 // total inodes; 0 on filesystems without a fixed inode table (btrfs)
+	filesFree uint64
+}
+
+// mountEntry is a single line from /proc/self/mounts.
+type mountEntry struct {
+	device string
+	mount  string
+	fsType string
+}
+
+func readProcFile(name string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(procRoot, name))
+}
+
+// linuxUptime reads /proc/uptime directly (no coreutils needed).
+func linuxUptime() string {
+	data, err := readProcFile("uptime")
+	if err != nil {
+		return "unknown"
+	}
+	secs, ok := parseUptime(string(data))
+	if !ok {
+		return "unknown"
+	}
+	return formatUptime(time.Duration(secs) * time.Second)
+}
+
+// parseUptime returns the first field of /proc/uptime (seconds since boot).
+func parseUptime(data string) (float64, bool) {
+	fields := strings.Fields(data)
+	if len(fields) == 0 {
+		return 0, false
+	}
+	secs, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, false
+	}
+	return secs, true
+}
+
+// formatUptime renders a duration as "4d 12h" or "3h 25m".
+func formatUptime(dur time.Duration) string {
+	days := int(dur.Hours() / 24)
+	hours := int(dur.Hours()) % 24
+	if days > 0 {
+		return fmt.Sprintf("%dd %dh", days, hours)
+	}
+	return fmt.Sprintf("%dh %dm", hours, int(dur.Minutes())%60)
+}
+
+// linuxMemory reads /proc/meminfo directly and reports exact byte counts.
+func linuxMemory() MemInfo {
+	data, err := readProcFile("meminfo")
+	if err != nil {
+		return MemInfo{}
+	}
+	mem := parseMeminfo(string(data))
+	total := mem["MemTotal"]
+	avail, ok := mem["MemAvailable"]
+	if !ok {
+		// Kernels before 3.14 have no MemAvailable; approximate it.
+		avail = mem["MemFree"] + mem["Buffers"] + mem["Cached"]
+	}
+	if avail > total {
+		avail = total
+	}
+	info := memInfoFromBytes(total, total-avail)
+	info.BuffersBytes = mem["Buffers"]
+	info.CachedBytes = mem["Cached"] + mem["SReclaimable"]
+
+	swapTotal, swapFree := mem["SwapTotal"], mem["SwapFree"]
+	if swapFree > swapTotal {
+		swapFree = swapTotal
+	}
+	info.Swap = swapInfoFromBytes(swapTotal, swapTotal-swapFree)
+
+	if n := mem["HugePages_Total"]; n > 0 {
+		info.HugePages = &HugePagesInfo{
+			Total:     n,
+			Free:      mem["HugePages_Free"],
+			PageBytes: mem["Hugepagesize"],
+		}
+	}
+	return info
+}
+
+// parseMeminfo parses /proc/meminfo into a map of field name → bytes.
+// Values with a "kB" unit are converted to bytes; unitless values
+// (e.g. HugePages_Total) are returned as-is.
+func parseMeminfo(data string) map[string]uint64 {
+	result := make(map[string]uint64)
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		key, rest, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			continue
+		}
+		v, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		if len(fields) > 1 && fields[1] == "kB" {
+			v *= 1024
+		}
+		result[key] = v
+	}
+	return result
+}
+
+func memInfoFromBytes(total, used uint64) MemInfo {
+	info := MemInfo{
+		TotalGB:    round2(bytesToGB(total)),
+		UsedGB:     round2(bytesToGB(used)),
+		TotalBytes: total,
+		UsedBytes:  used,
+	}
+	if total > 0 {
+		info.Percent = round2(float64(used) / float64(total) * 100)
+	}
+	return info
+}
+
+func swapInfoFromBytes(total, used uint64) SwapInfo {
+	info := SwapInfo{
+		TotalGB:    round2(bytesToGB(total)),
+		UsedGB:     round2(bytesToGB(used)),
+		TotalBytes: total,
+		UsedBytes:  used,
+	}
+	if total > 0 {
+		info.Percent = round2(float64(used) / float64(total) * 100)
+	}
+	return info
+}
+
+// linuxDisks lists relevant mounts from /proc/self/mounts and calls statfs
+// on each, so no df binary is required.
+func linuxDisks() []DiskInfo {
+	data, err := readProcFile("self/mounts")
+	if err != nil {
+		return nil
+	}
+
+	var disks []DiskInfo
+	seen := make(map[string]bool)
+	for _, m := range parseMounts(string(data)) {
+		if seen[m.mount] || !isRelevantMount(m.mount) {
+			continue
+		}
+		seen[m.mount] = true
+
+		usage, err := statfs(m.mount)
+		if err != nil || usage.total == 0 {
+			continue
+		}
+		disks = append(disks, diskInfoFromUsage(m.mount, usage))
+	}
+	return disks
+}
+
+// diskInfoFromUsage converts statfs numbers into a DiskInfo.
+// Percent matches df: used / (used + available to non-root).
+func diskInfoFromUsage(mount string, u fsUsage) DiskInfo {
+	used := u.total - u.free
+	info := DiskInfo{
+		Mount:      mount,
+		TotalGB:    round2(bytesToGB(u.total)),
+		UsedGB:     round2(bytesToGB(used)),
+		TotalBytes: u.total,
+		UsedBytes:  used,
+	}
+	if used+u.avail > 0 {
+		info.Percent = round2(float64(used) / float64(used+u.avail) * 100)
+	}
+	if u.files > 0 && u.filesFree <= u.files {
+		info.InodesTotal = u.files
+		info.InodesUsed = u.files - u.filesFree
+		info.InodePercent = round2(float64(info.InodesUsed) / float64(u.files) * 100)
+	}
+	return info
+}
+
+// parseMounts parses the /proc/self/mounts table.
+func parseMounts(data string) []mountEntry {
+	var mounts []mountEntry
+	for _, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		mounts = append(mounts, mountEntry{
+			device: unescapeMount(fields[0]),
+			mount:  unescapeMount(fields[1]),
+			fsType: fields[2],
+		})
+	}
+	return mounts
+}
+
+// unescapeMount decodes the octal escapes (\040 for space, etc.) the kernel
+// uses in the mount table.
+func unescapeMount(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) {
+			if v, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(v))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// defaultMounts is the include list used when the config doesn't set one.
+var defaultMounts = []string{"/", "/home", "/mnt", "/Volumes"}
+
+// mountInclude and mountExclude select the mounts getDisks reports.
+// Set once at startup via SetMountFilter.
+var (
+	mountInclude = defaultMounts
+	mountExclude []string
+)
+
+// SetMountFilter configures which mounts are reported. Each entry matches
+// that mount point and everything below it, except "/" which matches only
+// the root filesystem. An empty include list keeps the defaults; exclude
+// wins over include.
+func SetMountFilter(include, exclude []string) {
+	mountInclude = defaultMounts
+	if len(include) > 0 {
+		mountInclude = include
+	}
+	mountExclude = exclude
+}
+
+// isRelevantMount reports whether a mount point should be shown.
+func isRelevantMount(mount string) bool {
+	return matchesMount(mountInclude, mount) && !matchesMount(mountExclude, mount)
+}
+
+func matchesMount(list []string, mount string) bool {
+	for _, entry := range list {
+		prefix := strings.TrimSuffix(entry, "/")
+		if prefix == "" {
+			if mount == "/" {
+				return true
+			}
+			continue
+		}
+		if mount == prefix || strings.HasPrefix(mount, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func bytesToGB(b uint64) float64 {
+	return float64(b) / (1024 * 1024 * 1024)
+}
@@ -0,0 +1,240 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fakeProc creates a fake /proc tree with the given files and points
+// procRoot at it for the duration of the test.
+func fakeProc(t *testing.T, files map[string]string) {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	orig := procRoot
+	procRoot = dir
+	t.Cleanup(func() { procRoot = orig })
+}
+
+func TestLinuxUptime(t *testing.T) {
+	fakeProc(t, map[string]string{"uptime": "389224.51 3048121.07\n"})
+	if got := linuxUptime(); got != "4d 12h" {
+		t.Errorf("linuxUptime() = %q, want %q", got, "4d 12h")
+	}
+}
+
+func TestLinuxUptime_Missing(t *testing.T) {
+	fakeProc(t, nil)
+	if got := linuxUptime(); got != "unknown" {
+		t.Errorf("linuxUptime() = %q, want unknown", got)
+	}
+}
+
+func TestParseMeminfo(t *testing.T) {
+	data := `MemTotal:       16307724 kB
+MemFree:          512340 kB
+MemAvailable:    8153862 kB
+HugePages_Total:       4
+`
+	mem := parseMeminfo(data)
+	if mem["MemTotal"] != 16307724*1024 {
+		t.Errorf("MemTotal = %d, want %d", mem["MemTotal"], uint64(16307724*1024))
+	}
+	if mem["HugePages_Total"] != 4 {
+		t.Errorf("HugePages_Total = %d, want 4 (unitless)", mem["HugePages_Total"])
+	}
+}
+
+func TestLinuxMemory(t *testing.T) {
+	fakeProc(t, map[string]string{"meminfo": "MemTotal: 4194304 kB\nMemFree: 1048576 kB\nMemAvailable: 1048576 kB\n"})
+	mem := linuxMemory()
+	if mem.TotalBytes != 4*1024*1024*1024 {
+		t.Errorf("TotalBytes = %d, want 4 GiB", mem.TotalBytes)
+	}
+	if mem.UsedBytes != 3*1024*1024*1024 {
+		t.Errorf("UsedBytes = %d, want 3 GiB", mem.UsedBytes)
+	}
+	if mem.TotalGB != 4 || mem.UsedGB != 3 || mem.Percent != 75 {
+		t.Errorf("got %+v, want 4 GB total, 3 GB used, 75%%", mem)
+	}
+}
+
+func TestLinuxMemory_NoMemAvailable(t *testing.T) {
+	fakeProc(t, map[string]string{"meminfo": "MemTotal: 1000 kB\nMemFree: 200 kB\nBuffers: 100 kB\nCached: 200 kB\n"})
+	mem := linuxMemory()
+	if mem.UsedBytes != 500*1024 {
+		t.Errorf("UsedBytes = %d, want %d", mem.UsedBytes, 500*1024)
+	}
+}
+
+func TestParseMounts(t *testing.T) {
+	data := `/dev/sda1 / ext4 rw,relatime 0 0
+proc /proc proc rw 0 0
+/dev/sdb1 /mnt/my\040drive ext4 rw 0 0
+`
+	mounts := parseMounts(data)
+	if len(mounts) != 3 {
+		t.Fatalf("expected 3 mounts, got %d", len(mounts))
+	}
+	if mounts[2].mount != "/mnt/my drive" {
+		t.Errorf("mount = %q, want %q", mounts[2].mount, "/mnt/my drive")
+	}
+	if mounts[0].fsType != "ext4" {
+		t.Errorf("fsType = %q, want ext4", mounts[0].fsType)
+	}
+}
+
+func TestLinuxDisks(t *testing.T) {
+	fakeProc(t, map[string]string{"self/mounts": `/dev/sda1 / ext4 rw 0 0
+proc /proc proc rw 0 0
+/dev/sdb1 /mnt/data ext4 rw 0 0
+/dev/sdb1 /mnt/data ext4 rw 0 0
+tmpfs /mnt/empty tmpfs rw 0 0
+`})
+	orig := statfs
+	statfs = func(path string) (fsUsage, error) {
+		switch path {
+		case "/":
+			// 2 TiB total, 1.8 TiB used — exactly the case df -h rounds badly
+			const tib = 1 << 40
+			return fsUsage{total: 2 * tib, free: tib / 5, avail: tib / 5}, nil
+		case "/mnt/data":
+			return fsUsage{total: 1000, free: 100, avail: 50, files: 2000, filesFree: 100}, nil
+		case "/mnt/empty":
+			return fsUsage{}, nil
+		}
+		return fsUsage{}, fmt.Errorf("unexpected statfs(%q)", path)
+	}
+	t.Cleanup(func() { statfs = orig })
+
+	disks := linuxDisks()
+	if len(disks) != 2 {
+		t.Fatalf("expected 2 disks (deduplicated, empty skipped), got %d: %+v", len(disks), disks)
+	}
+	root := disks[0]
+	if root.UsedBytes != 2*(1<<40)-(1<<40)/5 {
+		t.Errorf("root UsedBytes = %d, want exact byte count", root.UsedBytes)
+	}
+	if root.TotalGB != 2048 {
+		t.Errorf("root TotalGB = %f, want 2048", root.TotalGB)
+	}
+	// df-style percent: used / (used + avail) = 900 / 950
+	if disks[1].Percent != 94.73 {
+		t.Errorf("data Percent = %f, want 94.73", disks[1].Percent)
+	}
+	if disks[1].InodesUsed != 1900 || disks[1].InodePercent != 95 {
+		t.Errorf("data inodes = %d (%f%%), want 1900 (95%%)", disks[1].InodesUsed, disks[1].InodePercent)
+	}
+	// btrfs and friends report no inode table
+	if root.InodesTotal != 0 || root.InodePercent != 0 {
+		t.Errorf("root inodes should be empty, got %+v", root)
+	}
+}
+
+func TestIsRelevantMount(t *testing.T) {
+	tests := []struct {
+		name             string
+		include, exclude []string
+		mount            string
+		want             bool
+	}{
+		{"default root", nil, nil, "/", true},
+		{"default mnt child", nil, nil, "/mnt/data", true},
+		{"default volumes", nil, nil, "/Volumes/Backup", true},
+		{"default skips var", nil, nil, "/var/lib/docker", false},
+		{"default skips prefix lookalike", nil, nil, "/homework", false},
+		{"root matches only itself", []string{"/"}, nil, "/boot", false},
+		{"custom include", []string{"/", "/var/lib/docker"}, nil, "/var/lib/docker/overlay2/abc/merged", true},
+		{"trailing slash", []string{"/srv/"}, nil, "/srv", true},
+		{"exclude wins", nil, []string{"/mnt/backup"}, "/mnt/backup/daily", false},
+		{"exclude leaves siblings", nil, []string{"/mnt/backup"}, "/mnt/data", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetMountFilter(tt.include, tt.exclude)
+			t.Cleanup(func() { SetMountFilter(nil, nil) })
+			if got := isRelevantMount(tt.mount); got != tt.want {
+				t.Errorf("isRelevantMount(%q) = %v, want %v", tt.mount, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnescapeMount(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"/mnt/data", "/mnt/data"},
+		{`/mnt/a\040b`, "/mnt/a b"},
+		{`/mnt/tab\011x`, "/mnt/tab\tx"},
+		{`/mnt/trailing\04`, `/mnt/trailing\04`},
+	}
+	for _, tt := range tests {
+		if got := unescapeMount(tt.input); got != tt.want {
+			t.Errorf("unescapeMount(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestStatfsUsage_Root(t *testing.T) {
+	u, err := statfsUsage("/")
+	if err != nil {
+		t.Skipf("statfs not available: %v", err)
+	}
+	if u.total == 0 {
+		t.Error("expected non-zero total for /")
+	}
+	if u.free > u.total || u.avail > u.total {
+		t.Errorf("free/avail exceed total: %+v", u)
+	}
+}
+
+func TestLinuxMemory_Breakdown(t *testing.T) {
+	fakeProc(t, map[string]string{"meminfo": `MemTotal:        4194304 kB
+MemFree:          524288 kB
+MemAvailable:    2097152 kB
+Buffers:          102400 kB
+Cached:          1048576 kB
+SReclaimable:      65536 kB
+SwapTotal:       2097152 kB
+SwapFree:        1572864 kB
+HugePages_Total:       8
+HugePages_Free:        6
+Hugepagesize:       2048 kB
+`})
+	mem := linuxMemory()
+	if mem.BuffersBytes != 102400*1024 {
+		t.Errorf("BuffersBytes = %d, want %d", mem.BuffersBytes, 102400*1024)
+	}
+	if mem.CachedBytes != (1048576+65536)*1024 {
+		t.Errorf("CachedBytes = %d, want Cached + SReclaimable", mem.CachedBytes)
+	}
+	if mem.Swap.TotalGB != 2 || mem.Swap.UsedGB != 0.5 || mem.Swap.Percent != 25 {
+		t.Errorf("swap = %+v, want 0.5 / 2 GB (25%%)", mem.Swap)
+	}
+	if mem.HugePages == nil || mem.HugePages.Total != 8 || mem.HugePages.Free != 6 || mem.HugePages.PageBytes != 2*1024*1024 {
+		t.Errorf("hugepages = %+v", mem.HugePages)
+	}
+}
+
+func TestLinuxMemory_NoSwap(t *testing.T) {
+	fakeProc(t, map[string]string{"meminfo": "MemTotal: 1000 kB\nMemAvailable: 500 kB\nSwapTotal: 0 kB\nSwapFree: 0 kB\nHugePages_Total: 0\n"})
+	mem := linuxMemory()
+	if mem.Swap.TotalBytes != 0 || mem.Swap.Percent != 0 {
+		t.Errorf("expected empty swap, got %+v", mem.Swap)
+	}
+	if mem.HugePages != nil {
+		t.Errorf("expected no hugepages, got %+v", mem.HugePages)
+	}
+}
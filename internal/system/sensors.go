@@ -0,0 +1,199 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// sysRoot is the sysfs mount point. Tests point it at a fake tree.
+var sysRoot = "/sys"
+
+// SensorsInfo holds hardware temperature and fan readings.
+type SensorsInfo struct {
+	Temperatures []TempSensor `json:"temperatures"`
+	Fans         []FanSensor  `json:"fans"`
+}
+
+// TempSensor is a single temperature reading in °C.
+type TempSensor struct {
+	Chip     string  `json:"chip"`
+	Label    string  `json:"label"`
+	Device   string  `json:"device,omitempty"` // the hwmon device, e.g. nvme0; tells apart chips of the same name
+	Celsius  float64 `json:"celsius"`
+	High     float64 `json:"high,omitempty"`
+	Critical float64 `json:"critical,omitempty"`
+}
+
+// FanSensor is a single fan speed reading.
+type FanSensor struct {
+	Chip  string `json:"chip"`
+	Label string `json:"label"`
+	RPM   int    `json:"rpm"`
+}
+
+// Sensors reads temperatures, fan speeds and critical trip points from
+// /sys/class/hwmon and /sys/class/thermal.
+func Sensors() (*SensorsInfo, error) {
+	if runtime.GOOS != "linux" {
+		return nil, fmt.Errorf("sensors not supported on %s", runtime.GOOS)
+	}
+	return readSensors(), nil
+}
+
+// readSensors walks the hwmon and thermal classes under sysRoot.
+func readSensors() *SensorsInfo {
+	info := &SensorsInfo{
+		Temperatures: []TempSensor{},
+		Fans:         []FanSensor{},
+	}
+
+	chips := make(map[string]bool)
+	for _, dir := range sortedGlob(filepath.Join(sysRoot, "class", "hwmon", "hwmon*")) {
+		chip := readSysString(filepath.Join(dir, "name"))
+		if chip == "" {
+			chip = filepath.Base(dir)
+		}
+		chips[normalizeChip(chip)] = true
+		info.Temperatures = append(info.Temperatures, hwmonTemps(dir, chip, hwmonDevice(dir))...)
+		info.Fans = append(info.Fans, hwmonFans(dir, chip)...)
+	}
+
+	// Thermal zones often duplicate an hwmon chip (e.g. the Raspberry Pi's
+	// cpu-thermal shows up as hwmon "cpu_thermal"); only add the ones that don't.
+	for _, dir := range sortedGlob(filepath.Join(sysRoot, "class", "thermal", "thermal_zone*")) {
+		zone, ok := thermalZone(dir)
+		if !ok || chips[normalizeChip(zone.Chip)] {
+			continue
+		}
+		info.Temperatures = append(info.Temperatures, zone)
+	}
+
+	return info
+}
+
+// hwmonDevice names the device behind an hwmon directory, or is empty.
+// Unlike the hwmonN number, it stays the same across reboots.
+func hwmonDevice(dir string) string {
+	target, err := os.Readlink(filepath.Join(dir, "device"))
+	if err != nil {
+		return ""
+	}
+	return filepath.Base(target)
+}
+
+// hwmonTemps reads tempN_input/_label/_max/_crit from an hwmon directory.
+func hwmonTemps(dir, chip, device string) []TempSensor {
+	var temps []TempSensor
+	for _, input := range sortedGlob(filepath.Join(dir, "temp*_input")) {
+		prefix := strings.TrimSuffix(input, "_input")
+		milli, ok := readSysInt(input)
+		if !ok {
+			continue
+		}
+		label := readSysString(prefix + "_label")
+		if label == "" {
+			label = filepath.Base(prefix)
+		}
+		t := TempSensor{Chip: chip, Label: label, Device: device, Celsius: milliToCelsius(milli)}
+		if v, ok := readSysInt(prefix + "_max"); ok {
+			t.High = milliToCelsius(v)
+		}
+		if v, ok := readSysInt(prefix + "_crit"); ok {
+			t.Critical = milliToCelsius(v)
+		}
+		temps = append(temps, t)
+	}
+	return temps
+}
+
+// hwmonFans reads fanN_input/_label from an hwmon directory.
+func hwmonFans(dir, chip string) []FanSensor {
+	var fans []FanSensor
+	for _, input := range sortedGlob(filepath.Join(dir, "fan*_input")) {
+		prefix := strings.TrimSuffix(input, "_input")
+		rpm, ok := readSysInt(input)
+		if !ok {
+			continue
+		}
+		label := readSysString(prefix + "_label")
+		if label == "" {
+			label = filepath.Base(prefix)
+		}
+		fans = append(fans, FanSensor{Chip: chip, Label: label, RPM: int(rpm)})
+	}
+	return fans
+}
+
+// thermalZone reads a /sys/class/thermal/thermal_zoneN directory, including
+// its "critical" and "hot" trip points.
+func thermalZone(dir string) (TempSensor, bool) {
+	milli, ok := readSysInt(filepath.Join(dir, "temp"))
+	if !ok {
+		return TempSensor{}, false
+	}
+	zoneType := readSysString(filepath.Join(dir, "type"))
+	if zoneType == "" {
+		zoneType = filepath.Base(dir)
+	}
+	t := TempSensor{Chip: zoneType, Label: filepath.Base(dir), Celsius: milliToCelsius(milli)}
+	for _, typeFile := range sortedGlob(filepath.Join(dir, "trip_point_*_type")) {
+		v, ok := readSysInt(strings.TrimSuffix(typeFile, "_type") + "_temp")
+		if !ok {
+			continue
+		}
+		switch readSysString(typeFile) {
+		case "critical":
+			t.Critical = milliToCelsius(v)
+		case "hot":
+			t.High = milliToCelsius(v)
+		}
+	}
+	return t, true
+}
+
+// MaxCelsius returns the hottest temperature reading, or 0 if there are none.
+func (s *SensorsInfo) MaxCelsius() float64 {
+	var hottest float64
+	for _, t := range s.Temperatures {
+		hottest = max(hottest, t.Celsius)
+	}
+	return hottest
+}
+
+func normalizeChip(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "-", "_"))
+}
+
+func milliToCelsius(v int64) float64 {
+	return round2(float64(v) / 1000)
+}
+
+func readSysString(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func readSysInt(path string) (int64, bool) {
+	v, err := strconv.ParseInt(readSysString(path), 10, 64)
+	return v, err == nil
+}
+
+// sortedGlob returns glob matches in natural order (temp2 before temp10).
+func sortedGlob(pattern string) []string {
+	matches, _ := filepath.Glob(pattern)
+	sort.Slice(matches, func(i, j int) bool {
+		if len(matches[i]) != len(matches[j]) {
+			return len(matches[i]) < len(matches[j])
+		}
+		return matches[i] < matches[j]
+	})
+	return matches
+}
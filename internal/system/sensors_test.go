@@ -0,0 +1,131 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+// fakeSys creates a fake /sys tree with the given files and points
+// sysRoot at it for the duration of the test.
+func fakeSys(t *testing.T, files map[string]string) {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	orig := sysRoot
+	sysRoot = dir
+	t.Cleanup(func() { sysRoot = orig })
+}
+
+func TestReadSensors_Hwmon(t *testing.T) {
+	fakeSys(t, map[string]string{
+		"class/hwmon/hwmon0/name":         "coretemp",
+		"class/hwmon/hwmon0/temp1_input":  "54000",
+		"class/hwmon/hwmon0/temp1_label":  "Package id 0",
+		"class/hwmon/hwmon0/temp1_max":    "80000",
+		"class/hwmon/hwmon0/temp1_crit":   "100000",
+		"class/hwmon/hwmon0/temp2_input":  "51500",
+		"class/hwmon/hwmon0/temp10_input": "49000",
+		"class/hwmon/hwmon1/name":         "nct6775",
+		"class/hwmon/hwmon1/fan1_input":   "1245",
+		"class/hwmon/hwmon1/fan1_label":   "CPU Fan",
+		"class/hwmon/hwmon1/fan2_input":   "garbage",
+	})
+
+	info := readSensors()
+	if len(info.Temperatures) != 3 {
+		t.Fatalf("expected 3 temperatures, got %d: %+v", len(info.Temperatures), info.Temperatures)
+	}
+	pkg := info.Temperatures[0]
+	if pkg.Chip != "coretemp" || pkg.Label != "Package id 0" || pkg.Celsius != 54 {
+		t.Errorf("unexpected first sensor: %+v", pkg)
+	}
+	if pkg.High != 80 || pkg.Critical != 100 {
+		t.Errorf("high/crit = %f/%f, want 80/100", pkg.High, pkg.Critical)
+	}
+	// natural sort: temp2 before temp10, unlabelled sensors fall back to the file prefix
+	if info.Temperatures[1].Label != "temp2" || info.Temperatures[2].Label != "temp10" {
+		t.Errorf("unexpected order: %+v", info.Temperatures)
+	}
+	if len(info.Fans) != 1 || info.Fans[0].RPM != 1245 || info.Fans[0].Label != "CPU Fan" {
+		t.Errorf("unexpected fans: %+v", info.Fans)
+	}
+	if got := info.MaxCelsius(); got != 54 {
+		t.Errorf("MaxCelsius() = %f, want 54", got)
+	}
+}
+
+func TestReadSensors_Device(t *testing.T) {
+	fakeSys(t, map[string]string{
+		"class/hwmon/hwmon0/name":        "nvme",
+		"class/hwmon/hwmon0/temp1_input": "41000",
+		"class/hwmon/hwmon0/temp1_label": "Composite",
+		"class/hwmon/hwmon1/name":        "nvme",
+		"class/hwmon/hwmon1/temp1_input": "45000",
+		"class/hwmon/hwmon1/temp1_label": "Composite",
+	})
+	for i, dev := range []string{"nvme0", "nvme1"} {
+		link := filepath.Join(sysRoot, "class/hwmon", "hwmon"+strconv.Itoa(i), "device")
+		if err := os.Symlink("../../../devices/pci0000:00/"+dev, link); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	info := readSensors()
+	if len(info.Temperatures) != 2 || info.Temperatures[0].Device != "nvme0" || info.Temperatures[1].Device != "nvme1" {
+		t.Errorf("expected each sensor's device, got %+v", info.Temperatures)
+	}
+}
+
+func TestReadSensors_ThermalZones(t *testing.T) {
+	fakeSys(t, map[string]string{
+		// Raspberry Pi: the same sensor appears as hwmon and as a thermal zone
+		"class/hwmon/hwmon0/name":                       "cpu_thermal",
+		"class/hwmon/hwmon0/temp1_input":                "61300",
+		"class/thermal/thermal_zone0/type":              "cpu-thermal",
+		"class/thermal/thermal_zone0/temp":              "61300",
+		"class/thermal/thermal_zone1/type":              "acpitz",
+		"class/thermal/thermal_zone1/temp":              "27800",
+		"class/thermal/thermal_zone1/trip_point_0_type": "critical",
+		"class/thermal/thermal_zone1/trip_point_0_temp": "119000",
+		"class/thermal/thermal_zone1/trip_point_1_type": "hot",
+		"class/thermal/thermal_zone1/trip_point_1_temp": "95000",
+		"class/thermal/thermal_zone1/trip_point_2_type": "passive",
+		"class/thermal/thermal_zone1/trip_point_2_temp": "85000",
+		"class/thermal/thermal_zone2/type":              "broken",
+		"class/thermal/thermal_zone2/trip_point_0_type": "critical",
+		"class/thermal/thermal_zone2/trip_point_0_temp": "100000",
+	})
+
+	info := readSensors()
+	if len(info.Temperatures) != 2 {
+		t.Fatalf("expected hwmon + acpitz (cpu-thermal deduplicated), got %+v", info.Temperatures)
+	}
+	zone := info.Temperatures[1]
+	if zone.Chip != "acpitz" || zone.Label != "thermal_zone1" || zone.Celsius != 27.8 {
+		t.Errorf("unexpected thermal zone: %+v", zone)
+	}
+	if zone.Critical != 119 || zone.High != 95 {
+		t.Errorf("trip points = crit %f / hot %f, want 119/95", zone.Critical, zone.High)
+	}
+}
+
+func TestReadSensors_Empty(t *testing.T) {
+	fakeSys(t, nil)
+	info := readSensors()
+	if info.Temperatures == nil || info.Fans == nil {
+		t.Error("expected empty (non-nil) slices so JSON renders []")
+	}
+	if info.MaxCelsius() != 0 {
+		t.Errorf("MaxCelsius() = %f, want 0", info.MaxCelsius())
+	}
+}
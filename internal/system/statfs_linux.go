@@ -0,0 +1,23 @@
+package system
+
+import "syscall"
+
+// statfsUsage calls statfs(2) on a mount point and returns exact byte counts.
+func statfsUsage(path string) (fsUsage, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return fsUsage{}, err
+	}
+	bsize := uint64(st.Frsize)
+	if bsize == 0 {
+		bsize = uint64(st.Bsize)
+	}
+	return fsUsage{
+		total: st.Blocks * bsize,
+		free:  st.Bfree * bsize,
+		avail: st.Bavail * bsize,
+
+		files:     st.Files,
+		filesFree: st.Ffree,
+	}, nil
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package system
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// statfsUsage is only implemented on Linux; other platforms use df.
+func statfsUsage(path string) (fsUsage, error) {
+	return fsUsage{}, fmt.Errorf("statfs not supported on %s", runtime.GOOS)
+}
@@ -5,43 +5,100 @@ import (
 	"os"
 	"runtime"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Higangssh/homebutler/internal/util"
 )
 
 type StatusInfo struct {
-	Hostname string     `json:"hostname"`
-	OS       string     `json:"os"`
-	Arch     string     `json:"arch"`
-	Uptime   string     `json:"uptime"`
-	CPU      CPUInfo    `json:"cpu"`
-	Memory   MemInfo    `json:"memory"`
-	Disks    []DiskInfo `json:"disks"`
-	Time     string     `json:"time"`
+	Hostname string        `json:"hostname"`
+	OS       string        `json:"os"`
+	Arch     string        `json:"arch"`
+	Uptime   string        `json:"uptime"`
+	CPU      CPUInfo       `json:"cpu"`
+	Memory   MemInfo       `json:"memory"`
+	Disks    []DiskInfo    `json:"disks"`
+	DiskIO   []DiskIOInfo  `json:"disk_io,omitempty"`
+	Network  []NetInfo     `json:"network,omitempty"`
+	Pressure *PressureInfo `json:"pressure,omitempty"`
+	Time     string        `json:"time"`
 }
 
 type CPUInfo struct {
-	UsagePercent float64 `json:"usage_percent"`
-	Cores        int     `json:"cores"`
+	UsagePercent float64   `json:"usage_percent"`
+	Cores        int       `json:"cores"`
+	PerCore      []float64 `json:"per_core,omitempty"`
+	User         float64   `json:"user_percent"`
+	System       float64   `json:"system_percent"`
+	IOWait       float64   `json:"iowait_percent"`
+	Steal        float64   `json:"steal_percent"`
+	Load         LoadAvg   `json:"load"`
+}
+
+// LoadAvg holds the 1, 5 and 15 minute load averages.
+type LoadAvg struct {
+	Load1  float64 `json:"load1"`
+	Load5  float64 `json:"load5"`
+	Load15 float64 `json:"load15"`
 }
 
 type MemInfo struct {
-	TotalGB float64 `json:"total_gb"`
-	UsedGB  float64 `json:"used_gb"`
-	Percent float64 `json:"usage_percent"`
+	TotalGB      float64        `json:"total_gb"`
+	UsedGB       float64        `json:"used_gb"`
+	Percent      float64        `json:"usage_percent"`
+	TotalBytes   uint64         `json:"total_bytes"`
+	UsedBytes    uint64         `json:"used_bytes"`
+	BuffersBytes uint64         `json:"buffers_bytes"`
+	CachedBytes  uint64         `json:"cached_bytes"` // page cache + reclaimable slab
+	Swap         SwapInfo       `json:"swap"`
+	HugePages    *HugePagesInfo `json:"hugepages,omitempty"`
+}
+
+// SwapInfo holds swap space usage. All zero when no swap is configured.
+type SwapInfo struct {
+	TotalGB    float64 `json:"total_gb"`
+	UsedGB     float64 `json:"used_gb"`
+	Percent    float64 `json:"usage_percent"`
+	TotalBytes uint64  `json:"total_bytes"`
+	UsedBytes  uint64  `json:"used_bytes"`
+}
+
+// HugePagesInfo holds the explicit hugepage pool (not transparent hugepages).
+type HugePagesInfo struct {
+	Total     uint64 `json:"total"`
+	Free      uint64 `json:"free"`
+	PageBytes uint64 `json:"page_bytes"`
 }
 
 type DiskInfo struct {
-	Mount   string  `json:"mount"`
-	TotalGB float64 `json:"total_gb"`
-	UsedGB  float64 `json:"used_gb"`
-	Percent float64 `json:"usage_percent"`
+	Mount        string  `json:"mount"`
+	TotalGB      float64 `json:"total_gb"`
+	UsedGB       float64 `json:"used_gb"`
+	Percent      float64 `json:"usage_percent"`
+	TotalBytes   uint64  `json:"total_bytes"`
+	UsedBytes    uint64  `json:"used_bytes"`
+	InodesTotal  uint64  `json:"inodes_total"`
+	InodesUsed   uint64  `json:"inodes_used"`
+	InodePercent float64 `json:"inodes_percent"`
 }
 
+// sampleInterval is the delta window for counters sampled twice (CPU, disk I/O, network).
+const sampleInterval = 200 * time.Millisecond
+
 func Status() (*StatusInfo, error) {
 	hostname, _ := os.Hostname()
 
+	// Disk I/O and network rates need their own sampling window; run them
+	// alongside the CPU sample so status doesn't take three times as long.
+	var (
+		wg     sync.WaitGroup
+		diskIO []DiskIOInfo
+		nets   []NetInfo
+	)
+	wg.Go(func() { diskIO = getDiskIO() })
+	wg.Go(func() { nets = getNetwork() })
+
 	info := &StatusInfo{
 		Hostname: hostname,
 		OS:       runtime.GOOS,
@@ -50,8 +107,12 @@ func Status() (*StatusInfo, error) {
 		CPU:      getCPU(),
 		Memory:   getMemory(),
 		Disks:    getDisks(),
+		Pressure: getPressure(),
 		Time:     time.Now().Format(time.RFC3339),
 	}
+	wg.Wait()
+	info.DiskIO = diskIO
+	info.Network = nets
 
 	return info, nil
 }
@@ -72,35 +133,16 @@ func getUptime() string {
 		var sec int64
 		fmt.Sscanf(secStr, "%d", &sec)
 		boot := time.Unix(sec, 0)
-		dur := time.Since(boot)
-		days := int(dur.Hours() / 24)
-		hours := int(dur.Hours()) % 24
-		if days > 0 {
-			return fmt.Sprintf("%dd %dh", days, hours)
-		}
-		return fmt.Sprintf("%dh %dm", hours, int(dur.Minutes())%60)
+		return formatUptime(time.Since(boot))
 	case "linux":
-		out, err := util.RunCmd("cat", "/proc/uptime")
-		if err != nil {
-			return "unknown"
-		}
-		var secs float64
-		fmt.Sscanf(out, "%f", &secs)
-		dur := time.Duration(secs) * time.Second
-		days := int(dur.Hours() / 24)
-		hours := int(dur.Hours()) % 24
-		if days > 0 {
-			return fmt.Sprintf("%dd %dh", days, hours)
-		}
-		return fmt.Sprintf("%dh %dm", hours, int(dur.Minutes())%60)
+		return linuxUptime()
 	default:
 		return "unknown"
 	}
 }
 
 func getCPU() CPUInfo {
-	cores := runtime.NumCPU()
-	usage := 0.0
+	info := CPUInfo{Cores: runtime.NumCPU()}
 
 	switch runtime.GOOS {
 	case "darwin":
@@ -110,33 +152,48 @@ func getCPU() CPUInfo {
 		if t != nil {
 			// t.total = user+sys+idle, t.idle = idle → usage = (total-idle)/total*100
 			if t.total > 0 {
-				usage = ((t.total - t.idle) / t.total) * 100
+				info.UsagePercent = ((t.total - t.idle) / t.total) * 100
+				info.User = round2(t.user / t.total * 100)
+				info.System = round2(t.system / t.total * 100)
 			}
 		}
+		info.Load = darwinLoadAvg()
 	case "linux":
 		// Read /proc/stat twice with 200ms delta for instant CPU usage
-		t1 := readLinuxCPUTimes()
-		time.Sleep(200 * time.Millisecond)
-		t2 := readLinuxCPUTimes()
+		t1, cores1 := readLinuxCPUStat()
+		time.Sleep(sampleInterval)
+		t2, cores2 := readLinuxCPUStat()
 		if t1 != nil && t2 != nil {
-			usage = cpuDelta(t1, t2)
+			info.UsagePercent = cpuDelta(t1, t2)
+			info.User, info.System, info.IOWait, info.Steal = cpuBreakdown(t1, t2)
+		}
+		if len(cores1) > 0 && len(cores1) == len(cores2) {
+			info.PerCore = make([]float64, len(cores1))
+			for i := range cores1 {
+				info.PerCore[i] = round2(min(cpuDelta(cores1[i], cores2[i]), 100))
+			}
 		}
+		info.Load = linuxLoadAvg()
 	}
 
-	if usage > 100 {
-		usage = 100
+	if info.UsagePercent > 100 {
+		info.UsagePercent = 100
 	}
+	info.UsagePercent = round2(info.UsagePercent)
 
-	return CPUInfo{
-		UsagePercent: round2(usage),
-		Cores:        cores,
-	}
+	return info
 }
 
 // cpuTimes holds cumulative CPU tick counts.
+// idle includes iowait, so usage = (total-idle)/total counts only busy time;
+// the individual components are kept for the breakdown.
 type cpuTimes struct {
-	total float64
-	idle  float64
+	total  float64
+	idle   float64
+	user   float64 // user + nice
+	system float64 // system + irq + softirq
+	iowait float64
+	steal  float64
 }
 
 // cpuDelta calculates CPU usage percentage from two samples.
@@ -149,18 +206,45 @@ func cpuDelta(t1, t2 *cpuTimes) float64 {
 	return ((dTotal - dIdle) / dTotal) * 100
 }
 
-// readLinuxCPUTimes reads cumulative CPU times from /proc/stat.
-func readLinuxCPUTimes() *cpuTimes {
-	data, err := os.ReadFile("/proc/stat")
+// cpuBreakdown returns user, system, iowait and steal percentages between two samples.
+func cpuBreakdown(t1, t2 *cpuTimes) (user, system, iowait, steal float64) {
+	dTotal := t2.total - t1.total
+	if dTotal <= 0 {
+		return 0, 0, 0, 0
+	}
+	pct := func(a, b float64) float64 {
+		return round2(max(b-a, 0) / dTotal * 100)
+	}
+	return pct(t1.user, t2.user), pct(t1.system, t2.system), pct(t1.iowait, t2.iowait), pct(t1.steal, t2.steal)
+}
+
+// readLinuxCPUStat reads the aggregate "cpu" line and the per-core "cpuN"
+// lines from /proc/stat.
+func readLinuxCPUStat() (*cpuTimes, []*cpuTimes) {
+	data, err := readProcFile("stat")
 	if err != nil {
-		return nil
+		return nil, nil
 	}
-	for _, line := range strings.Split(string(data), "\n") {
+	return parseProcStat(string(data))
+}
+
+// parseProcStat parses /proc/stat content into aggregate and per-core times.
+func parseProcStat(data string) (*cpuTimes, []*cpuTimes) {
+	var total *cpuTimes
+	var cores []*cpuTimes
+	for _, line := range strings.Split(data, "\n") {
+		if !strings.HasPrefix(line, "cpu") {
+			continue
+		}
 		if strings.HasPrefix(line, "cpu ") {
-			return parseProcStatLine(line)
+			total = parseProcStatLine(line)
+			continue
+		}
+		if t := parseProcStatLine(line); t != nil {
+			cores = append(cores, t)
 		}
 	}
-	return nil
+	return total, cores
 }
 
 // parseProcStatLine parses a "cpu ..." line from /proc/stat.
@@ -196,7 +280,45 @@ func parseProcStatLine(line string) *cpuTimes {
 		fmt.Sscanf(fields[8], "%f", &steal)
 	}
 	total := user + nice + sys + idle + iowait + irq + softirq + steal
-	return &cpuTimes{total: total, idle: idle + iowait}
+	return &cpuTimes{
+		total:  total,
+		idle:   idle + iowait,
+		user:   user + nice,
+		system: sys + irq + softirq,
+		iowait: iowait,
+		steal:  steal,
+	}
+}
+
+// linuxLoadAvg reads /proc/loadavg.
+func linuxLoadAvg() LoadAvg {
+	data, err := readProcFile("loadavg")
+	if err != nil {
+		return LoadAvg{}
+	}
+	return parseLoadAvg(string(data))
+}
+
+// darwinLoadAvg reads vm.loadavg via sysctl ("{ 1.50 1.63 1.75 }").
+func darwinLoadAvg() LoadAvg {
+	out, err := util.RunCmd("/usr/sbin/sysctl", "-n", "vm.loadavg")
+	if err != nil {
+		return LoadAvg{}
+	}
+	return parseLoadAvg(strings.Trim(out, "{} "))
+}
+
+// parseLoadAvg parses the first three fields of /proc/loadavg-style output.
+func parseLoadAvg(data string) LoadAvg {
+	fields := strings.Fields(data)
+	if len(fields) < 3 {
+		return LoadAvg{}
+	}
+	var l LoadAvg
+	fmt.Sscanf(fields[0], "%f", &l.Load1)
+	fmt.Sscanf(fields[1], "%f", &l.Load5)
+	fmt.Sscanf(fields[2], "%f", &l.Load15)
+	return l
 }
 
 // readDarwinCPUTimes reads cumulative CPU times via host_processor_info.
@@ -232,7 +354,7 @@ func readDarwinCPUTimes() *cpuTimes {
 		return nil
 	}
 	total := user + sys + idle
-	return &cpuTimes{total: total, idle: idle}
+	return &cpuTimes{total: total, idle: idle, user: user, system: sys}
 }
 
 func getMemory() MemInfo {
@@ -244,12 +366,11 @@ func getMemory() MemInfo {
 		}
 		var totalBytes int64
 		fmt.Sscanf(strings.TrimSpace(out), "%d", &totalBytes)
-		totalGB := float64(totalBytes) / (1024 * 1024 * 1024)
 
 		// Get used memory from vm_stat
 		vmOut, err := util.RunCmd("vm_stat")
 		if err != nil {
-			return MemInfo{TotalGB: round2(totalGB)}
+			return memInfoFromBytes(uint64(totalBytes), 0)
 		}
 		pageSize := 16384 // Apple Silicon default
 		var active, wired, speculative int64
@@ -268,40 +389,43 @@ func getMemory() MemInfo {
 			}
 		}
 		usedBytes := (active + wired + speculative) * int64(pageSize)
-		usedGB := float64(usedBytes) / (1024 * 1024 * 1024)
-
-		return MemInfo{
-			TotalGB: round2(totalGB),
-			UsedGB:  round2(usedGB),
-			Percent: round2((usedGB / totalGB) * 100),
+		info := memInfoFromBytes(uint64(totalBytes), uint64(usedBytes))
+		if out, err := util.RunCmd("/usr/sbin/sysctl", "-n", "vm.swapusage"); err == nil {
+			info.Swap = parseDarwinSwap(out)
 		}
+		return info
 	case "linux":
-		out, err := util.RunCmd("cat", "/proc/meminfo")
-		if err != nil {
-			return MemInfo{}
-		}
-		var totalKB, availKB int64
-		for _, line := range strings.Split(out, "\n") {
-			if strings.HasPrefix(line, "MemTotal:") {
-				fmt.Sscanf(line, "MemTotal: %d kB", &totalKB)
-			}
-			if strings.HasPrefix(line, "MemAvailable:") {
-				fmt.Sscanf(line, "MemAvailable: %d kB", &availKB)
-			}
-		}
-		totalGB := float64(totalKB) / (1024 * 1024)
-		usedGB := float64(totalKB-availKB) / (1024 * 1024)
-		return MemInfo{
-			TotalGB: round2(totalGB),
-			UsedGB:  round2(usedGB),
-			Percent: round2((usedGB / totalGB) * 100),
-		}
+		return linuxMemory()
 	default:
 		return MemInfo{}
 	}
 }
 
+// parseDarwinSwap parses sysctl vm.swapusage:
+// "total = 2048.00M  used = 1024.50M  free = 1023.50M  (encrypted)"
+func parseDarwinSwap(out string) SwapInfo {
+	var total, used uint64
+	fields := strings.Fields(out)
+	for i := 0; i+2 < len(fields); i++ {
+		if fields[i+1] != "=" {
+			continue
+		}
+		v := uint64(parseSize(fields[i+2]) * 1024 * 1024 * 1024)
+		switch fields[i] {
+		case "total":
+			total = v
+		case "used":
+			used = v
+		}
+	}
+	return swapInfoFromBytes(total, used)
+}
+
 func getDisks() []DiskInfo {
+	if runtime.GOOS == "linux" {
+		return linuxDisks()
+	}
+
 	out, err := util.RunCmd("df", "-h")
 	if err != nil {
 		return nil
@@ -315,7 +439,7 @@ func getDisks() []DiskInfo {
 		}
 		mount := fields[len(fields)-1]
 		// Only show relevant mounts
-		if mount == "/" || strings.HasPrefix(mount, "/home") || strings.HasPrefix(mount, "/mnt") || strings.HasPrefix(mount, "/Volumes") {
+		if isRelevantMount(mount) {
 			var total, used float64
 			var percent float64
 			total = parseSize(fields[1])
@@ -323,12 +447,26 @@ func getDisks() []DiskInfo {
 			pctStr := strings.TrimSuffix(fields[4], "%")
 			fmt.Sscanf(pctStr, "%f", &percent)
 
-			disks = append(disks, DiskInfo{
-				Mount:   mount,
-				TotalGB: round2(total),
-				UsedGB:  round2(used),
-				Percent: percent,
-			})
+			disk := DiskInfo{
+				Mount:      mount,
+				TotalGB:    round2(total),
+				UsedGB:     round2(used),
+				Percent:    percent,
+				TotalBytes: uint64(total * 1024 * 1024 * 1024),
+				UsedBytes:  uint64(used * 1024 * 1024 * 1024),
+			}
+			// macOS df also prints: iused ifree %iused
+			if len(fields) >= 9 {
+				var iused, ifree uint64
+				fmt.Sscanf(fields[5], "%d", &iused)
+				fmt.Sscanf(fields[6], "%d", &ifree)
+				if iused+ifree > 0 {
+					disk.InodesTotal = iused + ifree
+					disk.InodesUsed = iused
+					disk.InodePercent = round2(float64(iused) / float64(iused+ifree) * 100)
+				}
+			}
+			disks = append(disks, disk)
 		}
 	}
 	return disks
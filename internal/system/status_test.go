@@ -201,19 +201,22 @@ func TestReadDarwinCPUTimes(t *testing.T) {
 	}
 }
 
-// --- readLinuxCPUTimes integration test ---
+// --- readLinuxCPUStat integration test ---
 
-func TestReadLinuxCPUTimes(t *testing.T) {
+func TestReadLinuxCPUStat(t *testing.T) {
 	if runtime.GOOS != "linux" {
 		t.Skip("skipping linux-only test")
 	}
-	result := readLinuxCPUTimes()
+	result, cores := readLinuxCPUStat()
 	if result == nil {
 		t.Fatal("expected non-nil result on linux")
 	}
 	if result.total <= 0 {
 		t.Errorf("expected total > 0, got %f", result.total)
 	}
+	if len(cores) == 0 {
+		t.Error("expected per-core times on linux")
+	}
 }
 
 // --- getCPU tests ---
@@ -234,3 +237,72 @@ func TestGetCPU_CoresMatchRuntime(t *testing.T) {
 		t.Errorf("expected cores = %d (runtime.NumCPU()), got %d", runtime.NumCPU(), cpu.Cores)
 	}
 }
+
+// --- per-core / breakdown / load average tests ---
+
+func TestParseProcStat(t *testing.T) {
+	data := `cpu  400 0 200 1000 100 0 0 50 0 0
+cpu0 200 0 100 500 50 0 0 25 0 0
+cpu1 200 0 100 500 50 0 0 25 0 0
+intr 12345 0 0
+ctxt 67890
+`
+	total, cores := parseProcStat(data)
+	if total == nil {
+		t.Fatal("expected aggregate cpu line")
+	}
+	if len(cores) != 2 {
+		t.Fatalf("expected 2 cores, got %d", len(cores))
+	}
+	if total.iowait != 100 || total.steal != 50 {
+		t.Errorf("iowait/steal = %f/%f, want 100/50", total.iowait, total.steal)
+	}
+}
+
+func TestCpuBreakdown(t *testing.T) {
+	t1 := &cpuTimes{total: 1000, idle: 500, user: 300, system: 100, iowait: 50, steal: 50}
+	t2 := &cpuTimes{total: 2000, idle: 900, user: 700, system: 200, iowait: 150, steal: 100}
+	user, sys, iowait, steal := cpuBreakdown(t1, t2)
+	if user != 40 || sys != 10 || iowait != 10 || steal != 5 {
+		t.Errorf("cpuBreakdown() = %v/%v/%v/%v, want 40/10/10/5", user, sys, iowait, steal)
+	}
+	if u, _, _, _ := cpuBreakdown(t2, t2); u != 0 {
+		t.Errorf("expected 0 for no delta, got %f", u)
+	}
+}
+
+func TestParseLoadAvg(t *testing.T) {
+	got := parseLoadAvg("0.52 0.58 0.59 1/1234 5678\n")
+	want := LoadAvg{Load1: 0.52, Load5: 0.58, Load15: 0.59}
+	if got != want {
+		t.Errorf("parseLoadAvg() = %+v, want %+v", got, want)
+	}
+	if got := parseLoadAvg("garbage"); got != (LoadAvg{}) {
+		t.Errorf("expected zero LoadAvg for bad input, got %+v", got)
+	}
+}
+
+func TestGetCPU_PerCore(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("skipping linux-only test")
+	}
+	cpu := getCPU()
+	if len(cpu.PerCore) == 0 {
+		t.Fatal("expected per-core usage on linux")
+	}
+	for i, v := range cpu.PerCore {
+		if v < 0 || v > 100 {
+			t.Errorf("core %d usage out of range: %f", i, v)
+		}
+	}
+}
+
+func TestParseDarwinSwap(t *testing.T) {
+	swap := parseDarwinSwap("total = 2048.00M  used = 512.00M  free = 1536.00M  (encrypted)")
+	if swap.TotalBytes != 2*1024*1024*1024 || swap.UsedGB != 0.5 || swap.Percent != 25 {
+		t.Errorf("unexpected swap: %+v", swap)
+	}
+	if got := parseDarwinSwap("total = 0.00M  used = 0.00M  free = 0.00M"); got.Percent != 0 {
+		t.Errorf("expected empty swap, got %+v", got)
+	}
+}
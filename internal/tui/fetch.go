@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -23,7 +22,8 @@ type ServerData struct {
 	Name         string
 	Status       *system.StatusInfo
 	Containers   []docker.Container
-	DockerStatus string // "ok", "not_installed", "unavailable", ""
+	DockerStatus string // "ok", "not_installed", "permission_denied", "unavailable", ""
+	DockerStats  []docker.ContainerStats
 	Alerts       *alerts.AlertResult
 	Processes    []system.ProcessInfo
 	Error        error
@@ -31,16 +31,16 @@ type ServerData struct {
 }
 
 // fetchServer collects data from a server (local or remote) with a timeout.
-func fetchServer(srv *config.ServerConfig, alertCfg *config.AlertConfig) ServerData {
+func fetchServer(srv *config.ServerConfig, cfg *config.Config) ServerData {
 	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
 	defer cancel()
 
 	ch := make(chan ServerData, 1)
 	go func() {
 		if srv.Local {
-			ch <- fetchLocal(alertCfg)
+			ch <- fetchLocal(srv, cfg)
 		} else {
-			ch <- fetchRemote(srv, alertCfg)
+			ch <- fetchRemote(srv, cfg)
 		}
 	}()
 
@@ -58,7 +58,7 @@ func fetchServer(srv *config.ServerConfig, alertCfg *config.AlertConfig) ServerD
 
 // fetchLocal gathers system status and alerts locally.
 // Docker is skipped here and fetched separately to avoid blocking.
-func fetchLocal(alertCfg *config.AlertConfig) ServerData {
+func fetchLocal(srv *config.ServerConfig, cfg *config.Config) ServerData {
 	data := ServerData{LastUpdate: time.Now()}
 
 	status, err := system.Status()
@@ -69,7 +69,7 @@ func fetchLocal(alertCfg *config.AlertConfig) ServerData {
 	data.Status = status
 	data.Name = status.Hostname
 
-	alertResult, _ := alerts.Check(alertCfg)
+	alertResult, _ := alerts.Evaluate(cfg, srv)
 	data.Alerts = alertResult
 
 	procs, _ := system.TopProcesses(5)
@@ -115,17 +115,9 @@ func fetchDocker() ([]docker.Container, string) {
 	select {
 	case res := <-ch:
 		var containers []docker.Container
-		var status string
-		if res.err != nil {
-			errMsg := res.err.Error()
-			if strings.Contains(errMsg, "not installed") || strings.Contains(errMsg, "not found") {
-				status = "not_installed"
-			} else {
-				status = "unavailable"
-			}
-		} else {
+		status := docker.ClassifyError(res.err)
+		if res.err == nil {
 			containers = res.containers
-			status = "ok"
 		}
 		dockerCacheMu.Lock()
 		dockerCacheCtr, dockerCacheSt = containers, status
@@ -140,8 +132,59 @@ func fetchDocker() ([]docker.Container, string) {
 	}
 }
 
+// statsCache holds the last container stats of a server. Sampling takes
+// the daemon about a second per refresh, plus the SSH round trip for a
+// remote one, so it runs in the background and the panel shows the
+// previous sample.
+type statsCache struct {
+	running atomic.Bool
+	mu      sync.Mutex
+	stats   []docker.ContainerStats
+}
+
+// statsCaches maps a server name, "" for this machine, to its *statsCache.
+var statsCaches sync.Map
+
+// cachedStats returns the cached stats of a server and starts a refresh
+// with sample unless one is already running.
+func cachedStats(server string, sample func() ([]docker.ContainerStats, error)) []docker.ContainerStats {
+	v, _ := statsCaches.LoadOrStore(server, &statsCache{})
+	c := v.(*statsCache)
+	if c.running.CompareAndSwap(false, true) {
+		go func() {
+			defer c.running.Store(false)
+			stats, _ := sample()
+			c.mu.Lock()
+			c.stats = stats
+			c.mu.Unlock()
+		}()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+// fetchDockerStats returns the cached local stats and starts a refresh
+// unless one is already running.
+func fetchDockerStats() []docker.ContainerStats {
+	return cachedStats("", docker.Stats)
+}
+
+// fetchRemoteStats is fetchDockerStats for a remote server.
+func fetchRemoteStats(srv *config.ServerConfig) []docker.ContainerStats {
+	return cachedStats(srv.Name, func() ([]docker.ContainerStats, error) {
+		out, err := remote.Run(srv, "docker", "stats", "--json")
+		if err != nil {
+			return nil, err
+		}
+		var stats []docker.ContainerStats
+		err = json.Unmarshal(out, &stats)
+		return stats, err
+	})
+}
+
 // fetchRemote collects data from a remote server via SSH.
-func fetchRemote(srv *config.ServerConfig, alertCfg *config.AlertConfig) ServerData {
+func fetchRemote(srv *config.ServerConfig, cfg *config.Config) ServerData {
 	data := ServerData{
 		Name:       srv.Name,
 		LastUpdate: time.Now(),
@@ -162,12 +205,8 @@ func fetchRemote(srv *config.ServerConfig, alertCfg *config.AlertConfig) ServerD
 	// Docker containers (non-fatal)
 	out, err = remote.Run(srv, "docker", "list", "--json")
 	if err != nil {
-		errMsg := err.Error()
-		if strings.Contains(errMsg, "not installed") || strings.Contains(errMsg, "not found") {
-			data.DockerStatus = "not_installed"
-		} else {
-			data.DockerStatus = "unavailable"
-		}
+		// The remote's error comes back as text; ClassifyError matches it.
+		data.DockerStatus = docker.ClassifyError(err)
 	} else {
 		var containers []docker.Container
 		if json.Unmarshal(out, &containers) == nil {
@@ -177,14 +216,13 @@ func fetchRemote(srv *config.ServerConfig, alertCfg *config.AlertConfig) ServerD
 			data.DockerStatus = "unavailable"
 		}
 	}
+	if data.DockerStatus == "ok" && len(data.Containers) > 0 {
+		data.DockerStats = fetchRemoteStats(srv)
+	}
 
 	// Alerts (non-fatal)
-	out, err = remote.Run(srv, "alerts", "--json")
-	if err == nil {
-		var alertResult alerts.AlertResult
-		if json.Unmarshal(out, &alertResult) == nil {
-			data.Alerts = &alertResult
-		}
+	if alertResult, err := alerts.Evaluate(cfg, srv); err == nil {
+		data.Alerts = alertResult
 	}
 
 	return data
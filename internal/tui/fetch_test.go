@@ -0,0 +1,49 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Higangssh/homebutler/internal/docker"
+)
+
+func TestCachedStatsDoesNotBlock(t *testing.T) {
+	t.Cleanup(func() { statsCaches.Delete("slow-host") })
+	release := make(chan struct{})
+	sampled := make(chan struct{})
+	plex := func() ([]docker.ContainerStats, error) {
+		return []docker.ContainerStats{{Name: "plex", CPUPercent: 12}}, nil
+	}
+	sample := func() ([]docker.ContainerStats, error) {
+		<-release
+		defer close(sampled)
+		return plex()
+	}
+
+	start := time.Now()
+	if stats := cachedStats("slow-host", sample); stats != nil {
+		t.Errorf("expected no stats before the first sample, got %+v", stats)
+	}
+	// a refresh is already running: no second sampler starts
+	cachedStats("slow-host", func() ([]docker.ContainerStats, error) {
+		t.Error("second sample started while one was running")
+		return nil, nil
+	})
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("cachedStats waited for the sample (%s)", d)
+	}
+
+	close(release)
+	<-sampled
+	deadline := time.Now().Add(time.Second)
+	for {
+		stats := cachedStats("slow-host", plex)
+		if len(stats) == 1 && stats[0].Name == "plex" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the sample cached, got %+v", stats)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -29,6 +29,7 @@ type dockerMsg struct {
 	index      int
 	containers []docker.Container
 	status     string
+	stats      []docker.ContainerStats
 }
 
 // serverTab represents one monitored server in the dashboard.
@@ -37,6 +38,7 @@ type serverTab struct {
 	data       ServerData
 	cpuHistory []float64
 	memHistory []float64
+	netHistory []float64 // total rx+tx bytes/sec across interfaces
 }
 
 // Model is the Bubble Tea model for the watch dashboard.
@@ -143,14 +145,13 @@ func (m Model) Init() tea.Cmd {
 		srv := m.servers[i]
 		// System data (fast)
 		cmds = append(cmds, func() tea.Msg {
-			data := fetchServer(srv.config, &m.cfg.Alerts)
+			data := fetchServer(srv.config, m.cfg)
 			return dataMsg{index: idx, data: data}
 		})
 		// Docker data separately (may be slow on local)
 		if srv.config.Local {
 			cmds = append(cmds, func() tea.Msg {
-				containers, status := fetchDocker()
-				return dockerMsg{index: idx, containers: containers, status: status}
+				return fetchDockerMsg(idx)
 			})
 		}
 	}
@@ -158,6 +159,16 @@ func (m Model) Init() tea.Cmd {
 	return tea.Batch(cmds...)
 }
 
+// fetchDockerMsg fetches local containers and, when docker is up, their stats.
+func fetchDockerMsg(idx int) dockerMsg {
+	containers, status := fetchDocker()
+	msg := dockerMsg{index: idx, containers: containers, status: status}
+	if status == "ok" {
+		msg.stats = fetchDockerStats()
+	}
+	return msg
+}
+
 func tickCmd() tea.Cmd {
 	return tea.Tick(refreshInterval, func(t time.Time) tea.Msg {
 		return tickMsg(t)
@@ -192,12 +203,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			idx := i
 			srv := m.servers[i]
 			cmds = append(cmds, func() tea.Msg {
-				return dataMsg{index: idx, data: fetchServer(srv.config, &m.cfg.Alerts)}
+				return dataMsg{index: idx, data: fetchServer(srv.config, m.cfg)}
 			})
 			if srv.config.Local {
 				cmds = append(cmds, func() tea.Msg {
-					containers, status := fetchDocker()
-					return dockerMsg{index: idx, containers: containers, status: status}
+					return fetchDockerMsg(idx)
 				})
 			}
 		}
@@ -209,10 +219,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Preserve docker data (fetched separately)
 			prevDocker := m.servers[msg.index].data.DockerStatus
 			prevContainers := m.servers[msg.index].data.Containers
+			prevStats := m.servers[msg.index].data.DockerStats
 			m.servers[msg.index].data = msg.data
 			if msg.data.DockerStatus == "" && prevDocker != "" {
 				m.servers[msg.index].data.DockerStatus = prevDocker
 				m.servers[msg.index].data.Containers = prevContainers
+				m.servers[msg.index].data.DockerStats = prevStats
 			}
 			// Track CPU/Memory history for sparklines
 			if msg.data.Status != nil {
@@ -220,6 +232,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.servers[msg.index].cpuHistory, msg.data.Status.CPU.UsagePercent)
 				m.servers[msg.index].memHistory = appendHistory(
 					m.servers[msg.index].memHistory, msg.data.Status.Memory.Percent)
+				m.servers[msg.index].netHistory = appendHistory(
+					m.servers[msg.index].netHistory, netThroughput(msg.data.Status.Network))
 			}
 		}
 
@@ -227,6 +241,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.index >= 0 && msg.index < len(m.servers) {
 			m.servers[msg.index].data.DockerStatus = msg.status
 			m.servers[msg.index].data.Containers = msg.containers
+			m.servers[msg.index].data.DockerStats = msg.stats
 		}
 	}
 
@@ -312,6 +327,11 @@ func (m Model) renderSystemPanel(data ServerData, w panelWidths) string {
 		// Metrics: progress bars with no blank lines between them
 		lines = append(lines, fmt.Sprintf("  CPU  %s %5.1f%%",
 			progressBar(s.CPU.UsagePercent, bw), s.CPU.UsagePercent))
+		if len(s.CPU.PerCore) > 0 {
+			// One block per core, same scale as the history sparklines
+			rendered := sparklineColor([]float64{s.CPU.UsagePercent}).Render(sparkline(s.CPU.PerCore, bw))
+			lines = append(lines, "  "+dimStyle.Render("core")+" "+rendered)
+		}
 		lines = append(lines, fmt.Sprintf("  Mem  %s %5.1f%%",
 			progressBar(s.Memory.Percent, bw), s.Memory.Percent))
 		for _, d := range s.Disks {
@@ -350,12 +370,39 @@ func (m Model) renderSystemPanel(data ServerData, w panelWidths) string {
 				dimStyle.Render(strings.Repeat("▁", bw))))
 		}
 
+		// Net sparkline: throughput relative to the window's peak, colored by
+		// the busiest link's utilization
+		if len(s.Network) > 0 {
+			var util float64
+			for _, n := range s.Network {
+				util = max(util, n.Utilization)
+			}
+			rendered := sparklineColor([]float64{util}).Render(sparkline(scaleHistory(tab.netHistory), bw))
+			lines = append(lines, "  Net "+lipgloss.NewStyle().Width(bw).Render(rendered)+" "+
+				dimStyle.Render(compactRate(netThroughput(s.Network))))
+		}
+
 		// Info section
 		lines = append(lines, "")
 		lines = append(lines, fmt.Sprintf("  Uptime:  %s", s.Uptime))
 		lines = append(lines, fmt.Sprintf("  OS:      %s/%s", s.OS, s.Arch))
 		lines = append(lines, fmt.Sprintf("  Cores:   %d", s.CPU.Cores))
+		lines = append(lines, fmt.Sprintf("  Load:    %.2f %.2f %.2f", s.CPU.Load.Load1, s.CPU.Load.Load5, s.CPU.Load.Load15))
+		lines = append(lines, fmt.Sprintf("  IO wait: %s  Steal: %s",
+			cpuWaitStyle(s.CPU.IOWait).Render(fmt.Sprintf("%.1f%%", s.CPU.IOWait)),
+			cpuWaitStyle(s.CPU.Steal).Render(fmt.Sprintf("%.1f%%", s.CPU.Steal))))
 		lines = append(lines, fmt.Sprintf("  Memory:  %.1f / %.1f GB", s.Memory.UsedGB, s.Memory.TotalGB))
+		if s.Memory.Swap.TotalBytes > 0 {
+			lines = append(lines, fmt.Sprintf("  Swap:    %.1f / %.1f GB", s.Memory.Swap.UsedGB, s.Memory.Swap.TotalGB))
+		}
+		if len(s.DiskIO) > 0 {
+			var read, write float64
+			for _, d := range s.DiskIO {
+				read += d.ReadBytesPerSec
+				write += d.WriteBytesPerSec
+			}
+			lines = append(lines, fmt.Sprintf("  Disk IO: r %s  w %s", compactRate(read), compactRate(write)))
+		}
 	} else {
 		lines = append(lines, dimStyle.Render("  Waiting for data..."))
 	}
@@ -374,14 +421,29 @@ func (m Model) renderRightPanel(data ServerData, w panelWidths) string {
 
 	switch data.DockerStatus {
 	case "not_installed":
-		lines = append(lines, dimStyle.Render("  Docker not installed"))
+		lines = append(lines, dimStyle.Render("  No Docker or Podman socket found"))
+	case "permission_denied":
+		lines = append(lines, warningStyle.Render("  Docker socket permission denied (docker group?)"))
 	case "unavailable":
 		lines = append(lines, warningStyle.Render("  Docker unavailable (daemon not running?)"))
 	case "ok":
 		if len(data.Containers) == 0 {
 			lines = append(lines, dimStyle.Render("  No containers"))
 		} else {
+			// With stats, resource columns replace image and status; I/O
+			// columns only when the panel is wide enough.
+			stats := make(map[string]docker.ContainerStats, len(data.DockerStats))
+			for _, s := range data.DockerStats {
+				stats[s.Name] = s
+			}
+			withIO := w.rightW-4 >= 66
 			header := fmt.Sprintf("  %-18s %-10s %-10s %s", "NAME", "STATE", "IMAGE", "STATUS")
+			if len(stats) > 0 {
+				header = fmt.Sprintf("  %-15s %-9s %6s %7s", "NAME", "STATE", "CPU%", "MEM")
+				if withIO {
+					header += fmt.Sprintf(" %-11s %-11s", "NET I/O", "BLOCK I/O")
+				}
+			}
 			lines = append(lines, headerStyle.Render(header))
 
 			maxContainers := 8
@@ -390,20 +452,43 @@ func (m Model) renderRightPanel(data ServerData, w panelWidths) string {
 				shown = shown[:maxContainers]
 			}
 			for _, c := range shown {
+				stateW := 10
+				if len(stats) > 0 {
+					stateW = 9
+				}
+				state := fmt.Sprintf("%-*s", stateW, truncate(c.State, stateW))
 				var stateStr string
 				switch c.State {
 				case "running":
-					stateStr = okStyle.Render(fmt.Sprintf("%-10s", c.State))
+					stateStr = okStyle.Render(state)
 				case "exited":
-					stateStr = criticalStyle.Render(fmt.Sprintf("%-10s", c.State))
+					stateStr = criticalStyle.Render(state)
 				default:
-					stateStr = warningStyle.Render(fmt.Sprintf("%-10s", c.State))
+					stateStr = warningStyle.Render(state)
+				}
+				if len(stats) == 0 {
+					lines = append(lines, fmt.Sprintf("  %-18s %s %-10s %s",
+						truncate(c.Name, 18),
+						stateStr,
+						truncate(c.Image, 10),
+						truncate(c.Status, 20)))
+					continue
+				}
+				s, ok := stats[c.Name]
+				if !ok {
+					lines = append(lines, fmt.Sprintf("  %-15s %s %6s %7s", truncate(c.Name, 15), stateStr, "-", "-"))
+					continue
 				}
-				line := fmt.Sprintf("  %-18s %s %-10s %s",
-					truncate(c.Name, 18),
+				line := fmt.Sprintf("  %-15s %s %5.1f%% %7s",
+					truncate(c.Name, 15),
 					stateStr,
-					truncate(c.Image, 10),
-					truncate(c.Status, 20))
+					s.CPUPercent,
+					compactBytes(float64(s.MemUsageBytes)))
+				if withIO {
+					line += fmt.Sprintf(" %-11s %-11s",
+						compactBytes(float64(s.NetRxBytes))+"/"+compactBytes(float64(s.NetTxBytes)),
+						compactBytes(float64(s.BlockReadBytes))+"/"+compactBytes(float64(s.BlockWriteBytes)))
+				}
 				lines = append(lines, line)
 			}
 			if len(data.Containers) > maxContainers {
@@ -452,10 +537,51 @@ func (m Model) renderFooter(w panelWidths) string {
 				alertStyle(a.CPU.Status).Render(fmt.Sprintf("CPU: %.0f%%", a.CPU.Current)))
 			alertParts = append(alertParts,
 				alertStyle(a.Memory.Status).Render(fmt.Sprintf("Mem: %.0f%%", a.Memory.Current)))
+			if a.Swap != nil && a.Swap.Status != "ok" {
+				alertParts = append(alertParts,
+					alertStyle(a.Swap.Status).Render(fmt.Sprintf("Swap: %.0f%%", a.Swap.Current)))
+			}
+			if a.MemoryPressure != nil && a.MemoryPressure.Status != "ok" {
+				alertParts = append(alertParts,
+					alertStyle(a.MemoryPressure.Status).Render(fmt.Sprintf("PSI mem: %.0f%%", a.MemoryPressure.Current)))
+			}
 			for _, d := range a.Disks {
 				alertParts = append(alertParts,
 					alertStyle(d.Status).Render(fmt.Sprintf("Disk %s: %.0f%%", d.Mount, d.Current)))
 			}
+			for _, d := range a.Inodes {
+				if d.Status != "ok" {
+					alertParts = append(alertParts,
+						alertStyle(d.Status).Render(fmt.Sprintf("Inodes %s: %.0f%%", d.Mount, d.Current)))
+				}
+			}
+			// Only the hottest sensor: boards often expose a dozen of them
+			if len(a.Temperatures) > 0 {
+				hottest := a.Temperatures[0]
+				for _, t := range a.Temperatures[1:] {
+					if t.Current > hottest.Current {
+						hottest = t
+					}
+				}
+				alertParts = append(alertParts,
+					alertStyle(hottest.Status).Render(fmt.Sprintf("Temp: %.0f°C", hottest.Current)))
+			}
+			// Network only when something is wrong; every link would be noise
+			for _, n := range a.Network {
+				if n.Status == "ok" {
+					continue
+				}
+				label := fmt.Sprintf("%s: %.0f%%", n.Interface, n.Current)
+				if n.Metric == "errors" {
+					label = fmt.Sprintf("%s: %.0f err/s", n.Interface, n.Current)
+				}
+				alertParts = append(alertParts, alertStyle(n.Status).Render(label))
+			}
+			for _, c := range a.Containers {
+				if c.Status != "ok" {
+					alertParts = append(alertParts, alertStyle(c.Status).Render(c.Message))
+				}
+			}
 			parts = append(parts, "  Alerts: "+strings.Join(alertParts, "  "))
 		}
 	}
@@ -19,7 +19,7 @@ func testConfig() *config.Config {
 			{Name: "rpi5", Host: "192.168.1.10", Local: true},
 			{Name: "nas", Host: "192.168.1.20"},
 		},
-		Alerts: config.AlertConfig{CPU: 90, Memory: 85, Disk: 90},
+		Alerts: config.AlertConfig{CPU: config.Threshold{Critical: 90}, Memory: config.Threshold{Critical: 85}, Disk: config.Threshold{Critical: 90}},
 	}
 }
 
@@ -52,7 +52,7 @@ func TestNewModel_SpecificServer(t *testing.T) {
 }
 
 func TestNewModel_NoServers_FallbackLocal(t *testing.T) {
-	cfg := &config.Config{Alerts: config.AlertConfig{CPU: 90, Memory: 85, Disk: 90}}
+	cfg := &config.Config{Alerts: config.AlertConfig{CPU: config.Threshold{Critical: 90}, Memory: config.Threshold{Critical: 85}, Disk: config.Threshold{Critical: 90}}}
 	m := NewModel(cfg, nil)
 	if len(m.servers) != 1 {
 		t.Fatalf("expected 1 fallback server, got %d", len(m.servers))
@@ -169,6 +169,9 @@ func TestUpdate_DataMsg(t *testing.T) {
 	if model.servers[0].data.Status.CPU.UsagePercent != 42.5 {
 		t.Errorf("expected CPU 42.5, got %.1f", model.servers[0].data.Status.CPU.UsagePercent)
 	}
+	if len(model.servers[0].netHistory) != 1 {
+		t.Errorf("expected one network history sample, got %d", len(model.servers[0].netHistory))
+	}
 }
 
 func TestUpdate_DataMsgOutOfBounds(t *testing.T) {
@@ -203,7 +206,7 @@ func TestView_Quitting(t *testing.T) {
 func TestView_WithData(t *testing.T) {
 	cfg := testConfig()
 	m := NewModel(cfg, nil)
-	m.width = 100
+	m.width = 120 // wide enough for the full alerts footer
 	m.height = 30
 
 	m.servers[0].data = ServerData{
@@ -213,9 +216,19 @@ func TestView_WithData(t *testing.T) {
 			OS:       "linux",
 			Arch:     "arm64",
 			Uptime:   "5d 3h",
-			CPU:      system.CPUInfo{UsagePercent: 45.2, Cores: 4},
-			Memory:   system.MemInfo{TotalGB: 8, UsedGB: 4.2, Percent: 52.5},
-			Disks:    []system.DiskInfo{{Mount: "/", TotalGB: 64, UsedGB: 30, Percent: 47}},
+			CPU: system.CPUInfo{
+				UsagePercent: 45.2, Cores: 4, PerCore: []float64{20, 40, 60, 60},
+				IOWait: 12.5, Steal: 0.3, Load: system.LoadAvg{Load1: 1.25, Load5: 0.9, Load15: 0.75},
+			},
+			Memory: system.MemInfo{TotalGB: 8, UsedGB: 4.2, Percent: 52.5,
+				Swap: system.SwapInfo{TotalGB: 2, UsedGB: 1.5, Percent: 75, TotalBytes: 2 << 30}},
+			Disks: []system.DiskInfo{{Mount: "/", TotalGB: 64, UsedGB: 30, Percent: 47}},
+			DiskIO: []system.DiskIOInfo{
+				{Device: "mmcblk0", ReadBytesPerSec: 2048, WriteBytesPerSec: 100, AwaitMs: 3.5},
+			},
+			Network: []system.NetInfo{
+				{Interface: "eth0", State: "up", SpeedMbps: 1000, RxBytesPerSec: 1.5 * 1024 * 1024, TxBytesPerSec: 512 * 1024},
+			},
 		},
 		DockerStatus: "ok",
 		Containers: []docker.Container{
@@ -225,9 +238,20 @@ func TestView_WithData(t *testing.T) {
 		Alerts: &alerts.AlertResult{
 			CPU:    alerts.AlertItem{Status: "ok", Current: 45.2, Threshold: 90},
 			Memory: alerts.AlertItem{Status: "ok", Current: 52.5, Threshold: 85},
+			Swap:   &alerts.AlertItem{Status: "warning", Current: 75, Threshold: 80},
 			Disks:  []alerts.DiskAlert{{Mount: "/", Status: "ok", Current: 47, Threshold: 90}},
+			Inodes: []alerts.DiskAlert{{Mount: "/", Status: "warning", Current: 83, Threshold: 90}},
+			Temperatures: []alerts.TempAlert{
+				{Sensor: "cpu_thermal/temp1", Status: "warning", Current: 74, Threshold: 80},
+				{Sensor: "nvme/Composite", Status: "ok", Current: 41, Threshold: 80},
+			},
+			Network: []alerts.NetAlert{
+				{Interface: "eth0", Metric: "utilization", Status: "ok", Current: 1.7, Threshold: 90},
+				{Interface: "eth0", Metric: "errors", Status: "critical", Current: 15, Threshold: 10},
+			},
 		},
 	}
+	m.servers[0].netHistory = []float64{1024, 2 * 1024 * 1024}
 
 	v := m.View()
 
@@ -249,6 +273,18 @@ func TestView_WithData(t *testing.T) {
 	if !strings.Contains(v, "5d 3h") {
 		t.Error("expected uptime value '5d 3h'")
 	}
+	if !strings.Contains(v, "1.25 0.90 0.75") {
+		t.Error("expected load averages in system panel")
+	}
+	if !strings.Contains(v, "12.5%") {
+		t.Error("expected iowait percentage in system panel")
+	}
+	if !strings.Contains(v, "Net") || !strings.Contains(v, "2.0M/s") {
+		t.Error("expected network sparkline with current throughput")
+	}
+	if !strings.Contains(v, "Disk IO: r 2.0K/s  w 100B/s") {
+		t.Error("expected disk I/O summary in system panel")
+	}
 
 	// Check docker panel
 	if !strings.Contains(v, "Docker") {
@@ -262,6 +298,18 @@ func TestView_WithData(t *testing.T) {
 	}
 
 	// Check footer
+	if !strings.Contains(v, "Temp: 74°C") {
+		t.Error("expected hottest temperature in footer")
+	}
+	if !strings.Contains(v, "eth0: 15 err/s") || strings.Contains(v, "eth0: 2%") {
+		t.Error("expected only non-ok network alerts in footer")
+	}
+	if !strings.Contains(v, "Swap: 75%") || !strings.Contains(v, "Swap:    1.5 / 2.0 GB") {
+		t.Error("expected swap usage in system panel and footer")
+	}
+	if !strings.Contains(v, "Inodes /: 83%") {
+		t.Error("expected inode warning in footer")
+	}
 	if !strings.Contains(v, "quit") {
 		t.Error("expected keybinding hints in footer")
 	}
@@ -304,6 +352,41 @@ func TestView_EmptyContainers(t *testing.T) {
 	}
 }
 
+func TestView_DockerStats(t *testing.T) {
+	cfg := testConfig()
+	m := NewModel(cfg, nil)
+	m.width = 120
+	m.height = 30
+	m.servers[0].data = ServerData{
+		Name:         "rpi5",
+		Status:       &system.StatusInfo{Hostname: "rpi5", CPU: system.CPUInfo{Cores: 4}},
+		DockerStatus: "ok",
+		Containers: []docker.Container{
+			{Name: "postgres", State: "running", Image: "postgres:16", Status: "Up 2 days"},
+			{Name: "backup", State: "exited", Image: "restic/restic", Status: "Stopped · 6h ago"},
+		},
+		DockerStats: []docker.ContainerStats{
+			{Name: "postgres", CPUPercent: 12.5, MemUsageBytes: 1536 << 20, NetRxBytes: 2 << 20, NetTxBytes: 512 << 10, BlockWriteBytes: 3 << 30},
+		},
+	}
+
+	v := m.View()
+	for _, want := range []string{"CPU%", "BLOCK I/O", " 12.5%", "1.5G", "2.0M/512K", "0B/3.0G"} {
+		if !strings.Contains(v, want) {
+			t.Errorf("expected %q in docker panel", want)
+		}
+	}
+	if strings.Contains(v, "postgres:16") {
+		t.Error("image column should give way to stats")
+	}
+
+	// Narrow terminal: CPU and memory only
+	m.width = 90
+	if v := m.View(); strings.Contains(v, "BLOCK I/O") || !strings.Contains(v, "1.5G") {
+		t.Error("expected I/O columns dropped on narrow terminals")
+	}
+}
+
 // -- Style helper tests --
 
 func TestProgressBar(t *testing.T) {
@@ -3,6 +3,7 @@ package tui
 import (
 	"strings"
 
+	"github.com/Higangssh/homebutler/internal/system"
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -42,6 +43,32 @@ func sparkline(data []float64, width int) string {
 	return b.String()
 }
 
+// scaleHistory rescales unbounded data (e.g. bytes/sec) to 0-100 relative
+// to the peak in the window, so it can be drawn with sparkline.
+func scaleHistory(data []float64) []float64 {
+	var peak float64
+	for _, v := range data {
+		peak = max(peak, v)
+	}
+	scaled := make([]float64, len(data))
+	if peak <= 0 {
+		return scaled
+	}
+	for i, v := range data {
+		scaled[i] = v / peak * 100
+	}
+	return scaled
+}
+
+// netThroughput sums rx+tx bytes/sec across all interfaces.
+func netThroughput(nets []system.NetInfo) float64 {
+	var total float64
+	for _, n := range nets {
+		total += n.RxBytesPerSec + n.TxBytesPerSec
+	}
+	return total
+}
+
 // sparklineColor returns the appropriate style for a sparkline
 // based on the last value: green <50%, yellow 50-80%, red >80%.
 func sparklineColor(data []float64) lipgloss.Style {
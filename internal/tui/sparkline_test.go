@@ -121,6 +121,39 @@ func TestAppendHistory_UnderMax(t *testing.T) {
 	}
 }
 
+func TestScaleHistory(t *testing.T) {
+	got := scaleHistory([]float64{0, 512, 2048, 1024})
+	want := []float64{0, 25, 100, 50}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("scaleHistory = %v, want %v", got, want)
+		}
+	}
+	for _, v := range scaleHistory([]float64{0, 0}) {
+		if v != 0 {
+			t.Fatalf("all-zero history should stay zero, got %v", v)
+		}
+	}
+}
+
+func TestCompactRate(t *testing.T) {
+	tests := []struct {
+		in   float64
+		want string
+	}{
+		{0, "0B/s"},
+		{900, "900B/s"},
+		{1536, "1.5K/s"},
+		{200 * 1024, "200K/s"},
+		{12.3 * 1024 * 1024, "12.3M/s"},
+	}
+	for _, tt := range tests {
+		if got := compactRate(tt.in); got != tt.want {
+			t.Errorf("compactRate(%f) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
 func TestSparklineColor(t *testing.T) {
 	tests := []struct {
 		name string
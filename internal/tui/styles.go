@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
@@ -87,6 +88,19 @@ func alertStyle(status string) lipgloss.Style {
 	}
 }
 
+// cpuWaitStyle highlights iowait/steal percentages: these should stay near
+// zero, so anything above 5% is worth noticing and above 20% is a problem.
+func cpuWaitStyle(percent float64) lipgloss.Style {
+	switch {
+	case percent >= 20:
+		return criticalStyle
+	case percent >= 5:
+		return warningStyle
+	default:
+		return dimStyle
+	}
+}
+
 // truncate shortens a string to max length with ellipsis.
 func truncate(s string, max int) string {
 	if len(s) <= max {
@@ -97,3 +111,22 @@ func truncate(s string, max int) string {
 	}
 	return s[:max-1] + "~"
 }
+
+// compactRate formats bytes/sec in at most 7 columns, e.g. "12.3M/s".
+func compactRate(bps float64) string {
+	return compactBytes(bps) + "/s"
+}
+
+// compactBytes formats a byte count in at most 5 columns, e.g. "12.3M".
+func compactBytes(b float64) string {
+	units := []string{"B", "K", "M", "G", "T"}
+	i := 0
+	for b >= 1024 && i < len(units)-1 {
+		b /= 1024
+		i++
+	}
+	if i == 0 || b >= 100 {
+		return fmt.Sprintf("%.0f%s", b, units[i])
+	}
+	return fmt.Sprintf("%.1f%s", b, units[i])
+}
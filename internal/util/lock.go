@@ -0,0 +1,26 @@
+//go:build unix
+
+package util
+
+import (
+	"os"
+	"syscall"
+)
+
+// LockFile takes an exclusive lock on path.lock, waiting while another
+// process or goroutine holds it, for files that are read, changed and
+// rewritten. unlock releases it.
+func LockFile(path string) (unlock func(), err error) {
+	f, err := os.OpenFile(path+".lock", os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return func() {
+		syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		f.Close()
+	}, nil
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package util
+
+// LockFile is a no-op where flock isn't available.
+func LockFile(path string) (unlock func(), err error) {
+	return func() {}, nil
+}
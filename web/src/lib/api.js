@@ -22,6 +22,34 @@ export function getDocker(server) {
   return fetchJSON(withServer('/api/docker', server));
 }
 
+// Tails container logs over server-sent events. onLine gets each
+// { time, stream, text }; onEnd gets an error message or null. Returns a
+// function that stops the stream.
+export function streamDockerLogs(name, server, { tail = 100, since = '', grep = '', follow = true } = {}, onLine, onEnd) {
+  const query = new URLSearchParams({ tail: String(tail) });
+  if (since) query.set('since', since);
+  if (grep) query.set('grep', grep);
+  if (follow) query.set('follow', '1');
+  if (server) query.set('server', server);
+  const source = new EventSource(`${BASE}/api/docker/${encodeURIComponent(name)}/logs?${query}`);
+  source.onmessage = (e) => onLine(JSON.parse(e.data));
+  source.addEventListener('end', () => {
+    source.close();
+    onEnd?.(null);
+  });
+  source.addEventListener('error', (e) => {
+    // Server-sent "error" events carry data; connection errors don't
+    source.close();
+    onEnd?.(e.data ? JSON.parse(e.data).error : 'connection lost');
+  });
+  return () => source.close();
+}
+
+// Registry lookups are slow; call on demand rather than on every refresh
+export function getDockerUpdates(server) {
+  return fetchJSON(withServer('/api/docker/updates', server));
+}
+
 export function getProcesses(server) {
   return fetchJSON(withServer('/api/processes', server));
 }